@@ -12,12 +12,16 @@ import (
 
 	mikrotikactions "github.com/micro-ha/mikrotik-presence/addon/internal/adapters/mikrotik/actions"
 	mikrotikstatesources "github.com/micro-ha/mikrotik-presence/addon/internal/adapters/mikrotik/statesources"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/adapters/webhook"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/aggregator"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/config"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/configsync"
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/events"
 	httpapi "github.com/micro-ha/mikrotik-presence/addon/internal/http"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/http/handlers"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/logging"
+	mqttbridge "github.com/micro-ha/mikrotik-presence/addon/internal/mqtt"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/oui"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/poller"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/repository/sqlite"
@@ -26,6 +30,8 @@ import (
 	automationengine "github.com/micro-ha/mikrotik-presence/addon/internal/services/automation/engine"
 	automationregistry "github.com/micro-ha/mikrotik-presence/addon/internal/services/automation/registry"
 	deviceservice "github.com/micro-ha/mikrotik-presence/addon/internal/services/device"
+	quarantineservice "github.com/micro-ha/mikrotik-presence/addon/internal/services/quarantine"
+	routerservice "github.com/micro-ha/mikrotik-presence/addon/internal/services/router"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/subnet"
 )
 
@@ -66,7 +72,9 @@ func main() {
 
 	deviceRepo := sqlite.NewDeviceRepository(db)
 	automationRepo := sqlite.NewAutomationRepository(db)
+	ledgerRepo := sqlite.NewLedgerRepository(db)
 
+	bus := events.NewBus(events.DefaultBufferSize)
 	deviceSvc := deviceservice.NewWithThresholds(
 		deviceRepo,
 		agg,
@@ -74,13 +82,29 @@ func main() {
 		cfgManager,
 		logger.With("service", "device"),
 		cfg.PresenceThresholds,
-	)
+	).WithEvents(bus)
 
+	commandAllowlist := loadCommandAllowlist(ctx, cfgClient, logger)
 	reg := automationregistry.New()
 	reg.RegisterAction(mikrotikactions.NewAddressListMembershipAction())
 	reg.RegisterAction(mikrotikactions.NewFirewallRuleToggleAction())
+	reg.RegisterAction(mikrotikactions.NewQueueLimitAction())
+	reg.RegisterAction(mikrotikactions.NewContentFilterAction())
+	reg.RegisterAction(mikrotikactions.NewKidControlPauseAction())
+	reg.RegisterAction(mikrotikactions.NewDNSAdlistToggleAction())
+	reg.RegisterAction(mikrotikactions.NewDNSStaticRecordAction())
+	reg.RegisterAction(mikrotikactions.NewWakeOnLANAction())
+	reg.RegisterAction(mikrotikactions.NewWiFiAccessAction())
+	reg.RegisterAction(webhook.NewHTTPRequestAction())
+	reg.RegisterAction(mikrotikactions.NewRouterCommandAction(commandAllowlist))
 	reg.RegisterStateSource(mikrotikstatesources.NewAddressListMembershipSource())
 	reg.RegisterStateSource(mikrotikstatesources.NewFirewallRuleEnabledSource())
+	reg.RegisterStateSource(mikrotikstatesources.NewQueueLimitActiveSource())
+	reg.RegisterStateSource(mikrotikstatesources.NewContentFilterActiveSource())
+	reg.RegisterStateSource(mikrotikstatesources.NewKidControlPausedSource())
+	reg.RegisterStateSource(mikrotikstatesources.NewDNSAdlistEnabledSource())
+	reg.RegisterStateSource(mikrotikstatesources.NewWiFiAccessDeniedSource())
+	reg.RegisterStateSource(mikrotikstatesources.NewRouterQuerySource(commandAllowlist))
 
 	engine := automationengine.New(
 		automationRepo,
@@ -89,26 +113,90 @@ func main() {
 		cfgManager,
 		routerClient,
 		logger.With("service", "automation_engine"),
+	).
+		WithEnforceInterval(cfg.AutomationEnforceDelay).
+		WithSyncWorkers(cfg.AutomationSyncWorkers).
+		WithRouterConcurrency(cfg.RouterSyncConcurrency).
+		WithLedger(ledgerRepo).
+		WithEvents(bus)
+	deviceSvc.AddIPChangeListener(engine)
+
+	quarantineCfg, err := cfgClient.FetchQuarantineConfig(ctx)
+	if err != nil {
+		logger.Warn("quarantine config load failed", "err", err)
+	}
+	quarantineSvc := quarantineservice.New(
+		sqlite.NewQuarantineRepository(db),
+		deviceSvc,
+		routerClient,
+		cfgManager,
+		quarantineCfg,
+		logger.With("service", "quarantine"),
 	)
+	deviceSvc.AddNewDeviceListener(quarantineSvc)
+	deviceSvc.AddRegistrationListener(quarantineSvc)
+	deviceSvc.AddIPChangeListener(quarantineSvc)
+	go quarantineSvc.Run(ctx)
 	automationSvc := automationservice.New(
 		automationRepo,
 		deviceSvc,
 		engine,
 		reg,
 		logger.With("service", "automation"),
-	)
+	).
+		WithParamOptions(routerClient, cfgManager).
+		WithArtefactGC(ledgerRepo, routerClient, cfgManager)
 
-	devicePoller := poller.New(deviceSvc, cfgManager, logger.With("component", "poller"))
+	devicePoller := poller.New(deviceSvc, cfgManager, logger.With("component", "poller")).WithEvents(bus)
 	go runConfigFallbackRefresh(ctx, cfgManager, devicePoller, logger, cfg.ConfigRefreshInterval)
 	go devicePoller.Run(ctx)
 	devicePoller.TriggerRefresh()
 
 	go engine.RunSyncLoop(ctx, cfg.AutomationSyncInterval)
+	go engine.RunIPChanges(ctx)
+	go automationSvc.RunArtefactGCLoop(ctx, cfg.ArtefactGCInterval)
+
+	mqttCfg, err := cfgClient.FetchMQTTConfig(ctx)
+	if err != nil {
+		logger.Warn("mqtt config load failed", "err", err)
+	}
+	if mqttCfg.Enabled() {
+		mqttLogger := logger.With("component", "mqtt")
+		broker, err := mqttbridge.Dial(mqttCfg, "mikrotik_presence_addon", mqttLogger)
+		if err != nil {
+			logger.Warn("mqtt disabled: broker connection failed", "err", err)
+		} else {
+			defer broker.Close()
+			bridge := mqttbridge.New(mqttCfg, broker, automationSvc, deviceSvc, mqttLogger)
+			if err := bridge.Start(ctx); err != nil {
+				logger.Warn("mqtt subscriptions failed", "err", err)
+			}
+			go bridge.Run(ctx, 0)
+		}
+	}
+
+	routerSvc := routerservice.New(
+		routerClient,
+		cfgManager,
+		automationRepo,
+		logger.With("service", "router"),
+	)
+	leaseComments, err := cfgClient.FetchDHCPLeaseComments(ctx)
+	if err != nil {
+		logger.Warn("dhcp lease comments option load failed", "err", err)
+	}
+	if leaseComments {
+		routerSvc.WithLeaseComments(deviceSvc)
+		deviceSvc.AddRegistrationListener(routerSvc)
+	}
 
 	api := handlers.New(
 		deviceSvc,
 		automationSvc,
+		routerSvc,
+		quarantineSvc,
 		devicePoller,
+		bus,
 		cfgManager,
 		logger.With("component", "http"),
 		cfg.FrontendDist,
@@ -129,6 +217,29 @@ func main() {
 		os.Exit(1)
 	}
 	logger.Info("server stopped")
+
+	hooksCtx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+	defer cancel()
+	if err := engine.Wait(hooksCtx); err != nil {
+		logger.Warn("capability disable hooks still running at shutdown", "err", err)
+	}
+}
+
+func loadCommandAllowlist(
+	ctx context.Context,
+	cfgClient *configsync.Client,
+	logger *slog.Logger,
+) automationdomain.CommandAllowlist {
+	entries, err := cfgClient.FetchCommandAllowlist(ctx)
+	if err != nil {
+		logger.Warn("router command allowlist load failed", "err", err)
+	}
+	allowlist, err := automationdomain.ParseCommandAllowlist(entries)
+	if err != nil {
+		logger.Warn("router command allowlist is invalid; generic commands disabled", "err", err)
+		return automationdomain.CommandAllowlist{}
+	}
+	return allowlist
 }
 
 func runConfigFallbackRefresh(
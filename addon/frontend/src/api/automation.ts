@@ -6,8 +6,12 @@ import {
   capabilityDeviceAssignmentSchema,
   capabilityTemplateSchema,
   capabilityUIModelSchema,
+  gcReportSchema,
+  paramOptionSchema,
+  routerArtefactSchema,
   stateSourceTypeSchema,
   setStateResultSchema,
+  syncStatusSchema,
   type CapabilityTemplate
 } from "@/types/automation";
 
@@ -16,6 +20,9 @@ const stateSourceTypesSchema = z.array(stateSourceTypeSchema);
 const capabilitiesSchema = z.array(capabilityTemplateSchema);
 const capabilityAssignmentsSchema = z.array(capabilityDeviceAssignmentSchema);
 const capabilityUIModelsSchema = z.array(capabilityUIModelSchema);
+const routerArtefactsSchema = z.array(routerArtefactSchema);
+const syncStatusesSchema = z.array(syncStatusSchema);
+const paramOptionsSchema = z.array(paramOptionSchema);
 
 export type CapabilitiesQuery = {
   search?: string;
@@ -32,6 +39,19 @@ export async function fetchStateSourceTypes() {
   return stateSourceTypesSchema.parse(raw);
 }
 
+export async function fetchParamOptions(provider: string, arg = "") {
+  const query = arg ? `?arg=${encodeURIComponent(arg)}` : "";
+  const raw = await apiRequest<unknown>(
+    `/api/automation/param-options/${encodeURIComponent(provider)}${query}`
+  );
+  return paramOptionsSchema.parse(raw);
+}
+
+export async function fetchSyncStatus() {
+  const raw = await apiRequest<unknown>("/api/automation/sync-status");
+  return syncStatusesSchema.parse(raw);
+}
+
 export async function fetchCapabilities(query: CapabilitiesQuery) {
   const params = new URLSearchParams();
   if (query.search?.trim()) {
@@ -155,3 +175,18 @@ export async function patchCapabilityDevice(
   );
   return setStateResultSchema.parse(raw);
 }
+
+export async function fetchArtefacts() {
+  const raw = await apiRequest<unknown>("/api/automation/artefacts");
+  return routerArtefactsSchema.parse(raw);
+}
+
+export async function fetchArtefactGCPreview() {
+  const raw = await apiRequest<unknown>("/api/automation/gc/preview");
+  return gcReportSchema.parse(raw);
+}
+
+export async function runArtefactGC() {
+  const raw = await apiRequest<unknown>("/api/automation/gc", { method: "POST" });
+  return gcReportSchema.parse(raw);
+}
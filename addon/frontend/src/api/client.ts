@@ -1,18 +1,26 @@
 import { withAppBase } from "@/lib/ingress";
 
+export type ApiFieldError = {
+  path: string;
+  message: string;
+};
+
 export class ApiError extends Error {
   public code: string;
+  public fields: ApiFieldError[];
 
-  constructor(message: string, code = "unknown_error") {
+  constructor(message: string, code = "unknown_error", fields: ApiFieldError[] = []) {
     super(message);
     this.name = "ApiError";
     this.code = code;
+    this.fields = fields;
   }
 }
 
 async function parseError(response: Response): Promise<never> {
   let message = `Request failed with status ${response.status}`;
   let code = "unknown_error";
+  let fields: ApiFieldError[] = [];
   try {
     const payload = await response.json();
     if (payload?.error?.message) {
@@ -21,10 +29,13 @@ async function parseError(response: Response): Promise<never> {
     if (payload?.error?.code) {
       code = payload.error.code;
     }
+    if (Array.isArray(payload?.error?.fields)) {
+      fields = payload.error.fields;
+    }
   } catch {
     // noop
   }
-  throw new ApiError(message, code);
+  throw new ApiError(message, code, fields);
 }
 
 export async function apiRequest<T>(path: string, init?: RequestInit): Promise<T> {
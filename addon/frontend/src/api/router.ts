@@ -0,0 +1,36 @@
+import { apiRequest } from "@/api/client";
+import {
+  firewallRuleSchema,
+  listAddressListEntriesResponseSchema,
+  listAddressListsResponseSchema,
+  listFirewallRulesResponseSchema,
+  type FirewallTable
+} from "@/types/router";
+
+export async function fetchFirewallRules(table: FirewallTable) {
+  const raw = await apiRequest<unknown>(`/api/router/firewall/${table}`);
+  return listFirewallRulesResponseSchema.parse(raw).items;
+}
+
+export async function patchFirewallRule(table: FirewallTable, ruleId: string, disabled: boolean) {
+  const raw = await apiRequest<unknown>(
+    `/api/router/firewall/${table}/${encodeURIComponent(ruleId)}`,
+    {
+      method: "PATCH",
+      body: JSON.stringify({ disabled })
+    }
+  );
+  return firewallRuleSchema.parse(raw);
+}
+
+export async function fetchAddressLists() {
+  const raw = await apiRequest<unknown>("/api/router/address-lists");
+  return listAddressListsResponseSchema.parse(raw).items;
+}
+
+export async function fetchAddressListEntries(list: string) {
+  const raw = await apiRequest<unknown>(
+    `/api/router/address-lists/${encodeURIComponent(list)}/entries`
+  );
+  return listAddressListEntriesResponseSchema.parse(raw).items;
+}
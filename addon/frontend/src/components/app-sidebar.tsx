@@ -1,7 +1,11 @@
 import { useEffect, useState } from "react";
 import {
   ChevronRight,
+  Eraser,
+  ListChecks,
   Monitor,
+  Router,
+  Shield,
   SlidersHorizontal,
   Sparkles,
   ToggleLeft,
@@ -33,12 +37,20 @@ const automationItems = [
   { to: "/automation/primitives", label: "Primitives", icon: Workflow }
 ];
 
+const routerItems = [
+  { to: "/router/firewall", label: "Firewall", icon: Shield },
+  { to: "/router/address-lists", label: "Address-lists", icon: ListChecks },
+  { to: "/router/cleanup", label: "Cleanup", icon: Eraser }
+];
+
 export function AppSidebar() {
   const location = useLocation();
   const pathname = location.pathname;
 
   const automationOpen = pathname.startsWith("/automation");
   const [isAutomationExpanded, setIsAutomationExpanded] = useState(automationOpen);
+  const routerOpen = pathname.startsWith("/router");
+  const [isRouterExpanded, setIsRouterExpanded] = useState(routerOpen);
 
   useEffect(() => {
     if (automationOpen) {
@@ -46,6 +58,12 @@ export function AppSidebar() {
     }
   }, [automationOpen]);
 
+  useEffect(() => {
+    if (routerOpen) {
+      setIsRouterExpanded(true);
+    }
+  }, [routerOpen]);
+
   return (
     <Sidebar variant="inset">
       <SidebarHeader>
@@ -100,6 +118,40 @@ export function AppSidebar() {
                   </CollapsibleContent>
                 </Collapsible>
               </SidebarMenuItem>
+
+              <SidebarMenuItem>
+                <Collapsible
+                  open={isRouterExpanded}
+                  onOpenChange={setIsRouterExpanded}
+                  className="group/collapsible"
+                >
+                  <CollapsibleTrigger asChild>
+                    <SidebarMenuButton isActive={routerOpen}>
+                      <Router className="h-4 w-4" />
+                      <span className="flex-1 text-left">Router</span>
+                      <ChevronRight className="h-4 w-4 transition-transform group-data-[state=open]/collapsible:rotate-90" />
+                    </SidebarMenuButton>
+                  </CollapsibleTrigger>
+
+                  <CollapsibleContent>
+                    <SidebarMenuSub>
+                      {routerItems.map((item) => {
+                        const active = pathname === item.to;
+                        return (
+                          <SidebarMenuSubItem key={item.to}>
+                            <SidebarMenuSubButton asChild isActive={active}>
+                              <NavLink to={item.to}>
+                                <item.icon className="mr-2 h-3.5 w-3.5" />
+                                <span>{item.label}</span>
+                              </NavLink>
+                            </SidebarMenuSubButton>
+                          </SidebarMenuSubItem>
+                        );
+                      })}
+                    </SidebarMenuSub>
+                  </CollapsibleContent>
+                </Collapsible>
+              </SidebarMenuItem>
             </SidebarMenu>
           </SidebarGroupContent>
         </SidebarGroup>
@@ -1,5 +1,6 @@
 import { useMemo, useState } from "react";
 
+import { ParamFieldInput } from "@/components/automation/ParamFieldInput";
 import { Button } from "@/components/ui/button";
 import {
   Dialog,
@@ -9,7 +10,6 @@ import {
   DialogHeader,
   DialogTitle
 } from "@/components/ui/dialog";
-import { Input } from "@/components/ui/input";
 import { Label } from "@/components/ui/label";
 import {
   Select,
@@ -18,7 +18,6 @@ import {
   SelectTrigger,
   SelectValue
 } from "@/components/ui/select";
-import { Switch } from "@/components/ui/switch";
 import {
   defaultValueForActionField,
   resolveVisibleFields,
@@ -133,68 +132,18 @@ export function ActionInstanceDialog({ open, onOpenChange, actionTypes, scope, o
           {selectedType ? (
             <div className="space-y-3">
               {visibleFields.map((field) => (
-                <div key={field.key} className="space-y-2">
-                  <Label>
-                    {field.label}
-                    {field.required ? " *" : ""}
-                  </Label>
-
-                  {field.kind === "string" ? (
-                    <Input
-                      value={(params[field.key] as string) ?? ""}
-                      onChange={(event) =>
-                        setParams((current) => ({
-                          ...current,
-                          [field.key]: event.target.value
-                        }))
-                      }
-                    />
-                  ) : null}
-
-                  {field.kind === "enum" ? (
-                    <Select
-                      value={String(params[field.key] ?? "")}
-                      onValueChange={(value) =>
-                        setParams((current) => ({
-                          ...current,
-                          [field.key]: value
-                        }))
-                      }
-                    >
-                      <SelectTrigger>
-                        <SelectValue placeholder="Select value" />
-                      </SelectTrigger>
-                      <SelectContent>
-                        {(field.options ?? []).map((option) => (
-                          <SelectItem key={option} value={option}>
-                            {option}
-                          </SelectItem>
-                        ))}
-                      </SelectContent>
-                    </Select>
-                  ) : null}
-
-                  {field.kind === "bool" ? (
-                    <div className="flex items-center gap-2">
-                      <Switch
-                        checked={Boolean(params[field.key])}
-                        onCheckedChange={(checked) =>
-                          setParams((current) => ({
-                            ...current,
-                            [field.key]: checked
-                          }))
-                        }
-                      />
-                      <span className="text-sm text-muted-foreground">
-                        {params[field.key] ? "True" : "False"}
-                      </span>
-                    </div>
-                  ) : null}
-
-                  {field.description ? (
-                    <p className="text-xs text-muted-foreground">{field.description}</p>
-                  ) : null}
-                </div>
+                <ParamFieldInput
+                  key={field.key}
+                  field={field}
+                  value={params[field.key]}
+                  params={params}
+                  onChange={(value) =>
+                    setParams((current) => ({
+                      ...current,
+                      [field.key]: value
+                    }))
+                  }
+                />
               ))}
             </div>
           ) : null}
@@ -0,0 +1,87 @@
+import { Trash2 } from "lucide-react";
+
+import { Button } from "@/components/ui/button";
+import { fieldErrorsWithPrefix } from "@/lib/automation";
+import { cn } from "@/lib/utils";
+import type { ActionInstance, ActionType } from "@/types/automation";
+
+type Props = {
+  title: string;
+  actions: ActionInstance[];
+  actionTypes: ActionType[];
+  errorPath: string;
+  emptyText: string;
+  onAdd: () => void;
+  onRemove: (actionId: string) => void;
+  fieldErrors?: Record<string, string>;
+};
+
+function actionLabel(actionTypes: ActionType[], typeId: string) {
+  return actionTypes.find((item) => item.id === typeId)?.label ?? typeId;
+}
+
+function paramsSummary(action: ActionInstance) {
+  const entries = Object.entries(action.params ?? {});
+  if (entries.length === 0) {
+    return "No params";
+  }
+  return entries
+    .slice(0, 3)
+    .map(([key, value]) => `${key}: ${String(value)}`)
+    .join(" · ");
+}
+
+export function ActionInstanceList({
+  title,
+  actions,
+  actionTypes,
+  errorPath,
+  emptyText,
+  onAdd,
+  onRemove,
+  fieldErrors = {}
+}: Props) {
+  return (
+    <div className="space-y-2">
+      <div className="flex items-center justify-between gap-2">
+        <p className="text-sm font-medium">{title}</p>
+        <Button variant="outline" size="sm" onClick={onAdd}>
+          Add action
+        </Button>
+      </div>
+
+      {actions.length === 0 ? (
+        <p className="text-sm text-muted-foreground">{emptyText}</p>
+      ) : (
+        <div className="space-y-2">
+          {actions.map((action, index) => {
+            const errors = fieldErrorsWithPrefix(fieldErrors, `${errorPath}[${index}]`);
+            return (
+              <div
+                key={action.id}
+                className={cn(
+                  "flex items-start justify-between gap-3 rounded-md border p-3",
+                  errors.length > 0 && "border-destructive"
+                )}
+              >
+                <div>
+                  <p className="text-sm font-medium">{actionLabel(actionTypes, action.type_id)}</p>
+                  <p className="text-xs text-muted-foreground">{action.type_id}</p>
+                  <p className="text-xs text-muted-foreground">{paramsSummary(action)}</p>
+                  {errors.map((error) => (
+                    <p key={error} className="text-xs text-destructive">
+                      {error}
+                    </p>
+                  ))}
+                </div>
+                <Button variant="ghost" size="icon" onClick={() => onRemove(action.id)}>
+                  <Trash2 className="h-4 w-4" />
+                </Button>
+              </div>
+            );
+          })}
+        </div>
+      )}
+    </div>
+  );
+}
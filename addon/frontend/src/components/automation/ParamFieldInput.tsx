@@ -0,0 +1,138 @@
+import { useId, useState } from "react";
+
+import { Input } from "@/components/ui/input";
+import { Label } from "@/components/ui/label";
+import {
+  Select,
+  SelectContent,
+  SelectItem,
+  SelectTrigger,
+  SelectValue
+} from "@/components/ui/select";
+import { Switch } from "@/components/ui/switch";
+import { Textarea } from "@/components/ui/textarea";
+import { useParamOptions } from "@/hooks/useParamOptions";
+import { cn } from "@/lib/utils";
+import type { ActionParamField } from "@/types/automation";
+
+type Props = {
+  field: ActionParamField;
+  value: unknown;
+  params?: Record<string, unknown>;
+  error?: string;
+  onChange: (value: unknown) => void;
+};
+
+const placeholders: Partial<Record<ActionParamField["kind"], string>> = {
+  int: "e.g. 10",
+  duration: "e.g. 30s, 5m",
+  ip: "e.g. 192.168.88.10",
+  cidr: "e.g. 192.168.88.0/24",
+  mac: "e.g. AA:BB:CC:DD:EE:FF",
+  string_list: "Comma-separated values"
+};
+
+function ObjectInput({ value, invalid, onChange }: { value: unknown; invalid: boolean; onChange: (value: unknown) => void }) {
+  const [text, setText] = useState(() =>
+    typeof value === "string" ? value : JSON.stringify(value ?? {}, null, 2)
+  );
+
+  return (
+    <Textarea
+      className={cn("font-mono text-xs", invalid && "border-destructive")}
+      value={text}
+      onChange={(event) => {
+        setText(event.target.value);
+        try {
+          onChange(JSON.parse(event.target.value));
+        } catch {
+          // Keep raw text; server-side validation reports the field.
+          onChange(event.target.value);
+        }
+      }}
+    />
+  );
+}
+
+export function ParamFieldInput({ field, value, params, error, onChange }: Props) {
+  const invalid = Boolean(error);
+  const optionsListId = useId();
+  const optionsArg = field.options_arg_key ? String(params?.[field.options_arg_key] ?? "") : "";
+  // Live options are suggestions only; templates and unknown values stay valid.
+  const liveOptions = useParamOptions(field.options_provider, optionsArg);
+
+  return (
+    <div className="space-y-2">
+      <Label className={cn(invalid && "text-destructive")}>
+        {field.label}
+        {field.required ? " *" : ""}
+      </Label>
+
+      {field.kind === "enum" ? (
+        <Select value={String(value ?? "")} onValueChange={onChange}>
+          <SelectTrigger className={cn(invalid && "border-destructive")}>
+            <SelectValue placeholder="Select value" />
+          </SelectTrigger>
+          <SelectContent>
+            {(field.options ?? []).map((option) => (
+              <SelectItem key={option} value={option}>
+                {option}
+              </SelectItem>
+            ))}
+          </SelectContent>
+        </Select>
+      ) : null}
+
+      {field.kind === "bool" ? (
+        <div className="flex items-center gap-2">
+          <Switch checked={Boolean(value)} onCheckedChange={onChange} />
+          <span className="text-sm text-muted-foreground">{value ? "True" : "False"}</span>
+        </div>
+      ) : null}
+
+      {field.kind === "string_list" ? (
+        <Input
+          className={cn(invalid && "border-destructive")}
+          placeholder={placeholders.string_list}
+          value={Array.isArray(value) ? value.join(", ") : String(value ?? "")}
+          onChange={(event) =>
+            onChange(
+              event.target.value
+                .split(",")
+                .map((item) => item.trim())
+                .filter(Boolean)
+            )
+          }
+        />
+      ) : null}
+
+      {field.kind === "object" ? <ObjectInput value={value} invalid={invalid} onChange={onChange} /> : null}
+
+      {["string", "int", "duration", "ip", "cidr", "mac"].includes(field.kind) ? (
+        <Input
+          className={cn(invalid && "border-destructive")}
+          placeholder={field.options_provider ? "Select or type value" : placeholders[field.kind]}
+          list={field.options_provider ? optionsListId : undefined}
+          value={String(value ?? "")}
+          onChange={(event) => onChange(event.target.value)}
+        />
+      ) : null}
+
+      {field.options_provider ? (
+        <datalist id={optionsListId}>
+          {(liveOptions.data ?? []).map((option) => (
+            <option key={option.value} value={option.value}>
+              {option.label}
+            </option>
+          ))}
+        </datalist>
+      ) : null}
+      {field.options_provider && liveOptions.isError ? (
+        <p className="text-xs text-muted-foreground">Router values unavailable; enter value manually.</p>
+      ) : null}
+
+      {error ? <p className="text-xs text-destructive">{error}</p> : null}
+      {field.description ? <p className="text-xs text-muted-foreground">{field.description}</p> : null}
+    </div>
+  );
+}
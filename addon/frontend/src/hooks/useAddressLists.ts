@@ -0,0 +1,23 @@
+import { useQuery } from "@tanstack/react-query";
+
+import { fetchAddressListEntries, fetchAddressLists } from "@/api/router";
+import { queryKeys } from "@/lib/query-keys";
+
+export function useAddressLists() {
+  return useQuery({
+    queryKey: queryKeys.routerAddressLists,
+    queryFn: () => fetchAddressLists(),
+    staleTime: 5000,
+    refetchInterval: 15000
+  });
+}
+
+export function useAddressListEntries(list: string | null) {
+  return useQuery({
+    queryKey: queryKeys.routerAddressListEntries(list ?? ""),
+    queryFn: () => fetchAddressListEntries(list ?? ""),
+    enabled: Boolean(list),
+    staleTime: 5000,
+    refetchInterval: 15000
+  });
+}
@@ -0,0 +1,35 @@
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+
+import { fetchArtefactGCPreview, fetchArtefacts, runArtefactGC } from "@/api/automation";
+import { queryKeys } from "@/lib/query-keys";
+import type { GCReport } from "@/types/automation";
+
+export function useArtefacts() {
+  return useQuery({
+    queryKey: queryKeys.automationArtefacts,
+    queryFn: () => fetchArtefacts(),
+    staleTime: 5000,
+    refetchInterval: 30000
+  });
+}
+
+export function useArtefactGCPreview() {
+  return useQuery({
+    queryKey: queryKeys.automationGCPreview,
+    queryFn: () => fetchArtefactGCPreview(),
+    staleTime: 5000,
+    refetchInterval: 30000
+  });
+}
+
+export function useRunArtefactGC() {
+  const client = useQueryClient();
+
+  return useMutation<GCReport, Error>({
+    mutationFn: () => runArtefactGC(),
+    onSuccess: async () => {
+      await client.invalidateQueries({ queryKey: queryKeys.automationArtefacts });
+      await client.invalidateQueries({ queryKey: queryKeys.automationGCPreview });
+    }
+  });
+}
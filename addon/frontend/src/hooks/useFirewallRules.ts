@@ -0,0 +1,30 @@
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+
+import { fetchFirewallRules, patchFirewallRule } from "@/api/router";
+import { queryKeys } from "@/lib/query-keys";
+import type { FirewallRule, FirewallTable } from "@/types/router";
+
+export function useFirewallRules(table: FirewallTable) {
+  return useQuery({
+    queryKey: queryKeys.routerFirewallRules(table),
+    queryFn: () => fetchFirewallRules(table),
+    staleTime: 5000,
+    refetchInterval: 10000
+  });
+}
+
+type ToggleInput = {
+  ruleId: string;
+  disabled: boolean;
+};
+
+export function useToggleFirewallRule(table: FirewallTable) {
+  const client = useQueryClient();
+
+  return useMutation<FirewallRule, Error, ToggleInput>({
+    mutationFn: ({ ruleId, disabled }) => patchFirewallRule(table, ruleId, disabled),
+    onSuccess: async () => {
+      await client.invalidateQueries({ queryKey: queryKeys.routerFirewallRules(table) });
+    }
+  });
+}
@@ -0,0 +1,14 @@
+import { useQuery } from "@tanstack/react-query";
+
+import { fetchParamOptions } from "@/api/automation";
+import { queryKeys } from "@/lib/query-keys";
+
+export function useParamOptions(provider: string | undefined, arg: string) {
+  return useQuery({
+    queryKey: queryKeys.automationParamOptions(provider ?? "", arg),
+    queryFn: () => fetchParamOptions(provider ?? "", arg),
+    enabled: Boolean(provider),
+    staleTime: 30_000,
+    retry: false
+  });
+}
@@ -21,10 +21,12 @@ export function createEmptyCapabilityTemplate(): CapabilityTemplate {
       ]
     },
     states: {
-      on: { label: "On", actions_on_enter: [] },
-      off: { label: "Off", actions_on_enter: [] }
+      on: { label: "On", actions_on_enter: [], actions_on_exit: [] },
+      off: { label: "Off", actions_on_enter: [], actions_on_exit: [] }
     },
     default_state: "off",
+    transitions: [],
+    actions_on_disable: [],
     ha_expose: {
       enabled: false,
       entity_type: "switch",
@@ -43,7 +45,8 @@ export function buildStateMapFromOptions(
     const previous = currentStates[option.value];
     result[option.value] = previous ?? {
       label: option.label,
-      actions_on_enter: []
+      actions_on_enter: [],
+      actions_on_exit: []
     };
   }
   return result;
@@ -75,11 +78,19 @@ export function normalizeControl(
   return Array.from(unique.values());
 }
 
+export function allTemplateActions(template: CapabilityTemplate) {
+  return [
+    ...Object.values(template.states).flatMap((state) => [
+      ...state.actions_on_enter,
+      ...state.actions_on_exit
+    ]),
+    ...template.transitions.flatMap((transition) => transition.actions),
+    ...template.actions_on_disable
+  ];
+}
+
 export function countActions(template: CapabilityTemplate) {
-  return Object.values(template.states).reduce(
-    (total, state) => total + state.actions_on_enter.length,
-    0
-  );
+  return allTemplateActions(template).length;
 }
 
 export function categoriesFromCapabilities(capabilities: CapabilityTemplate[]) {
@@ -99,9 +110,29 @@ export function defaultValueForActionField(field: ActionParamField): unknown {
   if (field.kind === "enum") {
     return field.options?.[0] ?? "";
   }
+  if (field.kind === "string_list") {
+    return [];
+  }
+  if (field.kind === "object") {
+    return {};
+  }
   return "";
 }
 
+export function fieldErrorsByPath(errors: { path: string; message: string }[]) {
+  const byPath: Record<string, string> = {};
+  for (const error of errors) {
+    byPath[error.path] ??= error.message;
+  }
+  return byPath;
+}
+
+export function fieldErrorsWithPrefix(errors: Record<string, string>, prefix: string) {
+  return Object.entries(errors)
+    .filter(([path]) => path === prefix || path.startsWith(`${prefix}.`) || path.startsWith(`${prefix}[`))
+    .map(([path, message]) => `${path.slice(prefix.length + 1) || path}: ${message}`);
+}
+
 export function resolveVisibleFields(fields: ActionParamField[], params: Record<string, unknown>) {
   return fields.filter((field) => {
     if (!field.visible_if) {
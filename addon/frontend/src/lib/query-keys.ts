@@ -18,11 +18,18 @@ export const queryKeys = {
   savedViews: ["devices", "savedViews"] as const,
   automationActionTypes: ["automation", "action-types"] as const,
   automationStateSourceTypes: ["automation", "state-source-types"] as const,
+  automationParamOptions: (provider: string, arg: string) =>
+    ["automation", "param-options", provider, arg] as const,
   automationCapabilities: (search: string, category: string) =>
     ["automation", "capabilities", { search, category }] as const,
   automationCapabilityDetail: (capabilityId: string) =>
     ["automation", "capability", capabilityId] as const,
   automationAssignments: (capabilityId: string) =>
     ["automation", "assignments", capabilityId] as const,
-  automationGlobalCapabilities: ["automation", "global-capabilities"] as const
+  automationGlobalCapabilities: ["automation", "global-capabilities"] as const,
+  automationArtefacts: ["automation", "artefacts"] as const,
+  automationGCPreview: ["automation", "gc", "preview"] as const,
+  routerFirewallRules: (table: string) => ["router", "firewall", table] as const,
+  routerAddressLists: ["router", "address-lists"] as const,
+  routerAddressListEntries: (list: string) => ["router", "address-lists", list] as const
 };
@@ -13,6 +13,9 @@ import { CapabilitiesPage } from "@/pages/automation/CapabilitiesPage";
 import { CapabilityEditorPage } from "@/pages/automation/CapabilityEditorPage";
 import { GlobalCapabilitiesPage } from "@/pages/automation/GlobalCapabilitiesPage";
 import { PrimitivesPage } from "@/pages/automation/PrimitivesPage";
+import { AddressListsPage } from "@/pages/router/AddressListsPage";
+import { CleanupPage } from "@/pages/router/CleanupPage";
+import { FirewallPage } from "@/pages/router/FirewallPage";
 
 import "./index.css";
 
@@ -33,6 +36,10 @@ createRoot(document.getElementById("root")!).render(
               <Route path="/automation/global" element={<GlobalCapabilitiesPage />} />
               <Route path="/automation/assignments" element={<AssignmentsPage />} />
               <Route path="/automation/primitives" element={<PrimitivesPage />} />
+              <Route path="/router" element={<Navigate to="/router/firewall" replace />} />
+              <Route path="/router/firewall" element={<FirewallPage />} />
+              <Route path="/router/address-lists" element={<AddressListsPage />} />
+              <Route path="/router/cleanup" element={<CleanupPage />} />
               <Route path="*" element={<Navigate to="/" replace />} />
             </Route>
           </Routes>
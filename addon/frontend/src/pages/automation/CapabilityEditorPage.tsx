@@ -3,8 +3,11 @@ import { Plus, Trash2 } from "lucide-react";
 import { useNavigate, useParams } from "react-router-dom";
 import { toast } from "sonner";
 
+import { ApiError } from "@/api/client";
 import { ActionInstanceDialog } from "@/components/automation/ActionInstanceDialog";
+import { ActionInstanceList } from "@/components/automation/ActionInstanceList";
 import { CapabilityStateCard } from "@/components/automation/CapabilityStateCard";
+import { ParamFieldInput } from "@/components/automation/ParamFieldInput";
 import { Button } from "@/components/ui/button";
 import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
 import { Input } from "@/components/ui/input";
@@ -24,15 +27,29 @@ import { useActionTypes } from "@/hooks/useActionTypes";
 import { useCapabilityEditor } from "@/hooks/useCapabilityEditor";
 import { useStateSourceTypes } from "@/hooks/useStateSourceTypes";
 import {
+  allTemplateActions,
   buildStateMapFromOptions,
   createEmptyCapabilityTemplate,
   defaultValueForActionField,
+  fieldErrorsByPath,
   hasScopeViolationForGlobal,
   normalizeControl,
   resolveVisibleFields,
   scopeParamSchema
 } from "@/lib/automation";
-import type { CapabilityTemplate, ControlType, StateSourceType } from "@/types/automation";
+import type {
+  ActionInstance,
+  CapabilityTemplate,
+  ControlType,
+  StateActionHook,
+  StateSourceType
+} from "@/types/automation";
+
+// ActionSlot points at the template action list an added action goes to.
+type ActionSlot =
+  | { kind: "state"; stateId: string; hook: StateActionHook }
+  | { kind: "transition"; index: number }
+  | { kind: "disable" };
 
 const categoryOptions = [
   "General",
@@ -89,11 +106,9 @@ function hasGlobalScopeParamViolations(template: CapabilityTemplate) {
     return false;
   }
 
-  for (const state of Object.values(template.states)) {
-    for (const action of state.actions_on_enter) {
-      if (hasScopeViolationForGlobal(action.params)) {
-        return true;
-      }
+  for (const action of allTemplateActions(template)) {
+    if (hasScopeViolationForGlobal(action.params)) {
+      return true;
     }
   }
 
@@ -110,7 +125,8 @@ export function CapabilityEditorPage() {
   const editor = useCapabilityEditor(capabilityId);
 
   const [draft, setDraft] = useState<CapabilityTemplate>(createEmptyCapabilityTemplate());
-  const [actionDialogStateId, setActionDialogStateId] = useState<string | null>(null);
+  const [actionSlot, setActionSlot] = useState<ActionSlot | null>(null);
+  const [fieldErrors, setFieldErrors] = useState<Record<string, string>>({});
 
   const title = editor.isNew ? "New capability" : `Edit ${capabilityId}`;
   const actionTypes = useMemo(
@@ -247,35 +263,68 @@ export function CapabilityEditorPage() {
     }));
   };
 
-  const addActionToState = (stateId: string) => {
-    setActionDialogStateId(stateId);
+  const addActionToState = (stateId: string, hook: StateActionHook) => {
+    setActionSlot({ kind: "state", stateId, hook });
+  };
+
+  const updateSlotActions = (slot: ActionSlot, update: (actions: ActionInstance[]) => ActionInstance[]) => {
+    setDraft((current) => {
+      switch (slot.kind) {
+        case "state":
+          return {
+            ...current,
+            states: {
+              ...current.states,
+              [slot.stateId]: {
+                ...current.states[slot.stateId],
+                [slot.hook]: update(current.states[slot.stateId][slot.hook])
+              }
+            }
+          };
+        case "transition":
+          return {
+            ...current,
+            transitions: current.transitions.map((transition, index) =>
+              index === slot.index ? { ...transition, actions: update(transition.actions) } : transition
+            )
+          };
+        case "disable":
+          return { ...current, actions_on_disable: update(current.actions_on_disable) };
+      }
+    });
+  };
+
+  const handleSaveAction = (slot: ActionSlot, action: ActionInstance) => {
+    updateSlotActions(slot, (actions) => [...actions, action]);
+  };
+
+  const removeActionFromSlot = (slot: ActionSlot, actionId: string) => {
+    updateSlotActions(slot, (actions) => actions.filter((action) => action.id !== actionId));
+  };
+
+  const addTransition = () => {
+    setDraft((current) => {
+      const [from = "", to = ""] = current.control.options.map((option) => option.value);
+      return {
+        ...current,
+        transitions: [...current.transitions, { from, to, actions: [] }]
+      };
+    });
   };
 
-  const handleSaveAction = (stateId: string, action: CapabilityTemplate["states"][string]["actions_on_enter"][number]) => {
+  const updateTransition = (index: number, key: "from" | "to", value: string) => {
     setDraft((current) => ({
       ...current,
-      states: {
-        ...current.states,
-        [stateId]: {
-          ...current.states[stateId],
-          actions_on_enter: [...current.states[stateId].actions_on_enter, action]
-        }
-      }
+      transitions: current.transitions.map((transition, itemIndex) =>
+        itemIndex === index ? { ...transition, [key]: value } : transition
+      )
     }));
   };
 
-  const removeActionFromState = (stateId: string, actionId: string) => {
+  const removeTransition = (index: number) => {
     setDraft((current) => ({
       ...current,
-      states: {
-        ...current.states,
-        [stateId]: {
-          ...current.states[stateId],
-          actions_on_enter: current.states[stateId].actions_on_enter.filter(
-            (action) => action.id !== actionId
-          )
-        }
-      }
+      transitions: current.transitions.filter((_, itemIndex) => itemIndex !== index)
     }));
   };
 
@@ -410,11 +459,17 @@ export function CapabilityEditorPage() {
       toast.error("Global scope does not allow device placeholders in actions or sync params.");
       return;
     }
+    setFieldErrors({});
     try {
       await editor.saveCapability(draft);
       toast.success("Capability saved");
       navigate(`/automation/capabilities/${encodeURIComponent(draft.id)}`);
     } catch (error) {
+      if (error instanceof ApiError && error.fields.length > 0) {
+        setFieldErrors(fieldErrorsByPath(error.fields));
+        toast.error("Some action or sync params are invalid, see highlighted fields.");
+        return;
+      }
       const message = error instanceof Error ? error.message : "Save failed";
       toast.error(message);
     }
@@ -643,10 +698,94 @@ export function CapabilityEditorPage() {
                 actionTypes={actionTypes}
                 onStateLabelChange={updateStateLabel}
                 onAddAction={addActionToState}
-                onRemoveAction={removeActionFromState}
+                onRemoveAction={(stateId, hook, actionId) =>
+                  removeActionFromSlot({ kind: "state", stateId, hook }, actionId)
+                }
+                fieldErrors={fieldErrors}
               />
             );
           })}
+
+          <Card>
+            <CardHeader className="pb-3">
+              <div className="flex items-center justify-between gap-3">
+                <CardTitle className="text-base">Transitions</CardTitle>
+                <Button variant="outline" size="sm" onClick={addTransition}>
+                  <Plus className="mr-1 h-4 w-4" />
+                  Add transition
+                </Button>
+              </div>
+              <p className="text-xs text-muted-foreground">
+                On state change actions run in order: exit of old state, matching transitions, enter of new state.
+              </p>
+            </CardHeader>
+            <CardContent className="space-y-4">
+              {draft.transitions.length === 0 ? (
+                <p className="text-sm text-muted-foreground">No transition-specific actions.</p>
+              ) : null}
+              {draft.transitions.map((transition, index) => (
+                <div key={index} className="space-y-3 rounded-md border p-3">
+                  <div className="flex items-end gap-2">
+                    {(["from", "to"] as const).map((key) => (
+                      <div key={key} className="flex-1 space-y-1">
+                        <Label className="text-xs capitalize">{key}</Label>
+                        <Select
+                          value={transition[key]}
+                          onValueChange={(value) => updateTransition(index, key, value)}
+                        >
+                          <SelectTrigger>
+                            <SelectValue placeholder="State" />
+                          </SelectTrigger>
+                          <SelectContent>
+                            {stateOrder.map((stateId) => (
+                              <SelectItem key={stateId} value={stateId}>
+                                {draft.states[stateId]?.label || stateId}
+                              </SelectItem>
+                            ))}
+                          </SelectContent>
+                        </Select>
+                      </div>
+                    ))}
+                    <Button variant="ghost" size="icon" onClick={() => removeTransition(index)}>
+                      <Trash2 className="h-4 w-4" />
+                    </Button>
+                  </div>
+                  <ActionInstanceList
+                    title="Actions"
+                    actions={transition.actions}
+                    actionTypes={actionTypes}
+                    errorPath={`transitions[${index}].actions`}
+                    emptyText="No actions for this transition."
+                    onAdd={() => setActionSlot({ kind: "transition", index })}
+                    onRemove={(actionId) => removeActionFromSlot({ kind: "transition", index }, actionId)}
+                    fieldErrors={fieldErrors}
+                  />
+                </div>
+              ))}
+            </CardContent>
+          </Card>
+
+          <Card>
+            <CardHeader className="pb-3">
+              <CardTitle className="text-base">On disable</CardTitle>
+              <p className="text-xs text-muted-foreground">
+                Runs when an enabled target is disabled or the capability is deleted. Re-enabling re-applies the
+                current state.
+              </p>
+            </CardHeader>
+            <CardContent>
+              <ActionInstanceList
+                title="Actions on disable"
+                actions={draft.actions_on_disable}
+                actionTypes={actionTypes}
+                errorPath="actions_on_disable"
+                emptyText="Disabling leaves router config as is."
+                onAdd={() => setActionSlot({ kind: "disable" })}
+                onRemove={(actionId) => removeActionFromSlot({ kind: "disable" }, actionId)}
+                fieldErrors={fieldErrors}
+              />
+            </CardContent>
+          </Card>
         </TabsContent>
 
         <TabsContent value="sync" className="space-y-4">
@@ -705,53 +844,14 @@ export function CapabilityEditorPage() {
                     <div className="space-y-3 rounded-md border p-3">
                       <p className="text-sm font-medium">State source params</p>
                       {visibleSyncFields.map((field) => (
-                        <div key={field.key} className="space-y-2">
-                          <Label>
-                            {field.label}
-                            {field.required ? " *" : ""}
-                          </Label>
-
-                          {field.kind === "string" ? (
-                            <Input
-                              value={String(draft.sync?.source.params?.[field.key] ?? "")}
-                              onChange={(event) => setSyncParam(field.key, event.target.value)}
-                            />
-                          ) : null}
-
-                          {field.kind === "enum" ? (
-                            <Select
-                              value={String(draft.sync?.source.params?.[field.key] ?? "")}
-                              onValueChange={(value) => setSyncParam(field.key, value)}
-                            >
-                              <SelectTrigger>
-                                <SelectValue placeholder="Select value" />
-                              </SelectTrigger>
-                              <SelectContent>
-                                {(field.options ?? []).map((option) => (
-                                  <SelectItem key={option} value={option}>
-                                    {option}
-                                  </SelectItem>
-                                ))}
-                              </SelectContent>
-                            </Select>
-                          ) : null}
-
-                          {field.kind === "bool" ? (
-                            <div className="flex items-center gap-2">
-                              <Switch
-                                checked={Boolean(draft.sync?.source.params?.[field.key])}
-                                onCheckedChange={(checked) => setSyncParam(field.key, checked)}
-                              />
-                              <span className="text-sm text-muted-foreground">
-                                {draft.sync?.source.params?.[field.key] ? "True" : "False"}
-                              </span>
-                            </div>
-                          ) : null}
-
-                          {field.description ? (
-                            <p className="text-xs text-muted-foreground">{field.description}</p>
-                          ) : null}
-                        </div>
+                        <ParamFieldInput
+                          key={field.key}
+                          field={field}
+                          value={draft.sync?.source.params?.[field.key]}
+                          params={draft.sync?.source.params}
+                          error={fieldErrors[`sync.source.params.${field.key}`]}
+                          onChange={(value) => setSyncParam(field.key, value)}
+                        />
                       ))}
                     </div>
                   ) : null}
@@ -831,7 +931,7 @@ export function CapabilityEditorPage() {
                                 ...current,
                                 sync: {
                                   ...current.sync,
-                                  mode: value === "internal_truth" ? "internal_truth" : "external_truth"
+                                  mode: value === "internal_truth" || value === "enforce" ? value : "external_truth"
                                 }
                               }
                             : current
@@ -844,15 +944,40 @@ export function CapabilityEditorPage() {
                       <SelectContent>
                         <SelectItem value="external_truth">external_truth</SelectItem>
                         <SelectItem value="internal_truth">internal_truth</SelectItem>
+                        <SelectItem value="enforce">enforce</SelectItem>
                       </SelectContent>
                     </Select>
                   </div>
 
+                  <div className="space-y-2">
+                    <Label>Sync interval (seconds)</Label>
+                    <Input
+                      type="number"
+                      min={0}
+                      value={draft.sync.interval_sec ?? 0}
+                      onChange={(event) => {
+                        const parsed = Number.parseInt(event.target.value, 10);
+                        setDraft((current) =>
+                          current.sync
+                            ? {
+                                ...current,
+                                sync: {
+                                  ...current.sync,
+                                  interval_sec: Number.isFinite(parsed) && parsed > 0 ? parsed : 0
+                                }
+                              }
+                            : current
+                        );
+                      }}
+                    />
+                    <p className="text-xs text-muted-foreground">0 uses the global sync interval.</p>
+                  </div>
+
                   <div className="flex items-center justify-between gap-2 rounded-md border p-3">
                     <div>
                       <p className="text-sm font-medium">Trigger actions on sync</p>
                       <p className="text-xs text-muted-foreground">
-                        If enabled, sync uses state transition flow and executes ActionsOnEnter.
+                        If enabled, sync uses state transition flow and runs exit, transition and enter actions.
                       </p>
                     </div>
                     <Switch
@@ -975,19 +1100,19 @@ export function CapabilityEditorPage() {
       </Tabs>
 
       <ActionInstanceDialog
-        open={actionDialogStateId !== null}
+        open={actionSlot !== null}
         onOpenChange={(open) => {
           if (!open) {
-            setActionDialogStateId(null);
+            setActionSlot(null);
           }
         }}
         actionTypes={actionTypes}
         scope={draft.scope}
         onSave={(action) => {
-          if (!actionDialogStateId) {
+          if (!actionSlot) {
             return;
           }
-          handleSaveAction(actionDialogStateId, action);
+          handleSaveAction(actionSlot, action);
         }}
       />
 
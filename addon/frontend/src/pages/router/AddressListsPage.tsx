@@ -0,0 +1,95 @@
+import { useState } from "react";
+
+import { Badge } from "@/components/ui/badge";
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow
+} from "@/components/ui/table";
+import { useAddressListEntries, useAddressLists } from "@/hooks/useAddressLists";
+import { cn } from "@/lib/utils";
+
+export function AddressListsPage() {
+  const listsQuery = useAddressLists();
+  const [selected, setSelected] = useState<string | null>(null);
+  const entriesQuery = useAddressListEntries(selected);
+
+  return (
+    <div className="space-y-4">
+      <header>
+        <h1 className="text-2xl font-semibold">Address-lists</h1>
+        <p className="text-sm text-muted-foreground">
+          Firewall address-lists on the router. Managed entries carry the add-on ownership comment.
+        </p>
+      </header>
+
+      {listsQuery.error ? <p className="text-sm text-destructive">{listsQuery.error.message}</p> : null}
+
+      <div className="grid gap-4 lg:grid-cols-[280px_1fr]">
+        <Card>
+          <CardContent className="space-y-1 p-2">
+            {(listsQuery.data ?? []).map((list) => (
+              <button
+                key={list.name}
+                type="button"
+                className={cn(
+                  "flex w-full items-center justify-between rounded-md px-3 py-2 text-left text-sm hover:bg-muted",
+                  selected === list.name && "bg-muted font-medium"
+                )}
+                onClick={() => setSelected(list.name)}
+              >
+                <span className="truncate">{list.name}</span>
+                <span className="flex gap-1">
+                  {list.managed > 0 ? <Badge variant="secondary">{list.managed} managed</Badge> : null}
+                  <Badge variant="outline">{list.entries}</Badge>
+                </span>
+              </button>
+            ))}
+          </CardContent>
+        </Card>
+
+        <Card>
+          <CardHeader>
+            <CardTitle className="text-base">{selected ?? "Select a list"}</CardTitle>
+          </CardHeader>
+          <CardContent className="p-0">
+            <Table>
+              <TableHeader>
+                <TableRow>
+                  <TableHead>Address</TableHead>
+                  <TableHead>Owner</TableHead>
+                  <TableHead>Comment</TableHead>
+                  <TableHead>Timeout</TableHead>
+                  <TableHead>Created</TableHead>
+                </TableRow>
+              </TableHeader>
+              <TableBody>
+                {(entriesQuery.data ?? []).map((entry) => (
+                  <TableRow key={entry.id} className={entry.disabled ? "text-muted-foreground" : undefined}>
+                    <TableCell className="font-mono text-xs">{entry.address}</TableCell>
+                    <TableCell>
+                      {entry.managed && entry.owner ? (
+                        <Badge variant="secondary" title={entry.owner.device_id}>
+                          {entry.owner.capability_id}
+                        </Badge>
+                      ) : (
+                        <Badge variant="outline">manual</Badge>
+                      )}
+                    </TableCell>
+                    <TableCell className="text-xs">{entry.managed ? "-" : entry.comment || "-"}</TableCell>
+                    <TableCell className="text-xs">{entry.timeout || (entry.dynamic ? "dynamic" : "-")}</TableCell>
+                    <TableCell className="text-xs">{entry.creation_time || "-"}</TableCell>
+                  </TableRow>
+                ))}
+              </TableBody>
+            </Table>
+          </CardContent>
+        </Card>
+      </div>
+    </div>
+  );
+}
@@ -0,0 +1,157 @@
+import { toast } from "sonner";
+
+import { Badge } from "@/components/ui/badge";
+import { Button } from "@/components/ui/button";
+import { Card, CardContent, CardHeader, CardTitle } from "@/components/ui/card";
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow
+} from "@/components/ui/table";
+import { useArtefactGCPreview, useArtefacts, useRunArtefactGC } from "@/hooks/useArtefactGC";
+import type { RouterArtefact } from "@/types/automation";
+
+function describeArtefact(artefact: RouterArtefact) {
+  switch (artefact.kind) {
+    case "address_list_entry":
+      return `${artefact.path} / ${artefact.key}`;
+    case "firewall_rule":
+      return `${artefact.path} rule ${artefact.key}`;
+    case "firewall_rule_comment":
+      return `${artefact.path} rules "${artefact.key}"`;
+    case "simple_queue":
+      return `queue for ${artefact.path}`;
+    case "content_filter":
+      return `site filter "${artefact.key}"`;
+    case "kid_control_pause":
+      return `kid-control "${artefact.key}" paused`;
+    case "kid_control_device":
+      return `${artefact.key} in kid-control`;
+    case "dns_adlist":
+      return `DNS adlist ${artefact.key || "(all)"}`;
+    case "dns_static":
+      return `static DNS ${artefact.key}`;
+    case "wifi_access":
+      return `${artefact.key} in wifi access list`;
+    default:
+      return `${artefact.path} ${artefact.key}`;
+  }
+}
+
+export function CleanupPage() {
+  const artefactsQuery = useArtefacts();
+  const previewQuery = useArtefactGCPreview();
+  const runMutation = useRunArtefactGC();
+  const orphans = previewQuery.data?.items ?? [];
+
+  const run = async () => {
+    try {
+      const report = await runMutation.mutateAsync();
+      const failed = report.items.filter((item) => !item.done).length;
+      if (failed > 0) {
+        toast.error(`${failed} artefact(s) could not be cleaned up`);
+      } else {
+        toast.success(`Cleaned up ${report.items.length} artefact(s)`);
+      }
+    } catch (error) {
+      const message = error instanceof Error ? error.message : "Cleanup failed";
+      toast.error(message);
+    }
+  };
+
+  return (
+    <div className="space-y-4">
+      <header className="flex items-start justify-between gap-4">
+        <div>
+          <h1 className="text-2xl font-semibold">Cleanup</h1>
+          <p className="text-sm text-muted-foreground">
+            Router objects written by capabilities. Orphans of deleted capabilities or forgotten devices
+            are removed or restored.
+          </p>
+        </div>
+        <Button onClick={() => void run()} disabled={runMutation.isPending || orphans.length === 0}>
+          Clean up {orphans.length > 0 ? orphans.length : ""}
+        </Button>
+      </header>
+
+      {previewQuery.error ? <p className="text-sm text-destructive">{previewQuery.error.message}</p> : null}
+
+      <Card>
+        <CardHeader>
+          <CardTitle className="text-base">Orphaned artefacts</CardTitle>
+        </CardHeader>
+        <CardContent className="p-0">
+          <Table>
+            <TableHeader>
+              <TableRow>
+                <TableHead>Object</TableHead>
+                <TableHead>Capability</TableHead>
+                <TableHead>Reason</TableHead>
+                <TableHead>Operation</TableHead>
+              </TableRow>
+            </TableHeader>
+            <TableBody>
+              {orphans.map((item) => (
+                <TableRow key={item.artefact.id}>
+                  <TableCell className="font-mono text-xs">{describeArtefact(item.artefact)}</TableCell>
+                  <TableCell>
+                    {item.artefact.capability_id}
+                    {item.artefact.device_id ? (
+                      <span className="ml-1 text-xs text-muted-foreground">{item.artefact.device_id}</span>
+                    ) : null}
+                  </TableCell>
+                  <TableCell>
+                    <Badge variant="outline">{item.reason.replace("_", " ")}</Badge>
+                  </TableCell>
+                  <TableCell>
+                    <Badge variant={item.operation === "forget" ? "outline" : "secondary"}>{item.operation}</Badge>
+                  </TableCell>
+                </TableRow>
+              ))}
+              {orphans.length === 0 ? (
+                <TableRow>
+                  <TableCell colSpan={4} className="text-center text-sm text-muted-foreground">
+                    Nothing to clean up
+                  </TableCell>
+                </TableRow>
+              ) : null}
+            </TableBody>
+          </Table>
+        </CardContent>
+      </Card>
+
+      <Card>
+        <CardHeader>
+          <CardTitle className="text-base">Ownership ledger ({artefactsQuery.data?.length ?? 0})</CardTitle>
+        </CardHeader>
+        <CardContent className="p-0">
+          <Table>
+            <TableHeader>
+              <TableRow>
+                <TableHead>Object</TableHead>
+                <TableHead>Kind</TableHead>
+                <TableHead>Capability</TableHead>
+                <TableHead>Device</TableHead>
+                <TableHead>Updated</TableHead>
+              </TableRow>
+            </TableHeader>
+            <TableBody>
+              {(artefactsQuery.data ?? []).map((artefact) => (
+                <TableRow key={artefact.id}>
+                  <TableCell className="font-mono text-xs">{describeArtefact(artefact)}</TableCell>
+                  <TableCell className="text-xs">{artefact.kind}</TableCell>
+                  <TableCell>{artefact.capability_id}</TableCell>
+                  <TableCell className="font-mono text-xs">{artefact.device_id ?? "-"}</TableCell>
+                  <TableCell className="text-xs">{new Date(artefact.updated_at).toLocaleString()}</TableCell>
+                </TableRow>
+              ))}
+            </TableBody>
+          </Table>
+        </CardContent>
+      </Card>
+    </div>
+  );
+}
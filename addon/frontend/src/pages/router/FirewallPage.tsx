@@ -0,0 +1,141 @@
+import { useState } from "react";
+import { Link } from "react-router-dom";
+import { toast } from "sonner";
+
+import { Badge } from "@/components/ui/badge";
+import { Card, CardContent } from "@/components/ui/card";
+import { Switch } from "@/components/ui/switch";
+import {
+  Table,
+  TableBody,
+  TableCell,
+  TableHead,
+  TableHeader,
+  TableRow
+} from "@/components/ui/table";
+import { Tabs, TabsList, TabsTrigger } from "@/components/ui/tabs";
+import { useFirewallRules, useToggleFirewallRule } from "@/hooks/useFirewallRules";
+import { firewallTableSchema, type FirewallRule, type FirewallTable } from "@/types/router";
+
+const tables = firewallTableSchema.options;
+
+function formatBytes(bytes: number) {
+  if (bytes < 1024) {
+    return `${bytes} B`;
+  }
+  const units = ["KiB", "MiB", "GiB", "TiB"];
+  let value = bytes / 1024;
+  let unit = 0;
+  while (value >= 1024 && unit < units.length - 1) {
+    value /= 1024;
+    unit += 1;
+  }
+  return `${value.toFixed(1)} ${units[unit]}`;
+}
+
+function FirewallRuleRow({ table, rule }: { table: FirewallTable; rule: FirewallRule }) {
+  const toggleMutation = useToggleFirewallRule(table);
+
+  const toggle = async (enabled: boolean) => {
+    try {
+      await toggleMutation.mutateAsync({ ruleId: rule.id, disabled: !enabled });
+    } catch (error) {
+      const message = error instanceof Error ? error.message : "Firewall rule update failed";
+      toast.error(message);
+    }
+  };
+
+  return (
+    <TableRow className={rule.disabled ? "text-muted-foreground" : undefined}>
+      <TableCell className="font-mono text-xs">{rule.id}</TableCell>
+      <TableCell>{rule.chain}</TableCell>
+      <TableCell>{rule.action}</TableCell>
+      <TableCell>{rule.comment || "-"}</TableCell>
+      <TableCell className="text-xs">
+        {rule.src_address_list ? <div>src: {rule.src_address_list}</div> : null}
+        {rule.dst_address_list ? <div>dst: {rule.dst_address_list}</div> : null}
+        {!rule.src_address_list && !rule.dst_address_list ? "-" : null}
+      </TableCell>
+      <TableCell className="text-xs">
+        {formatBytes(rule.bytes)} / {rule.packets} pkts
+      </TableCell>
+      <TableCell>
+        <div className="flex flex-wrap gap-1">
+          {rule.references.length === 0 ? "-" : null}
+          {rule.references.map((reference) => (
+            <Link
+              key={`${reference.capability_id}:${reference.location}`}
+              to={`/automation/capabilities/${encodeURIComponent(reference.capability_id)}`}
+              title={`${reference.location} (by ${reference.match_by})`}
+            >
+              <Badge variant="outline">{reference.capability_label || reference.capability_id}</Badge>
+            </Link>
+          ))}
+        </div>
+      </TableCell>
+      <TableCell>
+        <Switch
+          checked={!rule.disabled}
+          disabled={toggleMutation.isPending}
+          onCheckedChange={(checked) => {
+            void toggle(checked);
+          }}
+        />
+      </TableCell>
+    </TableRow>
+  );
+}
+
+export function FirewallPage() {
+  const [table, setTable] = useState<FirewallTable>("filter");
+  const rulesQuery = useFirewallRules(table);
+
+  return (
+    <div className="space-y-4">
+      <header>
+        <h1 className="text-2xl font-semibold">Firewall</h1>
+        <p className="text-sm text-muted-foreground">
+          Live firewall rules with counters and capability templates that target them.
+        </p>
+      </header>
+
+      <Tabs value={table} onValueChange={(value) => setTable(firewallTableSchema.parse(value))}>
+        <TabsList>
+          {tables.map((item) => (
+            <TabsTrigger key={item} value={item}>
+              {item}
+            </TabsTrigger>
+          ))}
+        </TabsList>
+      </Tabs>
+
+      {rulesQuery.error ? (
+        <p className="text-sm text-destructive">{rulesQuery.error.message}</p>
+      ) : null}
+
+      <Card>
+        <CardContent className="p-0">
+          <Table>
+            <TableHeader>
+              <TableRow>
+                <TableHead>ID</TableHead>
+                <TableHead>Chain</TableHead>
+                <TableHead>Action</TableHead>
+                <TableHead>Comment</TableHead>
+                <TableHead>Address-lists</TableHead>
+                <TableHead>Counters</TableHead>
+                <TableHead>Used by</TableHead>
+                <TableHead>Enabled</TableHead>
+              </TableRow>
+            </TableHeader>
+            <TableBody>
+              {(rulesQuery.data ?? []).map((rule) => (
+                <FirewallRuleRow key={rule.id} table={table} rule={rule} />
+              ))}
+            </TableBody>
+          </Table>
+        </CardContent>
+      </Card>
+    </div>
+  );
+}
@@ -1,6 +1,17 @@
 import { z } from "zod";
 
-export const actionParamFieldKindSchema = z.enum(["string", "enum", "bool"]);
+export const actionParamFieldKindSchema = z.enum([
+  "string",
+  "enum",
+  "bool",
+  "int",
+  "duration",
+  "ip",
+  "cidr",
+  "mac",
+  "string_list",
+  "object"
+]);
 export const capabilityScopeSchema = z.enum(["device", "global"]);
 
 export const visibleIfConditionSchema = z.object({
@@ -15,7 +26,23 @@ export const actionParamFieldSchema = z.object({
   required: z.boolean(),
   description: z.string().optional(),
   options: z.array(z.string()).optional().default([]),
-  visible_if: visibleIfConditionSchema.optional()
+  visible_if: visibleIfConditionSchema.optional(),
+  min: z.number().int().optional(),
+  max: z.number().int().optional(),
+  pattern: z.string().optional(),
+  fields: z.array(z.unknown()).optional(),
+  options_provider: z.string().optional(),
+  options_arg_key: z.string().optional()
+});
+
+export const paramOptionSchema = z.object({
+  value: z.string(),
+  label: z.string()
+});
+
+export const fieldErrorSchema = z.object({
+  path: z.string(),
+  message: z.string()
 });
 
 export const actionTypeSchema = z.object({
@@ -53,7 +80,14 @@ export const capabilityControlSchema = z.object({
 
 export const capabilityStateConfigSchema = z.object({
   label: z.string(),
-  actions_on_enter: z.array(actionInstanceSchema)
+  actions_on_enter: z.array(actionInstanceSchema),
+  actions_on_exit: z.array(actionInstanceSchema).optional().default([])
+});
+
+export const capabilityTransitionSchema = z.object({
+  from: z.string(),
+  to: z.string(),
+  actions: z.array(actionInstanceSchema).default([])
 });
 
 export const capabilitySyncSourceSchema = z.object({
@@ -70,8 +104,9 @@ export const capabilitySyncConfigSchema = z.object({
   enabled: z.boolean(),
   source: capabilitySyncSourceSchema,
   mapping: capabilitySyncMappingSchema,
-  mode: z.enum(["external_truth", "internal_truth"]),
-  trigger_actions_on_sync: z.boolean()
+  mode: z.enum(["external_truth", "internal_truth", "enforce"]),
+  trigger_actions_on_sync: z.boolean(),
+  interval_sec: z.number().int().nonnegative().optional()
 });
 
 export const haExposeSchema = z.object({
@@ -91,7 +126,9 @@ export const capabilityTemplateSchema = z.object({
   states: z.record(capabilityStateConfigSchema),
   default_state: z.string(),
   sync: capabilitySyncConfigSchema.optional(),
-  ha_expose: haExposeSchema
+  ha_expose: haExposeSchema,
+  transitions: z.array(capabilityTransitionSchema).optional().default([]),
+  actions_on_disable: z.array(actionInstanceSchema).optional().default([])
 });
 
 export const capabilityUIModelSchema = z.object({
@@ -123,15 +160,76 @@ export const setStateResultSchema = z.object({
   warnings: z.array(actionExecutionWarningSchema).optional().default([])
 });
 
+export const syncStatusSchema = z.object({
+  capability_id: z.string(),
+  interval_sec: z.number(),
+  running: z.boolean(),
+  targets: z.number(),
+  last_run_at: z.string().optional(),
+  last_duration_ms: z.number(),
+  last_error: z.string().optional(),
+  next_run_at: z.string().optional()
+});
+
+export const artefactKindSchema = z.enum([
+  "address_list_entry",
+  "firewall_rule",
+  "firewall_rule_comment",
+  "router_object",
+  "simple_queue",
+  "content_filter",
+  "kid_control_pause",
+  "kid_control_device",
+  "dns_adlist",
+  "dns_static",
+  "wifi_access"
+]);
+
+export const routerArtefactSchema = z.object({
+  id: z.number(),
+  capability_id: z.string(),
+  device_id: z.string().optional(),
+  kind: artefactKindSchema,
+  path: z.string(),
+  key: z.string(),
+  restore: z.string().optional(),
+  created_at: z.string(),
+  updated_at: z.string()
+});
+
+export const gcItemSchema = z.object({
+  artefact: routerArtefactSchema,
+  reason: z.enum(["capability_deleted", "device_gone"]),
+  operation: z.enum(["remove", "restore", "forget"]),
+  done: z.boolean(),
+  error: z.string().optional()
+});
+
+export const gcReportSchema = z.object({
+  dry_run: z.boolean(),
+  scanned: z.number(),
+  items: z.array(gcItemSchema),
+  started_at: z.string()
+});
+
 export type ActionParamField = z.infer<typeof actionParamFieldSchema>;
+export type ActionParamFieldKind = z.infer<typeof actionParamFieldKindSchema>;
+export type FieldError = z.infer<typeof fieldErrorSchema>;
+export type ParamOption = z.infer<typeof paramOptionSchema>;
 export type ActionType = z.infer<typeof actionTypeSchema>;
 export type StateSourceType = z.infer<typeof stateSourceTypeSchema>;
 export type CapabilityScope = z.infer<typeof capabilityScopeSchema>;
 export type ActionInstance = z.infer<typeof actionInstanceSchema>;
 export type CapabilityStateConfig = z.infer<typeof capabilityStateConfigSchema>;
+export type CapabilityTransition = z.infer<typeof capabilityTransitionSchema>;
+export type StateActionHook = "actions_on_enter" | "actions_on_exit";
 export type CapabilitySyncConfig = z.infer<typeof capabilitySyncConfigSchema>;
 export type CapabilityTemplate = z.infer<typeof capabilityTemplateSchema>;
 export type CapabilityUIModel = z.infer<typeof capabilityUIModelSchema>;
 export type CapabilityDeviceAssignment = z.infer<typeof capabilityDeviceAssignmentSchema>;
 export type SetStateResult = z.infer<typeof setStateResultSchema>;
+export type SyncStatus = z.infer<typeof syncStatusSchema>;
+export type RouterArtefact = z.infer<typeof routerArtefactSchema>;
+export type GCItem = z.infer<typeof gcItemSchema>;
+export type GCReport = z.infer<typeof gcReportSchema>;
 export type ControlType = z.infer<typeof controlTypeSchema>;
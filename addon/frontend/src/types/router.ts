@@ -0,0 +1,66 @@
+import { z } from "zod";
+
+export const firewallTableSchema = z.enum(["filter", "nat", "mangle", "raw"]);
+
+export const ruleReferenceSchema = z.object({
+  capability_id: z.string(),
+  capability_label: z.string(),
+  location: z.string(),
+  match_by: z.string()
+});
+
+export const firewallRuleSchema = z.object({
+  id: z.string(),
+  table: z.string(),
+  chain: z.string(),
+  action: z.string(),
+  comment: z.string(),
+  disabled: z.boolean(),
+  src_address_list: z.string().optional(),
+  dst_address_list: z.string().optional(),
+  bytes: z.number(),
+  packets: z.number(),
+  references: z.array(ruleReferenceSchema).default([])
+});
+
+export const listFirewallRulesResponseSchema = z.object({
+  items: z.array(firewallRuleSchema)
+});
+
+export const addressListSchema = z.object({
+  name: z.string(),
+  entries: z.number(),
+  managed: z.number()
+});
+
+export const ownershipTagSchema = z.object({
+  capability_id: z.string(),
+  device_id: z.string().optional()
+});
+
+export const addressListEntrySchema = z.object({
+  id: z.string(),
+  list: z.string(),
+  address: z.string(),
+  comment: z.string(),
+  timeout: z.string().optional(),
+  creation_time: z.string().optional(),
+  dynamic: z.boolean(),
+  disabled: z.boolean(),
+  managed: z.boolean(),
+  owner: ownershipTagSchema.optional()
+});
+
+export const listAddressListsResponseSchema = z.object({
+  items: z.array(addressListSchema)
+});
+
+export const listAddressListEntriesResponseSchema = z.object({
+  items: z.array(addressListEntrySchema)
+});
+
+export type FirewallTable = z.infer<typeof firewallTableSchema>;
+export type RuleReference = z.infer<typeof ruleReferenceSchema>;
+export type FirewallRule = z.infer<typeof firewallRuleSchema>;
+export type AddressList = z.infer<typeof addressListSchema>;
+export type AddressListEntry = z.infer<typeof addressListEntrySchema>;
@@ -3,7 +3,10 @@ package actions
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
+	"time"
 
 	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
 )
@@ -34,11 +37,12 @@ func (a *AddressListMembershipAction) Metadata() automationdomain.ActionMetadata
 		Description: "Add or remove a target value in a MikroTik firewall address-list",
 		ParamSchema: []automationdomain.ParamField{
 			{
-				Key:         "list",
-				Label:       "Address-list name",
-				Kind:        automationdomain.ParamString,
-				Required:    true,
-				Description: "RouterOS firewall address-list name",
+				Key:             "list",
+				Label:           "Address-list name",
+				Kind:            automationdomain.ParamString,
+				Required:        true,
+				Description:     "RouterOS firewall address-list name",
+				OptionsProvider: automationdomain.OptionsRouterAddressLists,
 			},
 			{
 				Key:         "mode",
@@ -63,6 +67,20 @@ func (a *AddressListMembershipAction) Metadata() automationdomain.ActionMetadata
 				Required:  true,
 				VisibleIf: &automationdomain.VisibleIfCondition{Key: "target", Equals: "literal_ip"},
 			},
+			{
+				Key:         "timeout",
+				Label:       "Entry timeout",
+				Kind:        automationdomain.ParamDuration,
+				Min:         automationdomain.IntBound(1),
+				Description: "Optional RouterOS timeout; the entry expires on the router even if the add-on is down",
+				VisibleIf:   &automationdomain.VisibleIfCondition{Key: "mode", Equals: "add"},
+			},
+			{
+				Key:         "kill_connections",
+				Label:       "Kill active connections",
+				Kind:        automationdomain.ParamBool,
+				Description: "Drop tracked connections of the device after membership changes so blocks apply to running streams",
+			},
 		},
 	}
 }
@@ -109,6 +127,14 @@ func (a *AddressListMembershipAction) Validate(
 	default:
 		return fmt.Errorf("unsupported target %q", targetParam)
 	}
+	if mode == "add" {
+		if _, err := timeoutParam(params); err != nil {
+			return err
+		}
+	}
+	if _, err := boolParam(params, "kill_connections"); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -135,12 +161,81 @@ func (a *AddressListMembershipAction) Execute(
 	}
 	switch mode {
 	case "add":
-		return execCtx.RouterClient.AddAddressListEntry(ctx, execCtx.RouterConfig, listName, address)
+		timeout, _ := timeoutParam(params)
+		managed, ok := execCtx.RouterClient.(automationdomain.ManagedAddressListClient)
+		if !ok {
+			if timeout != "" {
+				return fmt.Errorf("router client does not support address-list timeouts")
+			}
+			err = execCtx.RouterClient.AddAddressListEntry(ctx, execCtx.RouterConfig, listName, address)
+			break
+		}
+		comment := automationdomain.OwnershipComment(automationdomain.OwnershipTagFor(execCtx))
+		err = managed.AddManagedAddressListEntry(ctx, execCtx.RouterConfig, listName, address, comment, timeout)
 	case "remove":
-		return execCtx.RouterClient.RemoveAddressListEntry(ctx, execCtx.RouterConfig, listName, address)
+		err = execCtx.RouterClient.RemoveAddressListEntry(ctx, execCtx.RouterConfig, listName, address)
 	default:
 		return fmt.Errorf("unsupported mode %q", mode)
 	}
+	if err != nil {
+		return err
+	}
+	if kill, _ := boolParam(params, "kill_connections"); kill {
+		return killDeviceConnections(ctx, execCtx, target, address)
+	}
+	return nil
+}
+
+// killDeviceConnections flushes connection tracking for IP written to the
+// list; MAC entries fall back to the device's last known IP.
+func killDeviceConnections(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	target string,
+	address string,
+) error {
+	if target == "device.mac" {
+		ip, err := resolveTargetAddress("device.ip", nil, execCtx)
+		if err != nil {
+			return nil
+		}
+		address = ip
+	}
+	connectionClient, ok := execCtx.RouterClient.(automationdomain.ConnectionClient)
+	if !ok {
+		return fmt.Errorf("router client does not support connection tracking")
+	}
+	if _, err := connectionClient.KillConnections(ctx, execCtx.RouterConfig, address); err != nil {
+		return fmt.Errorf("kill connections of %s: %w", address, err)
+	}
+	return nil
+}
+
+// CleanupStaleTarget removes previous device IP added by this action.
+func (a *AddressListMembershipAction) CleanupStaleTarget(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+	mode, _ := stringParam(params, "mode")
+	target, _ := stringParam(params, "target")
+	// Only `device.ip` entries become stale; MAC and literal targets do not move.
+	if mode != "add" || target != "device.ip" {
+		return nil
+	}
+	if execCtx.RouterClient == nil {
+		return fmt.Errorf("router client is not configured")
+	}
+
+	listName, _ := stringParam(params, "list")
+	address, err := resolveTargetAddress(target, params, execCtx)
+	if err != nil {
+		return err
+	}
+	return execCtx.RouterClient.RemoveAddressListEntry(ctx, execCtx.RouterConfig, listName, address)
 }
 
 func resolveTargetAddress(
@@ -170,10 +265,85 @@ func resolveTargetAddress(
 	}
 }
 
+// timeoutParam returns optional timeout in RouterOS notation (e.g. 1h30m).
+func timeoutParam(params map[string]any) (string, error) {
+	return durationParam(params, "timeout")
+}
+
+// durationParam returns optional duration param in RouterOS notation.
+// Plain numbers are seconds; Go durations like 90s or 2h are accepted.
+func durationParam(params map[string]any, key string) (string, error) {
+	var seconds int
+	switch raw := params[key].(type) {
+	case nil:
+		return "", nil
+	case float64:
+		if raw != math.Trunc(raw) {
+			return "", fmt.Errorf("param %q must be whole seconds", key)
+		}
+		seconds = int(raw)
+	case string:
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			return "", nil
+		}
+		if value, err := strconv.Atoi(raw); err == nil {
+			seconds = value
+			break
+		}
+		duration, err := time.ParseDuration(raw)
+		if err != nil {
+			return "", fmt.Errorf("param %q must be duration like 30m or 2h", key)
+		}
+		seconds = int(duration / time.Second)
+	default:
+		return "", fmt.Errorf("param %q must be duration", key)
+	}
+	if seconds <= 0 {
+		return "", fmt.Errorf("param %q must be positive", key)
+	}
+	return routerOSDuration(seconds), nil
+}
+
+func routerOSDuration(seconds int) string {
+	var b strings.Builder
+	for _, unit := range []struct {
+		suffix string
+		size   int
+	}{{"d", 86400}, {"h", 3600}, {"m", 60}, {"s", 1}} {
+		if seconds >= unit.size {
+			fmt.Fprintf(&b, "%d%s", seconds/unit.size, unit.suffix)
+			seconds %= unit.size
+		}
+	}
+	return b.String()
+}
+
 func containsDevicePlaceholder(raw string) bool {
 	return strings.Contains(strings.ToLower(raw), "{{device.")
 }
 
+// boolParam reads optional boolean param; absent values are false.
+func boolParam(params map[string]any, key string) (bool, error) {
+	switch raw := params[key].(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return raw, nil
+	case string:
+		if strings.TrimSpace(raw) == "" {
+			return false, nil
+		}
+		value, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			return false, fmt.Errorf("param %q must be boolean", key)
+		}
+		return value, nil
+	default:
+		return false, fmt.Errorf("param %q must be boolean", key)
+	}
+}
+
 func stringParam(params map[string]any, key string) (string, error) {
 	raw, ok := params[key]
 	if !ok {
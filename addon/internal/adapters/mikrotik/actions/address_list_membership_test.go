@@ -108,3 +108,158 @@ func TestAddressListMembershipActionValidateRejectsDeviceTargetForGlobalScope(t
 		t.Fatalf("expected validation error")
 	}
 }
+
+func TestAddressListMembershipActionCleanupStaleTargetRemovesPreviousIP(t *testing.T) {
+	action := NewAddressListMembershipAction()
+	previousIP := "192.168.88.10"
+	client := &fakeAddressListClient{}
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", LastIP: &previousIP}
+
+	err := action.CleanupStaleTarget(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		RouterClient: client,
+		RouterConfig: model.RouterConfig{Host: "router.local"},
+	}, map[string]any{
+		"list":   "BLOCKED",
+		"mode":   "add",
+		"target": "device.ip",
+	})
+	if err != nil {
+		t.Fatalf("CleanupStaleTarget returned error: %v", err)
+	}
+	if client.removeCalls != 1 || client.lastAddress != previousIP {
+		t.Fatalf("unexpected cleanup calls: remove=%d address=%q", client.removeCalls, client.lastAddress)
+	}
+}
+
+func TestAddressListMembershipActionCleanupStaleTargetSkipsMACTarget(t *testing.T) {
+	action := NewAddressListMembershipAction()
+	client := &fakeAddressListClient{}
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01"}
+
+	err := action.CleanupStaleTarget(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		RouterClient: client,
+	}, map[string]any{
+		"list":   "BLOCKED",
+		"mode":   "add",
+		"target": "device.mac",
+	})
+	if err != nil {
+		t.Fatalf("CleanupStaleTarget returned error: %v", err)
+	}
+	if client.removeCalls != 0 {
+		t.Fatalf("expected no cleanup for MAC target, got %d", client.removeCalls)
+	}
+}
+
+type fakeManagedAddressListClient struct {
+	fakeAddressListClient
+	lastComment string
+	lastTimeout string
+}
+
+func (f *fakeManagedAddressListClient) AddManagedAddressListEntry(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+	address string,
+	comment string,
+	timeout string,
+) error {
+	f.addCalls++
+	f.lastList = list
+	f.lastAddress = address
+	f.lastComment = comment
+	f.lastTimeout = timeout
+	return nil
+}
+
+func TestAddressListMembershipActionExecuteAddTagsOwnershipAndTimeout(t *testing.T) {
+	action := NewAddressListMembershipAction()
+	ip := "192.168.88.10"
+	client := &fakeManagedAddressListClient{}
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", LastIP: &ip}
+
+	err := action.Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		CapabilityID: "kids.internet",
+		RouterClient: client,
+	}, map[string]any{
+		"list":    "BLOCKED",
+		"mode":    "add",
+		"target":  "device.ip",
+		"timeout": "90m",
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if client.lastTimeout != "1h30m" {
+		t.Fatalf("unexpected timeout %q", client.lastTimeout)
+	}
+	tag, ok := automationdomain.ParseOwnershipComment(client.lastComment)
+	if !ok || tag.CapabilityID != "kids.internet" || tag.DeviceID != device.MAC {
+		t.Fatalf("unexpected ownership comment %q", client.lastComment)
+	}
+
+	if err := action.Validate(automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice}, map[string]any{
+		"list":    "BLOCKED",
+		"mode":    "add",
+		"target":  "device.ip",
+		"timeout": "soon",
+	}); err == nil {
+		t.Fatalf("expected invalid timeout error")
+	}
+}
+
+type fakeConnectionAddressListClient struct {
+	fakeAddressListClient
+	killed []string
+}
+
+func (f *fakeConnectionAddressListClient) KillConnections(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	address string,
+) (int, error) {
+	f.killed = append(f.killed, address)
+	return 1, nil
+}
+
+func TestAddressListMembershipActionKillsConnectionsAfterChange(t *testing.T) {
+	action := NewAddressListMembershipAction()
+	ip := "192.168.88.10"
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", LastIP: &ip}
+	execCtx := func(client automationdomain.RouterActionClient) automationdomain.ActionExecutionContext {
+		return automationdomain.ActionExecutionContext{
+			Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+			RouterClient: client,
+		}
+	}
+
+	client := &fakeConnectionAddressListClient{}
+	for _, target := range []string{"device.ip", "device.mac"} {
+		err := action.Execute(context.Background(), execCtx(client), map[string]any{
+			"list":             "BLOCKED",
+			"mode":             "add",
+			"target":           target,
+			"kill_connections": true,
+		})
+		if err != nil {
+			t.Fatalf("Execute(%s) returned error: %v", target, err)
+		}
+	}
+	if len(client.killed) != 2 || client.killed[0] != ip || client.killed[1] != ip {
+		t.Fatalf("expected device IP connections killed twice, got %v", client.killed)
+	}
+
+	err := action.Execute(context.Background(), execCtx(&fakeAddressListClient{}), map[string]any{
+		"list":             "BLOCKED",
+		"mode":             "add",
+		"target":           "device.ip",
+		"kill_connections": true,
+	})
+	if err == nil {
+		t.Fatalf("expected error for client without connection tracking")
+	}
+}
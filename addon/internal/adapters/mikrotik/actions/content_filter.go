@@ -0,0 +1,192 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const (
+	// ActionIDContentFilterSet blocks domains for one device.
+	ActionIDContentFilterSet = "mikrotik.content_filter.set"
+)
+
+// contentFilterDomainPattern accepts example.com and *.example.com.
+const contentFilterDomainPattern = `^(\*\.)?([a-zA-Z0-9-]+\.)+[a-zA-Z0-9-]+$`
+
+var contentFilterDomainRegexp = regexp.MustCompile(contentFilterDomainPattern)
+
+// ContentFilterAction creates or removes per-device domain blocking rules.
+type ContentFilterAction struct{}
+
+// NewContentFilterAction creates MikroTik content filter action.
+func NewContentFilterAction() *ContentFilterAction {
+	return &ContentFilterAction{}
+}
+
+// ID returns unique action identifier.
+func (a *ContentFilterAction) ID() string {
+	return ActionIDContentFilterSet
+}
+
+// Metadata returns action descriptor for UI.
+func (a *ContentFilterAction) Metadata() automationdomain.ActionMetadata {
+	onSet := &automationdomain.VisibleIfCondition{Key: "mode", Equals: "set"}
+	return automationdomain.ActionMetadata{
+		ID:          ActionIDContentFilterSet,
+		Label:       "MikroTik: Block websites",
+		Description: "Block domains for target with firewall tls-host rules owned by this capability",
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "mode",
+				Label:       "Mode",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"set", "remove"},
+				Description: "Whether to apply or remove the filter",
+			},
+			{
+				Key:         "target",
+				Label:       "Target",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"device.ip", "literal_ip"},
+				Description: "Source address whose traffic is filtered",
+			},
+			{
+				Key:       "literal_ip",
+				Label:     "Literal IP",
+				Kind:      automationdomain.ParamString,
+				Required:  true,
+				VisibleIf: &automationdomain.VisibleIfCondition{Key: "target", Equals: "literal_ip"},
+			},
+			{
+				Key:         "domains",
+				Label:       "Domains",
+				Kind:        automationdomain.ParamStringList,
+				Required:    true,
+				Pattern:     contentFilterDomainPattern,
+				Description: "Domains to block, e.g. youtube.com; subdomains are included",
+				VisibleIf:   onSet,
+			},
+			{
+				Key:         "block_quic",
+				Label:       "Block QUIC",
+				Kind:        automationdomain.ParamBool,
+				Description: "Reject UDP/443 from target so browsers cannot bypass tls-host matching over HTTP/3",
+				VisibleIf:   onSet,
+			},
+			{
+				Key:         "block_dns",
+				Label:       "Block in DNS",
+				Kind:        automationdomain.ParamBool,
+				Description: "Also add static DNS entries; these apply to every client using the router DNS",
+				VisibleIf:   onSet,
+			},
+		},
+	}
+}
+
+// Validate validates action params against metadata schema.
+func (a *ContentFilterAction) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	mode, err := stringParam(params, "mode")
+	if err != nil {
+		return err
+	}
+	if mode != "set" && mode != "remove" {
+		return fmt.Errorf("unsupported mode %q", mode)
+	}
+
+	if err := automationdomain.ValidateIPTarget(target, params); err != nil {
+		return err
+	}
+
+	if mode == "set" {
+		if _, err := contentFilterParam(params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute reconciles or removes owned filter rules for target address.
+func (a *ContentFilterAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+	if execCtx.RouterClient == nil {
+		return fmt.Errorf("router client is not configured")
+	}
+	filterClient, ok := execCtx.RouterClient.(automationdomain.ContentFilterClient)
+	if !ok {
+		return fmt.Errorf("router client does not support content filters")
+	}
+
+	mode, _ := stringParam(params, "mode")
+	comment := automationdomain.OwnershipComment(automationdomain.OwnershipTagFor(execCtx))
+	if mode == "remove" {
+		return filterClient.RemoveContentFilter(ctx, execCtx.RouterConfig, comment)
+	}
+
+	target, _ := stringParam(params, "target")
+	address, err := resolveTargetAddress(target, params, execCtx)
+	if err != nil {
+		return err
+	}
+	filter, _ := contentFilterParam(params)
+	return filterClient.SetContentFilter(ctx, execCtx.RouterConfig, address, comment, filter)
+}
+
+func contentFilterParam(params map[string]any) (model.ContentFilter, error) {
+	var domains []string
+	switch raw := params["domains"].(type) {
+	case []string:
+		domains = raw
+	case []any:
+		for _, item := range raw {
+			text, ok := item.(string)
+			if !ok {
+				return model.ContentFilter{}, fmt.Errorf("param %q must be list of strings", "domains")
+			}
+			domains = append(domains, text)
+		}
+	case nil:
+	default:
+		return model.ContentFilter{}, fmt.Errorf("param %q must be list of strings", "domains")
+	}
+
+	filter := model.ContentFilter{Domains: make([]string, 0, len(domains))}
+	for _, domain := range domains {
+		domain = strings.TrimSpace(domain)
+		if domain == "" {
+			continue
+		}
+		if !contentFilterDomainRegexp.MatchString(domain) {
+			return model.ContentFilter{}, fmt.Errorf("invalid domain %q", domain)
+		}
+		filter.Domains = append(filter.Domains, strings.ToLower(domain))
+	}
+	if len(filter.Domains) == 0 {
+		return model.ContentFilter{}, fmt.Errorf("param %q is empty", "domains")
+	}
+
+	var err error
+	if filter.BlockQUIC, err = boolParam(params, "block_quic"); err != nil {
+		return model.ContentFilter{}, err
+	}
+	if filter.BlockDNS, err = boolParam(params, "block_dns"); err != nil {
+		return model.ContentFilter{}, err
+	}
+	return filter, nil
+}
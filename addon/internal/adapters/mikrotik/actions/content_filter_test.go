@@ -0,0 +1,89 @@
+package actions
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeContentFilterClient struct {
+	fakeAddressListClient
+	setCalls    int
+	removeCalls int
+	lastAddress string
+	lastComment string
+	lastFilter  model.ContentFilter
+}
+
+func (f *fakeContentFilterClient) SetContentFilter(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	address string,
+	comment string,
+	filter model.ContentFilter,
+) error {
+	f.setCalls++
+	f.lastAddress = address
+	f.lastComment = comment
+	f.lastFilter = filter
+	return nil
+}
+
+func (f *fakeContentFilterClient) RemoveContentFilter(ctx context.Context, cfg model.RouterConfig, comment string) error {
+	f.removeCalls++
+	f.lastComment = comment
+	return nil
+}
+
+func TestContentFilterActionSetAndRemove(t *testing.T) {
+	action := NewContentFilterAction()
+	ip := "192.168.88.10"
+	client := &fakeContentFilterClient{}
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", LastIP: &ip}
+	execCtx := automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		CapabilityID: "kids.sites",
+		RouterClient: client,
+	}
+
+	err := action.Execute(context.Background(), execCtx, map[string]any{
+		"mode":       "set",
+		"target":     "device.ip",
+		"domains":    []any{"YouTube.com", "*.tiktok.com"},
+		"block_quic": true,
+	})
+	if err != nil {
+		t.Fatalf("Execute set returned error: %v", err)
+	}
+	if client.setCalls != 1 || client.lastAddress != ip || !client.lastFilter.BlockQUIC || client.lastFilter.BlockDNS {
+		t.Fatalf("unexpected set call: %+v", client)
+	}
+	if strings.Join(client.lastFilter.Domains, ",") != "youtube.com,*.tiktok.com" {
+		t.Fatalf("unexpected domains: %v", client.lastFilter.Domains)
+	}
+	tag, ok := automationdomain.ParseOwnershipComment(client.lastComment)
+	if !ok || tag.CapabilityID != "kids.sites" || tag.DeviceID != device.MAC {
+		t.Fatalf("unexpected ownership comment %q", client.lastComment)
+	}
+
+	if err := action.Execute(context.Background(), execCtx, map[string]any{"mode": "remove", "target": "device.ip"}); err != nil {
+		t.Fatalf("Execute remove returned error: %v", err)
+	}
+	if client.removeCalls != 1 {
+		t.Fatalf("expected one remove call, got %d", client.removeCalls)
+	}
+}
+
+func TestContentFilterActionValidateRejectsBadDomains(t *testing.T) {
+	action := NewContentFilterAction()
+	target := automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice}
+	for i, domains := range []any{nil, []any{}, []any{"not a domain"}, []any{"http://example.com"}} {
+		err := action.Validate(target, map[string]any{"mode": "set", "target": "device.ip", "domains": domains})
+		if err == nil {
+			t.Fatalf("case %d: expected validation error", i)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+const (
+	// ActionIDDNSAdlistSetEnabled enables or disables RouterOS DNS adlists.
+	ActionIDDNSAdlistSetEnabled = "mikrotik.dns.adlist.set_enabled"
+)
+
+// DNSAdlistToggleAction enables or disables /ip/dns/adlist entries.
+type DNSAdlistToggleAction struct{}
+
+// NewDNSAdlistToggleAction creates MikroTik DNS adlist action.
+func NewDNSAdlistToggleAction() *DNSAdlistToggleAction {
+	return &DNSAdlistToggleAction{}
+}
+
+// ID returns unique action identifier.
+func (a *DNSAdlistToggleAction) ID() string {
+	return ActionIDDNSAdlistSetEnabled
+}
+
+// Metadata returns action descriptor for UI.
+func (a *DNSAdlistToggleAction) Metadata() automationdomain.ActionMetadata {
+	return automationdomain.ActionMetadata{
+		ID:          ActionIDDNSAdlistSetEnabled,
+		Label:       "MikroTik: DNS adlist",
+		Description: "Enable or disable RouterOS DNS adlists (RouterOS 7.15+); applies to every client using the router DNS",
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "mode",
+				Label:       "Mode",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"enable", "disable"},
+				Description: "Whether to enable or disable the adlists",
+			},
+			{
+				Key:             "adlist",
+				Label:           "Adlist",
+				Kind:            automationdomain.ParamString,
+				OptionsProvider: automationdomain.OptionsRouterDNSAdlists,
+				Description:     "URL or file of one adlist; empty selects every adlist",
+			},
+		},
+	}
+}
+
+// Validate validates action params against metadata schema.
+func (a *DNSAdlistToggleAction) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	mode, err := stringParam(params, "mode")
+	if err != nil {
+		return err
+	}
+	if mode != "enable" && mode != "disable" {
+		return fmt.Errorf("unsupported mode %q", mode)
+	}
+	if raw := params["adlist"]; raw != nil {
+		if _, ok := raw.(string); !ok {
+			return fmt.Errorf("param %q must be string", "adlist")
+		}
+	}
+	return nil
+}
+
+// Execute toggles selected adlists via RouterOS API.
+func (a *DNSAdlistToggleAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+	if execCtx.RouterClient == nil {
+		return fmt.Errorf("router client is not configured")
+	}
+	adlistClient, ok := execCtx.RouterClient.(automationdomain.DNSAdlistClient)
+	if !ok {
+		return fmt.Errorf("router client does not support dns adlists")
+	}
+
+	mode, _ := stringParam(params, "mode")
+	return adlistClient.SetDNSAdlistsEnabled(ctx, execCtx.RouterConfig, optionalStringParam(params, "adlist"), mode == "enable")
+}
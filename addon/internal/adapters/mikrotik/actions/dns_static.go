@@ -0,0 +1,183 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const (
+	// ActionIDDNSStaticSet adds or removes static DNS override.
+	ActionIDDNSStaticSet = "mikrotik.dns.static.set"
+)
+
+var dnsNameRegexp = regexp.MustCompile(`^([a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?\.)*[a-zA-Z0-9_]([a-zA-Z0-9_-]*[a-zA-Z0-9_])?\.?$`)
+
+// DNSStaticRecordAction manages static DNS record owned by capability.
+type DNSStaticRecordAction struct{}
+
+// NewDNSStaticRecordAction creates MikroTik static DNS action.
+func NewDNSStaticRecordAction() *DNSStaticRecordAction {
+	return &DNSStaticRecordAction{}
+}
+
+// ID returns unique action identifier.
+func (a *DNSStaticRecordAction) ID() string {
+	return ActionIDDNSStaticSet
+}
+
+// Metadata returns action descriptor for UI.
+func (a *DNSStaticRecordAction) Metadata() automationdomain.ActionMetadata {
+	onSet := &automationdomain.VisibleIfCondition{Key: "mode", Equals: "set"}
+	return automationdomain.ActionMetadata{
+		ID:          ActionIDDNSStaticSet,
+		Label:       "MikroTik: Static DNS record",
+		Description: "Add or remove a static DNS override owned by this capability",
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "mode",
+				Label:       "Mode",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"set", "remove"},
+				Description: "Whether to apply or remove the record",
+			},
+			{
+				Key:         "name",
+				Label:       "Name",
+				Kind:        automationdomain.ParamString,
+				Required:    true,
+				Description: "Host name, supports {{device.*}} and {{capability.*}} placeholders",
+			},
+			{
+				Key:         "type",
+				Label:       "Type",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{model.DNSRecordA, model.DNSRecordCNAME, model.DNSRecordFWD, model.DNSRecordNXDOMAIN},
+				Description: "A answers with an address, CNAME aliases, FWD forwards to another server, NXDOMAIN hides the name",
+				VisibleIf:   onSet,
+			},
+			{
+				Key:         "value",
+				Label:       "Value",
+				Kind:        automationdomain.ParamString,
+				Description: "Address, alias target or upstream server; supports placeholders, e.g. {{device.ip}}",
+				VisibleIf:   onSet,
+			},
+		},
+	}
+}
+
+// Validate validates action params against metadata schema.
+func (a *DNSStaticRecordAction) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	mode, err := stringParam(params, "mode")
+	if err != nil {
+		return err
+	}
+	if mode != "set" && mode != "remove" {
+		return fmt.Errorf("unsupported mode %q", mode)
+	}
+	name, err := stringParam(params, "name")
+	if err != nil {
+		return err
+	}
+	global := automationdomain.NormalizeCapabilityScope(target.Scope) == automationdomain.ScopeGlobal
+	if global && containsDevicePlaceholder(name) {
+		return fmt.Errorf("global scope does not support device placeholders")
+	}
+	if mode == "remove" {
+		return nil
+	}
+
+	recordType, err := stringParam(params, "type")
+	if err != nil {
+		return err
+	}
+	value := optionalStringParam(params, "value")
+	switch recordType {
+	case model.DNSRecordA, model.DNSRecordCNAME, model.DNSRecordFWD:
+		if value == "" {
+			return fmt.Errorf("param %q is required for %s record", "value", recordType)
+		}
+	case model.DNSRecordNXDOMAIN:
+	default:
+		return fmt.Errorf("unsupported type %q", recordType)
+	}
+	if global && containsDevicePlaceholder(value) {
+		return fmt.Errorf("global scope does not support device placeholders")
+	}
+	return nil
+}
+
+// Execute renders placeholders and applies or removes owned record.
+func (a *DNSStaticRecordAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+	if execCtx.RouterClient == nil {
+		return fmt.Errorf("router client is not configured")
+	}
+	dnsClient, ok := execCtx.RouterClient.(automationdomain.DNSStaticClient)
+	if !ok {
+		return fmt.Errorf("router client does not support static dns")
+	}
+
+	vars := automationdomain.TemplateVars(execCtx)
+	record, err := dnsStaticRecordParam(params, vars)
+	if err != nil {
+		return err
+	}
+	comment := automationdomain.OwnershipComment(automationdomain.OwnershipTagFor(execCtx))
+	if mode, _ := stringParam(params, "mode"); mode == "remove" {
+		return dnsClient.RemoveDNSStaticRecord(ctx, execCtx.RouterConfig, record.Name, comment)
+	}
+	return dnsClient.EnsureDNSStaticRecord(ctx, execCtx.RouterConfig, record, comment)
+}
+
+// dnsStaticRecordParam renders name and value and checks them against record type.
+func dnsStaticRecordParam(params map[string]any, vars map[string]string) (model.DNSStaticRecord, error) {
+	name, _ := stringParam(params, "name")
+	record := model.DNSStaticRecord{
+		Name: strings.TrimSuffix(strings.ToLower(strings.TrimSpace(automationdomain.RenderTemplate(name, vars))), "."),
+	}
+	if !dnsNameRegexp.MatchString(record.Name) {
+		return model.DNSStaticRecord{}, fmt.Errorf("invalid dns name %q", record.Name)
+	}
+	if mode, _ := stringParam(params, "mode"); mode == "remove" {
+		return record, nil
+	}
+
+	record.Type, _ = stringParam(params, "type")
+	record.Value = strings.TrimSpace(automationdomain.RenderTemplate(optionalStringParam(params, "value"), vars))
+	switch record.Type {
+	case model.DNSRecordA:
+		if ip := net.ParseIP(record.Value); ip == nil || ip.To4() == nil {
+			return model.DNSStaticRecord{}, fmt.Errorf("A record value %q is not an IPv4 address", record.Value)
+		}
+	case model.DNSRecordCNAME:
+		record.Value = strings.TrimSuffix(strings.ToLower(record.Value), ".")
+		if !dnsNameRegexp.MatchString(record.Value) {
+			return model.DNSStaticRecord{}, fmt.Errorf("invalid CNAME target %q", record.Value)
+		}
+	case model.DNSRecordFWD:
+		if net.ParseIP(record.Value) == nil && !dnsNameRegexp.MatchString(record.Value) {
+			return model.DNSStaticRecord{}, fmt.Errorf("invalid forward server %q", record.Value)
+		}
+	case model.DNSRecordNXDOMAIN:
+		record.Value = ""
+	}
+	return record, nil
+}
@@ -0,0 +1,124 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeDNSClient struct {
+	fakeAddressListClient
+	records     map[string]model.DNSStaticRecord
+	lastComment string
+	adlists     map[string]bool
+}
+
+func (f *fakeDNSClient) EnsureDNSStaticRecord(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	record model.DNSStaticRecord,
+	comment string,
+) error {
+	if f.records == nil {
+		f.records = map[string]model.DNSStaticRecord{}
+	}
+	f.records[record.Name] = record
+	f.lastComment = comment
+	return nil
+}
+
+func (f *fakeDNSClient) RemoveDNSStaticRecord(ctx context.Context, cfg model.RouterConfig, name, comment string) error {
+	delete(f.records, name)
+	f.lastComment = comment
+	return nil
+}
+
+func (f *fakeDNSClient) GetDNSAdlistsEnabled(ctx context.Context, cfg model.RouterConfig, source string) (bool, error) {
+	return f.adlists[source], nil
+}
+
+func (f *fakeDNSClient) SetDNSAdlistsEnabled(ctx context.Context, cfg model.RouterConfig, source string, enabled bool) error {
+	if f.adlists == nil {
+		f.adlists = map[string]bool{}
+	}
+	f.adlists[source] = enabled
+	return nil
+}
+
+func TestDNSStaticRecordActionRendersPlaceholders(t *testing.T) {
+	action := NewDNSStaticRecordAction()
+	ip := "192.168.88.10"
+	hostName := "tablet"
+	client := &fakeDNSClient{}
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", LastIP: &ip, HostName: &hostName}
+	execCtx := automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		CapabilityID: "dns.alias",
+		RouterClient: client,
+	}
+
+	params := map[string]any{
+		"mode":  "set",
+		"name":  "{{device.host_name}}.Home.Lan.",
+		"type":  "A",
+		"value": "{{device.ip}}",
+	}
+	if err := action.Execute(context.Background(), execCtx, params); err != nil {
+		t.Fatalf("Execute set returned error: %v", err)
+	}
+	record := client.records["tablet.home.lan"]
+	if record.Type != model.DNSRecordA || record.Value != ip {
+		t.Fatalf("unexpected records: %+v", client.records)
+	}
+	tag, ok := automationdomain.ParseOwnershipComment(client.lastComment)
+	if !ok || tag.CapabilityID != "dns.alias" || tag.DeviceID != device.MAC {
+		t.Fatalf("unexpected ownership comment %q", client.lastComment)
+	}
+
+	params["mode"] = "remove"
+	if err := action.Execute(context.Background(), execCtx, params); err != nil {
+		t.Fatalf("Execute remove returned error: %v", err)
+	}
+	if len(client.records) != 0 {
+		t.Fatalf("expected record removed, got %+v", client.records)
+	}
+}
+
+func TestDNSStaticRecordActionValidate(t *testing.T) {
+	action := NewDNSStaticRecordAction()
+	device := automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice}
+	global := automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal}
+
+	if err := action.Validate(global, map[string]any{"mode": "set", "name": "ads.example.com", "type": "NXDOMAIN"}); err != nil {
+		t.Fatalf("expected NXDOMAIN without value to be valid: %v", err)
+	}
+	if err := action.Validate(device, map[string]any{"mode": "set", "name": "nas.lan", "type": "CNAME"}); err == nil {
+		t.Fatalf("expected CNAME without value to be rejected")
+	}
+	if err := action.Validate(global, map[string]any{"mode": "set", "name": "{{device.host_name}}.lan", "type": "A", "value": "10.0.0.1"}); err == nil {
+		t.Fatalf("expected device placeholder to be rejected in global scope")
+	}
+	if err := action.Validate(device, map[string]any{"mode": "set", "name": "nas.lan", "type": "TXT", "value": "x"}); err == nil {
+		t.Fatalf("expected unsupported type to be rejected")
+	}
+}
+
+func TestDNSAdlistToggleActionExecute(t *testing.T) {
+	action := NewDNSAdlistToggleAction()
+	client := &fakeDNSClient{}
+	execCtx := automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal},
+		RouterClient: client,
+	}
+	if err := action.Execute(context.Background(), execCtx, map[string]any{"mode": "enable"}); err != nil {
+		t.Fatalf("Execute enable returned error: %v", err)
+	}
+	if enabled, ok := client.adlists[""]; !ok || !enabled {
+		t.Fatalf("expected every adlist enabled, got %+v", client.adlists)
+	}
+	if err := action.Validate(execCtx.Target, map[string]any{"mode": "toggle"}); err == nil {
+		t.Fatalf("expected unsupported mode to be rejected")
+	}
+}
@@ -58,18 +58,22 @@ func (a *FirewallRuleToggleAction) Metadata() automationdomain.ActionMetadata {
 				Description: "Choose whether to target one rule id or all rules by comment",
 			},
 			{
-				Key:       "rule_id",
-				Label:     "Rule id",
-				Kind:      automationdomain.ParamString,
-				Required:  true,
-				VisibleIf: &automationdomain.VisibleIfCondition{Key: "match_by", Equals: "id"},
+				Key:             "rule_id",
+				Label:           "Rule id",
+				Kind:            automationdomain.ParamString,
+				Required:        true,
+				OptionsProvider: automationdomain.OptionsRouterFirewallRules,
+				OptionsArgKey:   "table",
+				VisibleIf:       &automationdomain.VisibleIfCondition{Key: "match_by", Equals: "id"},
 			},
 			{
-				Key:       "comment",
-				Label:     "Rule comment",
-				Kind:      automationdomain.ParamString,
-				Required:  true,
-				VisibleIf: &automationdomain.VisibleIfCondition{Key: "match_by", Equals: "comment"},
+				Key:             "comment",
+				Label:           "Rule comment",
+				Kind:            automationdomain.ParamString,
+				Required:        true,
+				OptionsProvider: automationdomain.OptionsRouterFirewallComments,
+				OptionsArgKey:   "table",
+				VisibleIf:       &automationdomain.VisibleIfCondition{Key: "match_by", Equals: "comment"},
 			},
 		},
 	}
@@ -0,0 +1,176 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+const (
+	// ActionIDKidControlSetPaused pauses or resumes RouterOS kid-control profile.
+	ActionIDKidControlSetPaused = "mikrotik.kid_control.set_paused"
+)
+
+// KidControlPauseAction pauses or resumes kid-control profile, optionally
+// binding the target device to it first.
+type KidControlPauseAction struct{}
+
+// NewKidControlPauseAction creates MikroTik kid-control action.
+func NewKidControlPauseAction() *KidControlPauseAction {
+	return &KidControlPauseAction{}
+}
+
+// ID returns unique action identifier.
+func (a *KidControlPauseAction) ID() string {
+	return ActionIDKidControlSetPaused
+}
+
+// Metadata returns action descriptor for UI.
+func (a *KidControlPauseAction) Metadata() automationdomain.ActionMetadata {
+	onProfile := &automationdomain.VisibleIfCondition{Key: "profile_source", Equals: "profile"}
+	return automationdomain.ActionMetadata{
+		ID:          ActionIDKidControlSetPaused,
+		Label:       "MikroTik: Kid-control pause",
+		Description: "Pause or resume a RouterOS kid-control profile; pausing blocks every device of the profile",
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "mode",
+				Label:       "Mode",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"pause", "resume"},
+				Description: "Whether to pause or resume the profile",
+			},
+			{
+				Key:         "profile_source",
+				Label:       "Profile",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"profile", "device"},
+				Description: "Named profile, or the profile the device MAC is bound to on the router",
+			},
+			{
+				Key:             "profile",
+				Label:           "Profile name",
+				Kind:            automationdomain.ParamString,
+				Required:        true,
+				OptionsProvider: automationdomain.OptionsRouterKidControlProfiles,
+				VisibleIf:       onProfile,
+			},
+			{
+				Key:         "bind_device",
+				Label:       "Bind device",
+				Kind:        automationdomain.ParamBool,
+				Description: "Add the device MAC to the profile before applying the mode",
+				VisibleIf:   onProfile,
+			},
+		},
+	}
+}
+
+// Validate validates action params against metadata schema.
+func (a *KidControlPauseAction) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	mode, err := stringParam(params, "mode")
+	if err != nil {
+		return err
+	}
+	if mode != "pause" && mode != "resume" {
+		return fmt.Errorf("unsupported mode %q", mode)
+	}
+	if err := validateKidControlProfile(target, params); err != nil {
+		return err
+	}
+	bind, err := boolParam(params, "bind_device")
+	if err != nil {
+		return err
+	}
+	if bind && automationdomain.NormalizeCapabilityScope(target.Scope) == automationdomain.ScopeGlobal {
+		return fmt.Errorf("bind_device is not available for global scope")
+	}
+	return nil
+}
+
+// Execute pauses or resumes resolved kid-control profile.
+func (a *KidControlPauseAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+	if execCtx.RouterClient == nil {
+		return fmt.Errorf("router client is not configured")
+	}
+	kidClient, ok := execCtx.RouterClient.(automationdomain.KidControlClient)
+	if !ok {
+		return fmt.Errorf("router client does not support kid-control")
+	}
+
+	if bind, _ := boolParam(params, "bind_device"); bind {
+		device := execCtx.Target.Device
+		if device == nil || device.MAC == "" {
+			return fmt.Errorf("device MAC is empty")
+		}
+		profile, _ := stringParam(params, "profile")
+		comment := automationdomain.OwnershipComment(automationdomain.OwnershipTagFor(execCtx))
+		if err := kidClient.BindKidControlDevice(ctx, execCtx.RouterConfig, profile, device.MAC, device.Name, comment); err != nil {
+			return err
+		}
+	}
+
+	profile, err := resolveKidControlProfile(ctx, kidClient, execCtx, params)
+	if err != nil {
+		return err
+	}
+	mode, _ := stringParam(params, "mode")
+	return kidClient.SetKidControlPaused(ctx, execCtx.RouterConfig, profile, mode == "pause")
+}
+
+func validateKidControlProfile(target automationdomain.AutomationTarget, params map[string]any) error {
+	source, err := stringParam(params, "profile_source")
+	if err != nil {
+		return err
+	}
+	switch source {
+	case "profile":
+		_, err := stringParam(params, "profile")
+		return err
+	case "device":
+		if automationdomain.NormalizeCapabilityScope(target.Scope) == automationdomain.ScopeGlobal {
+			return fmt.Errorf("profile_source %q is not available for global scope", source)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported profile_source %q", source)
+	}
+}
+
+// resolveKidControlProfile returns named profile or the one device MAC is bound to.
+func resolveKidControlProfile(
+	ctx context.Context,
+	client automationdomain.KidControlStateClient,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) (string, error) {
+	source, _ := stringParam(params, "profile_source")
+	if source == "profile" {
+		return stringParam(params, "profile")
+	}
+	device := execCtx.Target.Device
+	if device == nil || device.MAC == "" {
+		return "", fmt.Errorf("device MAC is empty")
+	}
+	profile, err := client.KidControlProfileForMAC(ctx, execCtx.RouterConfig, device.MAC)
+	if err != nil {
+		return "", err
+	}
+	if profile == "" {
+		return "", fmt.Errorf("device %s is not bound to a kid-control profile", device.MAC)
+	}
+	return profile, nil
+}
@@ -0,0 +1,114 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeKidControlClient struct {
+	fakeAddressListClient
+	bindings    map[string]string
+	paused      map[string]bool
+	bindComment string
+}
+
+func (f *fakeKidControlClient) GetKidControlPaused(ctx context.Context, cfg model.RouterConfig, profile string) (bool, error) {
+	return f.paused[profile], nil
+}
+
+func (f *fakeKidControlClient) KidControlProfileForMAC(ctx context.Context, cfg model.RouterConfig, mac string) (string, error) {
+	return f.bindings[mac], nil
+}
+
+func (f *fakeKidControlClient) SetKidControlPaused(ctx context.Context, cfg model.RouterConfig, profile string, paused bool) error {
+	if f.paused == nil {
+		f.paused = map[string]bool{}
+	}
+	f.paused[profile] = paused
+	return nil
+}
+
+func (f *fakeKidControlClient) BindKidControlDevice(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	profile, mac, name, comment string,
+) error {
+	if f.bindings == nil {
+		f.bindings = map[string]string{}
+	}
+	f.bindings[mac] = profile
+	f.bindComment = comment
+	return nil
+}
+
+func (f *fakeKidControlClient) UnbindKidControlDevice(ctx context.Context, cfg model.RouterConfig, mac, comment string) error {
+	delete(f.bindings, mac)
+	return nil
+}
+
+func TestKidControlPauseActionBindsAndPauses(t *testing.T) {
+	action := NewKidControlPauseAction()
+	client := &fakeKidControlClient{}
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", Name: "Tablet"}
+	execCtx := automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		CapabilityID: "kids.bedtime",
+		RouterClient: client,
+	}
+
+	err := action.Execute(context.Background(), execCtx, map[string]any{
+		"mode":           "pause",
+		"profile_source": "profile",
+		"profile":        "kids",
+		"bind_device":    true,
+	})
+	if err != nil {
+		t.Fatalf("Execute pause returned error: %v", err)
+	}
+	if client.bindings[device.MAC] != "kids" || !client.paused["kids"] {
+		t.Fatalf("unexpected client state: %+v", client)
+	}
+	tag, ok := automationdomain.ParseOwnershipComment(client.bindComment)
+	if !ok || tag.CapabilityID != "kids.bedtime" || tag.DeviceID != device.MAC {
+		t.Fatalf("unexpected ownership comment %q", client.bindComment)
+	}
+
+	// Device source resolves the profile from the router binding.
+	if err := action.Execute(context.Background(), execCtx, map[string]any{"mode": "resume", "profile_source": "device"}); err != nil {
+		t.Fatalf("Execute resume returned error: %v", err)
+	}
+	if client.paused["kids"] {
+		t.Fatalf("expected profile to be resumed")
+	}
+}
+
+func TestKidControlPauseActionRejectsUnboundDevice(t *testing.T) {
+	action := NewKidControlPauseAction()
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01"}
+	err := action.Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		RouterClient: &fakeKidControlClient{},
+	}, map[string]any{"mode": "pause", "profile_source": "device"})
+	if err == nil {
+		t.Fatalf("expected error for device without kid-control binding")
+	}
+}
+
+func TestKidControlPauseActionValidateGlobalScope(t *testing.T) {
+	action := NewKidControlPauseAction()
+	global := automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal}
+	if err := action.Validate(global, map[string]any{"mode": "pause", "profile_source": "profile", "profile": "kids"}); err != nil {
+		t.Fatalf("expected named profile to be valid in global scope: %v", err)
+	}
+	if err := action.Validate(global, map[string]any{"mode": "pause", "profile_source": "device"}); err == nil {
+		t.Fatalf("expected device profile source to be rejected in global scope")
+	}
+	if err := action.Validate(global, map[string]any{
+		"mode": "pause", "profile_source": "profile", "profile": "kids", "bind_device": true,
+	}); err == nil {
+		t.Fatalf("expected bind_device to be rejected in global scope")
+	}
+}
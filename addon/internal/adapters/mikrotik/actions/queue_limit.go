@@ -0,0 +1,251 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const (
+	// ActionIDQueueSetLimit limits device bandwidth with RouterOS simple queue.
+	ActionIDQueueSetLimit = "mikrotik.queue.set_limit"
+)
+
+// queueRatePattern matches RouterOS rates such as 512k, 10M or 1.5G.
+const queueRatePattern = `^[0-9]+(\.[0-9]+)?[kMG]?$`
+
+var queueRateRegexp = regexp.MustCompile(queueRatePattern)
+
+// QueueLimitAction sets or removes simple queue limit owned by capability.
+type QueueLimitAction struct{}
+
+// NewQueueLimitAction creates MikroTik simple queue limit action.
+func NewQueueLimitAction() *QueueLimitAction {
+	return &QueueLimitAction{}
+}
+
+// ID returns unique action identifier.
+func (a *QueueLimitAction) ID() string {
+	return ActionIDQueueSetLimit
+}
+
+// Metadata returns action descriptor for UI.
+func (a *QueueLimitAction) Metadata() automationdomain.ActionMetadata {
+	onSet := &automationdomain.VisibleIfCondition{Key: "mode", Equals: "set"}
+	rateField := func(key, label, description string) automationdomain.ParamField {
+		return automationdomain.ParamField{
+			Key:         key,
+			Label:       label,
+			Kind:        automationdomain.ParamString,
+			Pattern:     queueRatePattern,
+			Description: description,
+			VisibleIf:   onSet,
+		}
+	}
+	return automationdomain.ActionMetadata{
+		ID:          ActionIDQueueSetLimit,
+		Label:       "MikroTik: Bandwidth limit",
+		Description: "Limit target bandwidth with a RouterOS simple queue owned by this capability",
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "mode",
+				Label:       "Mode",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"set", "remove"},
+				Description: "Whether to apply or remove the limit",
+			},
+			{
+				Key:         "target",
+				Label:       "Target",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"device.ip", "literal_ip"},
+				Description: "Address limited by the queue",
+			},
+			{
+				Key:       "literal_ip",
+				Label:     "Literal IP",
+				Kind:      automationdomain.ParamString,
+				Required:  true,
+				VisibleIf: &automationdomain.VisibleIfCondition{Key: "target", Equals: "literal_ip"},
+			},
+			rateField("max_upload", "Max upload", "Upload limit like 2M; empty or 0 is unlimited"),
+			rateField("max_download", "Max download", "Download limit like 10M; empty or 0 is unlimited"),
+			rateField("burst_upload", "Burst upload", "Optional upload burst limit, above max upload"),
+			rateField("burst_download", "Burst download", "Optional download burst limit, above max download"),
+			rateField("burst_threshold_upload", "Burst threshold upload", "Average upload rate below which burst is allowed"),
+			rateField("burst_threshold_download", "Burst threshold download", "Average download rate below which burst is allowed"),
+			{
+				Key:         "burst_time",
+				Label:       "Burst time",
+				Kind:        automationdomain.ParamDuration,
+				Min:         automationdomain.IntBound(1),
+				Description: "Averaging window for burst; required when a burst limit is set",
+				VisibleIf:   onSet,
+			},
+		},
+	}
+}
+
+// Validate validates action params against metadata schema.
+func (a *QueueLimitAction) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	mode, err := stringParam(params, "mode")
+	if err != nil {
+		return err
+	}
+	if mode != "set" && mode != "remove" {
+		return fmt.Errorf("unsupported mode %q", mode)
+	}
+
+	if err := automationdomain.ValidateIPTarget(target, params); err != nil {
+		return err
+	}
+
+	if mode == "set" {
+		if _, err := queueLimitParam(params); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute applies or removes owned simple queue for target address.
+func (a *QueueLimitAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+	queueClient, err := simpleQueueClient(execCtx)
+	if err != nil {
+		return err
+	}
+
+	mode, _ := stringParam(params, "mode")
+	target, _ := stringParam(params, "target")
+	address, err := resolveTargetAddress(target, params, execCtx)
+	if err != nil {
+		return err
+	}
+	comment := automationdomain.OwnershipComment(automationdomain.OwnershipTagFor(execCtx))
+
+	if mode == "remove" {
+		return queueClient.RemoveSimpleQueueLimit(ctx, execCtx.RouterConfig, address, comment)
+	}
+	limit, _ := queueLimitParam(params)
+	return queueClient.SetSimpleQueueLimit(ctx, execCtx.RouterConfig, address, comment, limit)
+}
+
+// CleanupStaleTarget removes queue left on previous device IP.
+func (a *QueueLimitAction) CleanupStaleTarget(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+	mode, _ := stringParam(params, "mode")
+	target, _ := stringParam(params, "target")
+	if mode != "set" || target != "device.ip" {
+		return nil
+	}
+	queueClient, err := simpleQueueClient(execCtx)
+	if err != nil {
+		return err
+	}
+
+	address, err := resolveTargetAddress(target, params, execCtx)
+	if err != nil {
+		return err
+	}
+	comment := automationdomain.OwnershipComment(automationdomain.OwnershipTagFor(execCtx))
+	return queueClient.RemoveSimpleQueueLimit(ctx, execCtx.RouterConfig, address, comment)
+}
+
+func simpleQueueClient(execCtx automationdomain.ActionExecutionContext) (automationdomain.SimpleQueueClient, error) {
+	if execCtx.RouterClient == nil {
+		return nil, fmt.Errorf("router client is not configured")
+	}
+	queueClient, ok := execCtx.RouterClient.(automationdomain.SimpleQueueClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support simple queues")
+	}
+	return queueClient, nil
+}
+
+// queueLimitParam builds RouterOS "upload/download" pairs from rate params.
+func queueLimitParam(params map[string]any) (model.QueueLimit, error) {
+	rate := func(key string) (string, error) {
+		value := optionalStringParam(params, key)
+		if value != "" && !queueRateRegexp.MatchString(value) {
+			return "", fmt.Errorf("param %q must be rate like 512k, 10M or 1G", key)
+		}
+		return value, nil
+	}
+	pair := func(uploadKey, downloadKey string) (string, error) {
+		upload, err := rate(uploadKey)
+		if err != nil {
+			return "", err
+		}
+		download, err := rate(downloadKey)
+		if err != nil {
+			return "", err
+		}
+		if !nonZeroRate(upload) && !nonZeroRate(download) {
+			return "", nil
+		}
+		return orZero(upload) + "/" + orZero(download), nil
+	}
+
+	var limit model.QueueLimit
+	var err error
+	if limit.MaxLimit, err = pair("max_upload", "max_download"); err != nil {
+		return model.QueueLimit{}, err
+	}
+	if limit.MaxLimit == "" {
+		return model.QueueLimit{}, fmt.Errorf("max_upload or max_download is required")
+	}
+	if limit.BurstLimit, err = pair("burst_upload", "burst_download"); err != nil {
+		return model.QueueLimit{}, err
+	}
+	if limit.BurstThreshold, err = pair("burst_threshold_upload", "burst_threshold_download"); err != nil {
+		return model.QueueLimit{}, err
+	}
+	burstTime, err := durationParam(params, "burst_time")
+	if err != nil {
+		return model.QueueLimit{}, err
+	}
+	if limit.BurstLimit == "" {
+		// Thresholds and burst time are meaningless without burst limit.
+		return model.QueueLimit{MaxLimit: limit.MaxLimit}, nil
+	}
+	if limit.BurstThreshold == "" {
+		return model.QueueLimit{}, fmt.Errorf("burst threshold is required when burst limit is set")
+	}
+	if burstTime == "" {
+		return model.QueueLimit{}, fmt.Errorf("param %q is required when burst limit is set", "burst_time")
+	}
+	limit.BurstTime = burstTime + "/" + burstTime
+	return limit, nil
+}
+
+func nonZeroRate(value string) bool {
+	return value != "" && value != "0"
+}
+
+func orZero(value string) string {
+	if value == "" {
+		return "0"
+	}
+	return value
+}
@@ -0,0 +1,118 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeSimpleQueueClient struct {
+	fakeAddressListClient
+	setCalls    int
+	removeCalls int
+	lastTarget  string
+	lastComment string
+	lastLimit   model.QueueLimit
+}
+
+func (f *fakeSimpleQueueClient) SetSimpleQueueLimit(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	target string,
+	comment string,
+	limit model.QueueLimit,
+) error {
+	f.setCalls++
+	f.lastTarget = target
+	f.lastComment = comment
+	f.lastLimit = limit
+	return nil
+}
+
+func (f *fakeSimpleQueueClient) RemoveSimpleQueueLimit(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	target string,
+	comment string,
+) error {
+	f.removeCalls++
+	f.lastTarget = target
+	f.lastComment = comment
+	return nil
+}
+
+func TestQueueLimitActionExecuteSetBuildsLimitAndOwnership(t *testing.T) {
+	action := NewQueueLimitAction()
+	ip := "192.168.88.10"
+	client := &fakeSimpleQueueClient{}
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", LastIP: &ip}
+
+	err := action.Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		CapabilityID: "kids.bandwidth",
+		RouterClient: client,
+	}, map[string]any{
+		"mode":                     "set",
+		"target":                   "device.ip",
+		"max_upload":               "1M",
+		"max_download":             "5M",
+		"burst_download":           "10M",
+		"burst_threshold_download": "4M",
+		"burst_time":               "8s",
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	want := model.QueueLimit{MaxLimit: "1M/5M", BurstLimit: "0/10M", BurstThreshold: "0/4M", BurstTime: "8s/8s"}
+	if client.setCalls != 1 || client.lastTarget != ip || client.lastLimit != want {
+		t.Fatalf("unexpected set: calls=%d target=%q limit=%+v", client.setCalls, client.lastTarget, client.lastLimit)
+	}
+	tag, ok := automationdomain.ParseOwnershipComment(client.lastComment)
+	if !ok || tag.CapabilityID != "kids.bandwidth" || tag.DeviceID != device.MAC {
+		t.Fatalf("unexpected ownership comment %q", client.lastComment)
+	}
+}
+
+func TestQueueLimitActionValidateRejectsBadLimits(t *testing.T) {
+	action := NewQueueLimitAction()
+	target := automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice}
+	cases := []map[string]any{
+		{"mode": "set", "target": "device.ip"},
+		{"mode": "set", "target": "device.ip", "max_download": "fast"},
+		{"mode": "set", "target": "device.ip", "max_download": "5M", "burst_download": "10M"},
+		{"mode": "set", "target": "device.ip", "max_download": "5M", "burst_download": "10M", "burst_threshold_download": "4M"},
+	}
+	for i, params := range cases {
+		if err := action.Validate(target, params); err == nil {
+			t.Fatalf("case %d: expected validation error", i)
+		}
+	}
+	if err := action.Validate(target, map[string]any{"mode": "remove", "target": "device.ip"}); err != nil {
+		t.Fatalf("remove without limits should validate: %v", err)
+	}
+}
+
+func TestQueueLimitActionCleanupStaleTargetRemovesPreviousIP(t *testing.T) {
+	action := NewQueueLimitAction()
+	previousIP := "192.168.88.10"
+	client := &fakeSimpleQueueClient{}
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", LastIP: &previousIP}
+
+	err := action.CleanupStaleTarget(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		CapabilityID: "kids.bandwidth",
+		RouterClient: client,
+	}, map[string]any{
+		"mode":         "set",
+		"target":       "device.ip",
+		"max_download": "5M",
+	})
+	if err != nil {
+		t.Fatalf("CleanupStaleTarget returned error: %v", err)
+	}
+	if client.removeCalls != 1 || client.lastTarget != previousIP {
+		t.Fatalf("unexpected cleanup: remove=%d target=%q", client.removeCalls, client.lastTarget)
+	}
+}
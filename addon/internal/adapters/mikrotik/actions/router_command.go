@@ -0,0 +1,201 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+const (
+	// ActionIDRouterCommand runs allowlisted RouterOS API command.
+	ActionIDRouterCommand = "mikrotik.command.run"
+)
+
+var commandAttributeKeyPattern = regexp.MustCompile(`^\.?[a-z0-9][a-z0-9-]*$`)
+
+// RouterCommandAction runs generic RouterOS command guarded by admin allowlist.
+type RouterCommandAction struct {
+	allowlist automationdomain.CommandAllowlist
+}
+
+// NewRouterCommandAction creates generic RouterOS command action.
+func NewRouterCommandAction(allowlist automationdomain.CommandAllowlist) *RouterCommandAction {
+	return &RouterCommandAction{allowlist: allowlist}
+}
+
+// ID returns unique action identifier.
+func (a *RouterCommandAction) ID() string {
+	return ActionIDRouterCommand
+}
+
+// Metadata returns action descriptor for UI.
+func (a *RouterCommandAction) Metadata() automationdomain.ActionMetadata {
+	allowed := "none, configure router_command_allowlist in add-on options"
+	if paths := a.allowlist.Paths(); len(paths) > 0 {
+		allowed = strings.Join(paths, ", ")
+	}
+	return automationdomain.ActionMetadata{
+		ID:          ActionIDRouterCommand,
+		Label:       "MikroTik: RouterOS command",
+		Description: "Run RouterOS API command on allowlisted menu path (" + allowed + ")",
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "command",
+				Label:       "Command",
+				Kind:        automationdomain.ParamString,
+				Required:    true,
+				Description: "Menu path with verb, for example /ip/dns/static/add",
+			},
+			{
+				Key:         "attributes",
+				Label:       "Attributes",
+				Kind:        automationdomain.ParamString,
+				Description: "One `name=value` per line, supports {{device.*}} and {{capability.*}} placeholders",
+			},
+			{
+				Key:         "find_where",
+				Label:       "Find where",
+				Kind:        automationdomain.ParamString,
+				Description: "Optional `name=value` lines; add is skipped when a match exists, other verbs run per matched .id",
+			},
+		},
+	}
+}
+
+// Validate validates action params against metadata schema and allowlist.
+func (a *RouterCommandAction) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	command, err := stringParam(params, "command")
+	if err != nil {
+		return err
+	}
+	menu, verb, err := automationdomain.SplitCommandPath(command)
+	if err != nil {
+		return err
+	}
+	if !a.allowlist.Allows(menu, verb) {
+		return fmt.Errorf("command %s/%s is not in router command allowlist", menu, verb)
+	}
+
+	attributes := optionalStringParam(params, "attributes")
+	if _, err := parseCommandWords(attributes); err != nil {
+		return err
+	}
+	findWhere := optionalStringParam(params, "find_where")
+	if _, err := parseCommandWords(findWhere); err != nil {
+		return err
+	}
+	// find_where reads matching entries first, so print must be allowed too.
+	if findWhere != "" && !a.allowlist.Allows(menu, "print") {
+		return fmt.Errorf("command %s/print is not in router command allowlist", menu)
+	}
+
+	if automationdomain.NormalizeCapabilityScope(target.Scope) == automationdomain.ScopeGlobal {
+		if containsDevicePlaceholder(attributes) || containsDevicePlaceholder(findWhere) {
+			return fmt.Errorf("global scope does not support device placeholders")
+		}
+	}
+	return nil
+}
+
+// Execute renders params and runs command via RouterOS API.
+func (a *RouterCommandAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+	client, ok := execCtx.RouterClient.(automationdomain.RouterCommandClient)
+	if !ok || client == nil {
+		return fmt.Errorf("router client does not support raw commands")
+	}
+
+	command, _ := stringParam(params, "command")
+	menu, verb, _ := automationdomain.SplitCommandPath(command)
+	vars := automationdomain.TemplateVars(execCtx)
+	// Words are parsed from raw template and rendered per value, so device
+	// fields cannot inject extra attributes.
+	rawAttributes, _ := parseCommandWords(optionalStringParam(params, "attributes"))
+	attributes, err := automationdomain.RenderWords(rawAttributes, vars)
+	if err != nil {
+		return err
+	}
+	rawFindWhere, _ := parseCommandWords(optionalStringParam(params, "find_where"))
+	findWhere, err := automationdomain.RenderWords(rawFindWhere, vars)
+	if err != nil {
+		return err
+	}
+
+	if len(findWhere) == 0 {
+		if _, err := client.RunCommand(ctx, execCtx.RouterConfig, menu+"/"+verb, attributes); err != nil {
+			return fmt.Errorf("run %s/%s: %w", menu, verb, err)
+		}
+		return nil
+	}
+
+	query := map[string]string{".proplist": ".id"}
+	for key, value := range findWhere {
+		query["?"+key] = value
+	}
+	rows, err := client.RunCommand(ctx, execCtx.RouterConfig, menu+"/print", query)
+	if err != nil {
+		return fmt.Errorf("find %s entries: %w", menu, err)
+	}
+
+	if verb == "add" {
+		if len(rows) > 0 {
+			// Entry already exists, add would duplicate it.
+			return nil
+		}
+		if _, err := client.RunCommand(ctx, execCtx.RouterConfig, menu+"/add", attributes); err != nil {
+			return fmt.Errorf("run %s/add: %w", menu, err)
+		}
+		return nil
+	}
+
+	for _, row := range rows {
+		id := strings.TrimSpace(row[".id"])
+		if id == "" {
+			continue
+		}
+		words := make(map[string]string, len(attributes)+1)
+		for key, value := range attributes {
+			words[key] = value
+		}
+		words[".id"] = id
+		if _, err := client.RunCommand(ctx, execCtx.RouterConfig, menu+"/"+verb, words); err != nil {
+			return fmt.Errorf("run %s/%s for %s: %w", menu, verb, id, err)
+		}
+	}
+	return nil
+}
+
+// parseCommandWords parses `name=value` lines into RouterOS attribute words.
+func parseCommandWords(raw string) (map[string]string, error) {
+	words := map[string]string{}
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || !commandAttributeKeyPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid attribute line %q", line)
+		}
+		words[key] = strings.TrimSpace(value)
+	}
+	return words, nil
+}
+
+func optionalStringParam(params map[string]any, key string) string {
+	value, _ := params[key].(string)
+	return strings.TrimSpace(value)
+}
@@ -0,0 +1,168 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type recordedCommand struct {
+	path   string
+	params map[string]string
+}
+
+type fakeCommandClient struct {
+	fakeFirewallRuleClient
+	printRows []map[string]string
+	commands  []recordedCommand
+}
+
+func (f *fakeCommandClient) RunCommand(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	path string,
+	params map[string]string,
+) ([]map[string]string, error) {
+	f.commands = append(f.commands, recordedCommand{path: path, params: params})
+	if path == "/ip/dns/static/print" {
+		return f.printRows, nil
+	}
+	return nil, nil
+}
+
+func mustCommandAllowlist(t *testing.T, entries ...string) automationdomain.CommandAllowlist {
+	t.Helper()
+	allowlist, err := automationdomain.ParseCommandAllowlist(entries)
+	if err != nil {
+		t.Fatalf("ParseCommandAllowlist returned error: %v", err)
+	}
+	return allowlist
+}
+
+func TestRouterCommandActionExecuteRendersAttributes(t *testing.T) {
+	ip := "192.168.88.20"
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", Name: "tablet", LastIP: &ip}
+	client := &fakeCommandClient{}
+	action := NewRouterCommandAction(mustCommandAllowlist(t, "/ip/dns/static:add,remove"))
+
+	err := action.Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		RouterClient: client,
+	}, map[string]any{
+		"command":    "/ip/dns/static/add",
+		"attributes": "name={{device.name}}.lan\naddress={{device.ip}}",
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(client.commands) != 1 {
+		t.Fatalf("expected 1 command, got %d", len(client.commands))
+	}
+	got := client.commands[0]
+	if got.path != "/ip/dns/static/add" || got.params["name"] != "tablet.lan" || got.params["address"] != ip {
+		t.Fatalf("unexpected command %+v", got)
+	}
+}
+
+func TestRouterCommandActionRendersValuesWithoutInjection(t *testing.T) {
+	action := NewRouterCommandAction(mustCommandAllowlist(t, "/ip/dns/static:add,print"))
+	params := map[string]any{
+		"command":    "/ip/dns/static/add",
+		"attributes": "name={{device.name}}.lan",
+		"find_where": "name={{device.name}}.lan",
+	}
+
+	spaced := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", Name: "tv disabled=yes"}
+	client := &fakeCommandClient{}
+	if err := action.Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &spaced},
+		RouterClient: client,
+	}, params); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	add := client.commands[1]
+	if len(add.params) != 1 || add.params["name"] != "tv disabled=yes.lan" {
+		t.Fatalf("expected single name attribute, got %+v", add.params)
+	}
+	if query := client.commands[0].params; len(query) != 2 || query["?name"] != "tv disabled=yes.lan" {
+		t.Fatalf("expected single name query, got %+v", query)
+	}
+
+	multiline := model.DeviceView{MAC: "AA:BB:CC:DD:EE:02", Name: "tv\naddress=10.0.0.1"}
+	client = &fakeCommandClient{}
+	if err := action.Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &multiline},
+		RouterClient: client,
+	}, params); err == nil {
+		t.Fatalf("expected control character error")
+	}
+	if len(client.commands) != 0 {
+		t.Fatalf("expected no router commands, got %+v", client.commands)
+	}
+}
+
+func TestRouterCommandActionFindWhereIdempotency(t *testing.T) {
+	action := NewRouterCommandAction(mustCommandAllowlist(t, "/ip/dns/static:*"))
+	params := map[string]any{
+		"command":    "/ip/dns/static/add",
+		"attributes": "name=nas.lan\naddress=192.168.88.2",
+		"find_where": "name=nas.lan",
+	}
+	global := automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal}
+
+	existing := &fakeCommandClient{printRows: []map[string]string{{".id": "*1"}}}
+	if err := action.Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       global,
+		RouterClient: existing,
+	}, params); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(existing.commands) != 1 || existing.commands[0].params["?name"] != "nas.lan" {
+		t.Fatalf("expected only find query, got %+v", existing.commands)
+	}
+
+	removal := &fakeCommandClient{printRows: []map[string]string{{".id": "*1"}, {".id": "*2"}}}
+	params["command"] = "/ip/dns/static/remove"
+	params["attributes"] = ""
+	if err := action.Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       global,
+		RouterClient: removal,
+	}, params); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if len(removal.commands) != 3 || removal.commands[2].path != "/ip/dns/static/remove" || removal.commands[2].params[".id"] != "*2" {
+		t.Fatalf("expected per-id remove commands, got %+v", removal.commands)
+	}
+}
+
+func TestRouterCommandActionValidate(t *testing.T) {
+	action := NewRouterCommandAction(mustCommandAllowlist(t, "/ip/dns/static:add"))
+	global := automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal}
+
+	cases := map[string]map[string]any{
+		"not allowlisted path": {"command": "/system/reboot"},
+		"not allowlisted verb": {"command": "/ip/dns/static/remove"},
+		"bad path":             {"command": "ip dns static add"},
+		"bad attribute":        {"command": "/ip/dns/static/add", "attributes": "?name=x"},
+		"global device var":    {"command": "/ip/dns/static/add", "attributes": "address={{device.ip}}"},
+		"find without print":   {"command": "/ip/dns/static/add", "find_where": "name=x"},
+	}
+	for name, params := range cases {
+		if err := action.Validate(global, params); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+
+	if err := action.Validate(global, map[string]any{
+		"command":    "/IP/DNS/static/add",
+		"attributes": "name=router.lan\naddress=192.168.88.1",
+	}); err != nil {
+		t.Fatalf("expected valid params, got %v", err)
+	}
+
+	if _, err := automationdomain.ParseCommandAllowlist([]string{"ip/dns"}); err == nil {
+		t.Fatalf("expected invalid allowlist entry error")
+	}
+}
@@ -0,0 +1,187 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const (
+	// ActionIDWiFiAccessSet denies, allows or disconnects wifi client by MAC.
+	ActionIDWiFiAccessSet = "mikrotik.wifi.access.set"
+)
+
+// WiFiAccessAction manages wifi access-list entry for MAC on every installed
+// wifi driver and drops the client's current registration.
+type WiFiAccessAction struct{}
+
+// NewWiFiAccessAction creates MikroTik wifi access action.
+func NewWiFiAccessAction() *WiFiAccessAction {
+	return &WiFiAccessAction{}
+}
+
+// ID returns unique action identifier.
+func (a *WiFiAccessAction) ID() string {
+	return ActionIDWiFiAccessSet
+}
+
+// Metadata returns action descriptor for UI.
+func (a *WiFiAccessAction) Metadata() automationdomain.ActionMetadata {
+	onAllow := &automationdomain.VisibleIfCondition{Key: "mode", Equals: "allow"}
+	return automationdomain.ActionMetadata{
+		ID:          ActionIDWiFiAccessSet,
+		Label:       "MikroTik: Wifi access",
+		Description: "Deny or allow a MAC in the wifi access list, or kick it off wifi; deny also disconnects the client",
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "mode",
+				Label:       "Mode",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"deny", "allow", "remove", "disconnect"},
+				Description: "deny and allow manage an access-list entry, remove deletes it, disconnect only drops the current registration",
+			},
+			{
+				Key:         "target",
+				Label:       "Target",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"device.mac", "literal_mac"},
+				Description: "MAC address of the wifi client",
+			},
+			{
+				Key:       "literal_mac",
+				Label:     "Literal MAC",
+				Kind:      automationdomain.ParamMAC,
+				Required:  true,
+				VisibleIf: &automationdomain.VisibleIfCondition{Key: "target", Equals: "literal_mac"},
+			},
+			{
+				Key:         "vlan_id",
+				Label:       "VLAN ID",
+				Kind:        automationdomain.ParamInt,
+				Min:         automationdomain.IntBound(1),
+				Max:         automationdomain.IntBound(4094),
+				Description: "Put the client into this VLAN",
+				VisibleIf:   onAllow,
+			},
+			{
+				Key:         "time",
+				Label:       "Time window",
+				Kind:        automationdomain.ParamString,
+				Description: "RouterOS time window for deny and allow, e.g. 7h-22h,mon,tue,wed,thu,fri; empty matches always",
+			},
+			{
+				Key:         "disconnect",
+				Label:       "Reconnect client",
+				Kind:        automationdomain.ParamBool,
+				Description: "Drop the current registration so the client reconnects with the new VLAN",
+				VisibleIf:   onAllow,
+			},
+		},
+	}
+}
+
+// Validate validates action params against metadata schema.
+func (a *WiFiAccessAction) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	mode, err := stringParam(params, "mode")
+	if err != nil {
+		return err
+	}
+	switch mode {
+	case "deny", "allow", "remove", "disconnect":
+	default:
+		return fmt.Errorf("unsupported mode %q", mode)
+	}
+	if err := validateMACTarget(target, params); err != nil {
+		return err
+	}
+	if _, err := wifiAccessPolicyParam(params); err != nil {
+		return err
+	}
+	_, err = boolParam(params, "disconnect")
+	return err
+}
+
+// Execute applies access-list entry and disconnects client via RouterOS API.
+func (a *WiFiAccessAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+	if execCtx.RouterClient == nil {
+		return fmt.Errorf("router client is not configured")
+	}
+	wifiClient, ok := execCtx.RouterClient.(automationdomain.WiFiAccessClient)
+	if !ok {
+		return fmt.Errorf("router client does not support wifi access lists")
+	}
+	mac, err := resolveTargetMAC(params, execCtx)
+	if err != nil {
+		return err
+	}
+
+	comment := automationdomain.OwnershipComment(automationdomain.OwnershipTagFor(execCtx))
+	mode, _ := stringParam(params, "mode")
+	disconnect, _ := boolParam(params, "disconnect")
+	switch mode {
+	case "remove":
+		return wifiClient.RemoveWiFiAccessRule(ctx, execCtx.RouterConfig, mac, comment)
+	case "deny", "allow":
+		policy, _ := wifiAccessPolicyParam(params)
+		if err := wifiClient.EnsureWiFiAccessRule(ctx, execCtx.RouterConfig, mac, policy, comment); err != nil {
+			return err
+		}
+		if mode == "allow" && !disconnect {
+			return nil
+		}
+	}
+	if _, err := wifiClient.DisconnectWiFiClient(ctx, execCtx.RouterConfig, mac); err != nil {
+		return fmt.Errorf("disconnect wifi client %s: %w", mac, err)
+	}
+	return nil
+}
+
+// wifiAccessPolicyParam builds access-list policy from mode, time and, for
+// allow, vlan_id.
+func wifiAccessPolicyParam(params map[string]any) (model.WiFiAccessPolicy, error) {
+	mode, _ := stringParam(params, "mode")
+	policy := model.WiFiAccessPolicy{Action: mode, Time: optionalStringParam(params, "time")}
+	if mode != model.WiFiAccessAllow {
+		return policy, nil
+	}
+
+	switch raw := params["vlan_id"].(type) {
+	case nil:
+	case float64:
+		if raw != math.Trunc(raw) {
+			return policy, fmt.Errorf("param %q must be integer", "vlan_id")
+		}
+		policy.VLANID = int(raw)
+	case string:
+		if raw = strings.TrimSpace(raw); raw != "" {
+			value, err := strconv.Atoi(raw)
+			if err != nil {
+				return policy, fmt.Errorf("param %q must be integer", "vlan_id")
+			}
+			policy.VLANID = value
+		}
+	default:
+		return policy, fmt.Errorf("param %q must be integer", "vlan_id")
+	}
+	if policy.VLANID < 0 || policy.VLANID > 4094 {
+		return policy, fmt.Errorf("vlan_id must be between 1 and 4094")
+	}
+	return policy, nil
+}
@@ -0,0 +1,118 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeWiFiAccessClient struct {
+	fakeAddressListClient
+	policies     map[string]model.WiFiAccessPolicy
+	comment      string
+	disconnected []string
+}
+
+func (f *fakeWiFiAccessClient) GetWiFiAccessDenied(ctx context.Context, cfg model.RouterConfig, mac string) (bool, error) {
+	return f.policies[mac].Action == model.WiFiAccessDeny, nil
+}
+
+func (f *fakeWiFiAccessClient) EnsureWiFiAccessRule(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	mac string,
+	policy model.WiFiAccessPolicy,
+	comment string,
+) error {
+	if f.policies == nil {
+		f.policies = map[string]model.WiFiAccessPolicy{}
+	}
+	f.policies[mac] = policy
+	f.comment = comment
+	return nil
+}
+
+func (f *fakeWiFiAccessClient) RemoveWiFiAccessRule(ctx context.Context, cfg model.RouterConfig, mac, comment string) error {
+	delete(f.policies, mac)
+	return nil
+}
+
+func (f *fakeWiFiAccessClient) DisconnectWiFiClient(ctx context.Context, cfg model.RouterConfig, mac string) (int, error) {
+	f.disconnected = append(f.disconnected, mac)
+	return 1, nil
+}
+
+func TestWiFiAccessActionDenyDisconnectsClient(t *testing.T) {
+	action := NewWiFiAccessAction()
+	client := &fakeWiFiAccessClient{}
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01"}
+	execCtx := automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		CapabilityID: "wifi.block",
+		RouterClient: client,
+	}
+
+	if err := action.Execute(context.Background(), execCtx, map[string]any{"mode": "deny", "target": "device.mac"}); err != nil {
+		t.Fatalf("Execute deny returned error: %v", err)
+	}
+	if client.policies[device.MAC].Action != model.WiFiAccessDeny || len(client.disconnected) != 1 {
+		t.Fatalf("expected deny entry and disconnect, got %+v", client)
+	}
+	tag, ok := automationdomain.ParseOwnershipComment(client.comment)
+	if !ok || tag.CapabilityID != "wifi.block" || tag.DeviceID != device.MAC {
+		t.Fatalf("unexpected ownership comment %q", client.comment)
+	}
+
+	if err := action.Execute(context.Background(), execCtx, map[string]any{"mode": "remove", "target": "device.mac"}); err != nil {
+		t.Fatalf("Execute remove returned error: %v", err)
+	}
+	if _, found := client.policies[device.MAC]; found || len(client.disconnected) != 1 {
+		t.Fatalf("expected entry removed without disconnect, got %+v", client)
+	}
+}
+
+func TestWiFiAccessActionAllowWithVLAN(t *testing.T) {
+	action := NewWiFiAccessAction()
+	client := &fakeWiFiAccessClient{}
+	execCtx := automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal},
+		CapabilityID: "guest.vlan",
+		RouterClient: client,
+	}
+
+	err := action.Execute(context.Background(), execCtx, map[string]any{
+		"mode":        "allow",
+		"target":      "literal_mac",
+		"literal_mac": "aa-bb-cc-dd-ee-02",
+		"vlan_id":     float64(30),
+		"time":        "7h-22h",
+	})
+	if err != nil {
+		t.Fatalf("Execute allow returned error: %v", err)
+	}
+	policy := client.policies["AA:BB:CC:DD:EE:02"]
+	if policy.Action != model.WiFiAccessAllow || policy.VLANID != 30 || policy.Time != "7h-22h" {
+		t.Fatalf("unexpected policy %+v", client.policies)
+	}
+	if len(client.disconnected) != 0 {
+		t.Fatalf("expected allow without reconnect to keep client, got %v", client.disconnected)
+	}
+}
+
+func TestWiFiAccessActionValidate(t *testing.T) {
+	action := NewWiFiAccessAction()
+	global := automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal}
+	if err := action.Validate(global, map[string]any{"mode": "deny", "target": "device.mac"}); err == nil {
+		t.Fatalf("expected device.mac to be rejected in global scope")
+	}
+	if err := action.Validate(global, map[string]any{"mode": "block", "target": "literal_mac", "literal_mac": "AA:BB:CC:DD:EE:01"}); err == nil {
+		t.Fatalf("expected unsupported mode to be rejected")
+	}
+	if err := action.Validate(global, map[string]any{
+		"mode": "allow", "target": "literal_mac", "literal_mac": "AA:BB:CC:DD:EE:01", "vlan_id": float64(5000),
+	}); err == nil {
+		t.Fatalf("expected out-of-range vlan_id to be rejected")
+	}
+}
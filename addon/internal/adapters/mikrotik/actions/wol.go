@@ -0,0 +1,145 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+const (
+	// ActionIDWakeOnLAN sends Wake-on-LAN magic packet through the router.
+	ActionIDWakeOnLAN = "mikrotik.wol"
+)
+
+// WakeOnLANAction wakes device with RouterOS /tool/wol.
+type WakeOnLANAction struct{}
+
+// NewWakeOnLANAction creates MikroTik Wake-on-LAN action.
+func NewWakeOnLANAction() *WakeOnLANAction {
+	return &WakeOnLANAction{}
+}
+
+// ID returns unique action identifier.
+func (a *WakeOnLANAction) ID() string {
+	return ActionIDWakeOnLAN
+}
+
+// Metadata returns action descriptor for UI.
+func (a *WakeOnLANAction) Metadata() automationdomain.ActionMetadata {
+	return automationdomain.ActionMetadata{
+		ID:          ActionIDWakeOnLAN,
+		Label:       "MikroTik: Wake-on-LAN",
+		Description: "Send Wake-on-LAN magic packet from the router",
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "target",
+				Label:       "Target",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"device.mac", "literal_mac"},
+				Description: "MAC address to wake",
+			},
+			{
+				Key:       "literal_mac",
+				Label:     "Literal MAC",
+				Kind:      automationdomain.ParamString,
+				Required:  true,
+				VisibleIf: &automationdomain.VisibleIfCondition{Key: "target", Equals: "literal_mac"},
+			},
+			{
+				Key:             "interface",
+				Label:           "Interface",
+				Kind:            automationdomain.ParamString,
+				OptionsProvider: automationdomain.OptionsRouterInterfaces,
+				Description:     "Interface to send from; empty uses the bridge or interface the device was last seen on",
+			},
+		},
+	}
+}
+
+// Validate validates action params against metadata schema.
+func (a *WakeOnLANAction) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	if err := validateMACTarget(target, params); err != nil {
+		return err
+	}
+	if raw := params["interface"]; raw != nil {
+		if _, ok := raw.(string); !ok {
+			return fmt.Errorf("param %q must be string", "interface")
+		}
+	}
+	return nil
+}
+
+// Execute sends magic packet via RouterOS API.
+func (a *WakeOnLANAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+	if execCtx.RouterClient == nil {
+		return fmt.Errorf("router client is not configured")
+	}
+	wolClient, ok := execCtx.RouterClient.(automationdomain.WakeOnLANClient)
+	if !ok {
+		return fmt.Errorf("router client does not support wake-on-lan")
+	}
+
+	mac, err := resolveTargetMAC(params, execCtx)
+	if err != nil {
+		return err
+	}
+	iface := optionalStringParam(params, "interface")
+	if iface == "" && execCtx.Target.Device != nil {
+		iface = execCtx.Target.Device.WakeInterface()
+	}
+	return wolClient.WakeOnLAN(ctx, execCtx.RouterConfig, mac, iface)
+}
+
+// validateMACTarget checks target param of MAC-keyed actions: device.mac or
+// literal_mac.
+func validateMACTarget(target automationdomain.AutomationTarget, params map[string]any) error {
+	targetParam, err := stringParam(params, "target")
+	if err != nil {
+		return err
+	}
+	switch targetParam {
+	case "device.mac":
+		if automationdomain.NormalizeCapabilityScope(target.Scope) == automationdomain.ScopeGlobal {
+			return fmt.Errorf("target %q is not available for global scope", targetParam)
+		}
+	case "literal_mac":
+		mac, err := stringParam(params, "literal_mac")
+		if err != nil {
+			return err
+		}
+		if _, err := net.ParseMAC(mac); err != nil {
+			return fmt.Errorf("invalid literal_mac %q", mac)
+		}
+	default:
+		return fmt.Errorf("unsupported target %q", targetParam)
+	}
+	return nil
+}
+
+// resolveTargetMAC returns device MAC or literal_mac in canonical
+// AA:BB:CC:DD:EE:FF form.
+func resolveTargetMAC(params map[string]any, execCtx automationdomain.ActionExecutionContext) (string, error) {
+	targetParam, _ := stringParam(params, "target")
+	if targetParam != "literal_mac" {
+		return resolveTargetAddress(targetParam, params, execCtx)
+	}
+	mac, err := stringParam(params, "literal_mac")
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(strings.ReplaceAll(mac, "-", ":")), nil
+}
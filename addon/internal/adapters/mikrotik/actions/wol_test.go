@@ -0,0 +1,62 @@
+package actions
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeWakeOnLANClient struct {
+	fakeAddressListClient
+	lastMAC   string
+	lastIface string
+}
+
+func (f *fakeWakeOnLANClient) WakeOnLAN(ctx context.Context, cfg model.RouterConfig, mac, iface string) error {
+	f.lastMAC = mac
+	f.lastIface = iface
+	return nil
+}
+
+func TestWakeOnLANActionInfersInterface(t *testing.T) {
+	action := NewWakeOnLANAction()
+	bridge := "bridge-lan"
+	port := "ether4"
+	client := &fakeWakeOnLANClient{}
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", Bridge: &bridge, Interface: &port}
+	execCtx := automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		RouterClient: client,
+	}
+
+	if err := action.Execute(context.Background(), execCtx, map[string]any{"target": "device.mac"}); err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if client.lastMAC != device.MAC || client.lastIface != bridge {
+		t.Fatalf("unexpected wake call: mac=%q iface=%q", client.lastMAC, client.lastIface)
+	}
+
+	err := action.Execute(context.Background(), execCtx, map[string]any{"target": "device.mac", "interface": "vlan20"})
+	if err != nil {
+		t.Fatalf("Execute with interface returned error: %v", err)
+	}
+	if client.lastIface != "vlan20" {
+		t.Fatalf("expected explicit interface, got %q", client.lastIface)
+	}
+}
+
+func TestWakeOnLANActionValidate(t *testing.T) {
+	action := NewWakeOnLANAction()
+	global := automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal}
+	if err := action.Validate(global, map[string]any{"target": "literal_mac", "literal_mac": "AA:BB:CC:DD:EE:02"}); err != nil {
+		t.Fatalf("expected literal MAC to be valid in global scope: %v", err)
+	}
+	if err := action.Validate(global, map[string]any{"target": "device.mac"}); err == nil {
+		t.Fatalf("expected device.mac to be rejected in global scope")
+	}
+	if err := action.Validate(global, map[string]any{"target": "literal_mac", "literal_mac": "nas"}); err == nil {
+		t.Fatalf("expected invalid MAC to be rejected")
+	}
+}
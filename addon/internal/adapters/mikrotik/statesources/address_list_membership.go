@@ -32,14 +32,15 @@ func (s *AddressListMembershipSource) Metadata() automationdomain.StateSourceMet
 		ID:          StateSourceIDAddressListMembership,
 		Label:       "MikroTik: Address-list membership",
 		Description: "Checks whether target value currently exists in MikroTik firewall address-list",
-		OutputType:  "boolean",
+		OutputType:  automationdomain.StateOutputBoolean,
 		ParamSchema: []automationdomain.ParamField{
 			{
-				Key:         "list",
-				Label:       "Address-list name",
-				Kind:        automationdomain.ParamString,
-				Required:    true,
-				Description: "RouterOS firewall address-list name",
+				Key:             "list",
+				Label:           "Address-list name",
+				Kind:            automationdomain.ParamString,
+				Required:        true,
+				Description:     "RouterOS firewall address-list name",
+				OptionsProvider: automationdomain.OptionsRouterAddressLists,
 			},
 			{
 				Key:         "target",
@@ -124,6 +125,51 @@ func (s *AddressListMembershipSource) Read(
 	return contains, nil
 }
 
+// ReadMany checks all targets against one address-list print when router client supports it.
+func (s *AddressListMembershipSource) ReadMany(
+	ctx context.Context,
+	batchCtx automationdomain.StateSourceBatchContext,
+	params map[string]any,
+) ([]automationdomain.StateReadResult, error) {
+	if batchCtx.RouterClient == nil {
+		return nil, fmt.Errorf("router client is not configured")
+	}
+
+	snapshotClient, ok := batchCtx.RouterClient.(automationdomain.AddressListSnapshotClient)
+	if !ok {
+		return readEach(ctx, s, batchCtx, params), nil
+	}
+
+	listName, err := stringParam(params, "list")
+	if err != nil {
+		return nil, err
+	}
+	entries, err := snapshotClient.ListAddressListAddresses(ctx, batchCtx.RouterConfig, listName)
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		members[automationdomain.NormalizeAddress(entry)] = struct{}{}
+	}
+
+	target, _ := stringParam(params, "target")
+	results := make([]automationdomain.StateReadResult, len(batchCtx.Targets))
+	for i, item := range batchCtx.Targets {
+		if err := s.Validate(item, params); err != nil {
+			results[i].Err = err
+			continue
+		}
+		address, err := resolveTargetAddress(target, params, automationdomain.StateSourceContext{Target: item})
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		_, results[i].Value = members[automationdomain.NormalizeAddress(address)]
+	}
+	return results, nil
+}
+
 func resolveTargetAddress(
 	target string,
 	params map[string]any,
@@ -151,6 +197,25 @@ func resolveTargetAddress(
 	}
 }
 
+// readEach falls back to per-target reads for clients without batch support.
+func readEach(
+	ctx context.Context,
+	source automationdomain.StateSource,
+	batchCtx automationdomain.StateSourceBatchContext,
+	params map[string]any,
+) []automationdomain.StateReadResult {
+	results := make([]automationdomain.StateReadResult, len(batchCtx.Targets))
+	for i, item := range batchCtx.Targets {
+		results[i].Value, results[i].Err = source.Read(ctx, automationdomain.StateSourceContext{
+			Target:       item,
+			RouterClient: batchCtx.RouterClient,
+			RouterConfig: batchCtx.RouterConfig,
+			Logger:       batchCtx.Logger,
+		}, params)
+	}
+	return results
+}
+
 func containsDevicePlaceholder(raw string) bool {
 	return strings.Contains(strings.ToLower(raw), "{{device.")
 }
@@ -96,3 +96,59 @@ func TestAddressListMembershipSourceValidateRejectsDeviceTargetForGlobalScope(t
 		t.Fatalf("expected validation error")
 	}
 }
+
+type fakeSnapshotStateClient struct {
+	fakeStateClient
+	listCalls int
+	entries   []string
+}
+
+func (f *fakeSnapshotStateClient) ListAddressListAddresses(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+) ([]string, error) {
+	f.listCalls++
+	return f.entries, nil
+}
+
+func TestAddressListMembershipSourceReadManyUsesOnePrint(t *testing.T) {
+	source := NewAddressListMembershipSource()
+	inIP := "192.168.88.15"
+	outIP := "192.168.88.16"
+	inDevice := model.DeviceView{MAC: "AA:BB:CC:DD:EE:02", LastIP: &inIP}
+	outDevice := model.DeviceView{MAC: "AA:BB:CC:DD:EE:03", LastIP: &outIP}
+	noIPDevice := model.DeviceView{MAC: "AA:BB:CC:DD:EE:04"}
+	client := &fakeSnapshotStateClient{entries: []string{"192.168.88.15/32"}}
+
+	results, err := source.ReadMany(context.Background(), automationdomain.StateSourceBatchContext{
+		Targets: []automationdomain.AutomationTarget{
+			{Scope: automationdomain.ScopeDevice, Device: &inDevice},
+			{Scope: automationdomain.ScopeDevice, Device: &outDevice},
+			{Scope: automationdomain.ScopeDevice, Device: &noIPDevice},
+		},
+		RouterClient: client,
+		RouterConfig: model.RouterConfig{Host: "router.local"},
+	}, map[string]any{
+		"list":   "VPN_CLIENTS",
+		"target": "device.ip",
+	})
+	if err != nil {
+		t.Fatalf("ReadMany returned error: %v", err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].Value != true {
+		t.Fatalf("unexpected first result: %+v", results[0])
+	}
+	if results[1].Err != nil || results[1].Value != false {
+		t.Fatalf("unexpected second result: %+v", results[1])
+	}
+	if results[2].Err == nil {
+		t.Fatalf("expected error for device without IP")
+	}
+	if client.listCalls != 1 || client.calls != 0 {
+		t.Fatalf("expected one list print and no per-target lookups, got list=%d lookups=%d", client.listCalls, client.calls)
+	}
+}
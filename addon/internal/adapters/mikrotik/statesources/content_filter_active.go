@@ -0,0 +1,100 @@
+package statesources
+
+import (
+	"context"
+	"fmt"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+const (
+	// StateSourceIDContentFilterActive reads whether domain blocking rules apply to target.
+	StateSourceIDContentFilterActive = "mikrotik.content_filter.active"
+)
+
+// ContentFilterActiveSource checks if enabled tls-host block rules match target address.
+type ContentFilterActiveSource struct{}
+
+// NewContentFilterActiveSource creates state-source implementation.
+func NewContentFilterActiveSource() *ContentFilterActiveSource {
+	return &ContentFilterActiveSource{}
+}
+
+// ID returns unique state-source identifier.
+func (s *ContentFilterActiveSource) ID() string {
+	return StateSourceIDContentFilterActive
+}
+
+// Metadata returns state-source descriptor for UI.
+func (s *ContentFilterActiveSource) Metadata() automationdomain.StateSourceMetadata {
+	return automationdomain.StateSourceMetadata{
+		ID:          StateSourceIDContentFilterActive,
+		Label:       "MikroTik: Website blocking active",
+		Description: "Checks whether enabled tls-host reject rules exist for the source address",
+		OutputType:  automationdomain.StateOutputBoolean,
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "target",
+				Label:       "Target",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"device.ip", "literal_ip"},
+				Description: "Address looked up in filter rule sources",
+			},
+			{
+				Key:       "literal_ip",
+				Label:     "Literal IP",
+				Kind:      automationdomain.ParamString,
+				Required:  true,
+				VisibleIf: &automationdomain.VisibleIfCondition{Key: "target", Equals: "literal_ip"},
+			},
+		},
+	}
+}
+
+// Validate validates state-source params against schema.
+func (s *ContentFilterActiveSource) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	return automationdomain.ValidateIPTarget(target, params)
+}
+
+// Read checks target address against filtered rule sources.
+func (s *ContentFilterActiveSource) Read(
+	ctx context.Context,
+	sourceCtx automationdomain.StateSourceContext,
+	params map[string]any,
+) (any, error) {
+	results, err := s.ReadMany(ctx, automationdomain.StateSourceBatchContext{
+		Targets:      []automationdomain.AutomationTarget{sourceCtx.Target},
+		RouterClient: sourceCtx.RouterClient,
+		RouterConfig: sourceCtx.RouterConfig,
+		Logger:       sourceCtx.Logger,
+	}, params)
+	if err != nil {
+		return nil, err
+	}
+	return results[0].Value, results[0].Err
+}
+
+// ReadMany answers all targets from one firewall filter print.
+func (s *ContentFilterActiveSource) ReadMany(
+	ctx context.Context,
+	batchCtx automationdomain.StateSourceBatchContext,
+	params map[string]any,
+) ([]automationdomain.StateReadResult, error) {
+	if batchCtx.RouterClient == nil {
+		return nil, fmt.Errorf("router client is not configured")
+	}
+	filterClient, ok := batchCtx.RouterClient.(automationdomain.ContentFilterStateClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support content filters")
+	}
+
+	targets, err := filterClient.ListContentFilteredTargets(ctx, batchCtx.RouterConfig)
+	if err != nil {
+		return nil, err
+	}
+	return matchAddressTargets(s, batchCtx, params, targets), nil
+}
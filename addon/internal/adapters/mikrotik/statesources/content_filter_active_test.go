@@ -0,0 +1,33 @@
+package statesources
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeContentFilterStateClient struct {
+	fakeStateClient
+	targets []string
+}
+
+func (f *fakeContentFilterStateClient) ListContentFilteredTargets(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	return f.targets, nil
+}
+
+func TestContentFilterActiveSourceRead(t *testing.T) {
+	source := NewContentFilterActiveSource()
+	ip := "192.168.88.10"
+	value, err := source.Read(context.Background(), automationdomain.StateSourceContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &model.DeviceView{LastIP: &ip}},
+		RouterClient: &fakeContentFilterStateClient{targets: []string{ip}},
+	}, map[string]any{"target": "device.ip"})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if value != true {
+		t.Fatalf("expected active filter, got %v", value)
+	}
+}
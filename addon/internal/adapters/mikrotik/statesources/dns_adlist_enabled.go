@@ -0,0 +1,80 @@
+package statesources
+
+import (
+	"context"
+	"fmt"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+const (
+	// StateSourceIDDNSAdlistEnabled reads enabled state of DNS adlists.
+	StateSourceIDDNSAdlistEnabled = "mikrotik.dns.adlist.enabled"
+)
+
+// DNSAdlistEnabledSource reads whether router-wide DNS adlists are enabled.
+type DNSAdlistEnabledSource struct{}
+
+// NewDNSAdlistEnabledSource creates state-source implementation.
+func NewDNSAdlistEnabledSource() *DNSAdlistEnabledSource {
+	return &DNSAdlistEnabledSource{}
+}
+
+// ID returns unique state-source identifier.
+func (s *DNSAdlistEnabledSource) ID() string {
+	return StateSourceIDDNSAdlistEnabled
+}
+
+// Metadata returns state-source descriptor for UI.
+func (s *DNSAdlistEnabledSource) Metadata() automationdomain.StateSourceMetadata {
+	return automationdomain.StateSourceMetadata{
+		ID:          StateSourceIDDNSAdlistEnabled,
+		Label:       "MikroTik: DNS adlist enabled",
+		Description: "Checks whether DNS adlists are enabled; global scope only, adlists apply to the whole router",
+		OutputType:  automationdomain.StateOutputBoolean,
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:             "adlist",
+				Label:           "Adlist",
+				Kind:            automationdomain.ParamString,
+				OptionsProvider: automationdomain.OptionsRouterDNSAdlists,
+				Description:     "URL or file of one adlist; empty requires every adlist to be enabled",
+			},
+		},
+	}
+}
+
+// Validate validates state-source params against schema.
+func (s *DNSAdlistEnabledSource) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	if automationdomain.NormalizeCapabilityScope(target.Scope) != automationdomain.ScopeGlobal {
+		return fmt.Errorf("state source %q is only available for global scope", StateSourceIDDNSAdlistEnabled)
+	}
+	if raw := params["adlist"]; raw != nil {
+		if _, ok := raw.(string); !ok {
+			return fmt.Errorf("param %q must be string", "adlist")
+		}
+	}
+	return nil
+}
+
+// Read reports enabled state of selected adlists.
+func (s *DNSAdlistEnabledSource) Read(
+	ctx context.Context,
+	sourceCtx automationdomain.StateSourceContext,
+	params map[string]any,
+) (any, error) {
+	if err := s.Validate(sourceCtx.Target, params); err != nil {
+		return nil, err
+	}
+	if sourceCtx.RouterClient == nil {
+		return nil, fmt.Errorf("router client is not configured")
+	}
+	adlistClient, ok := sourceCtx.RouterClient.(automationdomain.DNSAdlistStateClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support dns adlists")
+	}
+	return adlistClient.GetDNSAdlistsEnabled(ctx, sourceCtx.RouterConfig, optionalStringParam(params, "adlist"))
+}
@@ -0,0 +1,40 @@
+package statesources
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeDNSAdlistStateClient struct {
+	fakeStateClient
+	enabled    bool
+	lastSource string
+}
+
+func (f *fakeDNSAdlistStateClient) GetDNSAdlistsEnabled(ctx context.Context, cfg model.RouterConfig, source string) (bool, error) {
+	f.lastSource = source
+	return f.enabled, nil
+}
+
+func TestDNSAdlistEnabledSourceRead(t *testing.T) {
+	source := NewDNSAdlistEnabledSource()
+	client := &fakeDNSAdlistStateClient{enabled: true}
+	value, err := source.Read(context.Background(), automationdomain.StateSourceContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal},
+		RouterClient: client,
+	}, map[string]any{"adlist": " https://example.com/hosts.txt "})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if value != true || client.lastSource != "https://example.com/hosts.txt" {
+		t.Fatalf("unexpected read: value=%v source=%q", value, client.lastSource)
+	}
+
+	err = source.Validate(automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice}, map[string]any{})
+	if err == nil {
+		t.Fatalf("expected device scope to be rejected")
+	}
+}
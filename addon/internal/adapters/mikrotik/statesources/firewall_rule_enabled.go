@@ -31,7 +31,7 @@ func (s *FirewallRuleEnabledSource) Metadata() automationdomain.StateSourceMetad
 		ID:          StateSourceIDFirewallRuleEnabled,
 		Label:       "MikroTik: Firewall rule enabled",
 		Description: "Checks whether firewall rule is currently enabled in filter/nat/mangle/raw tables",
-		OutputType:  "boolean",
+		OutputType:  automationdomain.StateOutputBoolean,
 		ParamSchema: []automationdomain.ParamField{
 			{
 				Key:         "table",
@@ -50,18 +50,22 @@ func (s *FirewallRuleEnabledSource) Metadata() automationdomain.StateSourceMetad
 				Description: "Choose whether to read one rule id or all rules by comment",
 			},
 			{
-				Key:       "rule_id",
-				Label:     "Rule id",
-				Kind:      automationdomain.ParamString,
-				Required:  true,
-				VisibleIf: &automationdomain.VisibleIfCondition{Key: "match_by", Equals: "id"},
+				Key:             "rule_id",
+				Label:           "Rule id",
+				Kind:            automationdomain.ParamString,
+				Required:        true,
+				OptionsProvider: automationdomain.OptionsRouterFirewallRules,
+				OptionsArgKey:   "table",
+				VisibleIf:       &automationdomain.VisibleIfCondition{Key: "match_by", Equals: "id"},
 			},
 			{
-				Key:       "comment",
-				Label:     "Rule comment",
-				Kind:      automationdomain.ParamString,
-				Required:  true,
-				VisibleIf: &automationdomain.VisibleIfCondition{Key: "match_by", Equals: "comment"},
+				Key:             "comment",
+				Label:           "Rule comment",
+				Kind:            automationdomain.ParamString,
+				Required:        true,
+				OptionsProvider: automationdomain.OptionsRouterFirewallComments,
+				OptionsArgKey:   "table",
+				VisibleIf:       &automationdomain.VisibleIfCondition{Key: "match_by", Equals: "comment"},
 			},
 		},
 	}
@@ -148,3 +152,35 @@ func (s *FirewallRuleEnabledSource) Read(
 		return nil, fmt.Errorf("unsupported match_by %q", matchBy)
 	}
 }
+
+// ReadMany reads rule state once and shares it across all targets.
+func (s *FirewallRuleEnabledSource) ReadMany(
+	ctx context.Context,
+	batchCtx automationdomain.StateSourceBatchContext,
+	params map[string]any,
+) ([]automationdomain.StateReadResult, error) {
+	results := make([]automationdomain.StateReadResult, len(batchCtx.Targets))
+	var (
+		value   any
+		readErr error
+		read    bool
+	)
+	for i, item := range batchCtx.Targets {
+		if err := s.Validate(item, params); err != nil {
+			results[i].Err = err
+			continue
+		}
+		// Rule params do not depend on target, so first valid target answers for all.
+		if !read {
+			value, readErr = s.Read(ctx, automationdomain.StateSourceContext{
+				Target:       item,
+				RouterClient: batchCtx.RouterClient,
+				RouterConfig: batchCtx.RouterConfig,
+				Logger:       batchCtx.Logger,
+			}, params)
+			read = true
+		}
+		results[i] = automationdomain.StateReadResult{Value: value, Err: readErr}
+	}
+	return results, nil
+}
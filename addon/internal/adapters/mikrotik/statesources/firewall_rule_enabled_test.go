@@ -118,3 +118,37 @@ func TestFirewallRuleEnabledSourceValidateRejectsGlobalDevicePlaceholder(t *test
 		t.Fatalf("expected validation error")
 	}
 }
+
+func TestFirewallRuleEnabledSourceReadManySharesOneRead(t *testing.T) {
+	source := NewFirewallRuleEnabledSource()
+	client := &fakeFirewallRuleStateClient{enabledByComment: true}
+	first := model.DeviceView{MAC: "AA:BB:CC:DD:EE:02"}
+	second := model.DeviceView{MAC: "AA:BB:CC:DD:EE:03"}
+
+	results, err := source.ReadMany(context.Background(), automationdomain.StateSourceBatchContext{
+		Targets: []automationdomain.AutomationTarget{
+			{Scope: automationdomain.ScopeDevice, Device: &first},
+			{Scope: automationdomain.ScopeDevice, Device: &second},
+		},
+		RouterClient: client,
+		RouterConfig: model.RouterConfig{Host: "router.local"},
+	}, map[string]any{
+		"table":    "filter",
+		"match_by": "comment",
+		"comment":  "kids",
+	})
+	if err != nil {
+		t.Fatalf("ReadMany returned error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	for i, result := range results {
+		if result.Err != nil || result.Value != true {
+			t.Fatalf("unexpected result %d: %+v", i, result)
+		}
+	}
+	if client.commentCalls != 1 {
+		t.Fatalf("expected one router read, got %d", client.commentCalls)
+	}
+}
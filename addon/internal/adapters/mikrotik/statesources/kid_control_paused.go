@@ -0,0 +1,112 @@
+package statesources
+
+import (
+	"context"
+	"fmt"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+const (
+	// StateSourceIDKidControlPaused reads paused state of kid-control profile.
+	StateSourceIDKidControlPaused = "mikrotik.kid_control.paused"
+)
+
+// KidControlPausedSource reads whether kid-control profile is paused.
+type KidControlPausedSource struct{}
+
+// NewKidControlPausedSource creates state-source implementation.
+func NewKidControlPausedSource() *KidControlPausedSource {
+	return &KidControlPausedSource{}
+}
+
+// ID returns unique state-source identifier.
+func (s *KidControlPausedSource) ID() string {
+	return StateSourceIDKidControlPaused
+}
+
+// Metadata returns state-source descriptor for UI.
+func (s *KidControlPausedSource) Metadata() automationdomain.StateSourceMetadata {
+	return automationdomain.StateSourceMetadata{
+		ID:          StateSourceIDKidControlPaused,
+		Label:       "MikroTik: Kid-control paused",
+		Description: "Checks whether a RouterOS kid-control profile is paused",
+		OutputType:  automationdomain.StateOutputBoolean,
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "profile_source",
+				Label:       "Profile",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"profile", "device"},
+				Description: "Named profile, or the profile the device MAC is bound to on the router",
+			},
+			{
+				Key:             "profile",
+				Label:           "Profile name",
+				Kind:            automationdomain.ParamString,
+				Required:        true,
+				OptionsProvider: automationdomain.OptionsRouterKidControlProfiles,
+				VisibleIf:       &automationdomain.VisibleIfCondition{Key: "profile_source", Equals: "profile"},
+			},
+		},
+	}
+}
+
+// Validate validates state-source params against schema.
+func (s *KidControlPausedSource) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	source, err := stringParam(params, "profile_source")
+	if err != nil {
+		return err
+	}
+	switch source {
+	case "profile":
+		_, err := stringParam(params, "profile")
+		return err
+	case "device":
+		if automationdomain.NormalizeCapabilityScope(target.Scope) == automationdomain.ScopeGlobal {
+			return fmt.Errorf("profile_source %q is not available for global scope", source)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported profile_source %q", source)
+	}
+}
+
+// Read reports paused state of resolved profile.
+func (s *KidControlPausedSource) Read(
+	ctx context.Context,
+	sourceCtx automationdomain.StateSourceContext,
+	params map[string]any,
+) (any, error) {
+	if err := s.Validate(sourceCtx.Target, params); err != nil {
+		return nil, err
+	}
+	if sourceCtx.RouterClient == nil {
+		return nil, fmt.Errorf("router client is not configured")
+	}
+	kidClient, ok := sourceCtx.RouterClient.(automationdomain.KidControlStateClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support kid-control")
+	}
+
+	profile, _ := stringParam(params, "profile")
+	if source, _ := stringParam(params, "profile_source"); source == "device" {
+		device := sourceCtx.Target.Device
+		if device == nil || device.MAC == "" {
+			return nil, fmt.Errorf("device MAC is empty")
+		}
+		bound, err := kidClient.KidControlProfileForMAC(ctx, sourceCtx.RouterConfig, device.MAC)
+		if err != nil {
+			return nil, err
+		}
+		if bound == "" {
+			return nil, fmt.Errorf("device %s is not bound to a kid-control profile", device.MAC)
+		}
+		profile = bound
+	}
+	return kidClient.GetKidControlPaused(ctx, sourceCtx.RouterConfig, profile)
+}
@@ -0,0 +1,55 @@
+package statesources
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeKidControlStateClient struct {
+	fakeStateClient
+	bindings map[string]string
+	paused   map[string]bool
+}
+
+func (f *fakeKidControlStateClient) GetKidControlPaused(ctx context.Context, cfg model.RouterConfig, profile string) (bool, error) {
+	return f.paused[profile], nil
+}
+
+func (f *fakeKidControlStateClient) KidControlProfileForMAC(ctx context.Context, cfg model.RouterConfig, mac string) (string, error) {
+	return f.bindings[mac], nil
+}
+
+func TestKidControlPausedSourceReadsDeviceProfile(t *testing.T) {
+	source := NewKidControlPausedSource()
+	client := &fakeKidControlStateClient{
+		bindings: map[string]string{"AA:BB:CC:DD:EE:01": "kids"},
+		paused:   map[string]bool{"kids": true},
+	}
+	value, err := source.Read(context.Background(), automationdomain.StateSourceContext{
+		Target: automationdomain.AutomationTarget{
+			Scope:  automationdomain.ScopeDevice,
+			Device: &model.DeviceView{MAC: "AA:BB:CC:DD:EE:01"},
+		},
+		RouterClient: client,
+	}, map[string]any{"profile_source": "device"})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if value != true {
+		t.Fatalf("expected paused profile, got %v", value)
+	}
+
+	value, err = source.Read(context.Background(), automationdomain.StateSourceContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal},
+		RouterClient: client,
+	}, map[string]any{"profile_source": "profile", "profile": "guests"})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if value != false {
+		t.Fatalf("expected unpaused profile, got %v", value)
+	}
+}
@@ -0,0 +1,129 @@
+package statesources
+
+import (
+	"context"
+	"fmt"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+const (
+	// StateSourceIDQueueLimitActive reads whether simple queue limits target.
+	StateSourceIDQueueLimitActive = "mikrotik.queue.limit_active"
+)
+
+// QueueLimitActiveSource checks if an enabled simple queue caps target address.
+type QueueLimitActiveSource struct{}
+
+// NewQueueLimitActiveSource creates state-source implementation.
+func NewQueueLimitActiveSource() *QueueLimitActiveSource {
+	return &QueueLimitActiveSource{}
+}
+
+// ID returns unique state-source identifier.
+func (s *QueueLimitActiveSource) ID() string {
+	return StateSourceIDQueueLimitActive
+}
+
+// Metadata returns state-source descriptor for UI.
+func (s *QueueLimitActiveSource) Metadata() automationdomain.StateSourceMetadata {
+	return automationdomain.StateSourceMetadata{
+		ID:          StateSourceIDQueueLimitActive,
+		Label:       "MikroTik: Bandwidth limit active",
+		Description: "Checks whether an enabled simple queue with max-limit targets the address",
+		OutputType:  automationdomain.StateOutputBoolean,
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "target",
+				Label:       "Target",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"device.ip", "literal_ip"},
+				Description: "Address looked up in simple queue targets",
+			},
+			{
+				Key:       "literal_ip",
+				Label:     "Literal IP",
+				Kind:      automationdomain.ParamString,
+				Required:  true,
+				VisibleIf: &automationdomain.VisibleIfCondition{Key: "target", Equals: "literal_ip"},
+			},
+		},
+	}
+}
+
+// Validate validates state-source params against schema.
+func (s *QueueLimitActiveSource) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	return automationdomain.ValidateIPTarget(target, params)
+}
+
+// Read checks target address against limited simple queue targets.
+func (s *QueueLimitActiveSource) Read(
+	ctx context.Context,
+	sourceCtx automationdomain.StateSourceContext,
+	params map[string]any,
+) (any, error) {
+	results, err := s.ReadMany(ctx, automationdomain.StateSourceBatchContext{
+		Targets:      []automationdomain.AutomationTarget{sourceCtx.Target},
+		RouterClient: sourceCtx.RouterClient,
+		RouterConfig: sourceCtx.RouterConfig,
+		Logger:       sourceCtx.Logger,
+	}, params)
+	if err != nil {
+		return nil, err
+	}
+	return results[0].Value, results[0].Err
+}
+
+// ReadMany answers all targets from one simple queue print.
+func (s *QueueLimitActiveSource) ReadMany(
+	ctx context.Context,
+	batchCtx automationdomain.StateSourceBatchContext,
+	params map[string]any,
+) ([]automationdomain.StateReadResult, error) {
+	if batchCtx.RouterClient == nil {
+		return nil, fmt.Errorf("router client is not configured")
+	}
+	queueClient, ok := batchCtx.RouterClient.(automationdomain.SimpleQueueStateClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support simple queues")
+	}
+
+	targets, err := queueClient.ListLimitedQueueTargets(ctx, batchCtx.RouterConfig)
+	if err != nil {
+		return nil, err
+	}
+	return matchAddressTargets(s, batchCtx, params, targets), nil
+}
+
+// matchAddressTargets reports for each target whether its address is in addresses.
+func matchAddressTargets(
+	source automationdomain.StateSource,
+	batchCtx automationdomain.StateSourceBatchContext,
+	params map[string]any,
+	addresses []string,
+) []automationdomain.StateReadResult {
+	set := make(map[string]struct{}, len(addresses))
+	for _, address := range addresses {
+		set[automationdomain.NormalizeAddress(address)] = struct{}{}
+	}
+
+	target, _ := stringParam(params, "target")
+	results := make([]automationdomain.StateReadResult, len(batchCtx.Targets))
+	for i, item := range batchCtx.Targets {
+		if err := source.Validate(item, params); err != nil {
+			results[i].Err = err
+			continue
+		}
+		address, err := resolveTargetAddress(target, params, automationdomain.StateSourceContext{Target: item})
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		_, results[i].Value = set[automationdomain.NormalizeAddress(address)]
+	}
+	return results
+}
@@ -0,0 +1,60 @@
+package statesources
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeQueueStateClient struct {
+	fakeStateClient
+	targets []string
+	lists   int
+}
+
+func (f *fakeQueueStateClient) ListLimitedQueueTargets(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	f.lists++
+	return f.targets, nil
+}
+
+func TestQueueLimitActiveSourceReadManyUsesOnePrint(t *testing.T) {
+	source := NewQueueLimitActiveSource()
+	limitedIP := "192.168.88.10"
+	freeIP := "192.168.88.11"
+	client := &fakeQueueStateClient{targets: []string{"192.168.88.10/32"}}
+
+	results, err := source.ReadMany(context.Background(), automationdomain.StateSourceBatchContext{
+		Targets: []automationdomain.AutomationTarget{
+			{Scope: automationdomain.ScopeDevice, Device: &model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", LastIP: &limitedIP}},
+			{Scope: automationdomain.ScopeDevice, Device: &model.DeviceView{MAC: "AA:BB:CC:DD:EE:02", LastIP: &freeIP}},
+			{Scope: automationdomain.ScopeDevice, Device: &model.DeviceView{MAC: "AA:BB:CC:DD:EE:03"}},
+		},
+		RouterClient: client,
+	}, map[string]any{"target": "device.ip"})
+	if err != nil {
+		t.Fatalf("ReadMany returned error: %v", err)
+	}
+	if client.lists != 1 {
+		t.Fatalf("expected one queue list, got %d", client.lists)
+	}
+	if results[0].Value != true || results[1].Value != false {
+		t.Fatalf("unexpected values: %+v", results)
+	}
+	if results[2].Err == nil {
+		t.Fatalf("expected error for device without IP")
+	}
+}
+
+func TestQueueLimitActiveSourceRejectsClientWithoutQueues(t *testing.T) {
+	source := NewQueueLimitActiveSource()
+	ip := "192.168.88.10"
+	_, err := source.Read(context.Background(), automationdomain.StateSourceContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &model.DeviceView{LastIP: &ip}},
+		RouterClient: &fakeStateClient{},
+	}, map[string]any{"target": "device.ip"})
+	if err == nil {
+		t.Fatalf("expected unsupported client error")
+	}
+}
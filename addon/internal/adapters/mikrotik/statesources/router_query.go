@@ -0,0 +1,243 @@
+package statesources
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+const (
+	// StateSourceIDRouterQuery reads state from allowlisted RouterOS print query.
+	StateSourceIDRouterQuery = "mikrotik.query"
+
+	queryResultAnyRow       = "any_row"
+	queryResultCountAtLeast = "count_at_least"
+	queryResultFieldEquals  = "field_equals"
+	queryResultFieldValue   = "field_value"
+	queryVerbPrint          = "print"
+	defaultQueryMinCount    = 1
+)
+
+var queryFieldPattern = regexp.MustCompile(`^\.?[a-z0-9][a-z0-9-]*$`)
+
+// RouterQuerySource runs print on allowlisted RouterOS path and maps rows to state.
+type RouterQuerySource struct {
+	allowlist automationdomain.CommandAllowlist
+}
+
+// NewRouterQuerySource creates generic RouterOS query state-source.
+func NewRouterQuerySource(allowlist automationdomain.CommandAllowlist) *RouterQuerySource {
+	return &RouterQuerySource{allowlist: allowlist}
+}
+
+// ID returns unique state-source identifier.
+func (s *RouterQuerySource) ID() string {
+	return StateSourceIDRouterQuery
+}
+
+// Metadata returns state-source descriptor for UI.
+func (s *RouterQuerySource) Metadata() automationdomain.StateSourceMetadata {
+	return automationdomain.StateSourceMetadata{
+		ID:          StateSourceIDRouterQuery,
+		Label:       "MikroTik: RouterOS query",
+		Description: "Runs print on allowlisted menu path and maps matching rows to boolean or state value",
+		OutputType:  automationdomain.StateOutputBooleanOrString,
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "path",
+				Label:       "Menu path",
+				Kind:        automationdomain.ParamString,
+				Required:    true,
+				Description: "RouterOS menu path, for example /queue/simple or /tool/netwatch",
+			},
+			{
+				Key:         "where",
+				Label:       "Where",
+				Kind:        automationdomain.ParamString,
+				Description: "One `name=value` filter per line, supports {{device.*}} placeholders",
+			},
+			{
+				Key:      "result",
+				Label:    "Result",
+				Kind:     automationdomain.ParamEnum,
+				Required: true,
+				Options:  []string{queryResultAnyRow, queryResultCountAtLeast, queryResultFieldEquals, queryResultFieldValue},
+				Description: "any_row/count_at_least/field_equals return boolean, " +
+					"field_value returns first row field as capability state",
+			},
+			{
+				Key:       "min_count",
+				Label:     "Minimum rows",
+				Kind:      automationdomain.ParamInt,
+				Min:       automationdomain.IntBound(1),
+				VisibleIf: &automationdomain.VisibleIfCondition{Key: "result", Equals: queryResultCountAtLeast},
+			},
+			{
+				Key:         "field",
+				Label:       "Field",
+				Kind:        automationdomain.ParamString,
+				Description: "Row field for field_equals and field_value, for example disabled or status",
+			},
+			{
+				Key:       "value",
+				Label:     "Expected value",
+				Kind:      automationdomain.ParamString,
+				VisibleIf: &automationdomain.VisibleIfCondition{Key: "result", Equals: queryResultFieldEquals},
+			},
+		},
+	}
+}
+
+// Validate validates state-source params against schema and allowlist.
+func (s *RouterQuerySource) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	path, err := stringParam(params, "path")
+	if err != nil {
+		return err
+	}
+	path = strings.ToLower(path)
+	if _, _, err := automationdomain.SplitCommandPath(path + "/" + queryVerbPrint); err != nil {
+		return err
+	}
+	if !s.allowlist.Allows(path, queryVerbPrint) {
+		return fmt.Errorf("query %s/print is not in router command allowlist", path)
+	}
+
+	where := optionalStringParam(params, "where")
+	if _, err := parseWhere(where); err != nil {
+		return err
+	}
+
+	result, err := stringParam(params, "result")
+	if err != nil {
+		return err
+	}
+	switch result {
+	case queryResultAnyRow:
+	case queryResultCountAtLeast:
+		if _, err := parseMinCount(optionalStringParam(params, "min_count")); err != nil {
+			return err
+		}
+	case queryResultFieldEquals, queryResultFieldValue:
+		field, err := stringParam(params, "field")
+		if err != nil {
+			return err
+		}
+		if !queryFieldPattern.MatchString(strings.ToLower(field)) {
+			return fmt.Errorf("invalid field %q", field)
+		}
+	default:
+		return fmt.Errorf("unsupported result %q", result)
+	}
+
+	if automationdomain.NormalizeCapabilityScope(target.Scope) == automationdomain.ScopeGlobal && containsDevicePlaceholder(where) {
+		return fmt.Errorf("global scope does not support device placeholders")
+	}
+	return nil
+}
+
+// Read runs print query and maps rows using result mode.
+func (s *RouterQuerySource) Read(
+	ctx context.Context,
+	sourceCtx automationdomain.StateSourceContext,
+	params map[string]any,
+) (any, error) {
+	if err := s.Validate(sourceCtx.Target, params); err != nil {
+		return nil, err
+	}
+	client, ok := sourceCtx.RouterClient.(automationdomain.RouterCommandClient)
+	if !ok || client == nil {
+		return nil, fmt.Errorf("router client does not support raw queries")
+	}
+
+	path, _ := stringParam(params, "path")
+	result, _ := stringParam(params, "result")
+	field := strings.ToLower(optionalStringParam(params, "field"))
+
+	vars := automationdomain.TemplateVars(automationdomain.ActionExecutionContext{Target: sourceCtx.Target})
+	// Filter words are parsed from raw template and rendered per value, so
+	// device fields cannot add or replace query words.
+	rawWhere, _ := parseWhere(optionalStringParam(params, "where"))
+	where, err := automationdomain.RenderWords(rawWhere, vars)
+	if err != nil {
+		return nil, err
+	}
+	query := make(map[string]string, len(where))
+	for key, value := range where {
+		query["?"+key] = value
+	}
+
+	rows, err := client.RunCommand(ctx, sourceCtx.RouterConfig, strings.ToLower(path)+"/"+queryVerbPrint, query)
+	if err != nil {
+		return nil, fmt.Errorf("query %s: %w", path, err)
+	}
+
+	switch result {
+	case queryResultAnyRow:
+		return len(rows) > 0, nil
+	case queryResultCountAtLeast:
+		minCount, _ := parseMinCount(optionalStringParam(params, "min_count"))
+		return len(rows) >= minCount, nil
+	case queryResultFieldEquals:
+		expected := optionalStringParam(params, "value")
+		for _, row := range rows {
+			if strings.EqualFold(strings.TrimSpace(row[field]), expected) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case queryResultFieldValue:
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("query %s returned no rows", path)
+		}
+		return strings.TrimSpace(rows[0][field]), nil
+	default:
+		return nil, fmt.Errorf("unsupported result %q", result)
+	}
+}
+
+// parseWhere parses `name=value` filter lines.
+func parseWhere(raw string) (map[string]string, error) {
+	where := map[string]string{}
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !ok || !queryFieldPattern.MatchString(key) {
+			return nil, fmt.Errorf("invalid where line %q", line)
+		}
+		where[key] = strings.TrimSpace(value)
+	}
+	return where, nil
+}
+
+func parseMinCount(raw string) (int, error) {
+	if raw == "" {
+		return defaultQueryMinCount, nil
+	}
+	count, err := strconv.Atoi(raw)
+	if err != nil || count < 1 {
+		return 0, fmt.Errorf("min_count must be positive integer")
+	}
+	return count, nil
+}
+
+// optionalStringParam reads string param; JSON numbers from int fields are formatted.
+func optionalStringParam(params map[string]any, key string) string {
+	switch value := params[key].(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
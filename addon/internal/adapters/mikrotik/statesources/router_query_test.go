@@ -0,0 +1,130 @@
+package statesources
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeQueryStateClient struct {
+	fakeStateClient
+	rows      []map[string]string
+	lastPath  string
+	lastQuery map[string]string
+}
+
+func (f *fakeQueryStateClient) RunCommand(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	path string,
+	params map[string]string,
+) ([]map[string]string, error) {
+	f.lastPath = path
+	f.lastQuery = params
+	return f.rows, nil
+}
+
+func TestRouterQuerySourceReadMapsRows(t *testing.T) {
+	allowlist, err := automationdomain.ParseCommandAllowlist([]string{"/queue/simple:print"})
+	if err != nil {
+		t.Fatalf("ParseCommandAllowlist returned error: %v", err)
+	}
+	source := NewRouterQuerySource(allowlist)
+	ip := "192.168.88.30"
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", LastIP: &ip}
+	client := &fakeQueryStateClient{rows: []map[string]string{
+		{".id": "*1", "disabled": "false", "comment": "limited"},
+		{".id": "*2", "disabled": "true", "comment": "paused"},
+	}}
+	sourceCtx := automationdomain.StateSourceContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		RouterClient: client,
+	}
+
+	cases := []struct {
+		params map[string]any
+		want   any
+	}{
+		{map[string]any{"result": "any_row"}, true},
+		{map[string]any{"result": "count_at_least", "min_count": "3"}, false},
+		{map[string]any{"result": "field_equals", "field": "disabled", "value": "true"}, true},
+		{map[string]any{"result": "field_value", "field": "comment"}, "limited"},
+	}
+	for _, tc := range cases {
+		tc.params["path"] = "/queue/simple"
+		tc.params["where"] = "target={{device.ip}}/32"
+		got, err := source.Read(context.Background(), sourceCtx, tc.params)
+		if err != nil {
+			t.Fatalf("%v: Read returned error: %v", tc.params["result"], err)
+		}
+		if got != tc.want {
+			t.Fatalf("%v: got %v, want %v", tc.params["result"], got, tc.want)
+		}
+	}
+	if client.lastPath != "/queue/simple/print" || client.lastQuery["?target"] != "192.168.88.30/32" {
+		t.Fatalf("unexpected query %s %+v", client.lastPath, client.lastQuery)
+	}
+}
+
+func TestRouterQuerySourceRendersWhereValuesWithoutInjection(t *testing.T) {
+	allowlist, err := automationdomain.ParseCommandAllowlist([]string{"/queue/simple:print"})
+	if err != nil {
+		t.Fatalf("ParseCommandAllowlist returned error: %v", err)
+	}
+	source := NewRouterQuerySource(allowlist)
+	params := map[string]any{"path": "/queue/simple", "result": "any_row", "where": "name={{device.name}}"}
+
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", Name: "tv ?disabled=true"}
+	client := &fakeQueryStateClient{}
+	if _, err := source.Read(context.Background(), automationdomain.StateSourceContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		RouterClient: client,
+	}, params); err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if len(client.lastQuery) != 1 || client.lastQuery["?name"] != "tv ?disabled=true" {
+		t.Fatalf("expected single name query, got %+v", client.lastQuery)
+	}
+
+	device.Name = "tv\ndisabled=true"
+	client = &fakeQueryStateClient{}
+	if _, err := source.Read(context.Background(), automationdomain.StateSourceContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		RouterClient: client,
+	}, params); err == nil {
+		t.Fatalf("expected control character error")
+	}
+	if client.lastPath != "" {
+		t.Fatalf("expected no router query, got %s", client.lastPath)
+	}
+}
+
+func TestRouterQuerySourceValidate(t *testing.T) {
+	allowlist, _ := automationdomain.ParseCommandAllowlist([]string{"/tool/netwatch:print", "/ip/dns/static:add"})
+	source := NewRouterQuerySource(allowlist)
+	global := automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal}
+
+	cases := map[string]map[string]any{
+		"path not allowlisted": {"path": "/system/script", "result": "any_row"},
+		"print not allowed":    {"path": "/ip/dns/static", "result": "any_row"},
+		"missing field":        {"path": "/tool/netwatch", "result": "field_value"},
+		"bad min count":        {"path": "/tool/netwatch", "result": "count_at_least", "min_count": "0"},
+		"global device var":    {"path": "/tool/netwatch", "result": "any_row", "where": "host={{device.ip}}"},
+	}
+	for name, params := range cases {
+		if err := source.Validate(global, params); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+	if err := source.Validate(global, map[string]any{
+		"path":   "/tool/netwatch",
+		"where":  "host=8.8.8.8",
+		"result": "field_equals",
+		"field":  "status",
+		"value":  "up",
+	}); err != nil {
+		t.Fatalf("expected valid params, got %v", err)
+	}
+}
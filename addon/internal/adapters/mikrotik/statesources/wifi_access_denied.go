@@ -0,0 +1,116 @@
+package statesources
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+const (
+	// StateSourceIDWiFiAccessDenied reads whether wifi access list denies MAC.
+	StateSourceIDWiFiAccessDenied = "mikrotik.wifi.access.denied"
+)
+
+// WiFiAccessDeniedSource reads whether first wifi access-list entry for MAC
+// denies it.
+type WiFiAccessDeniedSource struct{}
+
+// NewWiFiAccessDeniedSource creates state-source implementation.
+func NewWiFiAccessDeniedSource() *WiFiAccessDeniedSource {
+	return &WiFiAccessDeniedSource{}
+}
+
+// ID returns unique state-source identifier.
+func (s *WiFiAccessDeniedSource) ID() string {
+	return StateSourceIDWiFiAccessDenied
+}
+
+// Metadata returns state-source descriptor for UI.
+func (s *WiFiAccessDeniedSource) Metadata() automationdomain.StateSourceMetadata {
+	return automationdomain.StateSourceMetadata{
+		ID:          StateSourceIDWiFiAccessDenied,
+		Label:       "MikroTik: Wifi access denied",
+		Description: "Checks whether the wifi access list denies a MAC on any wifi driver",
+		OutputType:  automationdomain.StateOutputBoolean,
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:         "target",
+				Label:       "Target",
+				Kind:        automationdomain.ParamEnum,
+				Required:    true,
+				Options:     []string{"device.mac", "literal_mac"},
+				Description: "MAC address to check",
+			},
+			{
+				Key:       "literal_mac",
+				Label:     "Literal MAC",
+				Kind:      automationdomain.ParamMAC,
+				Required:  true,
+				VisibleIf: &automationdomain.VisibleIfCondition{Key: "target", Equals: "literal_mac"},
+			},
+		},
+	}
+}
+
+// Validate validates state-source params against schema.
+func (s *WiFiAccessDeniedSource) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	targetParam, err := stringParam(params, "target")
+	if err != nil {
+		return err
+	}
+	switch targetParam {
+	case "device.mac":
+		if automationdomain.NormalizeCapabilityScope(target.Scope) == automationdomain.ScopeGlobal {
+			return fmt.Errorf("target %q is not available for global scope", targetParam)
+		}
+		return nil
+	case "literal_mac":
+		mac, err := stringParam(params, "literal_mac")
+		if err != nil {
+			return err
+		}
+		if _, err := net.ParseMAC(mac); err != nil {
+			return fmt.Errorf("invalid literal_mac %q", mac)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported target %q", targetParam)
+	}
+}
+
+// Read reports denied state of resolved MAC.
+func (s *WiFiAccessDeniedSource) Read(
+	ctx context.Context,
+	sourceCtx automationdomain.StateSourceContext,
+	params map[string]any,
+) (any, error) {
+	if err := s.Validate(sourceCtx.Target, params); err != nil {
+		return nil, err
+	}
+	if sourceCtx.RouterClient == nil {
+		return nil, fmt.Errorf("router client is not configured")
+	}
+	wifiClient, ok := sourceCtx.RouterClient.(automationdomain.WiFiAccessStateClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support wifi access lists")
+	}
+
+	var mac string
+	if targetParam, _ := stringParam(params, "target"); targetParam == "literal_mac" {
+		literal, _ := stringParam(params, "literal_mac")
+		mac = strings.ToUpper(strings.ReplaceAll(literal, "-", ":"))
+	} else {
+		device := sourceCtx.Target.Device
+		if device == nil || device.MAC == "" {
+			return nil, fmt.Errorf("device MAC is empty")
+		}
+		mac = device.MAC
+	}
+	return wifiClient.GetWiFiAccessDenied(ctx, sourceCtx.RouterConfig, mac)
+}
@@ -0,0 +1,52 @@
+package statesources
+
+import (
+	"context"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeWiFiAccessStateClient struct {
+	fakeStateClient
+	denied map[string]bool
+}
+
+func (f *fakeWiFiAccessStateClient) GetWiFiAccessDenied(ctx context.Context, cfg model.RouterConfig, mac string) (bool, error) {
+	return f.denied[mac], nil
+}
+
+func TestWiFiAccessDeniedSourceRead(t *testing.T) {
+	source := NewWiFiAccessDeniedSource()
+	client := &fakeWiFiAccessStateClient{denied: map[string]bool{"AA:BB:CC:DD:EE:01": true}}
+
+	value, err := source.Read(context.Background(), automationdomain.StateSourceContext{
+		Target: automationdomain.AutomationTarget{
+			Scope:  automationdomain.ScopeDevice,
+			Device: &model.DeviceView{MAC: "AA:BB:CC:DD:EE:01"},
+		},
+		RouterClient: client,
+	}, map[string]any{"target": "device.mac"})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if value != true {
+		t.Fatalf("expected denied MAC, got %v", value)
+	}
+
+	value, err = source.Read(context.Background(), automationdomain.StateSourceContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal},
+		RouterClient: client,
+	}, map[string]any{"target": "literal_mac", "literal_mac": "aa-bb-cc-dd-ee-01"})
+	if err != nil {
+		t.Fatalf("Read returned error: %v", err)
+	}
+	if value != true {
+		t.Fatalf("expected literal MAC to be normalized, got %v", value)
+	}
+
+	if err := source.Validate(automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal}, map[string]any{"target": "device.mac"}); err == nil {
+		t.Fatalf("expected device.mac to be rejected in global scope")
+	}
+}
@@ -0,0 +1,339 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const (
+	// ActionIDHTTPRequest sends HTTP request to external service.
+	ActionIDHTTPRequest = "http.request"
+
+	defaultRequestTimeout = 5 * time.Second
+	maxRequestTimeout     = 10 * time.Second
+	maxErrorBodyBytes     = 512
+)
+
+// HTTPRequestAction notifies local services on capability transitions.
+type HTTPRequestAction struct {
+	client *http.Client
+}
+
+// NewHTTPRequestAction creates generic HTTP/webhook action.
+func NewHTTPRequestAction() *HTTPRequestAction {
+	return &HTTPRequestAction{client: &http.Client{}}
+}
+
+// ID returns unique action identifier.
+func (a *HTTPRequestAction) ID() string {
+	return ActionIDHTTPRequest
+}
+
+// Metadata returns action descriptor for UI.
+func (a *HTTPRequestAction) Metadata() automationdomain.ActionMetadata {
+	return automationdomain.ActionMetadata{
+		ID:          ActionIDHTTPRequest,
+		Label:       "HTTP request",
+		Description: "Send HTTP request (webhook) to a local service such as Pi-hole, NAS or script server",
+		ParamSchema: []automationdomain.ParamField{
+			{
+				Key:      "method",
+				Label:    "Method",
+				Kind:     automationdomain.ParamEnum,
+				Required: true,
+				Options:  []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+			},
+			{
+				Key:         "url",
+				Label:       "URL",
+				Kind:        automationdomain.ParamString,
+				Required:    true,
+				Pattern:     `^https?://`,
+				Description: "http(s) URL, supports {{device.*}} and {{capability.*}} placeholders",
+			},
+			{
+				Key:         "headers",
+				Label:       "Headers",
+				Kind:        automationdomain.ParamString,
+				Description: "One `Name: value` header per line",
+			},
+			{
+				Key:         "body",
+				Label:       "JSON body",
+				Kind:        automationdomain.ParamString,
+				Description: "JSON document, placeholders are JSON-escaped",
+			},
+			{
+				Key:         "expected_status",
+				Label:       "Expected status codes",
+				Kind:        automationdomain.ParamString,
+				Description: "Comma-separated codes or classes like 2xx, defaults to 2xx",
+			},
+			{
+				Key:         "timeout_sec",
+				Label:       "Timeout (seconds)",
+				Kind:        automationdomain.ParamInt,
+				Min:         automationdomain.IntBound(1),
+				Max:         automationdomain.IntBound(int(maxRequestTimeout / time.Second)),
+				Description: "Request timeout from 1 to 10 seconds, defaults to 5",
+			},
+		},
+	}
+}
+
+// Validate validates action params against metadata schema.
+func (a *HTTPRequestAction) Validate(
+	target automationdomain.AutomationTarget,
+	params map[string]any,
+) error {
+	method, err := stringParam(params, "method")
+	if err != nil {
+		return err
+	}
+	switch strings.ToUpper(method) {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return fmt.Errorf("unsupported method %q", method)
+	}
+
+	rawURL, err := stringParam(params, "url")
+	if err != nil {
+		return err
+	}
+	parsed, err := url.Parse(renderURL(rawURL, sampleURLVars()))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("param %q must be absolute http(s) URL", "url")
+	}
+
+	headers := optionalStringParam(params, "headers")
+	if _, err := parseHeaders(headers); err != nil {
+		return err
+	}
+	body := optionalStringParam(params, "body")
+	if _, err := parseExpectedStatus(optionalStringParam(params, "expected_status")); err != nil {
+		return err
+	}
+	if _, err := parseTimeout(optionalStringParam(params, "timeout_sec")); err != nil {
+		return err
+	}
+
+	if automationdomain.NormalizeCapabilityScope(target.Scope) == automationdomain.ScopeGlobal {
+		for _, value := range []string{rawURL, headers, body} {
+			if containsDevicePlaceholder(value) {
+				return fmt.Errorf("global scope does not support device placeholders")
+			}
+		}
+	}
+	return nil
+}
+
+// Execute sends rendered HTTP request and checks response status.
+func (a *HTTPRequestAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	if err := a.Validate(execCtx.Target, params); err != nil {
+		return err
+	}
+
+	vars := automationdomain.TemplateVars(execCtx)
+	method, _ := stringParam(params, "method")
+	rawURL, _ := stringParam(params, "url")
+	headers, _ := parseHeaders(automationdomain.RenderTemplate(optionalStringParam(params, "headers"), vars))
+	expected, _ := parseExpectedStatus(optionalStringParam(params, "expected_status"))
+	timeout, _ := parseTimeout(optionalStringParam(params, "timeout_sec"))
+
+	var body io.Reader
+	if rawBody := optionalStringParam(params, "body"); rawBody != "" {
+		rendered := automationdomain.RenderTemplate(rawBody, jsonEscapedVars(vars))
+		if !json.Valid([]byte(rendered)) {
+			return fmt.Errorf("rendered body is not valid JSON")
+		}
+		body = bytes.NewBufferString(rendered)
+	}
+
+	requestCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(
+		requestCtx,
+		strings.ToUpper(method),
+		renderURL(rawURL, vars),
+		body,
+	)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if !expected(resp.StatusCode) {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(snippet)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func parseHeaders(raw string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || strings.ContainsAny(name, " \t") {
+			return nil, fmt.Errorf("invalid header line %q", line)
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+func parseExpectedStatus(raw string) (func(int) bool, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		raw = "2xx"
+	}
+
+	var (
+		codes   = map[int]struct{}{}
+		classes = map[int]struct{}{}
+	)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item == "" {
+			continue
+		}
+		if len(item) == 3 && strings.HasSuffix(item, "xx") && item[0] >= '1' && item[0] <= '5' {
+			classes[int(item[0]-'0')] = struct{}{}
+			continue
+		}
+		code, err := strconv.Atoi(item)
+		if err != nil || code < 100 || code > 599 {
+			return nil, fmt.Errorf("invalid expected status %q", item)
+		}
+		codes[code] = struct{}{}
+	}
+	return func(status int) bool {
+		if _, ok := codes[status]; ok {
+			return true
+		}
+		_, ok := classes[status/100]
+		return ok
+	}, nil
+}
+
+func parseTimeout(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return defaultRequestTimeout, nil
+	}
+	seconds, err := strconv.Atoi(raw)
+	if err != nil || seconds <= 0 || time.Duration(seconds)*time.Second > maxRequestTimeout {
+		return 0, fmt.Errorf("timeout_sec must be between 1 and %d", int(maxRequestTimeout/time.Second))
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+func jsonEscapedVars(vars map[string]string) map[string]string {
+	out := make(map[string]string, len(vars))
+	for key, value := range vars {
+		encoded, _ := json.Marshal(value)
+		out[key] = string(encoded[1 : len(encoded)-1])
+	}
+	return out
+}
+
+// renderURL escapes values by where they land: path placeholders use path
+// escaping so spaces stay %20, query and fragment ones use query escaping.
+func renderURL(raw string, vars map[string]string) string {
+	path, rest := raw, ""
+	if index := strings.IndexAny(raw, "?#"); index >= 0 {
+		path, rest = raw[:index], raw[index:]
+	}
+	return automationdomain.RenderTemplate(path, escapedVars(vars, url.PathEscape)) +
+		automationdomain.RenderTemplate(rest, escapedVars(vars, url.QueryEscape))
+}
+
+// sampleURLVars stands in for runtime values so URLs with placeholders in host
+// or port still parse during validation.
+func sampleURLVars() map[string]string {
+	ip, hostName := "192.0.2.1", "device"
+	return automationdomain.TemplateVars(automationdomain.ActionExecutionContext{
+		CapabilityID: "capability",
+		State:        "state",
+		Target: automationdomain.AutomationTarget{
+			Scope: automationdomain.ScopeDevice,
+			Device: &model.DeviceView{
+				MAC:      "00:00:5E:00:53:01",
+				Name:     "device",
+				LastIP:   &ip,
+				HostName: &hostName,
+			},
+		},
+	})
+}
+
+func escapedVars(vars map[string]string, escape func(string) string) map[string]string {
+	out := make(map[string]string, len(vars))
+	for key, value := range vars {
+		out[key] = escape(value)
+	}
+	return out
+}
+
+func containsDevicePlaceholder(raw string) bool {
+	return strings.Contains(strings.ToLower(raw), "{{device.")
+}
+
+// optionalStringParam reads string param; JSON numbers from int fields are formatted.
+func optionalStringParam(params map[string]any, key string) string {
+	switch value := params[key].(type) {
+	case string:
+		return strings.TrimSpace(value)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	}
+	return ""
+}
+
+func stringParam(params map[string]any, key string) (string, error) {
+	raw, ok := params[key]
+	if !ok {
+		return "", fmt.Errorf("missing param %q", key)
+	}
+	value, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("param %q must be string", key)
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("param %q is empty", key)
+	}
+	return value, nil
+}
@@ -0,0 +1,143 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+func TestHTTPRequestActionExecuteRendersTemplate(t *testing.T) {
+	var (
+		gotMethod string
+		gotPath   string
+		gotQuery  string
+		gotHeader string
+		gotBody   map[string]any
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		gotQuery = r.URL.Query().Get("name")
+		gotHeader = r.Header.Get("X-Token")
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &gotBody)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	ip := "192.168.88.20"
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01", Name: `Kid "tablet"`, LastIP: &ip}
+	action := NewHTTPRequestAction()
+	err := action.Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+		CapabilityID: "routing.vpn",
+		State:        "on",
+	}, map[string]any{
+		"method":          "post",
+		"url":             server.URL + "/hook/{{device.mac}}?name={{device.name}}",
+		"headers":         "X-Token: secret-{{capability.id}}",
+		"body":            `{"ip":"{{device.ip}}","name":"{{device.name}}","state":"{{capability.state}}"}`,
+		"expected_status": "200,202",
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Fatalf("unexpected method %q", gotMethod)
+	}
+	if gotPath != "/hook/AA:BB:CC:DD:EE:01" || gotQuery != `Kid "tablet"` {
+		t.Fatalf("unexpected url path=%q name=%q", gotPath, gotQuery)
+	}
+	if gotHeader != "secret-routing.vpn" {
+		t.Fatalf("unexpected header %q", gotHeader)
+	}
+	if gotBody["ip"] != ip || gotBody["name"] != `Kid "tablet"` || gotBody["state"] != "on" {
+		t.Fatalf("unexpected body %+v", gotBody)
+	}
+}
+
+func TestHTTPRequestActionExecuteEscapesPathAndQuery(t *testing.T) {
+	var gotPath, gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotQuery = r.URL.Query().Get("name")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:02", Name: "Living room+tv"}
+	action := NewHTTPRequestAction()
+	err := action.Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target: automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &device},
+	}, map[string]any{
+		"method": "get",
+		"url":    server.URL + "/devices/{{device.name}}?name={{device.name}}",
+	})
+	if err != nil {
+		t.Fatalf("Execute returned error: %v", err)
+	}
+	if gotPath != "/devices/Living room+tv" || gotQuery != "Living room+tv" {
+		t.Fatalf("unexpected url path=%q name=%q", gotPath, gotQuery)
+	}
+}
+
+func TestHTTPRequestActionExecuteRejectsUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	err := NewHTTPRequestAction().Execute(context.Background(), automationdomain.ActionExecutionContext{
+		Target: automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal},
+	}, map[string]any{
+		"method": "GET",
+		"url":    server.URL,
+	})
+	if err == nil || !strings.Contains(err.Error(), "unexpected status 500") {
+		t.Fatalf("expected status error, got %v", err)
+	}
+}
+
+func TestHTTPRequestActionValidate(t *testing.T) {
+	action := NewHTTPRequestAction()
+	global := automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal}
+
+	cases := map[string]map[string]any{
+		"bad scheme":       {"method": "GET", "url": "ftp://example.local"},
+		"bad method":       {"method": "TRACE", "url": "http://example.local"},
+		"bad header":       {"method": "GET", "url": "http://example.local", "headers": "missing-colon"},
+		"bad status":       {"method": "GET", "url": "http://example.local", "expected_status": "abc"},
+		"bad timeout":      {"method": "GET", "url": "http://example.local", "timeout_sec": "60"},
+		"global device ip": {"method": "GET", "url": "http://example.local/{{device.ip}}"},
+	}
+	for name, params := range cases {
+		if err := action.Validate(global, params); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+
+	if err := action.Validate(global, map[string]any{
+		"method":          "PUT",
+		"url":             "https://example.local/{{capability.id}}",
+		"expected_status": "2xx, 409",
+		"timeout_sec":     "3",
+	}); err != nil {
+		t.Fatalf("expected valid params, got %v", err)
+	}
+
+	device := automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice}
+	if err := action.Validate(device, map[string]any{
+		"method": "POST",
+		"url":    "http://{{device.ip}}:8080/notify?name={{device.name}}",
+	}); err != nil {
+		t.Fatalf("expected placeholder host to be valid, got %v", err)
+	}
+}
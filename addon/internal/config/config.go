@@ -4,6 +4,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,6 +18,9 @@ const (
 	defaultAddonOptionsPath       = "/data/options.json"
 	defaultAutomationSyncInterval = 20 * time.Second
 	defaultConfigRefreshInterval  = 20 * time.Second
+	defaultAutomationEnforceDelay = time.Minute
+	defaultAutomationSyncWorkers  = 4
+	defaultRouterSyncConcurrency  = 2
 )
 
 // Config stores runtime settings loaded from environment variables.
@@ -28,7 +32,13 @@ type Config struct {
 	ConfigRefreshInterval  time.Duration
 	LogLevel               slog.Level
 	AutomationSyncInterval time.Duration
-	PresenceThresholds     model.PresenceThresholds
+	// AutomationEnforceDelay limits how often enforce mode corrects one target.
+	AutomationEnforceDelay time.Duration
+	AutomationSyncWorkers  int
+	RouterSyncConcurrency  int
+	// ArtefactGCInterval enables scheduled ledger reconciliation; zero disables it.
+	ArtefactGCInterval time.Duration
+	PresenceThresholds model.PresenceThresholds
 }
 
 // Load builds Config from environment variables using stable defaults.
@@ -41,6 +51,10 @@ func Load() Config {
 		ConfigRefreshInterval:  parseDuration("CONFIG_REFRESH_INTERVAL", defaultConfigRefreshInterval),
 		LogLevel:               parseLogLevel(getenv("LOG_LEVEL", "info")),
 		AutomationSyncInterval: parseDuration("AUTOMATION_SYNC_INTERVAL", defaultAutomationSyncInterval),
+		AutomationEnforceDelay: parseDuration("AUTOMATION_ENFORCE_DELAY", defaultAutomationEnforceDelay),
+		AutomationSyncWorkers:  parseInt("AUTOMATION_SYNC_WORKERS", defaultAutomationSyncWorkers),
+		RouterSyncConcurrency:  parseInt("ROUTER_SYNC_CONCURRENCY", defaultRouterSyncConcurrency),
+		ArtefactGCInterval:     parseDuration("ARTEFACT_GC_INTERVAL", 0),
 		PresenceThresholds: model.PresenceThresholds{
 			WiFiIdleThreshold:    parseDuration("WIFI_IDLE_THRESHOLD", 5*time.Minute),
 			DHCPRecentThreshold:  parseDuration("DHCP_RECENT_THRESHOLD", 30*time.Minute),
@@ -76,6 +90,18 @@ func parseDuration(key string, fallback time.Duration) time.Duration {
 	return value
 }
 
+func parseInt(key string, fallback int) int {
+	raw, ok := os.LookupEnv(key)
+	if !ok {
+		return fallback
+	}
+	value, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
 func parseLogLevel(raw string) slog.Level {
 	switch strings.ToLower(strings.TrimSpace(raw)) {
 	case "debug":
@@ -41,6 +41,17 @@ type optionsPayload struct {
 	RouterVerifyTLS *bool    `json:"router_verify_tls"`
 	PollIntervalSec int      `json:"poll_interval_sec"`
 	Roles           []string `json:"roles"`
+	MQTTHost        string   `json:"mqtt_host"`
+	MQTTPort        int      `json:"mqtt_port"`
+	MQTTUsername    string   `json:"mqtt_username"`
+	MQTTPassword    string   `json:"mqtt_password"`
+	MQTTDiscovery   string   `json:"mqtt_discovery_prefix"`
+	MQTTBaseTopic   string   `json:"mqtt_base_topic"`
+	CommandAllow    []string `json:"router_command_allowlist"`
+	QuarantineMode  string   `json:"quarantine_mode"`
+	QuarantineVLAN  int      `json:"quarantine_vlan_id"`
+	QuarantineList  string   `json:"quarantine_address_list"`
+	LeaseComments   *bool    `json:"dhcp_lease_comments"`
 	LegacyHost      string   `json:"host"`
 	LegacyUsername  string   `json:"username"`
 	LegacyPassword  string   `json:"password"`
@@ -79,6 +90,66 @@ func (c *Client) FetchConfig(ctx context.Context) (FetchResult, error) {
 	return FetchResult{Configured: true, Config: cfg}, nil
 }
 
+// FetchMQTTConfig reads MQTT broker settings from add-on options.
+func (c *Client) FetchMQTTConfig(ctx context.Context) (model.MQTTConfig, error) {
+	options, err := c.loadOptionsFromFile(ctx)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return model.MQTTConfig{}, err
+		}
+		options = loadOptionsFromEnv()
+	}
+	return model.MQTTConfig{
+		Host:            strings.TrimSpace(options.MQTTHost),
+		Port:            firstPositive(options.MQTTPort, 1883),
+		Username:        strings.TrimSpace(options.MQTTUsername),
+		Password:        options.MQTTPassword,
+		DiscoveryPrefix: firstNonEmpty(options.MQTTDiscovery, "homeassistant"),
+		BaseTopic:       firstNonEmpty(options.MQTTBaseTopic, "mikrotik_presence"),
+	}, nil
+}
+
+// FetchCommandAllowlist reads RouterOS paths permitted for generic command actions.
+func (c *Client) FetchCommandAllowlist(ctx context.Context) ([]string, error) {
+	options, err := c.loadOptionsFromFile(ctx)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		options = loadOptionsFromEnv()
+	}
+	return options.CommandAllow, nil
+}
+
+// FetchQuarantineConfig reads onboarding policy for unknown devices.
+func (c *Client) FetchQuarantineConfig(ctx context.Context) (model.QuarantineConfig, error) {
+	options, err := c.loadOptionsFromFile(ctx)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return model.QuarantineConfig{}, err
+		}
+		options = loadOptionsFromEnv()
+	}
+	return model.QuarantineConfig{
+		Mode:        strings.ToLower(firstNonEmpty(options.QuarantineMode, model.QuarantineOff)),
+		VLANID:      options.QuarantineVLAN,
+		AddressList: firstNonEmpty(options.QuarantineList, "quarantine"),
+	}, nil
+}
+
+// FetchDHCPLeaseComments reports whether registered names are pushed as
+// static DHCP lease comments.
+func (c *Client) FetchDHCPLeaseComments(ctx context.Context) (bool, error) {
+	options, err := c.loadOptionsFromFile(ctx)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+		options = loadOptionsFromEnv()
+	}
+	return pickBool(options.LeaseComments, nil, parseBoolEnv("DHCP_LEASE_COMMENTS", false)), nil
+}
+
 func (c *Client) loadOptionsFromFile(ctx context.Context) (optionsPayload, error) {
 	select {
 	case <-ctx.Done():
@@ -107,6 +178,16 @@ func loadOptionsFromEnv() optionsPayload {
 		RouterUsername:  strings.TrimSpace(os.Getenv("ROUTER_USERNAME")),
 		RouterPassword:  strings.TrimSpace(os.Getenv("ROUTER_PASSWORD")),
 		PollIntervalSec: parseIntEnv("ROUTER_POLL_INTERVAL_SEC", 5),
+		MQTTHost:        strings.TrimSpace(os.Getenv("MQTT_HOST")),
+		MQTTPort:        parseIntEnv("MQTT_PORT", 1883),
+		MQTTUsername:    strings.TrimSpace(os.Getenv("MQTT_USERNAME")),
+		MQTTPassword:    os.Getenv("MQTT_PASSWORD"),
+		MQTTDiscovery:   strings.TrimSpace(os.Getenv("MQTT_DISCOVERY_PREFIX")),
+		MQTTBaseTopic:   strings.TrimSpace(os.Getenv("MQTT_BASE_TOPIC")),
+		CommandAllow:    splitListEnv("ROUTER_COMMAND_ALLOWLIST"),
+		QuarantineMode:  strings.TrimSpace(os.Getenv("QUARANTINE_MODE")),
+		QuarantineVLAN:  parseIntEnv("QUARANTINE_VLAN_ID", 0),
+		QuarantineList:  strings.TrimSpace(os.Getenv("QUARANTINE_ADDRESS_LIST")),
 	}
 }
 
@@ -151,6 +232,17 @@ func parseIntEnv(key string, fallback int) int {
 	return parsed
 }
 
+// splitListEnv splits semicolon-separated env value.
+func splitListEnv(key string) []string {
+	var values []string
+	for _, item := range strings.Split(os.Getenv(key), ";") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
 func firstNonEmpty(values ...string) string {
 	for _, value := range values {
 		if trimmed := strings.TrimSpace(value); trimmed != "" {
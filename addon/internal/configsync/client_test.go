@@ -117,3 +117,88 @@ func TestFetchConfigReturnsErrorForInvalidOptionsJSON(t *testing.T) {
 		t.Fatal("FetchConfig() error = nil, want non-nil")
 	}
 }
+
+func TestFetchQuarantineConfigDefaultsToOff(t *testing.T) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "options.json")
+	if err := os.WriteFile(path, []byte(`{"router_host": "192.168.88.1"}`), 0o644); err != nil {
+		t.Fatalf("write options file: %v", err)
+	}
+
+	got, err := NewClient(path).FetchQuarantineConfig(context.Background())
+	if err != nil {
+		t.Fatalf("FetchQuarantineConfig() error: %v", err)
+	}
+	if got.Enabled() || got.AddressList != "quarantine" {
+		t.Fatalf("FetchQuarantineConfig() = %+v, want disabled with default list", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"quarantine_mode": "wifi_vlan", "quarantine_vlan_id": 99}`), 0o644); err != nil {
+		t.Fatalf("write options file: %v", err)
+	}
+	got, err = NewClient(path).FetchQuarantineConfig(context.Background())
+	if err != nil {
+		t.Fatalf("FetchQuarantineConfig() error: %v", err)
+	}
+	if !got.Enabled() || got.VLANID != 99 {
+		t.Fatalf("FetchQuarantineConfig() = %+v, want wifi_vlan on vlan 99", got)
+	}
+}
+
+func TestFetchDHCPLeaseComments(t *testing.T) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "options.json")
+	if err := os.WriteFile(path, []byte(`{"dhcp_lease_comments": true}`), 0o644); err != nil {
+		t.Fatalf("write options file: %v", err)
+	}
+	got, err := NewClient(path).FetchDHCPLeaseComments(context.Background())
+	if err != nil || !got {
+		t.Fatalf("FetchDHCPLeaseComments() = %v, %v; want true", got, err)
+	}
+
+	t.Setenv("DHCP_LEASE_COMMENTS", "false")
+	got, err = NewClient(filepath.Join(t.TempDir(), "missing-options.json")).FetchDHCPLeaseComments(context.Background())
+	if err != nil || got {
+		t.Fatalf("FetchDHCPLeaseComments() from env = %v, %v; want false", got, err)
+	}
+}
+
+func TestFetchMQTTConfig(t *testing.T) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "options.json")
+	if err := os.WriteFile(path, []byte(`{"router_host": "192.168.88.1"}`), 0o644); err != nil {
+		t.Fatalf("write options file: %v", err)
+	}
+	got, err := NewClient(path).FetchMQTTConfig(context.Background())
+	if err != nil {
+		t.Fatalf("FetchMQTTConfig() error: %v", err)
+	}
+	if got.Enabled() || got.Port != 1883 || got.DiscoveryPrefix != "homeassistant" || got.BaseTopic != "mikrotik_presence" {
+		t.Fatalf("FetchMQTTConfig() = %+v, want disabled with defaults", got)
+	}
+
+	options := `{"mqtt_host": " core-mosquitto ", "mqtt_port": 1884, "mqtt_username": "addon", "mqtt_password": "secret", "mqtt_base_topic": "presence"}`
+	if err := os.WriteFile(path, []byte(options), 0o644); err != nil {
+		t.Fatalf("write options file: %v", err)
+	}
+	got, err = NewClient(path).FetchMQTTConfig(context.Background())
+	if err != nil {
+		t.Fatalf("FetchMQTTConfig() error: %v", err)
+	}
+	if !got.Enabled() || got.Host != "core-mosquitto" || got.Port != 1884 || got.Username != "addon" || got.Password != "secret" || got.BaseTopic != "presence" {
+		t.Fatalf("FetchMQTTConfig() = %+v, want options values", got)
+	}
+
+	t.Setenv("MQTT_HOST", "broker.lan")
+	t.Setenv("MQTT_DISCOVERY_PREFIX", "ha")
+	got, err = NewClient(filepath.Join(t.TempDir(), "missing-options.json")).FetchMQTTConfig(context.Background())
+	if err != nil {
+		t.Fatalf("FetchMQTTConfig() from env error: %v", err)
+	}
+	if got.Host != "broker.lan" || got.Port != 1883 || got.DiscoveryPrefix != "ha" {
+		t.Fatalf("FetchMQTTConfig() from env = %+v, want env values", got)
+	}
+}
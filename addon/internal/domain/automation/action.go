@@ -20,12 +20,87 @@ type AddressListClient interface {
 	RemoveAddressListEntry(ctx context.Context, cfg model.RouterConfig, list, address string) error
 }
 
+// ManagedAddressListClient adds entries tagged with ownership comment and
+// optional RouterOS timeout so they expire even when the add-on is down.
+type ManagedAddressListClient interface {
+	AddManagedAddressListEntry(ctx context.Context, cfg model.RouterConfig, list, address, comment, timeout string) error
+}
+
+// OwnedAddressListClient adds entries tagged with ownership comment reporting
+// whether they were created, and removes only entries carrying that comment.
+type OwnedAddressListClient interface {
+	EnsureOwnedAddressListEntry(ctx context.Context, cfg model.RouterConfig, list, address, comment, timeout string) (bool, error)
+	RemoveOwnedAddressListEntry(ctx context.Context, cfg model.RouterConfig, list, address, comment string) error
+}
+
 // FirewallRuleClient is required by MikroTik firewall rule actions.
 type FirewallRuleClient interface {
 	SetFirewallRuleDisabled(ctx context.Context, cfg model.RouterConfig, table, ruleID string, disabled bool) error
 	SetFirewallRulesDisabledByComment(ctx context.Context, cfg model.RouterConfig, table, comment string, disabled bool) error
 }
 
+// RouterCommandClient runs raw RouterOS API commands for generic actions.
+type RouterCommandClient interface {
+	RunCommand(ctx context.Context, cfg model.RouterConfig, path string, params map[string]string) ([]map[string]string, error)
+}
+
+// ConnectionClient flushes connection tracking so firewall changes apply to
+// established flows immediately.
+type ConnectionClient interface {
+	KillConnections(ctx context.Context, cfg model.RouterConfig, address string) (int, error)
+}
+
+// WakeOnLANClient sends Wake-on-LAN magic packets through the router.
+type WakeOnLANClient interface {
+	WakeOnLAN(ctx context.Context, cfg model.RouterConfig, mac, iface string) error
+}
+
+// SimpleQueueClient limits device bandwidth through /queue/simple entries
+// owned by ownership comment.
+type SimpleQueueClient interface {
+	SetSimpleQueueLimit(ctx context.Context, cfg model.RouterConfig, target, comment string, limit model.QueueLimit) error
+	RemoveSimpleQueueLimit(ctx context.Context, cfg model.RouterConfig, target, comment string) error
+}
+
+// ContentFilterClient manages per-device domain blocking rules owned by
+// ownership comment.
+type ContentFilterClient interface {
+	SetContentFilter(ctx context.Context, cfg model.RouterConfig, address, comment string, filter model.ContentFilter) error
+	RemoveContentFilter(ctx context.Context, cfg model.RouterConfig, comment string) error
+}
+
+// KidControlClient pauses RouterOS kid-control profiles and binds device MACs
+// to them; bindings are owned by ownership comment.
+type KidControlClient interface {
+	KidControlStateClient
+	SetKidControlPaused(ctx context.Context, cfg model.RouterConfig, profile string, paused bool) error
+	BindKidControlDevice(ctx context.Context, cfg model.RouterConfig, profile, mac, name, comment string) error
+	UnbindKidControlDevice(ctx context.Context, cfg model.RouterConfig, mac, comment string) error
+}
+
+// DNSAdlistClient enables or disables RouterOS DNS adlists by URL or file;
+// empty source selects every adlist.
+type DNSAdlistClient interface {
+	DNSAdlistStateClient
+	SetDNSAdlistsEnabled(ctx context.Context, cfg model.RouterConfig, source string, enabled bool) error
+}
+
+// DNSStaticClient manages static DNS records owned by ownership comment.
+type DNSStaticClient interface {
+	EnsureDNSStaticRecord(ctx context.Context, cfg model.RouterConfig, record model.DNSStaticRecord, comment string) error
+	RemoveDNSStaticRecord(ctx context.Context, cfg model.RouterConfig, name, comment string) error
+}
+
+// WiFiAccessClient manages wifi access-list entries keyed by MAC on every
+// installed wifi driver, owned by ownership comment, and drops registered
+// clients.
+type WiFiAccessClient interface {
+	WiFiAccessStateClient
+	EnsureWiFiAccessRule(ctx context.Context, cfg model.RouterConfig, mac string, policy model.WiFiAccessPolicy, comment string) error
+	RemoveWiFiAccessRule(ctx context.Context, cfg model.RouterConfig, mac, comment string) error
+	DisconnectWiFiClient(ctx context.Context, cfg model.RouterConfig, mac string) (int, error)
+}
+
 // RouterActionClient groups RouterOS operations used by automation actions.
 type RouterActionClient interface {
 	AddressListClient
@@ -35,6 +110,8 @@ type RouterActionClient interface {
 // ActionExecutionContext contains runtime dependencies for action execution.
 type ActionExecutionContext struct {
 	Target       AutomationTarget
+	CapabilityID string
+	State        string
 	RouterClient RouterActionClient
 	RouterConfig model.RouterConfig
 	Logger       *slog.Logger
@@ -55,3 +132,10 @@ type Action interface {
 	Validate(target AutomationTarget, params map[string]any) error
 	Execute(ctx context.Context, execCtx ActionExecutionContext, params map[string]any) error
 }
+
+// StaleTargetCleaner is implemented by actions that write device-derived values
+// to the router and can remove them after the device moved to another address.
+// Execution context target holds the previous device snapshot.
+type StaleTargetCleaner interface {
+	CleanupStaleTarget(ctx context.Context, execCtx ActionExecutionContext, params map[string]any) error
+}
@@ -1,6 +1,8 @@
 package automation
 
 import (
+	"fmt"
+	"sort"
 	"strings"
 	"time"
 )
@@ -45,6 +47,20 @@ const (
 	ParamEnum ParamFieldKind = "enum"
 	// ParamBool is boolean checkbox value.
 	ParamBool ParamFieldKind = "bool"
+	// ParamInt is whole number, Min/Max bound the value.
+	ParamInt ParamFieldKind = "int"
+	// ParamDuration is Go duration string or seconds, Min/Max bound seconds.
+	ParamDuration ParamFieldKind = "duration"
+	// ParamIP is IPv4/IPv6 address.
+	ParamIP ParamFieldKind = "ip"
+	// ParamCIDR is IP prefix like 192.168.88.0/24.
+	ParamCIDR ParamFieldKind = "cidr"
+	// ParamMAC is hardware address.
+	ParamMAC ParamFieldKind = "mac"
+	// ParamStringList is list of strings, Min/Max bound item count.
+	ParamStringList ParamFieldKind = "string_list"
+	// ParamObject is nested object described by Fields.
+	ParamObject ParamFieldKind = "object"
 )
 
 // VisibleIfCondition controls conditional field rendering in UI.
@@ -62,6 +78,21 @@ type ParamField struct {
 	Description string              `json:"description,omitempty"`
 	Options     []string            `json:"options,omitempty"`
 	VisibleIf   *VisibleIfCondition `json:"visible_if,omitempty"`
+	// Min/Max bound int value, duration seconds, string length or list size.
+	Min *int `json:"min,omitempty"`
+	Max *int `json:"max,omitempty"`
+	// Pattern is regexp applied to string values and list items.
+	Pattern string       `json:"pattern,omitempty"`
+	Fields  []ParamField `json:"fields,omitempty"`
+	// OptionsProvider names live router options source (Options* constants);
+	// OptionsArgKey is sibling param passed to provider, for example table.
+	OptionsProvider string `json:"options_provider,omitempty"`
+	OptionsArgKey   string `json:"options_arg_key,omitempty"`
+}
+
+// IntBound returns pointer for ParamField Min/Max literals.
+func IntBound(value int) *int {
+	return &value
 }
 
 // ActionInstance is one runtime action invocation configuration.
@@ -75,6 +106,16 @@ type ActionInstance struct {
 type CapabilityStateConfig struct {
 	Label          string           `json:"label"`
 	ActionsOnEnter []ActionInstance `json:"actions_on_enter"`
+	// ActionsOnExit run when target leaves this state, before next state enters.
+	ActionsOnExit []ActionInstance `json:"actions_on_exit,omitempty"`
+}
+
+// CapabilityTransition runs actions on one specific state change, between
+// exit actions of From and enter actions of To.
+type CapabilityTransition struct {
+	From    string           `json:"from"`
+	To      string           `json:"to"`
+	Actions []ActionInstance `json:"actions"`
 }
 
 // CapabilityControlOption is one selectable state option in UI.
@@ -109,6 +150,16 @@ type CapabilitySyncMapping struct {
 	WhenFalse string `json:"when_false"`
 }
 
+// Sync modes supported by CapabilitySyncConfig.Mode.
+const (
+	// SyncModeExternalTruth adopts router value into stored capability state.
+	SyncModeExternalTruth = "external_truth"
+	// SyncModeInternalTruth keeps stored state and skips router reads.
+	SyncModeInternalTruth = "internal_truth"
+	// SyncModeEnforce keeps stored state and re-applies actions on router drift.
+	SyncModeEnforce = "enforce"
+)
+
 // CapabilitySyncConfig configures periodic sync behavior.
 type CapabilitySyncConfig struct {
 	Enabled              bool                  `json:"enabled"`
@@ -116,6 +167,20 @@ type CapabilitySyncConfig struct {
 	Mapping              CapabilitySyncMapping `json:"mapping"`
 	Mode                 string                `json:"mode"`
 	TriggerActionsOnSync bool                  `json:"trigger_actions_on_sync"`
+	// IntervalSec overrides global sync interval when positive.
+	IntervalSec int `json:"interval_sec,omitempty"`
+}
+
+// SyncStatus reports scheduled sync progress of one capability template.
+type SyncStatus struct {
+	CapabilityID   string     `json:"capability_id"`
+	IntervalSec    int        `json:"interval_sec"`
+	Running        bool       `json:"running"`
+	Targets        int        `json:"targets"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	LastDurationMS int64      `json:"last_duration_ms"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextRunAt      *time.Time `json:"next_run_at,omitempty"`
 }
 
 // CapabilityTemplate defines reusable automation behavior.
@@ -130,6 +195,56 @@ type CapabilityTemplate struct {
 	DefaultState string                           `json:"default_state"`
 	Sync         *CapabilitySyncConfig            `json:"sync,omitempty"`
 	HAExpose     HAExposeConfig                   `json:"ha_expose"`
+	Transitions  []CapabilityTransition           `json:"transitions,omitempty"`
+	// ActionsOnDisable run when an enabled target is disabled or template is deleted.
+	ActionsOnDisable []ActionInstance `json:"actions_on_disable,omitempty"`
+}
+
+// TransitionActions returns actions for state change in execution order:
+// exit of from, matching transitions, then enter of to.
+func (t CapabilityTemplate) TransitionActions(from, to string) []ActionInstance {
+	actions := make([]ActionInstance, 0)
+	if from != to {
+		actions = append(actions, t.States[from].ActionsOnExit...)
+		for _, transition := range t.Transitions {
+			if transition.From == from && transition.To == to {
+				actions = append(actions, transition.Actions...)
+			}
+		}
+	}
+	return append(actions, t.States[to].ActionsOnEnter...)
+}
+
+// ActionLocations lists every action in template keyed by its JSON path, in
+// stable order.
+func (t CapabilityTemplate) ActionLocations() []ActionLocation {
+	stateIDs := make([]string, 0, len(t.States))
+	for stateID := range t.States {
+		stateIDs = append(stateIDs, stateID)
+	}
+	sort.Strings(stateIDs)
+
+	locations := make([]ActionLocation, 0)
+	add := func(prefix string, actions []ActionInstance) {
+		for i, action := range actions {
+			locations = append(locations, ActionLocation{Path: fmt.Sprintf("%s[%d]", prefix, i), Action: action})
+		}
+	}
+	for _, stateID := range stateIDs {
+		add("states."+stateID+".actions_on_enter", t.States[stateID].ActionsOnEnter)
+		add("states."+stateID+".actions_on_exit", t.States[stateID].ActionsOnExit)
+	}
+	for i, transition := range t.Transitions {
+		add(fmt.Sprintf("transitions[%d].actions", i), transition.Actions)
+	}
+	add("actions_on_disable", t.ActionsOnDisable)
+	return locations
+}
+
+// ActionLocation is one template action with its JSON path.
+type ActionLocation struct {
+	Path   string
+	Action ActionInstance
 }
 
 // DeviceCapability stores per-device applied state.
@@ -186,3 +301,33 @@ type SetStateResult struct {
 	OK       bool                     `json:"ok"`
 	Warnings []ActionExecutionWarning `json:"warnings,omitempty"`
 }
+
+// Drift outcomes recorded for enforce sync mode.
+const (
+	// DriftOutcomeCorrected means state actions were re-applied without warnings.
+	DriftOutcomeCorrected = "corrected"
+	// DriftOutcomeCorrectionFailed means re-applied actions reported warnings.
+	DriftOutcomeCorrectionFailed = "correction_failed"
+	// DriftOutcomeRateLimited means correction was skipped by rate limiting.
+	DriftOutcomeRateLimited = "rate_limited"
+)
+
+// DriftEvent records router drift detected by enforce sync mode.
+type DriftEvent struct {
+	ID            int64                    `json:"id"`
+	CapabilityID  string                   `json:"capability_id"`
+	Scope         CapabilityScope          `json:"scope"`
+	DeviceID      string                   `json:"device_id,omitempty"`
+	ExpectedState string                   `json:"expected_state"`
+	ObservedState string                   `json:"observed_state"`
+	Outcome       string                   `json:"outcome"`
+	Warnings      []ActionExecutionWarning `json:"warnings,omitempty"`
+	DetectedAt    time.Time                `json:"detected_at"`
+}
+
+// DriftEventFilter narrows drift event listing.
+type DriftEventFilter struct {
+	CapabilityID string
+	DeviceID     string
+	Limit        int
+}
@@ -0,0 +1,92 @@
+package automation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// AllowAllVerbs permits every verb for one allowlisted RouterOS menu path.
+const AllowAllVerbs = "*"
+
+var routerMenuPathPattern = regexp.MustCompile(`^(/[a-z0-9][a-z0-9-]*)+$`)
+
+// CommandAllowlist lists RouterOS menu paths and verbs generic router
+// commands are permitted to run.
+type CommandAllowlist struct {
+	verbs map[string]map[string]struct{}
+}
+
+// ParseCommandAllowlist parses entries like "/ip/dns/static:add,set,remove".
+// Entry without verbs or with "*" allows every verb for that path.
+func ParseCommandAllowlist(entries []string) (CommandAllowlist, error) {
+	allowlist := CommandAllowlist{verbs: map[string]map[string]struct{}{}}
+	for _, entry := range entries {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		path, rawVerbs, hasVerbs := strings.Cut(entry, ":")
+		path = strings.ToLower(strings.TrimSpace(path))
+		if !routerMenuPathPattern.MatchString(path) {
+			return CommandAllowlist{}, fmt.Errorf("invalid allowlist path %q", path)
+		}
+		verbs := allowlist.verbs[path]
+		if verbs == nil {
+			verbs = map[string]struct{}{}
+			allowlist.verbs[path] = verbs
+		}
+		if !hasVerbs || strings.TrimSpace(rawVerbs) == "" {
+			verbs[AllowAllVerbs] = struct{}{}
+			continue
+		}
+		for _, verb := range strings.Split(rawVerbs, ",") {
+			verb = strings.ToLower(strings.TrimSpace(verb))
+			if verb == "" {
+				continue
+			}
+			if verb != AllowAllVerbs && !routerMenuPathPattern.MatchString("/"+verb) {
+				return CommandAllowlist{}, fmt.Errorf("invalid allowlist verb %q for %s", verb, path)
+			}
+			verbs[verb] = struct{}{}
+		}
+	}
+	return allowlist, nil
+}
+
+// Allows reports whether verb may run on menu path.
+func (a CommandAllowlist) Allows(path string, verb string) bool {
+	verbs, ok := a.verbs[strings.ToLower(strings.TrimSpace(path))]
+	if !ok {
+		return false
+	}
+	if _, ok := verbs[AllowAllVerbs]; ok {
+		return true
+	}
+	_, ok = verbs[strings.ToLower(strings.TrimSpace(verb))]
+	return ok
+}
+
+// Paths returns allowlisted menu paths in stable order.
+func (a CommandAllowlist) Paths() []string {
+	paths := make([]string, 0, len(a.verbs))
+	for path := range a.verbs {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// SplitCommandPath splits "/ip/dns/static/add" into menu path and verb.
+func SplitCommandPath(command string) (string, string, error) {
+	command = strings.ToLower(strings.TrimSpace(command))
+	if !routerMenuPathPattern.MatchString(command) {
+		return "", "", fmt.Errorf("invalid command path %q", command)
+	}
+	idx := strings.LastIndex(command, "/")
+	if idx <= 0 {
+		return "", "", fmt.Errorf("command path %q must include menu and verb", command)
+	}
+	return command[:idx], command[idx+1:], nil
+}
@@ -1,6 +1,9 @@
 package automation
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 var (
 	// ErrCapabilityNotFound means a capability template is missing.
@@ -21,6 +24,28 @@ var (
 	ErrCapabilityScopeMismatch = errors.New("capability scope mismatch")
 	// ErrCapabilityScopeInvalid means unsupported capability scope.
 	ErrCapabilityScopeInvalid = errors.New("capability scope invalid")
+	// ErrOptionsProviderUnknown means param options provider is not supported.
+	ErrOptionsProviderUnknown = errors.New("options provider unknown")
 	// ErrNotFound is generic repository-level missing row marker.
 	ErrNotFound = errors.New("not found")
 )
+
+// FieldError is one invalid field addressed by dotted path from template root,
+// for example "states.on.actions_on_enter[0].params.list".
+type FieldError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ParamValidationError collects param schema violations for editor highlighting.
+type ParamValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ParamValidationError) Error() string {
+	parts := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		parts = append(parts, field.Path+": "+field.Message)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,103 @@
+package automation
+
+import (
+	"context"
+	"time"
+)
+
+// ArtefactKind classifies router objects recorded in ownership ledger.
+type ArtefactKind string
+
+const (
+	// ArtefactAddressListEntry is list/address entry added by an action.
+	ArtefactAddressListEntry ArtefactKind = "address_list_entry"
+	// ArtefactFirewallRule is firewall rule toggled by id.
+	ArtefactFirewallRule ArtefactKind = "firewall_rule"
+	// ArtefactFirewallComment is set of firewall rules toggled by comment.
+	ArtefactFirewallComment ArtefactKind = "firewall_rule_comment"
+	// ArtefactRouterObject is object created by a raw RouterOS add command.
+	ArtefactRouterObject ArtefactKind = "router_object"
+	// ArtefactSimpleQueue is simple queue owned by comment for one target.
+	ArtefactSimpleQueue ArtefactKind = "simple_queue"
+	// ArtefactContentFilter is set of filter rules and DNS entries owned by comment.
+	ArtefactContentFilter ArtefactKind = "content_filter"
+	// ArtefactKidControlPause is kid-control profile paused or resumed by name.
+	ArtefactKidControlPause ArtefactKind = "kid_control_pause"
+	// ArtefactKidControlDevice is MAC bound to kid-control profile.
+	ArtefactKidControlDevice ArtefactKind = "kid_control_device"
+	// ArtefactDNSAdlist is DNS adlist enabled or disabled by source; empty
+	// key stands for every adlist.
+	ArtefactDNSAdlist ArtefactKind = "dns_adlist"
+	// ArtefactDNSStatic is static DNS record owned by comment.
+	ArtefactDNSStatic ArtefactKind = "dns_static"
+	// ArtefactWiFiAccess is wifi access-list entry for MAC owned by comment.
+	ArtefactWiFiAccess ArtefactKind = "wifi_access"
+)
+
+// RouterArtefact is one router write recorded in ownership ledger. Path and
+// Key identify the object: list+address, table+rule id, table+comment,
+// menu+.id, queue target+comment, filter menu+comment, kid-control
+// menu+profile or menu+MAC, adlist menu+source, DNS menu+name or wifi
+// access-list menu+MAC depending on Kind.
+type RouterArtefact struct {
+	ID           int64        `json:"id"`
+	CapabilityID string       `json:"capability_id"`
+	DeviceID     string       `json:"device_id,omitempty"`
+	Kind         ArtefactKind `json:"kind"`
+	Path         string       `json:"path"`
+	Key          string       `json:"key"`
+	// Restore is value put back by GC, e.g. original "disabled" of a rule.
+	Restore   string    `json:"restore,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SameObject reports whether both artefacts point to the same router object.
+func (a RouterArtefact) SameObject(other RouterArtefact) bool {
+	return a.Kind == other.Kind && a.Path == other.Path && a.Key == other.Key
+}
+
+// LedgerRepository persists router artefacts owned by capabilities.
+type LedgerRepository interface {
+	// GetArtefact looks up artefact by owner, kind, path and key.
+	GetArtefact(ctx context.Context, key RouterArtefact) (RouterArtefact, bool, error)
+	// UpsertArtefact inserts artefact or bumps UpdatedAt; Restore of first write is kept.
+	UpsertArtefact(ctx context.Context, artefact RouterArtefact) error
+	// DeleteArtefact removes artefact by owner, kind, path and key.
+	DeleteArtefact(ctx context.Context, key RouterArtefact) error
+	DeleteArtefactByID(ctx context.Context, id int64) error
+	ListArtefacts(ctx context.Context) ([]RouterArtefact, error)
+}
+
+// GC reasons explain why an artefact is orphaned.
+const (
+	GCReasonCapabilityDeleted = "capability_deleted"
+	GCReasonDeviceGone        = "device_gone"
+)
+
+// GC operations describe what reconciler does with an orphaned artefact.
+const (
+	// GCOperationRemove deletes address-list entry or created object.
+	GCOperationRemove = "remove"
+	// GCOperationRestore puts firewall rule back to its original state.
+	GCOperationRestore = "restore"
+	// GCOperationForget only drops ledger row, e.g. object still owned by a live capability.
+	GCOperationForget = "forget"
+)
+
+// GCItem is one orphaned artefact and reconciler outcome.
+type GCItem struct {
+	Artefact  RouterArtefact `json:"artefact"`
+	Reason    string         `json:"reason"`
+	Operation string         `json:"operation"`
+	Done      bool           `json:"done"`
+	Error     string         `json:"error,omitempty"`
+}
+
+// GCReport summarizes one reconciler pass.
+type GCReport struct {
+	DryRun    bool      `json:"dry_run"`
+	Scanned   int       `json:"scanned"`
+	Items     []GCItem  `json:"items"`
+	StartedAt time.Time `json:"started_at"`
+}
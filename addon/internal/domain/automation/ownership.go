@@ -0,0 +1,44 @@
+package automation
+
+import "strings"
+
+// OwnershipCommentPrefix marks router objects created by the add-on.
+const OwnershipCommentPrefix = "mikrotik-presence:"
+
+// OwnershipTag identifies capability (and device, for device scope) owning a router object.
+type OwnershipTag struct {
+	CapabilityID string `json:"capability_id"`
+	DeviceID     string `json:"device_id,omitempty"`
+}
+
+// OwnershipComment formats tag as RouterOS comment, e.g.
+// "mikrotik-presence:kids.internet/AA:BB:CC:DD:EE:FF".
+func OwnershipComment(tag OwnershipTag) string {
+	comment := OwnershipCommentPrefix + strings.TrimSpace(tag.CapabilityID)
+	if device := strings.TrimSpace(tag.DeviceID); device != "" {
+		comment += "/" + device
+	}
+	return comment
+}
+
+// ParseOwnershipComment extracts tag from comment written by OwnershipComment.
+func ParseOwnershipComment(comment string) (OwnershipTag, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(comment), OwnershipCommentPrefix)
+	if !ok || rest == "" {
+		return OwnershipTag{}, false
+	}
+	capabilityID, deviceID, _ := strings.Cut(rest, "/")
+	if capabilityID == "" {
+		return OwnershipTag{}, false
+	}
+	return OwnershipTag{CapabilityID: capabilityID, DeviceID: deviceID}, true
+}
+
+// OwnershipTagFor returns tag for action execution context.
+func OwnershipTagFor(execCtx ActionExecutionContext) OwnershipTag {
+	tag := OwnershipTag{CapabilityID: execCtx.CapabilityID}
+	if execCtx.Target.Device != nil {
+		tag.DeviceID = execCtx.Target.Device.MAC
+	}
+	return tag
+}
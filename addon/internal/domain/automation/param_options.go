@@ -0,0 +1,24 @@
+package automation
+
+const (
+	// OptionsRouterAddressLists lists firewall address-list names.
+	OptionsRouterAddressLists = "router.address_lists"
+	// OptionsRouterFirewallRules lists firewall rule ids, arg filters by table.
+	OptionsRouterFirewallRules = "router.firewall_rules"
+	// OptionsRouterFirewallComments lists distinct firewall rule comments, arg filters by table.
+	OptionsRouterFirewallComments = "router.firewall_comments"
+	// OptionsRouterInterfaces lists interface names.
+	OptionsRouterInterfaces = "router.interfaces"
+	// OptionsRouterQueues lists simple queue names.
+	OptionsRouterQueues = "router.queues"
+	// OptionsRouterKidControlProfiles lists kid-control profile names.
+	OptionsRouterKidControlProfiles = "router.kid_control_profiles"
+	// OptionsRouterDNSAdlists lists DNS adlist URLs and files.
+	OptionsRouterDNSAdlists = "router.dns_adlists"
+)
+
+// ParamOption is one live value offered by options provider.
+type ParamOption struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+}
@@ -18,4 +18,7 @@ type Repository interface {
 	GetGlobalCapability(ctx context.Context, capabilityID string) (*GlobalCapability, error)
 	SaveGlobalCapability(ctx context.Context, capability *GlobalCapability) error
 	ListGlobalCapabilities(ctx context.Context) ([]GlobalCapability, error)
+
+	InsertDriftEvent(ctx context.Context, event DriftEvent) error
+	ListDriftEvents(ctx context.Context, filter DriftEventFilter) ([]DriftEvent, error)
 }
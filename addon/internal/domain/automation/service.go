@@ -29,4 +29,12 @@ type Service interface {
 		state *string,
 		enabled *bool,
 	) (SetStateResult, error)
+
+	ListDriftEvents(ctx context.Context, filter DriftEventFilter) ([]DriftEvent, error)
+	ListSyncStatus(ctx context.Context) ([]SyncStatus, error)
+	ParamOptions(ctx context.Context, provider string, arg string) ([]ParamOption, error)
+
+	ListArtefacts(ctx context.Context) ([]RouterArtefact, error)
+	PreviewArtefactGC(ctx context.Context) (GCReport, error)
+	RunArtefactGC(ctx context.Context) (GCReport, error)
 }
@@ -7,17 +7,57 @@ import (
 	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
 )
 
+const (
+	// StateOutputBoolean is output type mapped through sync.mapping.
+	StateOutputBoolean = "boolean"
+	// StateOutputBooleanOrString is output type chosen by source params;
+	// string values are used as capability state directly.
+	StateOutputBooleanOrString = "boolean|string"
+)
+
 // AddressListStateClient provides read operations for RouterOS address-lists.
 type AddressListStateClient interface {
 	AddressListContains(ctx context.Context, cfg model.RouterConfig, list, address string) (bool, error)
 }
 
+// AddressListSnapshotClient lists address-list entries in one router read.
+type AddressListSnapshotClient interface {
+	ListAddressListAddresses(ctx context.Context, cfg model.RouterConfig, list string) ([]string, error)
+}
+
 // FirewallRuleStateClient provides read operations for RouterOS firewall rules.
 type FirewallRuleStateClient interface {
 	GetFirewallRuleEnabled(ctx context.Context, cfg model.RouterConfig, table, ruleID string) (bool, error)
 	GetFirewallRulesEnabledByComment(ctx context.Context, cfg model.RouterConfig, table, comment string) (bool, error)
 }
 
+// SimpleQueueStateClient lists addresses capped by enabled simple queues.
+type SimpleQueueStateClient interface {
+	ListLimitedQueueTargets(ctx context.Context, cfg model.RouterConfig) ([]string, error)
+}
+
+// ContentFilterStateClient lists addresses with active domain blocking rules.
+type ContentFilterStateClient interface {
+	ListContentFilteredTargets(ctx context.Context, cfg model.RouterConfig) ([]string, error)
+}
+
+// KidControlStateClient reads kid-control profile state and MAC bindings.
+type KidControlStateClient interface {
+	GetKidControlPaused(ctx context.Context, cfg model.RouterConfig, profile string) (bool, error)
+	KidControlProfileForMAC(ctx context.Context, cfg model.RouterConfig, mac string) (string, error)
+}
+
+// DNSAdlistStateClient reports whether DNS adlists are enabled; empty source
+// selects every adlist.
+type DNSAdlistStateClient interface {
+	GetDNSAdlistsEnabled(ctx context.Context, cfg model.RouterConfig, source string) (bool, error)
+}
+
+// WiFiAccessStateClient reports whether wifi access list denies MAC.
+type WiFiAccessStateClient interface {
+	GetWiFiAccessDenied(ctx context.Context, cfg model.RouterConfig, mac string) (bool, error)
+}
+
 // RouterStateClient groups RouterOS read operations for state sources.
 type RouterStateClient interface {
 	AddressListStateClient
@@ -48,3 +88,24 @@ type StateSource interface {
 	Validate(target AutomationTarget, params map[string]any) error
 	Read(ctx context.Context, sourceCtx StateSourceContext, params map[string]any) (any, error)
 }
+
+// StateSourceBatchContext contains runtime dependencies for batch state reads.
+type StateSourceBatchContext struct {
+	Targets      []AutomationTarget
+	RouterClient RouterStateClient
+	RouterConfig model.RouterConfig
+	Logger       *slog.Logger
+}
+
+// StateReadResult is one target outcome of a batch state read.
+type StateReadResult struct {
+	Value any
+	Err   error
+}
+
+// BatchStateSource reads external truth for many targets in one router round-trip.
+// Results are aligned with StateSourceBatchContext.Targets by index.
+type BatchStateSource interface {
+	StateSource
+	ReadMany(ctx context.Context, batchCtx StateSourceBatchContext, params map[string]any) ([]StateReadResult, error)
+}
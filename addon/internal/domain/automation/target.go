@@ -0,0 +1,56 @@
+package automation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidateIPTarget checks "target" param of actions and state sources that
+// address one IP: "device.ip" needs device scope and "literal_ip" must not
+// use device placeholders in global scope.
+func ValidateIPTarget(target AutomationTarget, params map[string]any) error {
+	targetParam, err := requiredStringParam(params, "target")
+	if err != nil {
+		return err
+	}
+	scope := NormalizeCapabilityScope(target.Scope)
+	switch targetParam {
+	case "device.ip":
+		if scope == ScopeGlobal {
+			return fmt.Errorf("target %q is not available for global scope", targetParam)
+		}
+	case "literal_ip":
+		literalIP, err := requiredStringParam(params, "literal_ip")
+		if err != nil {
+			return err
+		}
+		if scope == ScopeGlobal && strings.Contains(strings.ToLower(literalIP), "{{device.") {
+			return fmt.Errorf("global scope does not support device placeholders")
+		}
+	default:
+		return fmt.Errorf("unsupported target %q", targetParam)
+	}
+	return nil
+}
+
+// NormalizeAddress makes router and device addresses comparable: lower case,
+// without surrounding space or a host "/32" suffix.
+func NormalizeAddress(value string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(value)), "/32")
+}
+
+func requiredStringParam(params map[string]any, key string) (string, error) {
+	raw, ok := params[key]
+	if !ok {
+		return "", fmt.Errorf("missing param %q", key)
+	}
+	value, ok := raw.(string)
+	if !ok {
+		return "", fmt.Errorf("param %q must be string", key)
+	}
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", fmt.Errorf("param %q is empty", key)
+	}
+	return value, nil
+}
@@ -0,0 +1,69 @@
+package automation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_.]+)\s*\}\}`)
+
+// TemplateVars returns placeholder values available to action params.
+func TemplateVars(execCtx ActionExecutionContext) map[string]string {
+	vars := map[string]string{
+		"capability.id":    execCtx.CapabilityID,
+		"capability.state": execCtx.State,
+		"scope":            string(NormalizeCapabilityScope(execCtx.Target.Scope)),
+	}
+	device := execCtx.Target.Device
+	if device == nil {
+		return vars
+	}
+	vars["device.mac"] = device.MAC
+	vars["device.name"] = device.Name
+	vars["device.vendor"] = device.Vendor
+	vars["device.status"] = device.Status
+	vars["device.ip"] = derefString(device.LastIP)
+	vars["device.host_name"] = derefString(device.HostName)
+	vars["device.interface"] = derefString(device.Interface)
+	vars["device.ssid"] = derefString(device.SSID)
+	vars["device.online"] = "false"
+	if device.Online {
+		vars["device.online"] = "true"
+	}
+	return vars
+}
+
+// RenderTemplate replaces {{key}} placeholders; unknown keys are left as-is.
+func RenderTemplate(raw string, vars map[string]string) string {
+	return templatePlaceholder.ReplaceAllStringFunc(raw, func(match string) string {
+		key := templatePlaceholder.FindStringSubmatch(match)[1]
+		if value, ok := vars[strings.ToLower(key)]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// RenderWords renders placeholders in each already-parsed word value on its
+// own, so substituted text can never add or replace RouterOS words. Values
+// that render to control characters are rejected.
+func RenderWords(words map[string]string, vars map[string]string) (map[string]string, error) {
+	rendered := make(map[string]string, len(words))
+	for key, value := range words {
+		value = RenderTemplate(value, vars)
+		if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+			return nil, fmt.Errorf("rendered value for %q contains control characters", key)
+		}
+		rendered[key] = value
+	}
+	return rendered, nil
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return strings.TrimSpace(*value)
+}
@@ -52,6 +52,15 @@ type ListFilter struct {
 	Query  string
 }
 
+// IPChange describes a device address change detected by a poll cycle.
+// PreviousIP is empty when a known device gets its first address.
+type IPChange struct {
+	MAC        string    `json:"mac"`
+	PreviousIP string    `json:"previous_ip"`
+	CurrentIP  string    `json:"current_ip"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
 // PollSnapshotResult summarizes writes from one poll cycle.
 type PollSnapshotResult struct {
 	ObservedCount int
@@ -10,3 +10,20 @@ type Service interface {
 	RegisterDevice(ctx context.Context, mac string, in RegisterInput) error
 	PatchDevice(ctx context.Context, mac string, in RegisterInput) error
 }
+
+// IPChangeListener receives device IP changes after they are persisted.
+type IPChangeListener interface {
+	HandleDeviceIPChange(ctx context.Context, change IPChange) error
+}
+
+// NewDeviceListener receives MACs that appeared for the first time and are
+// not registered, after the poll cycle is persisted.
+type NewDeviceListener interface {
+	HandleNewDevice(ctx context.Context, mac string) error
+}
+
+// RegistrationListener receives MACs after registered metadata is created or
+// updated.
+type RegistrationListener interface {
+	HandleDeviceRegistered(ctx context.Context, mac string) error
+}
@@ -0,0 +1,10 @@
+package quarantine
+
+import "errors"
+
+var (
+	// ErrNotQuarantined means MAC has no quarantine entry.
+	ErrNotQuarantined = errors.New("device is not quarantined")
+	// ErrStatusInvalid means unsupported status filter.
+	ErrStatusInvalid = errors.New("quarantine status invalid")
+)
@@ -0,0 +1,56 @@
+package quarantine
+
+import "time"
+
+// Status is queue state of a quarantined device.
+type Status string
+
+const (
+	// StatusPending means device waits for approve or deny.
+	StatusPending Status = "pending"
+	// StatusDenied means device was denied and stays blocked.
+	StatusDenied Status = "denied"
+)
+
+// Audit actions recorded for onboarding decisions.
+const (
+	AuditQuarantined = "quarantined"
+	AuditApproved    = "approved"
+	AuditDenied      = "denied"
+	AuditReleased    = "released"
+	AuditApplyFailed = "apply_failed"
+)
+
+// Audit actors.
+const (
+	ActorPolicy = "policy"
+	ActorAPI    = "api"
+)
+
+// Entry is one unknown device held by onboarding policy. Mode and Address
+// keep what was applied on router so release undoes it even after options
+// change.
+type Entry struct {
+	MAC       string    `json:"mac"`
+	Status    Status    `json:"status"`
+	Mode      string    `json:"mode"`
+	Address   string    `json:"address,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AuditEvent is one append-only record of onboarding trail.
+type AuditEvent struct {
+	ID        int64     `json:"id"`
+	MAC       string    `json:"mac"`
+	Action    string    `json:"action"`
+	Actor     string    `json:"actor"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AuditFilter narrows audit trail query.
+type AuditFilter struct {
+	MAC   string
+	Limit int
+}
@@ -0,0 +1,14 @@
+package quarantine
+
+import "context"
+
+// Repository defines persistent storage of quarantine queue and audit trail.
+type Repository interface {
+	GetEntry(ctx context.Context, mac string) (Entry, bool, error)
+	ListEntries(ctx context.Context, status Status) ([]Entry, error)
+	UpsertEntry(ctx context.Context, entry Entry) error
+	DeleteEntry(ctx context.Context, mac string) error
+
+	InsertAuditEvent(ctx context.Context, event AuditEvent) error
+	ListAuditEvents(ctx context.Context, filter AuditFilter) ([]AuditEvent, error)
+}
@@ -0,0 +1,15 @@
+package quarantine
+
+import (
+	"context"
+
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+)
+
+// Service exposes onboarding queue use-cases used by HTTP layer.
+type Service interface {
+	ListEntries(ctx context.Context, status Status) ([]Entry, error)
+	Approve(ctx context.Context, mac string, in devicedomain.RegisterInput) error
+	Deny(ctx context.Context, mac string) error
+	ListAudit(ctx context.Context, filter AuditFilter) ([]AuditEvent, error)
+}
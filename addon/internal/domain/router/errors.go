@@ -0,0 +1,26 @@
+package router
+
+import "errors"
+
+var (
+	// ErrAddonNotConfigured means MikroTik config in add-on options is absent.
+	ErrAddonNotConfigured = errors.New("addon not configured")
+	// ErrFirewallTableInvalid means table is not one of FirewallTables.
+	ErrFirewallTableInvalid = errors.New("firewall table invalid")
+	// ErrFirewallRuleNotFound means rule id does not exist in table.
+	ErrFirewallRuleNotFound = errors.New("firewall rule not found")
+	// ErrMACInvalid means value is not a MAC address.
+	ErrMACInvalid = errors.New("mac address invalid")
+	// ErrAddressInvalid means value is not an IPv4 address.
+	ErrAddressInvalid = errors.New("address invalid")
+	// ErrAddressConflict means address is leased to another MAC.
+	ErrAddressConflict = errors.New("address leased to another device")
+	// ErrAddressInPool means address belongs to DHCP server's dynamic pool.
+	ErrAddressInPool = errors.New("address inside dhcp pool")
+	// ErrDHCPServerRequired means device has no lease and server is ambiguous.
+	ErrDHCPServerRequired = errors.New("dhcp server required")
+	// ErrDHCPServerNotFound means named DHCP server does not exist.
+	ErrDHCPServerNotFound = errors.New("dhcp server not found")
+	// ErrReservationNotFound means MAC has no static lease.
+	ErrReservationNotFound = errors.New("reservation not found")
+)
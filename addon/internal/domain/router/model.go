@@ -0,0 +1,70 @@
+package router
+
+import automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+
+// FirewallTables lists RouterOS firewall tables exposed by the rule browser.
+var FirewallTables = []string{"filter", "nat", "mangle", "raw"}
+
+// FirewallRule is rule browser read model with automation references.
+type FirewallRule struct {
+	ID             string          `json:"id"`
+	Table          string          `json:"table"`
+	Chain          string          `json:"chain"`
+	Action         string          `json:"action"`
+	Comment        string          `json:"comment"`
+	Disabled       bool            `json:"disabled"`
+	SrcAddressList string          `json:"src_address_list,omitempty"`
+	DstAddressList string          `json:"dst_address_list,omitempty"`
+	Bytes          int64           `json:"bytes"`
+	Packets        int64           `json:"packets"`
+	References     []RuleReference `json:"references"`
+}
+
+// RuleReference points to capability template param that targets a rule.
+type RuleReference struct {
+	CapabilityID    string `json:"capability_id"`
+	CapabilityLabel string `json:"capability_label"`
+	// Location is template path, e.g. states.on.actions_on_enter[0] or sync.source.
+	Location string `json:"location"`
+	// MatchBy is "id" or "comment".
+	MatchBy string `json:"match_by"`
+}
+
+// AddressList summarizes one firewall address-list.
+type AddressList struct {
+	Name    string `json:"name"`
+	Entries int    `json:"entries"`
+	// Managed counts entries tagged with add-on ownership comment.
+	Managed int `json:"managed"`
+}
+
+// AddressListEntry is address-list row with ownership resolved from comment.
+type AddressListEntry struct {
+	ID           string                         `json:"id"`
+	List         string                         `json:"list"`
+	Address      string                         `json:"address"`
+	Comment      string                         `json:"comment"`
+	Timeout      string                         `json:"timeout,omitempty"`
+	CreationTime string                         `json:"creation_time,omitempty"`
+	Dynamic      bool                           `json:"dynamic"`
+	Disabled     bool                           `json:"disabled"`
+	Managed      bool                           `json:"managed"`
+	Owner        *automationdomain.OwnershipTag `json:"owner,omitempty"`
+}
+
+// ReservationInput pins device address. Empty Address keeps currently leased
+// one; Server is only needed when device has no lease and router runs several
+// DHCP servers. Empty Comment keeps existing comment.
+type ReservationInput struct {
+	Address string `json:"address"`
+	Server  string `json:"server"`
+	Comment string `json:"comment"`
+}
+
+// Reservation is static DHCP lease pinning device address.
+type Reservation struct {
+	MAC     string `json:"mac"`
+	Address string `json:"address"`
+	Server  string `json:"server"`
+	Comment string `json:"comment,omitempty"`
+}
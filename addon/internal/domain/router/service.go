@@ -0,0 +1,14 @@
+package router
+
+import "context"
+
+// Service exposes direct router inspection and management use-cases.
+type Service interface {
+	ListFirewallRules(ctx context.Context, table string) ([]FirewallRule, error)
+	SetFirewallRuleDisabled(ctx context.Context, table string, id string, disabled bool) (FirewallRule, error)
+	ListAddressLists(ctx context.Context) ([]AddressList, error)
+	ListAddressListEntries(ctx context.Context, list string) ([]AddressListEntry, error)
+	WakeOnLAN(ctx context.Context, mac string, iface string) error
+	ReserveAddress(ctx context.Context, mac string, in ReservationInput) (Reservation, error)
+	RemoveReservation(ctx context.Context, mac string) error
+}
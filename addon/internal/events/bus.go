@@ -0,0 +1,195 @@
+package events
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Topics group event types for subscription filters.
+const (
+	TopicDevice     = "device"
+	TopicCapability = "capability"
+	TopicPoll       = "poll"
+)
+
+// Event types published by backend services.
+const (
+	TypeDeviceStatus    = "device.status"
+	TypeDeviceNew       = "device.new"
+	TypeCapabilityState = "capability.state"
+	TypePollSucceeded   = "poll.succeeded"
+	TypePollFailed      = "poll.failed"
+)
+
+// Topics lists every known topic.
+var Topics = []string{TopicDevice, TopicCapability, TopicPoll}
+
+// DefaultBufferSize is number of recent events kept for resume.
+const DefaultBufferSize = 512
+
+// subscriberQueue bounds events waiting for one subscriber; a subscriber that
+// falls further behind is dropped and expected to resume by last event id.
+const subscriberQueue = 64
+
+// Event is one typed message on the bus. Topic is type prefix before the dot.
+type Event struct {
+	ID    uint64    `json:"id"`
+	Type  string    `json:"type"`
+	Topic string    `json:"topic"`
+	Time  time.Time `json:"time"`
+	Data  any       `json:"data"`
+}
+
+// Publisher publishes events to the bus.
+type Publisher interface {
+	Publish(eventType string, data any) Event
+}
+
+// Bus fans events out to subscribers and keeps a bounded ring of recent
+// events so reconnecting clients can resume.
+type Bus struct {
+	mu     sync.Mutex
+	nextID uint64
+	ring   []Event
+	start  int
+	size   int
+	subs   map[*Subscription]struct{}
+}
+
+// NewBus creates bus keeping up to capacity recent events.
+func NewBus(capacity int) *Bus {
+	if capacity <= 0 {
+		capacity = DefaultBufferSize
+	}
+	return &Bus{
+		ring: make([]Event, capacity),
+		subs: map[*Subscription]struct{}{},
+	}
+}
+
+// Publish stamps event with next id and delivers it to matching subscribers.
+func (b *Bus) Publish(eventType string, data any) Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	event := Event{
+		ID:    b.nextID,
+		Type:  eventType,
+		Topic: TopicOf(eventType),
+		Time:  time.Now().UTC(),
+		Data:  data,
+	}
+	if b.size < len(b.ring) {
+		b.ring[(b.start+b.size)%len(b.ring)] = event
+		b.size++
+	} else {
+		b.ring[b.start] = event
+		b.start = (b.start + 1) % len(b.ring)
+	}
+
+	for sub := range b.subs {
+		if !sub.matches(event) {
+			continue
+		}
+		select {
+		case sub.ch <- event:
+		default:
+			b.dropLocked(sub)
+		}
+	}
+	return event
+}
+
+// Subscribe registers subscriber for topics (empty means all). Buffered
+// events after lastID are returned as backlog; complete is false when events
+// after lastID were already evicted and client should reload full state.
+func (b *Bus) Subscribe(topics []string, lastID uint64) (backlog []Event, sub *Subscription, complete bool) {
+	sub = &Subscription{
+		bus:    b,
+		ch:     make(chan Event, subscriberQueue),
+		topics: map[string]struct{}{},
+	}
+	for _, topic := range topics {
+		sub.topics[topic] = struct{}{}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete = true
+	if lastID > 0 {
+		oldest := b.nextID + 1
+		if b.size > 0 {
+			oldest = b.ring[b.start].ID
+		}
+		complete = lastID+1 >= oldest && lastID <= b.nextID
+		for i := 0; i < b.size; i++ {
+			event := b.ring[(b.start+i)%len(b.ring)]
+			if event.ID > lastID && sub.matches(event) {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+	b.subs[sub] = struct{}{}
+	return backlog, sub, complete
+}
+
+// LastID returns id of most recent event.
+func (b *Bus) LastID() uint64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.nextID
+}
+
+func (b *Bus) dropLocked(sub *Subscription) {
+	if _, ok := b.subs[sub]; !ok {
+		return
+	}
+	delete(b.subs, sub)
+	close(sub.ch)
+}
+
+// Subscription receives events until closed by subscriber or dropped by bus.
+type Subscription struct {
+	bus    *Bus
+	ch     chan Event
+	topics map[string]struct{}
+}
+
+// C delivers events; it is closed when subscription ends.
+func (s *Subscription) C() <-chan Event {
+	return s.ch
+}
+
+// Close unregisters subscription.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.dropLocked(s)
+}
+
+func (s *Subscription) matches(event Event) bool {
+	if len(s.topics) == 0 {
+		return true
+	}
+	_, ok := s.topics[event.Topic]
+	return ok
+}
+
+// TopicOf returns topic of event type.
+func TopicOf(eventType string) string {
+	topic, _, _ := strings.Cut(eventType, ".")
+	return topic
+}
+
+// KnownTopic reports whether topic can be subscribed to.
+func KnownTopic(topic string) bool {
+	for _, known := range Topics {
+		if topic == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package events
+
+import "testing"
+
+func TestBusDeliversFilteredEvents(t *testing.T) {
+	bus := NewBus(8)
+	_, devices, _ := bus.Subscribe([]string{TopicDevice}, 0)
+	defer devices.Close()
+	_, all, _ := bus.Subscribe(nil, 0)
+	defer all.Close()
+
+	bus.Publish(TypePollSucceeded, nil)
+	bus.Publish(TypeDeviceNew, map[string]string{"mac": "AA:BB:CC:DD:EE:01"})
+
+	if event := <-devices.C(); event.Type != TypeDeviceNew || event.Topic != TopicDevice || event.ID != 2 {
+		t.Fatalf("unexpected device event %+v", event)
+	}
+	if first, second := <-all.C(), <-all.C(); first.Type != TypePollSucceeded || second.Type != TypeDeviceNew {
+		t.Fatalf("unexpected unfiltered events %+v %+v", first, second)
+	}
+}
+
+func TestBusResumesFromBuffer(t *testing.T) {
+	bus := NewBus(3)
+	for i := 0; i < 5; i++ {
+		bus.Publish(TypeDeviceStatus, i)
+	}
+
+	backlog, sub, complete := bus.Subscribe(nil, 3)
+	sub.Close()
+	if !complete || len(backlog) != 2 || backlog[0].ID != 4 || backlog[1].ID != 5 {
+		t.Fatalf("unexpected resume backlog %+v complete=%v", backlog, complete)
+	}
+
+	backlog, sub, complete = bus.Subscribe(nil, 1)
+	sub.Close()
+	if complete || len(backlog) != 3 {
+		t.Fatalf("expected evicted gap to be reported, got %d events complete=%v", len(backlog), complete)
+	}
+
+	if _, sub, complete = bus.Subscribe(nil, 99); complete {
+		t.Fatal("expected id from previous run to be reported as incomplete")
+	}
+	sub.Close()
+}
+
+func TestBusDropsSlowSubscriber(t *testing.T) {
+	bus := NewBus(0)
+	_, sub, _ := bus.Subscribe(nil, 0)
+	for i := 0; i <= subscriberQueue; i++ {
+		bus.Publish(TypePollSucceeded, nil)
+	}
+	received := 0
+	for range sub.C() {
+		received++
+	}
+	if received != subscriberQueue {
+		t.Fatalf("expected %d queued events before drop, got %d", subscriberQueue, received)
+	}
+	sub.Close()
+}
@@ -0,0 +1,34 @@
+package events
+
+// DeviceStatusChange is data of device.status event.
+type DeviceStatusChange struct {
+	MAC            string `json:"mac"`
+	PreviousStatus string `json:"previous_status,omitempty"`
+	Status         string `json:"status"`
+	Reason         string `json:"reason"`
+	Online         bool   `json:"online"`
+	IP             string `json:"ip,omitempty"`
+}
+
+// NewDevice is data of device.new event.
+type NewDevice struct {
+	MAC    string `json:"mac"`
+	Name   string `json:"name"`
+	Vendor string `json:"vendor"`
+	IP     string `json:"ip,omitempty"`
+}
+
+// CapabilityStateChange is data of capability.state event.
+type CapabilityStateChange struct {
+	Scope        string `json:"scope"`
+	DeviceID     string `json:"device_id,omitempty"`
+	CapabilityID string `json:"capability_id"`
+	Enabled      bool   `json:"enabled"`
+	State        string `json:"state"`
+}
+
+// PollResult is data of poll.succeeded and poll.failed events.
+type PollResult struct {
+	DurationMS int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
@@ -10,6 +10,8 @@ import (
 
 	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
 	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	quarantinedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/quarantine"
+	routerdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/router"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
 )
 
@@ -27,7 +29,10 @@ type ConfigProvider interface {
 type API struct {
 	devices    devicedomain.Service
 	automation automationdomain.Service
+	router     routerdomain.Service
+	quarantine quarantinedomain.Service
 	poller     Poller
+	events     EventSource
 	config     ConfigProvider
 	logger     *slog.Logger
 	staticDir  string
@@ -37,7 +42,10 @@ type API struct {
 func New(
 	devices devicedomain.Service,
 	automation automationdomain.Service,
+	router routerdomain.Service,
+	quarantine quarantinedomain.Service,
 	poller Poller,
+	events EventSource,
 	config ConfigProvider,
 	logger *slog.Logger,
 	staticDir string,
@@ -45,7 +53,10 @@ func New(
 	return &API{
 		devices:    devices,
 		automation: automation,
+		router:     router,
+		quarantine: quarantine,
 		poller:     poller,
+		events:     events,
 		config:     config,
 		logger:     logger,
 		staticDir:  staticDir,
@@ -96,3 +107,20 @@ func writeError(w http.ResponseWriter, status int, code string, message string)
 		},
 	})
 }
+
+// writeFieldErrors extends error envelope with field-level validation paths.
+func writeFieldErrors(
+	w http.ResponseWriter,
+	status int,
+	code string,
+	message string,
+	fields []automationdomain.FieldError,
+) {
+	writeJSON(w, status, map[string]any{
+		"error": map[string]any{
+			"code":    code,
+			"message": message,
+			"fields":  fields,
+		},
+	})
+}
@@ -0,0 +1,33 @@
+package handlers
+
+import "net/http"
+
+// ListArtefacts returns router artefacts recorded in ownership ledger.
+func (a *API) ListArtefacts(w http.ResponseWriter, r *http.Request) {
+	artefacts, err := a.automation.ListArtefacts(r.Context())
+	if err != nil {
+		writeAutomationServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, artefacts)
+}
+
+// PreviewArtefactGC lists orphaned artefacts without changing router config.
+func (a *API) PreviewArtefactGC(w http.ResponseWriter, r *http.Request) {
+	report, err := a.automation.PreviewArtefactGC(r.Context())
+	if err != nil {
+		writeAutomationServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// RunArtefactGC removes or restores orphaned artefacts on router.
+func (a *API) RunArtefactGC(w http.ResponseWriter, r *http.Request) {
+	report, err := a.automation.RunArtefactGC(r.Context())
+	if err != nil {
+		writeAutomationServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
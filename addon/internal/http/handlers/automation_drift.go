@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+// ListDriftEvents returns router drift detected by enforce sync mode.
+func (a *API) ListDriftEvents(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := automationdomain.DriftEventFilter{
+		CapabilityID: strings.TrimSpace(query.Get("capability_id")),
+		DeviceID:     strings.TrimSpace(query.Get("device_id")),
+	}
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid_limit", "limit must be a positive integer")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := a.automation.ListDriftEvents(r.Context(), filter)
+	if err != nil {
+		writeAutomationServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+// ListSyncStatus returns last run details of scheduled capability sync.
+func (a *API) ListSyncStatus(w http.ResponseWriter, r *http.Request) {
+	items, err := a.automation.ListSyncStatus(r.Context())
+	if err != nil {
+		writeAutomationServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
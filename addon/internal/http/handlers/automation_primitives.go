@@ -17,23 +17,52 @@ func (a *API) ListStateSourceTypes(w http.ResponseWriter, _ *http.Request) {
 	writeJSON(w, http.StatusOK, a.automation.StateSourceTypes())
 }
 
+// ListParamOptions returns live router values for dynamic param pickers.
+func (a *API) ListParamOptions(w http.ResponseWriter, r *http.Request, provider string) {
+	options, err := a.automation.ParamOptions(r.Context(), provider, r.URL.Query().Get("arg"))
+	if err != nil {
+		writeAutomationServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, options)
+}
+
 func writeAutomationServiceError(w http.ResponseWriter, err error) {
+	var paramErr *automationdomain.ParamValidationError
+	if errors.As(err, &paramErr) {
+		writeFieldErrors(w, http.StatusBadRequest, "capability_invalid", err.Error(), paramErr.Fields)
+		return
+	}
+	status, code := automationErrorCode(err)
+	writeError(w, status, code, err.Error())
+}
+
+// automationErrorCode maps automation service error to HTTP status and
+// stable error code shared by REST and WebSocket responses.
+func automationErrorCode(err error) (int, string) {
+	var paramErr *automationdomain.ParamValidationError
 	switch {
+	case errors.As(err, &paramErr):
+		return http.StatusBadRequest, "capability_invalid"
 	case errors.Is(err, automationdomain.ErrCapabilityNotFound):
-		writeError(w, http.StatusNotFound, "capability_not_found", err.Error())
+		return http.StatusNotFound, "capability_not_found"
 	case errors.Is(err, automationdomain.ErrCapabilityConflict):
-		writeError(w, http.StatusConflict, "capability_conflict", err.Error())
+		return http.StatusConflict, "capability_conflict"
 	case errors.Is(err, automationdomain.ErrCapabilityInvalid):
-		writeError(w, http.StatusBadRequest, "capability_invalid", err.Error())
+		return http.StatusBadRequest, "capability_invalid"
 	case errors.Is(err, automationdomain.ErrCapabilityStateInvalid):
-		writeError(w, http.StatusBadRequest, "capability_state_invalid", err.Error())
+		return http.StatusBadRequest, "capability_state_invalid"
 	case errors.Is(err, automationdomain.ErrCapabilityScopeMismatch):
-		writeError(w, http.StatusBadRequest, "capability_scope_mismatch", err.Error())
+		return http.StatusBadRequest, "capability_scope_mismatch"
 	case errors.Is(err, automationdomain.ErrCapabilityScopeInvalid):
-		writeError(w, http.StatusBadRequest, "capability_scope_invalid", err.Error())
+		return http.StatusBadRequest, "capability_scope_invalid"
 	case errors.Is(err, automationdomain.ErrDeviceNotFound):
-		writeError(w, http.StatusNotFound, "device_not_found", err.Error())
+		return http.StatusNotFound, "device_not_found"
+	case errors.Is(err, automationdomain.ErrOptionsProviderUnknown):
+		return http.StatusNotFound, "options_provider_not_found"
+	case errors.Is(err, automationdomain.ErrAddonNotConfigured):
+		return http.StatusConflict, "addon_not_configured"
 	default:
-		writeError(w, http.StatusInternalServerError, "automation_failed", err.Error())
+		return http.StatusInternalServerError, "automation_failed"
 	}
 }
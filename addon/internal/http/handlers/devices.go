@@ -3,11 +3,13 @@ package handlers
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"strings"
 
 	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	routerdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/router"
 )
 
 // ListDevices returns current device list.
@@ -83,6 +85,76 @@ func (a *API) PatchDevice(w http.ResponseWriter, r *http.Request, mac string) {
 	writeJSON(w, http.StatusAccepted, map[string]any{"ok": true})
 }
 
+type wakeDeviceRequest struct {
+	Interface string `json:"interface"`
+}
+
+// WakeDevice sends Wake-on-LAN packet to a known device. Interface defaults to
+// the bridge or interface the device was last seen on; body is optional.
+func (a *API) WakeDevice(w http.ResponseWriter, r *http.Request, mac string) {
+	var payload wakeDeviceRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid_payload", "Invalid JSON payload")
+		return
+	}
+
+	device, err := a.devices.GetDevice(r.Context(), mac)
+	if errors.Is(err, devicedomain.ErrDeviceNotFound) {
+		writeError(w, http.StatusNotFound, "not_found", "Device not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "get_failed", err.Error())
+		return
+	}
+
+	iface := strings.TrimSpace(payload.Interface)
+	if iface == "" {
+		iface = device.WakeInterface()
+	}
+	if err := a.router.WakeOnLAN(r.Context(), device.MAC, iface); err != nil {
+		writeRouterServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"ok": true, "interface": iface})
+}
+
+// ReserveDevice pins device address with static DHCP lease; body is optional
+// and defaults to the currently leased address.
+func (a *API) ReserveDevice(w http.ResponseWriter, r *http.Request, mac string) {
+	var payload routerdomain.ReservationInput
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid_payload", "Invalid JSON payload")
+		return
+	}
+
+	device, err := a.devices.GetDevice(r.Context(), mac)
+	if errors.Is(err, devicedomain.ErrDeviceNotFound) {
+		writeError(w, http.StatusNotFound, "not_found", "Device not found")
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "get_failed", err.Error())
+		return
+	}
+
+	reservation, err := a.router.ReserveAddress(r.Context(), device.MAC, payload)
+	if err != nil {
+		writeRouterServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, reservation)
+}
+
+// UnreserveDevice removes static DHCP leases of device.
+func (a *API) UnreserveDevice(w http.ResponseWriter, r *http.Request, mac string) {
+	if err := a.router.RemoveReservation(r.Context(), mac); err != nil {
+		writeRouterServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"ok": true})
+}
+
 // Refresh triggers immediate poll cycle asynchronously.
 func (a *API) Refresh(w http.ResponseWriter, _ *http.Request) {
 	a.poller.TriggerRefresh()
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/events"
+)
+
+// EventSource streams backend events to live clients.
+type EventSource interface {
+	Subscribe(topics []string, lastID uint64) ([]events.Event, *events.Subscription, bool)
+}
+
+const (
+	eventsKeepAlive = 15 * time.Second
+	eventsRetry     = 3 * time.Second
+)
+
+// Events streams backend events as Server-Sent Events. Clients filter with
+// comma-separated topics and resume with Last-Event-ID; a reset event tells
+// client that buffered history was lost and full state should be reloaded.
+func (a *API) Events(w http.ResponseWriter, r *http.Request) {
+	if a.events == nil {
+		writeError(w, http.StatusServiceUnavailable, "events_unavailable", "Event stream is not available")
+		return
+	}
+	query := r.URL.Query()
+	var topics []string
+	for _, raw := range strings.Split(query.Get("topics"), ",") {
+		topic := strings.ToLower(strings.TrimSpace(raw))
+		if topic == "" {
+			continue
+		}
+		if !events.KnownTopic(topic) {
+			writeError(w, http.StatusBadRequest, "invalid_topic", fmt.Sprintf("Unknown topic %q", topic))
+			return
+		}
+		topics = append(topics, topic)
+	}
+	rawLastID := strings.TrimSpace(r.Header.Get("Last-Event-ID"))
+	if rawLastID == "" {
+		rawLastID = strings.TrimSpace(query.Get("last_event_id"))
+	}
+	var lastID uint64
+	if rawLastID != "" {
+		parsed, err := strconv.ParseUint(rawLastID, 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid_last_event_id", "Last-Event-ID must be a non-negative integer")
+			return
+		}
+		lastID = parsed
+	}
+
+	controller := http.NewResponseController(w)
+	// Stream outlives server write timeout; clear it for this response only.
+	_ = controller.SetWriteDeadline(time.Time{})
+
+	backlog, sub, complete := a.events.Subscribe(topics, lastID)
+	defer sub.Close()
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", eventsRetry.Milliseconds()); err != nil {
+		return
+	}
+	if !complete {
+		if _, err := fmt.Fprint(w, "event: reset\ndata: {}\n\n"); err != nil {
+			return
+		}
+	}
+	for _, event := range backlog {
+		if err := writeEvent(w, event); err != nil {
+			return
+		}
+	}
+	if err := controller.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(eventsKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-sub.C():
+			if !ok {
+				// Dropped for falling behind; client reconnects and resumes.
+				return
+			}
+			if err := writeEvent(w, event); err != nil {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+	return err
+}
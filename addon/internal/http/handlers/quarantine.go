@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	quarantinedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/quarantine"
+)
+
+// ListQuarantine returns devices held by onboarding policy.
+func (a *API) ListQuarantine(w http.ResponseWriter, r *http.Request) {
+	status := quarantinedomain.Status(strings.ToLower(strings.TrimSpace(r.URL.Query().Get("status"))))
+	items, err := a.quarantine.ListEntries(r.Context(), status)
+	if err != nil {
+		writeQuarantineServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, items)
+}
+
+// ApproveQuarantine releases device and registers it; body is optional
+// register payload.
+func (a *API) ApproveQuarantine(w http.ResponseWriter, r *http.Request, mac string) {
+	var payload devicedomain.RegisterInput
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil && !errors.Is(err, io.EOF) {
+		writeError(w, http.StatusBadRequest, "invalid_payload", "Invalid JSON payload")
+		return
+	}
+	if err := a.quarantine.Approve(r.Context(), mac, payload); err != nil {
+		writeQuarantineServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"ok": true})
+}
+
+// DenyQuarantine keeps device blocked.
+func (a *API) DenyQuarantine(w http.ResponseWriter, r *http.Request, mac string) {
+	if err := a.quarantine.Deny(r.Context(), mac); err != nil {
+		writeQuarantineServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, map[string]any{"ok": true})
+}
+
+// ListQuarantineAudit returns onboarding audit trail, newest first.
+func (a *API) ListQuarantineAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := quarantinedomain.AuditFilter{MAC: strings.TrimSpace(query.Get("mac"))}
+	if raw := strings.TrimSpace(query.Get("limit")); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			writeError(w, http.StatusBadRequest, "invalid_limit", "limit must be a positive integer")
+			return
+		}
+		filter.Limit = limit
+	}
+
+	events, err := a.quarantine.ListAudit(r.Context(), filter)
+	if err != nil {
+		writeQuarantineServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, events)
+}
+
+func writeQuarantineServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, quarantinedomain.ErrNotQuarantined):
+		writeError(w, http.StatusNotFound, "not_found", "Device is not quarantined")
+	case errors.Is(err, quarantinedomain.ErrStatusInvalid):
+		writeError(w, http.StatusBadRequest, "invalid_status", "status must be pending or denied")
+	case errors.Is(err, devicedomain.ErrAddonNotConfigured):
+		writeError(w, http.StatusConflict, "addon_not_configured", "Add-on is not configured")
+	default:
+		writeError(w, http.StatusInternalServerError, "quarantine_failed", err.Error())
+	}
+}
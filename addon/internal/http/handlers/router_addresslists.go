@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// ListAddressLists returns address-list names with entry counts.
+func (a *API) ListAddressLists(w http.ResponseWriter, r *http.Request) {
+	items, err := a.router.ListAddressLists(r.Context())
+	if err != nil {
+		writeRouterServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// ListAddressListEntries returns entries of one address-list with ownership info.
+func (a *API) ListAddressListEntries(w http.ResponseWriter, r *http.Request, list string) {
+	// chi matches on escaped path, list names may contain spaces.
+	if unescaped, err := url.PathUnescape(list); err == nil {
+		list = unescaped
+	}
+	items, err := a.router.ListAddressListEntries(r.Context(), list)
+	if err != nil {
+		writeRouterServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	routerdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/router"
+)
+
+type patchFirewallRuleRequest struct {
+	Disabled *bool `json:"disabled"`
+}
+
+// ListFirewallRules returns rules of one firewall table with capability references.
+func (a *API) ListFirewallRules(w http.ResponseWriter, r *http.Request, table string) {
+	items, err := a.router.ListFirewallRules(r.Context(), table)
+	if err != nil {
+		writeRouterServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"items": items})
+}
+
+// PatchFirewallRule enables or disables one firewall rule directly.
+func (a *API) PatchFirewallRule(w http.ResponseWriter, r *http.Request, table string, id string) {
+	var payload patchFirewallRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_payload", "Invalid JSON payload")
+		return
+	}
+	if payload.Disabled == nil {
+		writeError(w, http.StatusBadRequest, "invalid_payload", "disabled is required")
+		return
+	}
+
+	rule, err := a.router.SetFirewallRuleDisabled(r.Context(), table, id, *payload.Disabled)
+	if err != nil {
+		writeRouterServiceError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rule)
+}
+
+func writeRouterServiceError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, routerdomain.ErrAddonNotConfigured):
+		writeError(w, http.StatusConflict, "addon_not_configured", err.Error())
+	case errors.Is(err, routerdomain.ErrFirewallTableInvalid):
+		writeError(w, http.StatusBadRequest, "firewall_table_invalid", err.Error())
+	case errors.Is(err, routerdomain.ErrFirewallRuleNotFound):
+		writeError(w, http.StatusNotFound, "firewall_rule_not_found", err.Error())
+	case errors.Is(err, routerdomain.ErrMACInvalid):
+		writeError(w, http.StatusBadRequest, "mac_invalid", err.Error())
+	case errors.Is(err, routerdomain.ErrAddressInvalid):
+		writeError(w, http.StatusBadRequest, "address_invalid", err.Error())
+	case errors.Is(err, routerdomain.ErrAddressConflict):
+		writeError(w, http.StatusConflict, "address_conflict", err.Error())
+	case errors.Is(err, routerdomain.ErrAddressInPool):
+		writeError(w, http.StatusConflict, "address_in_pool", err.Error())
+	case errors.Is(err, routerdomain.ErrDHCPServerRequired):
+		writeError(w, http.StatusBadRequest, "dhcp_server_required", err.Error())
+	case errors.Is(err, routerdomain.ErrDHCPServerNotFound):
+		writeError(w, http.StatusBadRequest, "dhcp_server_not_found", err.Error())
+	case errors.Is(err, routerdomain.ErrReservationNotFound):
+		writeError(w, http.StatusNotFound, "reservation_not_found", err.Error())
+	default:
+		writeError(w, http.StatusBadGateway, "router_failed", err.Error())
+	}
+}
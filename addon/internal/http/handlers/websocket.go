@@ -0,0 +1,346 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/events"
+)
+
+// WebSocket command types.
+const (
+	wsSubscribe          = "subscribe"
+	wsUnsubscribe        = "unsubscribe"
+	wsSetCapabilityState = "set_capability_state"
+	wsRefresh            = "refresh"
+	wsRegisterDevice     = "register_device"
+)
+
+const (
+	wsWriteWait    = 10 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsPingInterval = 30 * time.Second
+	wsMaxMessage   = 64 << 10
+	// wsMaxInFlight bounds concurrently running commands per connection;
+	// reading pauses until one finishes.
+	wsMaxInFlight = 8
+)
+
+var wsUpgrader = websocket.Upgrader{ReadBufferSize: 4096, WriteBufferSize: 4096, CheckOrigin: checkWSOrigin}
+
+// checkWSOrigin accepts clients without Origin (integrations and scripts),
+// same-origin pages, and pages served through Home Assistant ingress, where
+// supervisor authenticates user and sets X-Ingress-Path. Browsers cannot add
+// that header to a cross-site handshake, so foreign pages are refused and
+// cannot drive the API through a visitor's session.
+func checkWSOrigin(r *http.Request) bool {
+	origin := strings.TrimSpace(r.Header.Get("Origin"))
+	if origin == "" || strings.TrimSpace(r.Header.Get("X-Ingress-Path")) != "" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(parsed.Host, r.Host)
+}
+
+// wsRequest is one client command. ID is echoed in its result.
+type wsRequest struct {
+	ID           int64                      `json:"id"`
+	Type         string                     `json:"type"`
+	Topics       []string                   `json:"topics"`
+	LastEventID  uint64                     `json:"last_event_id"`
+	DeviceID     string                     `json:"device_id"`
+	CapabilityID string                     `json:"capability_id"`
+	State        *string                    `json:"state"`
+	Enabled      *bool                      `json:"enabled"`
+	MAC          string                     `json:"mac"`
+	Device       devicedomain.RegisterInput `json:"device"`
+}
+
+type wsResult struct {
+	ID      int64    `json:"id"`
+	Type    string   `json:"type"`
+	Success bool     `json:"success"`
+	Result  any      `json:"result,omitempty"`
+	Error   *wsError `json:"error,omitempty"`
+}
+
+type wsError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type wsEvent struct {
+	Type  string        `json:"type"`
+	Event *events.Event `json:"event,omitempty"`
+}
+
+// WebSocket serves bidirectional API on one connection: clients subscribe to
+// event topics and issue commands, each answered by result with same id.
+func (a *API) WebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrader has already written HTTP error response.
+		return
+	}
+	ctx, cancel := context.WithCancel(r.Context())
+	session := &wsSession{api: a, conn: conn, ctx: ctx, cancel: cancel}
+	session.run()
+}
+
+type wsSession struct {
+	api    *API
+	conn   *websocket.Conn
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	writeMu   sync.Mutex
+	subMu     sync.Mutex
+	cancelSub context.CancelFunc
+	workers   sync.WaitGroup
+}
+
+func (s *wsSession) run() {
+	defer func() {
+		s.cancel()
+		s.workers.Wait()
+		_ = s.conn.Close()
+	}()
+
+	s.conn.SetReadLimit(wsMaxMessage)
+	_ = s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	s.workers.Add(1)
+	go s.keepAlive()
+
+	inFlight := make(chan struct{}, wsMaxInFlight)
+	for {
+		_, payload, err := s.conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req wsRequest
+		if err := json.Unmarshal(payload, &req); err != nil {
+			s.fail(0, "invalid_payload", "Invalid JSON payload")
+			continue
+		}
+		switch req.Type {
+		case wsSubscribe:
+			s.subscribe(req)
+		case wsUnsubscribe:
+			s.unsubscribe()
+			s.reply(req.ID, map[string]any{"ok": true})
+		case wsRefresh:
+			s.api.poller.TriggerRefresh()
+			s.reply(req.ID, map[string]any{"ok": true})
+		case wsSetCapabilityState, wsRegisterDevice:
+			select {
+			case inFlight <- struct{}{}:
+			case <-s.ctx.Done():
+				return
+			}
+			s.workers.Add(1)
+			go func() {
+				defer func() {
+					<-inFlight
+					s.workers.Done()
+				}()
+				s.execute(req)
+			}()
+		default:
+			s.fail(req.ID, "unknown_command", fmt.Sprintf("Unknown command type %q", req.Type))
+		}
+	}
+}
+
+func (s *wsSession) execute(req wsRequest) {
+	switch req.Type {
+	case wsSetCapabilityState:
+		s.setCapabilityState(req)
+	case wsRegisterDevice:
+		s.registerDevice(req)
+	}
+}
+
+func (s *wsSession) setCapabilityState(req wsRequest) {
+	capabilityID := strings.TrimSpace(req.CapabilityID)
+	if capabilityID == "" {
+		s.fail(req.ID, "invalid_payload", "capability_id is required")
+		return
+	}
+	if req.State != nil {
+		trimmed := strings.TrimSpace(*req.State)
+		req.State = &trimmed
+	}
+	if req.State == nil && req.Enabled == nil {
+		s.fail(req.ID, "invalid_payload", "Either state or enabled must be provided")
+		return
+	}
+	// Empty device_id targets global capability.
+	var err error
+	var result any
+	if deviceID := strings.TrimSpace(req.DeviceID); deviceID != "" {
+		result, err = s.api.automation.PatchDeviceCapability(s.ctx, deviceID, capabilityID, req.State, req.Enabled)
+	} else {
+		result, err = s.api.automation.PatchGlobalCapability(s.ctx, capabilityID, req.State, req.Enabled)
+	}
+	if err != nil {
+		_, code := automationErrorCode(err)
+		s.fail(req.ID, code, err.Error())
+		return
+	}
+	s.reply(req.ID, result)
+}
+
+func (s *wsSession) registerDevice(req wsRequest) {
+	if strings.TrimSpace(req.MAC) == "" {
+		s.fail(req.ID, "invalid_payload", "mac is required")
+		return
+	}
+	if err := s.api.devices.RegisterDevice(s.ctx, req.MAC, req.Device); err != nil {
+		s.fail(req.ID, "register_failed", err.Error())
+		return
+	}
+	s.reply(req.ID, map[string]any{"ok": true})
+}
+
+// subscribe replaces connection subscription. Result is sent before any
+// event so clients can rely on ordering.
+func (s *wsSession) subscribe(req wsRequest) {
+	if s.api.events == nil {
+		s.fail(req.ID, "events_unavailable", "Event stream is not available")
+		return
+	}
+	var topics []string
+	for _, raw := range req.Topics {
+		topic := strings.ToLower(strings.TrimSpace(raw))
+		if !events.KnownTopic(topic) {
+			s.fail(req.ID, "invalid_topic", fmt.Sprintf("Unknown topic %q", topic))
+			return
+		}
+		topics = append(topics, topic)
+	}
+
+	s.unsubscribe()
+	backlog, sub, complete := s.api.events.Subscribe(topics, req.LastEventID)
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.subMu.Lock()
+	s.cancelSub = cancel
+	s.subMu.Unlock()
+
+	s.reply(req.ID, map[string]any{"ok": true, "complete": complete})
+	s.workers.Add(1)
+	go s.forward(ctx, topics, req.LastEventID, backlog, sub, complete)
+}
+
+func (s *wsSession) unsubscribe() {
+	s.subMu.Lock()
+	defer s.subMu.Unlock()
+	if s.cancelSub != nil {
+		s.cancelSub()
+		s.cancelSub = nil
+	}
+}
+
+// forward delivers events until ctx ends. When bus drops subscription for
+// falling behind, it resubscribes from last delivered id so gap is replayed
+// from buffer, or reset is sent when buffer no longer covers it.
+func (s *wsSession) forward(
+	ctx context.Context,
+	topics []string,
+	lastID uint64,
+	backlog []events.Event,
+	sub *events.Subscription,
+	complete bool,
+) {
+	defer s.workers.Done()
+	for {
+		if !complete && !s.send(wsEvent{Type: "reset"}) {
+			sub.Close()
+			return
+		}
+		for i := range backlog {
+			if !s.send(wsEvent{Type: "event", Event: &backlog[i]}) {
+				sub.Close()
+				return
+			}
+			lastID = backlog[i].ID
+		}
+		if !s.drain(ctx, sub, &lastID) {
+			return
+		}
+		backlog, sub, complete = s.api.events.Subscribe(topics, lastID)
+	}
+}
+
+// drain forwards live events; it returns true when subscription was dropped
+// by bus and false when forwarding should stop.
+func (s *wsSession) drain(ctx context.Context, sub *events.Subscription, lastID *uint64) bool {
+	defer sub.Close()
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-sub.C():
+			if !ok {
+				return true
+			}
+			if !s.send(wsEvent{Type: "event", Event: &event}) {
+				return false
+			}
+			*lastID = event.ID
+		}
+	}
+}
+
+func (s *wsSession) keepAlive() {
+	defer s.workers.Done()
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+			s.writeMu.Lock()
+			err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait))
+			s.writeMu.Unlock()
+			if err != nil {
+				s.cancel()
+				_ = s.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+func (s *wsSession) reply(id int64, result any) {
+	s.send(wsResult{ID: id, Type: "result", Success: true, Result: result})
+}
+
+func (s *wsSession) fail(id int64, code string, message string) {
+	s.send(wsResult{ID: id, Type: "result", Error: &wsError{Code: code, Message: message}})
+}
+
+// send writes one message; on failure it closes connection so read loop ends.
+func (s *wsSession) send(message any) bool {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	_ = s.conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+	if err := s.conn.WriteJSON(message); err != nil {
+		s.cancel()
+		_ = s.conn.Close()
+		return false
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/events"
+)
+
+type countingPoller struct {
+	refreshes chan struct{}
+}
+
+func (p *countingPoller) TriggerRefresh() {
+	p.refreshes <- struct{}{}
+}
+
+// wsMessage is union of result and event frames sent by server.
+type wsMessage struct {
+	ID      int64         `json:"id"`
+	Type    string        `json:"type"`
+	Success bool          `json:"success"`
+	Error   *wsError      `json:"error"`
+	Event   *events.Event `json:"event"`
+}
+
+func dialWebSocket(t *testing.T, api *API) *websocket.Conn {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(api.WebSocket))
+	t.Cleanup(server.Close)
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func readMessage(t *testing.T, conn *websocket.Conn) wsMessage {
+	t.Helper()
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var message wsMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("read failed: %v", err)
+	}
+	return message
+}
+
+func TestWebSocketSubscribeReplaysFilteredTopic(t *testing.T) {
+	bus := events.NewBus(16)
+	first := bus.Publish(events.TypeDeviceStatus, map[string]string{"mac": "AA:BB:CC:DD:EE:01"})
+	bus.Publish(events.TypePollSucceeded, nil)
+	bus.Publish(events.TypeDeviceNew, map[string]string{"mac": "AA:BB:CC:DD:EE:02"})
+
+	conn := dialWebSocket(t, &API{events: bus})
+	if err := conn.WriteJSON(map[string]any{"id": 1, "type": "subscribe", "topics": []string{"device"}, "last_event_id": first.ID}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if result := readMessage(t, conn); result.Type != "result" || result.ID != 1 || !result.Success {
+		t.Fatalf("unexpected subscribe result %+v", result)
+	}
+	replayed := readMessage(t, conn)
+	if replayed.Type != "event" || replayed.Event == nil || replayed.Event.Type != events.TypeDeviceNew {
+		t.Fatalf("expected replayed device.new, got %+v", replayed)
+	}
+
+	bus.Publish(events.TypePollFailed, nil)
+	live := bus.Publish(events.TypeDeviceStatus, nil)
+	if got := readMessage(t, conn); got.Event == nil || got.Event.ID != live.ID {
+		t.Fatalf("expected live device event %d, got %+v", live.ID, got)
+	}
+}
+
+func TestWebSocketCommandsEchoID(t *testing.T) {
+	poller := &countingPoller{refreshes: make(chan struct{}, 1)}
+	conn := dialWebSocket(t, &API{poller: poller})
+
+	if err := conn.WriteJSON(map[string]any{"id": 7, "type": "refresh"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	if result := readMessage(t, conn); result.ID != 7 || !result.Success {
+		t.Fatalf("unexpected refresh result %+v", result)
+	}
+	select {
+	case <-poller.refreshes:
+	default:
+		t.Fatal("expected refresh to be triggered")
+	}
+
+	if err := conn.WriteJSON(map[string]any{"id": 8, "type": "reboot"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	result := readMessage(t, conn)
+	if result.ID != 8 || result.Success || result.Error == nil || result.Error.Code != "unknown_command" {
+		t.Fatalf("expected unknown_command error, got %+v", result)
+	}
+}
+
+func TestWebSocketOriginPolicy(t *testing.T) {
+	cases := []struct {
+		origin  string
+		ingress string
+		want    bool
+	}{
+		{origin: "", want: true},
+		{origin: "http://addon.local:8099", want: true},
+		{origin: "https://evil.example", want: false},
+		{origin: "https://homeassistant.local:8123", ingress: "/api/hassio_ingress/token", want: true},
+	}
+	for _, tc := range cases {
+		r := httptest.NewRequest(http.MethodGet, "http://addon.local:8099/api/ws", nil)
+		if tc.origin != "" {
+			r.Header.Set("Origin", tc.origin)
+		}
+		if tc.ingress != "" {
+			r.Header.Set("X-Ingress-Path", tc.ingress)
+		}
+		if got := checkWSOrigin(r); got != tc.want {
+			t.Fatalf("origin %q ingress %q: got %t, want %t", tc.origin, tc.ingress, got, tc.want)
+		}
+	}
+}
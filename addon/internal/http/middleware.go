@@ -1,12 +1,16 @@
 package httpapi
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
 )
 
 // LogProvider provides request logger for middleware.
@@ -38,6 +42,33 @@ func RequestLogger(provider LogProvider) func(http.Handler) http.Handler {
 	}
 }
 
+// streamPathSuffixes name long-lived endpoints exempt from request timeout.
+// Suffix match keeps them exempt before ingress prefix is stripped.
+var streamPathSuffixes = []string{"/api/events", "/api/ws"}
+
+// Timeout bounds request handling time except for streaming endpoints.
+func Timeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		limited := middleware.Timeout(timeout)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if isStreamPath(r.URL.Path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			limited.ServeHTTP(w, r)
+		})
+	}
+}
+
+func isStreamPath(path string) bool {
+	for _, suffix := range streamPathSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
 // StripIngressPrefix removes ingress path prefix sent in reverse proxy header.
 func StripIngressPrefix(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -88,3 +119,17 @@ func (w *responseCapture) Write(body []byte) (int, error) {
 	w.size += size
 	return size, err
 }
+
+// Unwrap exposes underlying writer to http.ResponseController for flushing.
+func (w *responseCapture) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Hijack lets WebSocket upgrades take over connection through the logger.
+func (w *responseCapture) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.statusCode = http.StatusSwitchingProtocols
+	}
+	return conn, rw, err
+}
@@ -17,7 +17,7 @@ func NewRouter(api *handlers.API) http.Handler {
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
 	r.Use(RecoverJSON)
-	r.Use(middleware.Timeout(20 * time.Second))
+	r.Use(Timeout(20 * time.Second))
 	r.Use(StripIngressPrefix)
 	r.Use(RequestLogger(api))
 
@@ -25,6 +25,9 @@ func NewRouter(api *handlers.API) http.Handler {
 	r.Route("/api", func(apiRouter chi.Router) {
 		apiRouter.Get("/automation/action-types", api.ListActionTypes)
 		apiRouter.Get("/automation/state-source-types", api.ListStateSourceTypes)
+		apiRouter.Get("/automation/param-options/{provider}", func(w http.ResponseWriter, r *http.Request) {
+			api.ListParamOptions(w, r, chi.URLParam(r, "provider"))
+		})
 
 		apiRouter.Get("/automation/capabilities", api.ListCapabilities)
 		apiRouter.Get("/automation/capabilities/{id}", func(w http.ResponseWriter, r *http.Request) {
@@ -43,11 +46,38 @@ func NewRouter(api *handlers.API) http.Handler {
 		apiRouter.Patch("/automation/capabilities/{id}/devices/{mac}", func(w http.ResponseWriter, r *http.Request) {
 			api.PatchCapabilityDevice(w, r, chi.URLParam(r, "id"), chi.URLParam(r, "mac"))
 		})
+		apiRouter.Get("/automation/drift-events", api.ListDriftEvents)
+		apiRouter.Get("/automation/artefacts", api.ListArtefacts)
+		apiRouter.Get("/automation/gc/preview", api.PreviewArtefactGC)
+		apiRouter.Post("/automation/gc", api.RunArtefactGC)
+		apiRouter.Get("/automation/sync-status", api.ListSyncStatus)
 		apiRouter.Get("/global/capabilities", api.ListGlobalCapabilities)
 		apiRouter.Patch("/global/capabilities/{capabilityId}", func(w http.ResponseWriter, r *http.Request) {
 			api.PatchGlobalCapability(w, r, chi.URLParam(r, "capabilityId"))
 		})
 
+		apiRouter.Get("/router/firewall/{table}", func(w http.ResponseWriter, r *http.Request) {
+			api.ListFirewallRules(w, r, chi.URLParam(r, "table"))
+		})
+		apiRouter.Patch("/router/firewall/{table}/{id}", func(w http.ResponseWriter, r *http.Request) {
+			api.PatchFirewallRule(w, r, chi.URLParam(r, "table"), chi.URLParam(r, "id"))
+		})
+		apiRouter.Get("/router/address-lists", api.ListAddressLists)
+		apiRouter.Get("/router/address-lists/{list}/entries", func(w http.ResponseWriter, r *http.Request) {
+			api.ListAddressListEntries(w, r, chi.URLParam(r, "list"))
+		})
+
+		apiRouter.Get("/events", api.Events)
+		apiRouter.Get("/ws", api.WebSocket)
+		apiRouter.Get("/quarantine", api.ListQuarantine)
+		apiRouter.Get("/quarantine/audit", api.ListQuarantineAudit)
+		apiRouter.Post("/quarantine/{mac}/approve", func(w http.ResponseWriter, r *http.Request) {
+			api.ApproveQuarantine(w, r, chi.URLParam(r, "mac"))
+		})
+		apiRouter.Post("/quarantine/{mac}/deny", func(w http.ResponseWriter, r *http.Request) {
+			api.DenyQuarantine(w, r, chi.URLParam(r, "mac"))
+		})
+
 		apiRouter.Get("/devices", api.ListDevices)
 		apiRouter.Get("/devices/{mac}/capabilities", func(w http.ResponseWriter, r *http.Request) {
 			api.ListDeviceCapabilities(w, r, chi.URLParam(r, "mac"))
@@ -61,6 +91,15 @@ func NewRouter(api *handlers.API) http.Handler {
 		apiRouter.Post("/devices/{mac}/register", func(w http.ResponseWriter, r *http.Request) {
 			api.RegisterDevice(w, r, chi.URLParam(r, "mac"))
 		})
+		apiRouter.Post("/devices/{mac}/wake", func(w http.ResponseWriter, r *http.Request) {
+			api.WakeDevice(w, r, chi.URLParam(r, "mac"))
+		})
+		apiRouter.Post("/devices/{mac}/reservation", func(w http.ResponseWriter, r *http.Request) {
+			api.ReserveDevice(w, r, chi.URLParam(r, "mac"))
+		})
+		apiRouter.Delete("/devices/{mac}/reservation", func(w http.ResponseWriter, r *http.Request) {
+			api.UnreserveDevice(w, r, chi.URLParam(r, "mac"))
+		})
 		apiRouter.Patch("/devices/{mac}", func(w http.ResponseWriter, r *http.Request) {
 			api.PatchDevice(w, r, chi.URLParam(r, "mac"))
 		})
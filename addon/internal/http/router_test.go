@@ -0,0 +1,65 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/http/handlers"
+)
+
+// artefactService answers artefact routes; other service methods are unused.
+type artefactService struct {
+	automationdomain.Service
+	gcRuns int
+}
+
+func (s *artefactService) ListArtefacts(context.Context) ([]automationdomain.RouterArtefact, error) {
+	return []automationdomain.RouterArtefact{{CapabilityID: "kids.internet", Kind: automationdomain.ArtefactAddressListEntry}}, nil
+}
+
+func (s *artefactService) PreviewArtefactGC(context.Context) (automationdomain.GCReport, error) {
+	return automationdomain.GCReport{DryRun: true, Scanned: 1}, nil
+}
+
+func (s *artefactService) RunArtefactGC(context.Context) (automationdomain.GCReport, error) {
+	s.gcRuns++
+	return automationdomain.GCReport{Scanned: 1}, nil
+}
+
+func TestRouterServesArtefactGCRoutes(t *testing.T) {
+	service := &artefactService{}
+	router := NewRouter(handlers.New(nil, service, nil, nil, nil, nil, nil, nil, ""))
+
+	cases := []struct {
+		method string
+		path   string
+		check  func(body []byte) bool
+	}{
+		{http.MethodGet, "/api/automation/artefacts", func(body []byte) bool {
+			var rows []automationdomain.RouterArtefact
+			return json.Unmarshal(body, &rows) == nil && len(rows) == 1 && rows[0].CapabilityID == "kids.internet"
+		}},
+		{http.MethodGet, "/api/automation/gc/preview", func(body []byte) bool {
+			var report automationdomain.GCReport
+			return json.Unmarshal(body, &report) == nil && report.DryRun
+		}},
+		{http.MethodPost, "/api/automation/gc", func(body []byte) bool {
+			var report automationdomain.GCReport
+			return json.Unmarshal(body, &report) == nil && !report.DryRun && report.Scanned == 1
+		}},
+	}
+	for _, tc := range cases {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(tc.method, tc.path, nil))
+		if recorder.Code != http.StatusOK || !tc.check(recorder.Body.Bytes()) {
+			t.Fatalf("%s %s: status %d body %s", tc.method, tc.path, recorder.Code, recorder.Body.String())
+		}
+	}
+	if service.gcRuns != 1 {
+		t.Fatalf("expected one gc run, got %d", service.gcRuns)
+	}
+}
@@ -1,7 +1,9 @@
 package model
 
 import (
+	"net"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -65,3 +67,58 @@ func (c RouterConfig) BaseURL() string {
 
 	return scheme + "://" + parsed.Host + path
 }
+
+// MQTTConfig holds broker settings for Home Assistant MQTT integration.
+type MQTTConfig struct {
+	Host            string `json:"host"`
+	Port            int    `json:"port"`
+	Username        string `json:"username"`
+	Password        string `json:"password"`
+	DiscoveryPrefix string `json:"discovery_prefix"`
+	BaseTopic       string `json:"base_topic"`
+}
+
+// Enabled reports whether broker host is configured.
+func (c MQTTConfig) Enabled() bool {
+	return strings.TrimSpace(c.Host) != ""
+}
+
+// BrokerURL returns tcp broker address for MQTT clients.
+func (c MQTTConfig) BrokerURL() string {
+	host := strings.TrimSpace(c.Host)
+	if strings.Contains(host, "://") {
+		return host
+	}
+	port := c.Port
+	if port <= 0 {
+		port = 1883
+	}
+	return "tcp://" + net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// Quarantine modes applied to unknown MACs on first sight.
+const (
+	QuarantineOff         = "off"
+	QuarantineWiFiVLAN    = "wifi_vlan"
+	QuarantineAddressList = "address_list"
+)
+
+// QuarantineConfig is onboarding policy for devices that are not registered.
+// VLANID is used by wifi_vlan mode, AddressList by address_list mode.
+type QuarantineConfig struct {
+	Mode        string `json:"mode"`
+	VLANID      int    `json:"vlan_id"`
+	AddressList string `json:"address_list"`
+}
+
+// Enabled reports whether unknown devices are quarantined.
+func (c QuarantineConfig) Enabled() bool {
+	switch c.Mode {
+	case QuarantineWiFiVLAN:
+		return c.VLANID > 0 && c.VLANID <= 4094
+	case QuarantineAddressList:
+		return strings.TrimSpace(c.AddressList) != ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,10 @@
+package model
+
+// ContentFilter lists domains blocked for one device. Rules match TLS SNI
+// (tls-host); BlockQUIC also rejects UDP/443 so browsers fall back to TCP,
+// and BlockDNS adds router-wide static DNS sinkhole entries.
+type ContentFilter struct {
+	Domains   []string `json:"domains"`
+	BlockQUIC bool     `json:"block_quic,omitempty"`
+	BlockDNS  bool     `json:"block_dns,omitempty"`
+}
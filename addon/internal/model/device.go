@@ -1,6 +1,9 @@
 package model
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 const (
 	SourceDHCP   = "dhcp"
@@ -170,3 +173,14 @@ type DeviceView struct {
 	UpdatedAt        time.Time  `json:"updated_at"`
 	FirstSeenAt      *time.Time `json:"first_seen_at,omitempty"`
 }
+
+// WakeInterface returns interface Wake-on-LAN packets should leave through:
+// the bridge device was last seen behind, otherwise its last interface.
+func (d DeviceView) WakeInterface() string {
+	for _, candidate := range []*string{d.Bridge, d.Interface} {
+		if candidate != nil && strings.TrimSpace(*candidate) != "" {
+			return strings.TrimSpace(*candidate)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,24 @@
+package model
+
+import "testing"
+
+func TestDeviceViewWakeInterface(t *testing.T) {
+	bridge := "bridge"
+	port := "ether3"
+	blank := " "
+
+	tests := []struct {
+		name   string
+		device DeviceView
+		want   string
+	}{
+		{name: "bridge wins over port", device: DeviceView{Bridge: &bridge, Interface: &port}, want: "bridge"},
+		{name: "blank bridge falls back to interface", device: DeviceView{Bridge: &blank, Interface: &port}, want: "ether3"},
+		{name: "unknown interface", device: DeviceView{}, want: ""},
+	}
+	for _, tt := range tests {
+		if got := tt.device.WakeInterface(); got != tt.want {
+			t.Fatalf("%s: WakeInterface() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
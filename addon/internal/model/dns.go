@@ -0,0 +1,17 @@
+package model
+
+// Static DNS record types supported by /ip/dns/static.
+const (
+	DNSRecordA        = "A"
+	DNSRecordCNAME    = "CNAME"
+	DNSRecordFWD      = "FWD"
+	DNSRecordNXDOMAIN = "NXDOMAIN"
+)
+
+// DNSStaticRecord is one static DNS override. Value holds address for A,
+// target name for CNAME and upstream server for FWD; NXDOMAIN has no value.
+type DNSStaticRecord struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+}
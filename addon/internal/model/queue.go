@@ -0,0 +1,10 @@
+package model
+
+// QueueLimit holds RouterOS simple queue rates. Each field uses RouterOS
+// "upload/download" notation, e.g. MaxLimit "2M/10M"; empty fields are unset.
+type QueueLimit struct {
+	MaxLimit       string `json:"max_limit"`
+	BurstLimit     string `json:"burst_limit,omitempty"`
+	BurstThreshold string `json:"burst_threshold,omitempty"`
+	BurstTime      string `json:"burst_time,omitempty"`
+}
@@ -0,0 +1,16 @@
+package model
+
+// Wifi access-list actions applied to one MAC.
+const (
+	WiFiAccessDeny  = "deny"
+	WiFiAccessAllow = "allow"
+)
+
+// WiFiAccessPolicy is desired access-list entry for one MAC. VLANID applies to
+// allow entries only; Time uses RouterOS notation such as "7h-22h,mon,tue" and
+// limits when the entry matches.
+type WiFiAccessPolicy struct {
+	Action string `json:"action"`
+	VLANID int    `json:"vlan_id,omitempty"`
+	Time   string `json:"time,omitempty"`
+}
@@ -0,0 +1,487 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const (
+	defaultPublishInterval = 10 * time.Second
+	commandTimeout         = 20 * time.Second
+	commandQueueSize       = 32
+	haBirthPayload         = "online"
+	statusRegistered       = "registered"
+)
+
+// CapabilityService exposes automation operations used by MQTT bridge.
+type CapabilityService interface {
+	ListCapabilities(ctx context.Context, search string, category string) ([]automationdomain.CapabilityTemplate, error)
+	ListCapabilityAssignments(ctx context.Context, capabilityID string) ([]automationdomain.CapabilityDeviceAssignment, error)
+	GetGlobalCapabilities(ctx context.Context) ([]automationdomain.CapabilityUIModel, error)
+	PatchDeviceCapability(
+		ctx context.Context,
+		deviceID string,
+		capabilityID string,
+		state *string,
+		enabled *bool,
+	) (automationdomain.SetStateResult, error)
+	PatchGlobalCapability(
+		ctx context.Context,
+		capabilityID string,
+		state *string,
+		enabled *bool,
+	) (automationdomain.SetStateResult, error)
+}
+
+// DeviceService exposes device reads used by MQTT bridge.
+type DeviceService interface {
+	ListDevices(ctx context.Context, filter devicedomain.ListFilter) ([]devicedomain.Device, error)
+}
+
+// Bridge publishes HA discovery/state topics and routes HA commands.
+type Bridge struct {
+	cfg          model.MQTTConfig
+	broker       Broker
+	capabilities CapabilityService
+	devices      DeviceService
+	logger       *slog.Logger
+
+	mu         sync.Mutex
+	published  map[string]string
+	discovered map[string]struct{}
+	refresh    chan struct{}
+	commands   chan command
+}
+
+// command is one inbound HA state change waiting for command worker.
+type command struct {
+	topic string
+	state string
+}
+
+// New creates MQTT bridge.
+func New(
+	cfg model.MQTTConfig,
+	broker Broker,
+	capabilities CapabilityService,
+	devices DeviceService,
+	logger *slog.Logger,
+) *Bridge {
+	return &Bridge{
+		cfg:          cfg,
+		broker:       broker,
+		capabilities: capabilities,
+		devices:      devices,
+		logger:       logger,
+		published:    map[string]string{},
+		discovered:   map[string]struct{}{},
+		refresh:      make(chan struct{}, 1),
+		commands:     make(chan command, commandQueueSize),
+	}
+}
+
+// Start runs command worker and subscribes to command and HA birth topics.
+func (b *Bridge) Start(ctx context.Context) error {
+	go b.runCommands(ctx)
+	if err := b.broker.Subscribe(deviceCapabilityTopic(b.cfg, "+", "+", "set"), b.enqueueCommand); err != nil {
+		return fmt.Errorf("subscribe device commands: %w", err)
+	}
+	if err := b.broker.Subscribe(globalCapabilityTopic(b.cfg, "+", "set"), b.enqueueCommand); err != nil {
+		return fmt.Errorf("subscribe global commands: %w", err)
+	}
+	if err := b.broker.Subscribe(discoveryPrefix(b.cfg)+"/status", b.handleBirth); err != nil {
+		return fmt.Errorf("subscribe ha status: %w", err)
+	}
+	if err := b.broker.Subscribe(discoveryTopic(b.cfg, "+", "+"), b.trackDiscoveryConfig); err != nil {
+		return fmt.Errorf("subscribe discovery configs: %w", err)
+	}
+	return nil
+}
+
+// Run publishes entities periodically until context cancellation.
+func (b *Bridge) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		interval = defaultPublishInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.PublishOnce(ctx); err != nil && b.logger != nil {
+			b.logger.Warn("mqtt publish failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-b.refresh:
+		}
+	}
+}
+
+// PublishOnce publishes discovery and state for all exposed entities.
+// Unchanged payloads are skipped and vanished entities are removed from HA.
+func (b *Bridge) PublishOnce(ctx context.Context) error {
+	var errs []error
+	seen := map[string]struct{}{}
+
+	devices, err := b.devices.ListDevices(ctx, devicedomain.ListFilter{Status: statusRegistered})
+	if err != nil {
+		return fmt.Errorf("list devices: %w", err)
+	}
+	registered := make(map[string]devicedomain.Device, len(devices))
+	for _, device := range devices {
+		registered[deviceKey(device.MAC)] = device
+		errs = append(errs, b.publishDeviceTracker(device, seen))
+	}
+
+	templates, err := b.capabilities.ListCapabilities(ctx, "", "")
+	if err != nil {
+		return fmt.Errorf("list capabilities: %w", err)
+	}
+	var globalStates map[string]automationdomain.CapabilityUIModel
+	for _, template := range templates {
+		if !template.HAExpose.Enabled || !topicSafe(template.ID) {
+			continue
+		}
+		switch automationdomain.NormalizeCapabilityScope(template.Scope) {
+		case automationdomain.ScopeGlobal:
+			if globalStates == nil {
+				globalStates, err = b.loadGlobalStates(ctx)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+			}
+			if current, ok := globalStates[template.ID]; ok {
+				errs = append(errs, b.publishGlobalCapability(template, current.State, seen))
+			}
+		default:
+			assignments, err := b.capabilities.ListCapabilityAssignments(ctx, template.ID)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("capability %s assignments: %w", template.ID, err))
+				continue
+			}
+			for _, assignment := range assignments {
+				device, ok := registered[deviceKey(assignment.DeviceID)]
+				if !ok || !assignment.Enabled {
+					continue
+				}
+				errs = append(errs, b.publishDeviceCapability(template, device, assignment.State, seen))
+			}
+		}
+	}
+
+	errs = append(errs, b.removeVanished(seen))
+	return errors.Join(errs...)
+}
+
+func (b *Bridge) loadGlobalStates(ctx context.Context) (map[string]automationdomain.CapabilityUIModel, error) {
+	items, err := b.capabilities.GetGlobalCapabilities(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("global capabilities: %w", err)
+	}
+	out := make(map[string]automationdomain.CapabilityUIModel, len(items))
+	for _, item := range items {
+		if item.Enabled {
+			out[item.ID] = item
+		}
+	}
+	return out, nil
+}
+
+func (b *Bridge) publishDeviceTracker(device devicedomain.Device, seen map[string]struct{}) error {
+	key := deviceKey(device.MAC)
+	stateTopic := presenceTopic(b.cfg, key)
+	config := map[string]any{
+		"name":               nil,
+		"unique_id":          objectID(discoveryNodeID, key, "presence"),
+		"state_topic":        stateTopic,
+		"payload_home":       "home",
+		"payload_not_home":   "not_home",
+		"source_type":        "router",
+		"availability_topic": availabilityTopic(b.cfg),
+		"device":             deviceInfo(device),
+	}
+	state := "not_home"
+	if device.Online {
+		state = "home"
+	}
+	return b.publishEntity(discoveryTopic(b.cfg, "device_tracker", objectID(key, "presence")), config, stateTopic, state, seen)
+}
+
+func (b *Bridge) publishDeviceCapability(
+	template automationdomain.CapabilityTemplate,
+	device devicedomain.Device,
+	state string,
+	seen map[string]struct{},
+) error {
+	key := deviceKey(device.MAC)
+	deviceCopy := device
+	vars := automationdomain.TemplateVars(automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &deviceCopy},
+		CapabilityID: template.ID,
+		State:        state,
+	})
+	stateTopic := deviceCapabilityTopic(b.cfg, key, template.ID, "state")
+	config := entityConfig(template, vars)
+	config["unique_id"] = objectID(discoveryNodeID, key, template.ID, template.HAExpose.EntitySuffix)
+	config["state_topic"] = stateTopic
+	config["command_topic"] = deviceCapabilityTopic(b.cfg, key, template.ID, "set")
+	config["availability_topic"] = availabilityTopic(b.cfg)
+	config["device"] = deviceInfo(device)
+
+	return b.publishEntity(
+		discoveryTopic(b.cfg, entityComponent(template), objectID(key, template.ID, template.HAExpose.EntitySuffix)),
+		config,
+		stateTopic,
+		state,
+		seen,
+	)
+}
+
+func (b *Bridge) publishGlobalCapability(
+	template automationdomain.CapabilityTemplate,
+	state string,
+	seen map[string]struct{},
+) error {
+	vars := automationdomain.TemplateVars(automationdomain.ActionExecutionContext{
+		Target:       automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal},
+		CapabilityID: template.ID,
+		State:        state,
+	})
+	stateTopic := globalCapabilityTopic(b.cfg, template.ID, "state")
+	config := entityConfig(template, vars)
+	config["unique_id"] = objectID(discoveryNodeID, "global", template.ID, template.HAExpose.EntitySuffix)
+	config["state_topic"] = stateTopic
+	config["command_topic"] = globalCapabilityTopic(b.cfg, template.ID, "set")
+	config["availability_topic"] = availabilityTopic(b.cfg)
+	config["device"] = map[string]any{
+		"identifiers":  []string{discoveryNodeID},
+		"name":         "MikroTik Presence",
+		"manufacturer": "MikroTik",
+	}
+
+	return b.publishEntity(
+		discoveryTopic(b.cfg, entityComponent(template), objectID("global", template.ID, template.HAExpose.EntitySuffix)),
+		config,
+		stateTopic,
+		state,
+		seen,
+	)
+}
+
+func (b *Bridge) publishEntity(
+	configTopic string,
+	config map[string]any,
+	stateTopic string,
+	state string,
+	seen map[string]struct{},
+) error {
+	payload, err := json.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("encode discovery %s: %w", configTopic, err)
+	}
+	seen[configTopic] = struct{}{}
+	if err := b.publishChanged(configTopic, string(payload)); err != nil {
+		return err
+	}
+	return b.publishChanged(stateTopic, state)
+}
+
+func (b *Bridge) publishChanged(topic string, payload string) error {
+	b.mu.Lock()
+	previous, ok := b.published[topic]
+	b.mu.Unlock()
+	if ok && previous == payload {
+		return nil
+	}
+
+	if err := b.broker.Publish(topic, []byte(payload), true); err != nil {
+		return fmt.Errorf("publish %s: %w", topic, err)
+	}
+
+	b.mu.Lock()
+	b.published[topic] = payload
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *Bridge) removeVanished(seen map[string]struct{}) error {
+	b.mu.Lock()
+	stale := make([]string, 0)
+	for topic := range b.discovered {
+		if _, ok := seen[topic]; !ok {
+			stale = append(stale, topic)
+		}
+	}
+	b.discovered = seen
+	b.mu.Unlock()
+
+	sort.Strings(stale)
+	var errs []error
+	for _, topic := range stale {
+		// Empty retained config removes entity from HA.
+		if err := b.broker.Publish(topic, nil, true); err != nil {
+			errs = append(errs, fmt.Errorf("remove %s: %w", topic, err))
+			continue
+		}
+		b.mu.Lock()
+		delete(b.published, topic)
+		b.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// trackDiscoveryConfig adopts retained configs left by previous runs, so
+// next publish cycle removes entities deleted while add-on was down.
+func (b *Bridge) trackDiscoveryConfig(topic string, payload []byte) {
+	if len(payload) == 0 {
+		return
+	}
+	b.mu.Lock()
+	b.discovered[topic] = struct{}{}
+	b.mu.Unlock()
+}
+
+func (b *Bridge) handleBirth(_ string, payload []byte) {
+	if strings.TrimSpace(string(payload)) != haBirthPayload {
+		return
+	}
+	// HA restarted and lost non-retained state; publish everything again.
+	b.mu.Lock()
+	b.published = map[string]string{}
+	b.mu.Unlock()
+	b.requestRefresh()
+}
+
+func (b *Bridge) requestRefresh() {
+	select {
+	case b.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// enqueueCommand hands command to worker. Paho delivers messages in order
+// from one goroutine, so router writes and state publish (which waits on
+// same client) must not run inside callback.
+func (b *Bridge) enqueueCommand(topic string, payload []byte) {
+	select {
+	case b.commands <- command{topic: topic, state: strings.TrimSpace(string(payload))}:
+	default:
+		if b.logger != nil {
+			b.logger.Warn("mqtt command dropped; queue is full", "topic", topic)
+		}
+	}
+}
+
+func (b *Bridge) runCommands(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case cmd := <-b.commands:
+			if err := b.handleCommand(ctx, cmd.topic, cmd.state); err != nil && b.logger != nil {
+				b.logger.Warn("mqtt command failed", "topic", cmd.topic, "err", err)
+			}
+		}
+	}
+}
+
+func (b *Bridge) handleCommand(ctx context.Context, topic string, state string) error {
+	if state == "" {
+		return fmt.Errorf("empty command payload")
+	}
+	rest := strings.TrimPrefix(topic, baseTopic(b.cfg)+"/")
+	parts := strings.Split(rest, "/")
+
+	ctx, cancel := context.WithTimeout(ctx, commandTimeout)
+	defer cancel()
+
+	var (
+		result automationdomain.SetStateResult
+		err    error
+	)
+	switch {
+	case len(parts) == 4 && parts[0] == "device" && parts[3] == "set":
+		mac, ok := macFromDeviceKey(parts[1])
+		if !ok {
+			return fmt.Errorf("invalid device key %q", parts[1])
+		}
+		result, err = b.capabilities.PatchDeviceCapability(ctx, mac, parts[2], &state, nil)
+	case len(parts) == 3 && parts[0] == "global" && parts[2] == "set":
+		result, err = b.capabilities.PatchGlobalCapability(ctx, parts[1], &state, nil)
+	default:
+		return fmt.Errorf("unsupported command topic")
+	}
+	if err != nil {
+		return err
+	}
+	for _, warning := range result.Warnings {
+		if b.logger != nil {
+			b.logger.Warn("mqtt command action warning", "topic", topic, "action_type", warning.TypeID, "message", warning.Message)
+		}
+	}
+
+	// Confirm new state right away instead of waiting for next publish cycle.
+	return b.publishChanged(strings.TrimSuffix(topic, "/set")+"/state", state)
+}
+
+func entityComponent(template automationdomain.CapabilityTemplate) string {
+	if strings.TrimSpace(template.HAExpose.EntityType) == "select" {
+		return "select"
+	}
+	return "switch"
+}
+
+func entityConfig(template automationdomain.CapabilityTemplate, vars map[string]string) map[string]any {
+	name := strings.TrimSpace(automationdomain.RenderTemplate(template.HAExpose.NameTemplate, vars))
+	if name == "" {
+		name = template.Label
+	}
+	config := map[string]any{"name": name}
+
+	options := make([]string, 0, len(template.Control.Options))
+	for _, option := range template.Control.Options {
+		options = append(options, option.Value)
+	}
+	if entityComponent(template) == "select" {
+		config["options"] = options
+		return config
+	}
+	// Switch controls list the "on" state first.
+	if len(options) == 2 {
+		config["payload_on"] = options[0]
+		config["payload_off"] = options[1]
+		config["state_on"] = options[0]
+		config["state_off"] = options[1]
+	}
+	return config
+}
+
+func deviceInfo(device devicedomain.Device) map[string]any {
+	name := strings.TrimSpace(device.Name)
+	if name == "" {
+		name = device.MAC
+	}
+	info := map[string]any{
+		"identifiers": []string{objectID(discoveryNodeID, deviceKey(device.MAC))},
+		"connections": [][]string{{"mac", strings.ToLower(device.MAC)}},
+		"name":        name,
+	}
+	if vendor := strings.TrimSpace(device.Vendor); vendor != "" {
+		info["manufacturer"] = vendor
+	}
+	return info
+}
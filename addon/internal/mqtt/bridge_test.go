@@ -0,0 +1,316 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeCapabilityService struct {
+	mu          sync.Mutex
+	templates   []automationdomain.CapabilityTemplate
+	assignments map[string][]automationdomain.CapabilityDeviceAssignment
+	globals     []automationdomain.CapabilityUIModel
+	patches     []string
+}
+
+func (f *fakeCapabilityService) ListCapabilities(
+	ctx context.Context,
+	search string,
+	category string,
+) ([]automationdomain.CapabilityTemplate, error) {
+	return f.templates, nil
+}
+
+func (f *fakeCapabilityService) ListCapabilityAssignments(
+	ctx context.Context,
+	capabilityID string,
+) ([]automationdomain.CapabilityDeviceAssignment, error) {
+	return f.assignments[capabilityID], nil
+}
+
+func (f *fakeCapabilityService) GetGlobalCapabilities(ctx context.Context) ([]automationdomain.CapabilityUIModel, error) {
+	return f.globals, nil
+}
+
+func (f *fakeCapabilityService) PatchDeviceCapability(
+	ctx context.Context,
+	deviceID string,
+	capabilityID string,
+	state *string,
+	enabled *bool,
+) (automationdomain.SetStateResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.patches = append(f.patches, deviceID+"|"+capabilityID+"|"+*state)
+	return automationdomain.SetStateResult{OK: true}, nil
+}
+
+func (f *fakeCapabilityService) PatchGlobalCapability(
+	ctx context.Context,
+	capabilityID string,
+	state *string,
+	enabled *bool,
+) (automationdomain.SetStateResult, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.patches = append(f.patches, "global|"+capabilityID+"|"+*state)
+	return automationdomain.SetStateResult{OK: true}, nil
+}
+
+func (f *fakeCapabilityService) patchesSnapshot() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.patches...)
+}
+
+type fakeDeviceService struct {
+	devices []devicedomain.Device
+}
+
+func (f *fakeDeviceService) ListDevices(
+	ctx context.Context,
+	filter devicedomain.ListFilter,
+) ([]devicedomain.Device, error) {
+	return f.devices, nil
+}
+
+type retainedMessages struct {
+	mu       sync.Mutex
+	messages map[string]string
+}
+
+func (r *retainedMessages) get(topic string) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	value, ok := r.messages[topic]
+	return value, ok
+}
+
+func startBroker(t *testing.T) (*mochi.Server, string, *retainedMessages) {
+	t.Helper()
+
+	server := mochi.New(&mochi.Options{InlineClient: true, Logger: slog.New(slog.NewTextHandler(io.Discard, nil))})
+	if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatalf("add auth hook: %v", err)
+	}
+	listener := listeners.NewTCP(listeners.Config{ID: "test", Address: "127.0.0.1:0"})
+	if err := server.AddListener(listener); err != nil {
+		t.Fatalf("add listener: %v", err)
+	}
+	go func() { _ = server.Serve() }()
+	t.Cleanup(func() { _ = server.Close() })
+
+	seen := &retainedMessages{messages: map[string]string{}}
+	err := server.Subscribe("#", 1, func(_ *mochi.Client, _ packets.Subscription, pk packets.Packet) {
+		seen.mu.Lock()
+		seen.messages[pk.TopicName] = string(pk.Payload)
+		seen.mu.Unlock()
+	})
+	if err != nil {
+		t.Fatalf("inline subscribe: %v", err)
+	}
+	return server, listener.Address(), seen
+}
+
+func waitFor(t *testing.T, what string, check func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if check() {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out waiting for %s", what)
+}
+
+func TestBridgePublishesDiscoveryAndRoutesCommands(t *testing.T) {
+	server, address, seen := startBroker(t)
+	host, rawPort, _ := strings.Cut(address, ":")
+	port, err := strconv.Atoi(rawPort)
+	if err != nil {
+		t.Fatalf("parse broker port: %v", err)
+	}
+	cfg := model.MQTTConfig{Host: host, Port: port, DiscoveryPrefix: "homeassistant", BaseTopic: "mikrotik_presence"}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	broker, err := Dial(cfg, "bridge-test", logger)
+	if err != nil {
+		t.Fatalf("Dial returned error: %v", err)
+	}
+	defer broker.Close()
+
+	capabilities := &fakeCapabilityService{
+		templates: []automationdomain.CapabilityTemplate{
+			{
+				ID:      "routing.vpn",
+				Label:   "VPN",
+				Scope:   automationdomain.ScopeDevice,
+				Control: automationdomain.CapabilityControl{Type: automationdomain.ControlSwitch, Options: []automationdomain.CapabilityControlOption{{Value: "on"}, {Value: "off"}}},
+				HAExpose: automationdomain.HAExposeConfig{
+					Enabled:      true,
+					EntityType:   "switch",
+					EntitySuffix: "vpn",
+					NameTemplate: "{{device.name}} VPN",
+				},
+			},
+			{
+				ID:      "guest.mode",
+				Label:   "Guest mode",
+				Scope:   automationdomain.ScopeGlobal,
+				Control: automationdomain.CapabilityControl{Type: automationdomain.ControlSelect, Options: []automationdomain.CapabilityControlOption{{Value: "open"}, {Value: "closed"}}},
+				HAExpose: automationdomain.HAExposeConfig{
+					Enabled:      true,
+					EntityType:   "select",
+					EntitySuffix: "guest",
+				},
+			},
+		},
+		assignments: map[string][]automationdomain.CapabilityDeviceAssignment{
+			"routing.vpn": {{DeviceID: "AA:BB:CC:DD:EE:01", Enabled: true, State: "on"}},
+		},
+		globals: []automationdomain.CapabilityUIModel{{ID: "guest.mode", Enabled: true, State: "closed"}},
+	}
+	devices := &fakeDeviceService{devices: []devicedomain.Device{
+		{MAC: "AA:BB:CC:DD:EE:01", Name: "Laptop", Online: true},
+	}}
+
+	bridge := New(cfg, broker, capabilities, devices, logger)
+	if err := bridge.Start(context.Background()); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	if err := bridge.PublishOnce(context.Background()); err != nil {
+		t.Fatalf("PublishOnce returned error: %v", err)
+	}
+
+	trackerTopic := "homeassistant/device_tracker/mikrotik_presence/aabbccddee01_presence/config"
+	switchTopic := "homeassistant/switch/mikrotik_presence/aabbccddee01_routing_vpn_vpn/config"
+	selectTopic := "homeassistant/select/mikrotik_presence/global_guest_mode_guest/config"
+	waitFor(t, "discovery messages", func() bool {
+		_, a := seen.get(trackerTopic)
+		_, b := seen.get(switchTopic)
+		_, c := seen.get(selectTopic)
+		return a && b && c
+	})
+
+	var switchConfig map[string]any
+	raw, _ := seen.get(switchTopic)
+	if err := json.Unmarshal([]byte(raw), &switchConfig); err != nil {
+		t.Fatalf("decode switch config: %v", err)
+	}
+	if switchConfig["name"] != "Laptop VPN" || switchConfig["payload_on"] != "on" {
+		t.Fatalf("unexpected switch config: %v", switchConfig)
+	}
+	if switchConfig["command_topic"] != "mikrotik_presence/device/aabbccddee01/routing.vpn/set" {
+		t.Fatalf("unexpected command topic: %v", switchConfig["command_topic"])
+	}
+	if state, _ := seen.get("mikrotik_presence/device/aabbccddee01/presence"); state != "home" {
+		t.Fatalf("unexpected presence state %q", state)
+	}
+	if state, _ := seen.get("mikrotik_presence/global/guest.mode/state"); state != "closed" {
+		t.Fatalf("unexpected global state %q", state)
+	}
+
+	if err := server.Publish("mikrotik_presence/device/aabbccddee01/routing.vpn/set", []byte("off"), false, 1); err != nil {
+		t.Fatalf("publish command: %v", err)
+	}
+	if err := server.Publish("mikrotik_presence/global/guest.mode/set", []byte("open"), false, 1); err != nil {
+		t.Fatalf("publish command: %v", err)
+	}
+	waitFor(t, "routed commands", func() bool { return len(capabilities.patchesSnapshot()) == 2 })
+	patches := strings.Join(capabilities.patchesSnapshot(), ",")
+	if !strings.Contains(patches, "AA:BB:CC:DD:EE:01|routing.vpn|off") || !strings.Contains(patches, "global|guest.mode|open") {
+		t.Fatalf("unexpected patches: %s", patches)
+	}
+	waitFor(t, "echoed command states", func() bool {
+		device, _ := seen.get("mikrotik_presence/device/aabbccddee01/routing.vpn/state")
+		global, _ := seen.get("mikrotik_presence/global/guest.mode/state")
+		return device == "off" && global == "open"
+	})
+
+	// Removing device from registry must clear its discovery config.
+	devices.devices = nil
+	capabilities.assignments = nil
+	if err := bridge.PublishOnce(context.Background()); err != nil {
+		t.Fatalf("second PublishOnce returned error: %v", err)
+	}
+	waitFor(t, "discovery removal", func() bool {
+		payload, _ := seen.get(trackerTopic)
+		return payload == ""
+	})
+}
+
+func TestBridgeRemovesStaleRetainedDiscovery(t *testing.T) {
+	server, address, seen := startBroker(t)
+	host, rawPort, _ := strings.Cut(address, ":")
+	port, err := strconv.Atoi(rawPort)
+	if err != nil {
+		t.Fatalf("parse broker port: %v", err)
+	}
+	cfg := model.MQTTConfig{Host: host, Port: port, DiscoveryPrefix: "homeassistant", BaseTopic: "mikrotik_presence"}
+
+	// Config retained by previous run for device deleted while add-on was down.
+	staleTopic := "homeassistant/device_tracker/mikrotik_presence/aabbccddee09_presence/config"
+	if err := server.Publish(staleTopic, []byte(`{"name":null}`), true, 1); err != nil {
+		t.Fatalf("publish stale config: %v", err)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	broker, err := Dial(cfg, "bridge-stale-test", logger)
+	if err != nil {
+		t.Fatalf("Dial returned error: %v", err)
+	}
+	defer broker.Close()
+
+	devices := &fakeDeviceService{devices: []devicedomain.Device{{MAC: "AA:BB:CC:DD:EE:01", Name: "Laptop"}}}
+	bridge := New(cfg, broker, &fakeCapabilityService{}, devices, logger)
+	if err := bridge.Start(context.Background()); err != nil {
+		t.Fatalf("Start returned error: %v", err)
+	}
+	waitFor(t, "retained config delivery", func() bool {
+		bridge.mu.Lock()
+		defer bridge.mu.Unlock()
+		_, ok := bridge.discovered[staleTopic]
+		return ok
+	})
+	if err := bridge.PublishOnce(context.Background()); err != nil {
+		t.Fatalf("PublishOnce returned error: %v", err)
+	}
+
+	currentTopic := "homeassistant/device_tracker/mikrotik_presence/aabbccddee01_presence/config"
+	waitFor(t, "stale config removal", func() bool {
+		stale, _ := seen.get(staleTopic)
+		current, _ := seen.get(currentTopic)
+		return stale == "" && current != ""
+	})
+}
+
+func TestDeviceKeyRoundTrip(t *testing.T) {
+	key := deviceKey("aa:bb:cc:dd:ee:0f")
+	if key != "aabbccddee0f" {
+		t.Fatalf("unexpected device key %q", key)
+	}
+	mac, ok := macFromDeviceKey(key)
+	if !ok || mac != "AA:BB:CC:DD:EE:0F" {
+		t.Fatalf("unexpected mac %q ok=%v", mac, ok)
+	}
+	if _, ok := macFromDeviceKey("bad"); ok {
+		t.Fatalf("expected invalid key to fail")
+	}
+}
@@ -0,0 +1,132 @@
+package mqtt
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const (
+	brokerOpTimeout = 10 * time.Second
+	payloadOnline   = "online"
+	payloadOffline  = "offline"
+)
+
+// MessageHandler receives one inbound MQTT message.
+type MessageHandler func(topic string, payload []byte)
+
+// Broker is minimal MQTT transport used by Bridge.
+type Broker interface {
+	Publish(topic string, payload []byte, retain bool) error
+	Subscribe(filter string, handler MessageHandler) error
+	Close()
+}
+
+// PahoBroker implements Broker on top of paho MQTT client.
+type PahoBroker struct {
+	client       paho.Client
+	availability string
+	logger       *slog.Logger
+
+	mu       sync.Mutex
+	handlers map[string]MessageHandler
+}
+
+// Dial connects to broker, sets availability LWT and resubscribes on reconnect.
+func Dial(cfg model.MQTTConfig, clientID string, logger *slog.Logger) (*PahoBroker, error) {
+	availability := availabilityTopic(cfg)
+	broker := &PahoBroker{availability: availability, logger: logger, handlers: map[string]MessageHandler{}}
+
+	opts := paho.NewClientOptions().
+		AddBroker(cfg.BrokerURL()).
+		SetClientID(clientID).
+		SetCleanSession(true).
+		SetAutoReconnect(true).
+		SetConnectRetry(true).
+		SetConnectTimeout(brokerOpTimeout).
+		SetWill(availability, payloadOffline, 1, true).
+		SetOnConnectHandler(func(client paho.Client) {
+			client.Publish(availability, 1, true, payloadOnline)
+			broker.resubscribe(client)
+		}).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			if logger != nil {
+				logger.Warn("mqtt connection lost", "err", err)
+			}
+		})
+	if strings.TrimSpace(cfg.Username) != "" {
+		opts.SetUsername(cfg.Username)
+		opts.SetPassword(cfg.Password)
+	}
+
+	broker.client = paho.NewClient(opts)
+	token := broker.client.Connect()
+	if !token.WaitTimeout(brokerOpTimeout) {
+		// ConnectRetry keeps trying in background; publishes queue until connected.
+		if logger != nil {
+			logger.Warn("mqtt broker not reachable yet, retrying in background", "broker", cfg.BrokerURL())
+		}
+		return broker, nil
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("connect mqtt broker: %w", err)
+	}
+	return broker, nil
+}
+
+// Publish sends QoS 1 message.
+func (b *PahoBroker) Publish(topic string, payload []byte, retain bool) error {
+	token := b.client.Publish(topic, 1, retain, payload)
+	if !token.WaitTimeout(brokerOpTimeout) {
+		return fmt.Errorf("publish %s: timeout", topic)
+	}
+	return token.Error()
+}
+
+// Subscribe registers handler and keeps it across reconnects.
+func (b *PahoBroker) Subscribe(filter string, handler MessageHandler) error {
+	b.mu.Lock()
+	b.handlers[filter] = handler
+	b.mu.Unlock()
+
+	if !b.client.IsConnectionOpen() {
+		// Subscription is applied by OnConnect handler.
+		return nil
+	}
+	token := b.client.Subscribe(filter, 1, wrapHandler(handler))
+	if !token.WaitTimeout(brokerOpTimeout) {
+		return fmt.Errorf("subscribe %s: timeout", filter)
+	}
+	return token.Error()
+}
+
+// Close publishes offline availability and disconnects.
+func (b *PahoBroker) Close() {
+	if b.client.IsConnectionOpen() {
+		b.client.Publish(b.availability, 1, true, payloadOffline).WaitTimeout(time.Second)
+	}
+	b.client.Disconnect(250)
+}
+
+func (b *PahoBroker) resubscribe(client paho.Client) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for filter, handler := range b.handlers {
+		token := client.Subscribe(filter, 1, wrapHandler(handler))
+		if token.WaitTimeout(brokerOpTimeout) && token.Error() != nil && b.logger != nil {
+			b.logger.Warn("mqtt resubscribe failed", "filter", filter, "err", token.Error())
+		}
+	}
+}
+
+func wrapHandler(handler MessageHandler) paho.MessageHandler {
+	return func(_ paho.Client, msg paho.Message) {
+		handler(msg.Topic(), msg.Payload())
+	}
+}
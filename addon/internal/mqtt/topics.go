@@ -0,0 +1,82 @@
+package mqtt
+
+import (
+	"strings"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const discoveryNodeID = "mikrotik_presence"
+
+func baseTopic(cfg model.MQTTConfig) string {
+	base := strings.Trim(strings.TrimSpace(cfg.BaseTopic), "/")
+	if base == "" {
+		return "mikrotik_presence"
+	}
+	return base
+}
+
+func discoveryPrefix(cfg model.MQTTConfig) string {
+	prefix := strings.Trim(strings.TrimSpace(cfg.DiscoveryPrefix), "/")
+	if prefix == "" {
+		return "homeassistant"
+	}
+	return prefix
+}
+
+func availabilityTopic(cfg model.MQTTConfig) string {
+	return baseTopic(cfg) + "/status"
+}
+
+func discoveryTopic(cfg model.MQTTConfig, component string, objectID string) string {
+	return discoveryPrefix(cfg) + "/" + component + "/" + discoveryNodeID + "/" + objectID + "/config"
+}
+
+func presenceTopic(cfg model.MQTTConfig, deviceKey string) string {
+	return baseTopic(cfg) + "/device/" + deviceKey + "/presence"
+}
+
+func deviceCapabilityTopic(cfg model.MQTTConfig, deviceKey string, capabilityID string, leaf string) string {
+	return baseTopic(cfg) + "/device/" + deviceKey + "/" + capabilityID + "/" + leaf
+}
+
+func globalCapabilityTopic(cfg model.MQTTConfig, capabilityID string, leaf string) string {
+	return baseTopic(cfg) + "/global/" + capabilityID + "/" + leaf
+}
+
+// deviceKey turns MAC into topic-safe lowercase hex.
+func deviceKey(mac string) string {
+	replacer := strings.NewReplacer(":", "", "-", "", ".", "")
+	return strings.ToLower(replacer.Replace(strings.TrimSpace(mac)))
+}
+
+// macFromDeviceKey restores canonical MAC from deviceKey output.
+func macFromDeviceKey(key string) (string, bool) {
+	key = strings.ToUpper(strings.TrimSpace(key))
+	if len(key) != 12 {
+		return "", false
+	}
+	parts := make([]string, 0, 6)
+	for i := 0; i < 12; i += 2 {
+		parts = append(parts, key[i:i+2])
+	}
+	return strings.Join(parts, ":"), true
+}
+
+// topicSafe reports whether value can be used as single MQTT topic level.
+func topicSafe(value string) bool {
+	return value != "" && !strings.ContainsAny(value, "/+#")
+}
+
+// objectID builds HA object id from arbitrary parts.
+func objectID(parts ...string) string {
+	replacer := strings.NewReplacer(".", "_", "-", "_", " ", "_", ":", "")
+	clean := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.ToLower(replacer.Replace(strings.TrimSpace(part)))
+		if part != "" {
+			clean = append(clean, part)
+		}
+	}
+	return strings.Join(clean, "_")
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/micro-ha/mikrotik-presence/addon/internal/configsync"
 	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/events"
 )
 
 type Poller struct {
@@ -15,12 +16,19 @@ type Poller struct {
 	config    *configsync.Manager
 	refreshCh chan struct{}
 	logger    *slog.Logger
+	publisher events.Publisher
 }
 
 func New(svc devicedomain.Service, cfg *configsync.Manager, logger *slog.Logger) *Poller {
 	return &Poller{service: svc, config: cfg, refreshCh: make(chan struct{}, 1), logger: logger}
 }
 
+// WithEvents publishes the outcome of every poll cycle.
+func (p *Poller) WithEvents(publisher events.Publisher) *Poller {
+	p.publisher = publisher
+	return p
+}
+
 func (p *Poller) TriggerRefresh() {
 	select {
 	case p.refreshCh <- struct{}{}:
@@ -43,12 +51,28 @@ func (p *Poller) Run(ctx context.Context) {
 			timer.Stop()
 		case <-timer.C:
 		}
-		if err := p.service.PollOnce(ctx); err != nil {
-			if errors.Is(err, devicedomain.ErrAddonNotConfigured) {
-				p.logger.Info("poll skipped; add-on is not configured")
-				continue
-			}
+		started := time.Now()
+		err := p.service.PollOnce(ctx)
+		if errors.Is(err, devicedomain.ErrAddonNotConfigured) {
+			p.logger.Info("poll skipped; add-on is not configured")
+			continue
+		}
+		if err != nil {
 			p.logger.Error("poll failed", "err", err)
 		}
+		p.publishResult(time.Since(started), err)
+	}
+}
+
+func (p *Poller) publishResult(duration time.Duration, err error) {
+	if p.publisher == nil {
+		return
+	}
+	result := events.PollResult{DurationMS: duration.Milliseconds()}
+	if err != nil {
+		result.Error = err.Error()
+		p.publisher.Publish(events.TypePollFailed, result)
+		return
 	}
+	p.publisher.Publish(events.TypePollSucceeded, result)
 }
@@ -11,6 +11,11 @@ import (
 	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
 )
 
+const (
+	driftEventsRetention    = 1000
+	defaultDriftEventsLimit = 100
+)
+
 // AutomationRepository is sqlite implementation of automation.Repository.
 type AutomationRepository struct {
 	db *DB
@@ -325,6 +330,111 @@ func (r *AutomationRepository) ListGlobalCapabilities(
 	return items, nil
 }
 
+// InsertDriftEvent stores one drift event and prunes events beyond retention.
+func (r *AutomationRepository) InsertDriftEvent(ctx context.Context, event automationdomain.DriftEvent) error {
+	if event.DetectedAt.IsZero() {
+		event.DetectedAt = time.Now().UTC()
+	}
+	warnings := event.Warnings
+	if warnings == nil {
+		warnings = []automationdomain.ActionExecutionWarning{}
+	}
+	encodedWarnings, err := json.Marshal(warnings)
+	if err != nil {
+		return fmt.Errorf("encode drift warnings: %w", err)
+	}
+	_, err = r.db.SQLDB().ExecContext(
+		ctx,
+		`INSERT INTO capability_drift_events(
+			capability_id, scope, device_id, expected_state, observed_state, outcome, warnings_json, detected_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.CapabilityID,
+		string(automationdomain.NormalizeCapabilityScope(event.Scope)),
+		event.DeviceID,
+		event.ExpectedState,
+		event.ObservedState,
+		event.Outcome,
+		string(encodedWarnings),
+		event.DetectedAt.UTC().Format(time.RFC3339Nano),
+	)
+	if err != nil {
+		return fmt.Errorf("insert drift event: %w", err)
+	}
+	_, err = r.db.SQLDB().ExecContext(
+		ctx,
+		`DELETE FROM capability_drift_events
+		 WHERE id <= (SELECT id FROM capability_drift_events ORDER BY id DESC LIMIT 1 OFFSET ?)`,
+		driftEventsRetention,
+	)
+	if err != nil {
+		return fmt.Errorf("prune drift events: %w", err)
+	}
+	return nil
+}
+
+// ListDriftEvents returns newest drift events first.
+func (r *AutomationRepository) ListDriftEvents(
+	ctx context.Context,
+	filter automationdomain.DriftEventFilter,
+) ([]automationdomain.DriftEvent, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > driftEventsRetention {
+		limit = defaultDriftEventsLimit
+	}
+	rows, err := r.db.SQLDB().QueryContext(
+		ctx,
+		`SELECT id, capability_id, scope, device_id, expected_state, observed_state, outcome, warnings_json, detected_at
+		 FROM capability_drift_events
+		 WHERE (? = '' OR capability_id = ?) AND (? = '' OR device_id = ?)
+		 ORDER BY id DESC
+		 LIMIT ?`,
+		filter.CapabilityID,
+		filter.CapabilityID,
+		filter.DeviceID,
+		filter.DeviceID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list drift events: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]automationdomain.DriftEvent, 0)
+	for rows.Next() {
+		var (
+			item       automationdomain.DriftEvent
+			scope      string
+			warnings   string
+			detectedAt string
+		)
+		if err := rows.Scan(
+			&item.ID,
+			&item.CapabilityID,
+			&scope,
+			&item.DeviceID,
+			&item.ExpectedState,
+			&item.ObservedState,
+			&item.Outcome,
+			&warnings,
+			&detectedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan drift event: %w", err)
+		}
+		item.Scope = automationdomain.NormalizeCapabilityScope(automationdomain.CapabilityScope(scope))
+		if err := json.Unmarshal([]byte(warnings), &item.Warnings); err != nil && r.db.logger != nil {
+			r.db.logger.Warn("failed to decode drift warnings", "id", item.ID, "err", err)
+		}
+		if parsed, err := time.Parse(time.RFC3339Nano, detectedAt); err == nil {
+			item.DetectedAt = parsed.UTC()
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 func decodeTemplate(id string, encoded string) (automationdomain.CapabilityTemplate, error) {
 	var template automationdomain.CapabilityTemplate
 	if err := json.Unmarshal([]byte(encoded), &template); err != nil {
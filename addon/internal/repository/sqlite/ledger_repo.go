@@ -0,0 +1,155 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+// LedgerRepository is sqlite implementation of automation.LedgerRepository.
+type LedgerRepository struct {
+	db *DB
+}
+
+// NewLedgerRepository creates sqlite-backed router artefact ledger.
+func NewLedgerRepository(db *DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// GetArtefact looks up artefact by owner, kind, path and key.
+func (r *LedgerRepository) GetArtefact(
+	ctx context.Context,
+	key automationdomain.RouterArtefact,
+) (automationdomain.RouterArtefact, bool, error) {
+	row := r.db.SQLDB().QueryRowContext(
+		ctx,
+		`SELECT id, capability_id, device_id, kind, path, object_key, restore, created_at, updated_at
+		 FROM router_artefacts
+		 WHERE capability_id = ? AND device_id = ? AND kind = ? AND path = ? AND object_key = ?`,
+		key.CapabilityID,
+		key.DeviceID,
+		string(key.Kind),
+		key.Path,
+		key.Key,
+	)
+	item, err := scanArtefact(row)
+	if err == sql.ErrNoRows {
+		return automationdomain.RouterArtefact{}, false, nil
+	}
+	if err != nil {
+		return automationdomain.RouterArtefact{}, false, fmt.Errorf("get router artefact: %w", err)
+	}
+	return item, true, nil
+}
+
+// UpsertArtefact inserts artefact or bumps updated_at keeping original restore value.
+func (r *LedgerRepository) UpsertArtefact(ctx context.Context, artefact automationdomain.RouterArtefact) error {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	_, err := r.db.SQLDB().ExecContext(
+		ctx,
+		`INSERT INTO router_artefacts(capability_id, device_id, kind, path, object_key, restore, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(capability_id, device_id, kind, path, object_key)
+		 DO UPDATE SET updated_at = excluded.updated_at`,
+		artefact.CapabilityID,
+		artefact.DeviceID,
+		string(artefact.Kind),
+		artefact.Path,
+		artefact.Key,
+		artefact.Restore,
+		now,
+		now,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert router artefact: %w", err)
+	}
+	return nil
+}
+
+// DeleteArtefact removes artefact by owner, kind, path and key.
+func (r *LedgerRepository) DeleteArtefact(ctx context.Context, key automationdomain.RouterArtefact) error {
+	_, err := r.db.SQLDB().ExecContext(
+		ctx,
+		`DELETE FROM router_artefacts
+		 WHERE capability_id = ? AND device_id = ? AND kind = ? AND path = ? AND object_key = ?`,
+		key.CapabilityID,
+		key.DeviceID,
+		string(key.Kind),
+		key.Path,
+		key.Key,
+	)
+	if err != nil {
+		return fmt.Errorf("delete router artefact: %w", err)
+	}
+	return nil
+}
+
+// DeleteArtefactByID removes one artefact row.
+func (r *LedgerRepository) DeleteArtefactByID(ctx context.Context, id int64) error {
+	if _, err := r.db.SQLDB().ExecContext(ctx, `DELETE FROM router_artefacts WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("delete router artefact %d: %w", id, err)
+	}
+	return nil
+}
+
+// ListArtefacts returns all recorded artefacts ordered by capability and object.
+func (r *LedgerRepository) ListArtefacts(ctx context.Context) ([]automationdomain.RouterArtefact, error) {
+	rows, err := r.db.SQLDB().QueryContext(
+		ctx,
+		`SELECT id, capability_id, device_id, kind, path, object_key, restore, created_at, updated_at
+		 FROM router_artefacts
+		 ORDER BY capability_id, device_id, kind, path, object_key`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list router artefacts: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]automationdomain.RouterArtefact, 0)
+	for rows.Next() {
+		item, err := scanArtefact(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan router artefact: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanArtefact(scanner interface {
+	Scan(dest ...any) error
+}) (automationdomain.RouterArtefact, error) {
+	var (
+		item      automationdomain.RouterArtefact
+		kind      string
+		createdAt string
+		updatedAt string
+	)
+	if err := scanner.Scan(
+		&item.ID,
+		&item.CapabilityID,
+		&item.DeviceID,
+		&kind,
+		&item.Path,
+		&item.Key,
+		&item.Restore,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
+		return automationdomain.RouterArtefact{}, err
+	}
+	item.Kind = automationdomain.ArtefactKind(kind)
+	if parsed, err := time.Parse(time.RFC3339Nano, createdAt); err == nil {
+		item.CreatedAt = parsed.UTC()
+	}
+	if parsed, err := time.Parse(time.RFC3339Nano, updatedAt); err == nil {
+		item.UpdatedAt = parsed.UTC()
+	}
+	return item, nil
+}
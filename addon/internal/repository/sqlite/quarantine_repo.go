@@ -0,0 +1,214 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	quarantinedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/quarantine"
+)
+
+const (
+	quarantineAuditRetention    = 1000
+	defaultQuarantineAuditLimit = 100
+)
+
+// QuarantineRepository is sqlite implementation of quarantine.Repository.
+type QuarantineRepository struct {
+	db *DB
+}
+
+// NewQuarantineRepository creates sqlite-backed quarantine queue and audit trail.
+func NewQuarantineRepository(db *DB) *QuarantineRepository {
+	return &QuarantineRepository{db: db}
+}
+
+// GetEntry looks up quarantine entry by MAC.
+func (r *QuarantineRepository) GetEntry(ctx context.Context, mac string) (quarantinedomain.Entry, bool, error) {
+	row := r.db.SQLDB().QueryRowContext(
+		ctx,
+		`SELECT mac, status, mode, address, created_at, updated_at
+		 FROM device_quarantine
+		 WHERE mac = ?`,
+		mac,
+	)
+	item, err := scanQuarantineEntry(row)
+	if err == sql.ErrNoRows {
+		return quarantinedomain.Entry{}, false, nil
+	}
+	if err != nil {
+		return quarantinedomain.Entry{}, false, fmt.Errorf("get quarantine entry: %w", err)
+	}
+	return item, true, nil
+}
+
+// ListEntries returns entries with status, or all entries when status is empty,
+// oldest first.
+func (r *QuarantineRepository) ListEntries(
+	ctx context.Context,
+	status quarantinedomain.Status,
+) ([]quarantinedomain.Entry, error) {
+	rows, err := r.db.SQLDB().QueryContext(
+		ctx,
+		`SELECT mac, status, mode, address, created_at, updated_at
+		 FROM device_quarantine
+		 WHERE (? = '' OR status = ?)
+		 ORDER BY created_at, mac`,
+		string(status),
+		string(status),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list quarantine entries: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]quarantinedomain.Entry, 0)
+	for rows.Next() {
+		item, err := scanQuarantineEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan quarantine entry: %w", err)
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// UpsertEntry inserts entry or updates status, mode and address keeping created_at.
+func (r *QuarantineRepository) UpsertEntry(ctx context.Context, entry quarantinedomain.Entry) error {
+	now := time.Now().UTC()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = now
+	}
+	if entry.UpdatedAt.IsZero() {
+		entry.UpdatedAt = now
+	}
+	_, err := r.db.SQLDB().ExecContext(
+		ctx,
+		`INSERT INTO device_quarantine(mac, status, mode, address, created_at, updated_at)
+		 VALUES (?, ?, ?, ?, ?, ?)
+		 ON CONFLICT(mac) DO UPDATE SET
+			status = excluded.status,
+			mode = excluded.mode,
+			address = excluded.address,
+			updated_at = excluded.updated_at`,
+		entry.MAC,
+		string(entry.Status),
+		entry.Mode,
+		entry.Address,
+		entry.CreatedAt.UTC().Format(time.RFC3339Nano),
+		entry.UpdatedAt.UTC().Format(time.RFC3339Nano),
+	)
+	if err != nil {
+		return fmt.Errorf("upsert quarantine entry: %w", err)
+	}
+	return nil
+}
+
+// DeleteEntry removes quarantine entry by MAC.
+func (r *QuarantineRepository) DeleteEntry(ctx context.Context, mac string) error {
+	if _, err := r.db.SQLDB().ExecContext(ctx, `DELETE FROM device_quarantine WHERE mac = ?`, mac); err != nil {
+		return fmt.Errorf("delete quarantine entry: %w", err)
+	}
+	return nil
+}
+
+// InsertAuditEvent stores one audit event and prunes events beyond retention.
+func (r *QuarantineRepository) InsertAuditEvent(ctx context.Context, event quarantinedomain.AuditEvent) error {
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now().UTC()
+	}
+	_, err := r.db.SQLDB().ExecContext(
+		ctx,
+		`INSERT INTO quarantine_audit(mac, action, actor, detail, created_at)
+		 VALUES (?, ?, ?, ?, ?)`,
+		event.MAC,
+		event.Action,
+		event.Actor,
+		event.Detail,
+		event.CreatedAt.UTC().Format(time.RFC3339Nano),
+	)
+	if err != nil {
+		return fmt.Errorf("insert quarantine audit event: %w", err)
+	}
+	_, err = r.db.SQLDB().ExecContext(
+		ctx,
+		`DELETE FROM quarantine_audit
+		 WHERE id <= (SELECT id FROM quarantine_audit ORDER BY id DESC LIMIT 1 OFFSET ?)`,
+		quarantineAuditRetention,
+	)
+	if err != nil {
+		return fmt.Errorf("prune quarantine audit events: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEvents returns newest audit events first.
+func (r *QuarantineRepository) ListAuditEvents(
+	ctx context.Context,
+	filter quarantinedomain.AuditFilter,
+) ([]quarantinedomain.AuditEvent, error) {
+	limit := filter.Limit
+	if limit <= 0 || limit > quarantineAuditRetention {
+		limit = defaultQuarantineAuditLimit
+	}
+	rows, err := r.db.SQLDB().QueryContext(
+		ctx,
+		`SELECT id, mac, action, actor, detail, created_at
+		 FROM quarantine_audit
+		 WHERE (? = '' OR mac = ?)
+		 ORDER BY id DESC
+		 LIMIT ?`,
+		filter.MAC,
+		filter.MAC,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list quarantine audit events: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]quarantinedomain.AuditEvent, 0)
+	for rows.Next() {
+		var (
+			item      quarantinedomain.AuditEvent
+			createdAt string
+		)
+		if err := rows.Scan(&item.ID, &item.MAC, &item.Action, &item.Actor, &item.Detail, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan quarantine audit event: %w", err)
+		}
+		if parsed, err := time.Parse(time.RFC3339Nano, createdAt); err == nil {
+			item.CreatedAt = parsed.UTC()
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func scanQuarantineEntry(scanner interface {
+	Scan(dest ...any) error
+}) (quarantinedomain.Entry, error) {
+	var (
+		item      quarantinedomain.Entry
+		status    string
+		createdAt string
+		updatedAt string
+	)
+	if err := scanner.Scan(&item.MAC, &status, &item.Mode, &item.Address, &createdAt, &updatedAt); err != nil {
+		return quarantinedomain.Entry{}, err
+	}
+	item.Status = quarantinedomain.Status(status)
+	if parsed, err := time.Parse(time.RFC3339Nano, createdAt); err == nil {
+		item.CreatedAt = parsed.UTC()
+	}
+	if parsed, err := time.Parse(time.RFC3339Nano, updatedAt); err == nil {
+		item.UpdatedAt = parsed.UTC()
+	}
+	return item, nil
+}
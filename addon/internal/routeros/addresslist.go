@@ -8,39 +8,102 @@ import (
 	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
 )
 
+// AddressListEntry is one firewall address-list row.
+type AddressListEntry struct {
+	ID           string
+	List         string
+	Address      string
+	Comment      string
+	Timeout      string
+	CreationTime string
+	Dynamic      bool
+	Disabled     bool
+}
+
 // AddAddressToList adds an entry into firewall address-list (idempotent).
 func (c *Client) AddAddressToList(ctx context.Context, list string, address string) error {
+	return c.AddAddressToListWithOptions(ctx, list, address, "", "")
+}
+
+// AddAddressToListWithOptions adds an entry with optional comment and RouterOS
+// timeout. When a dynamic entry already exists its timeout is refreshed; static
+// entries are left untouched so hand-made entries are never converted.
+func (c *Client) AddAddressToListWithOptions(
+	ctx context.Context,
+	list string,
+	address string,
+	comment string,
+	timeout string,
+) error {
+	_, err := c.EnsureAddressListEntry(ctx, list, address, comment, timeout)
+	return err
+}
+
+// EnsureAddressListEntry behaves like AddAddressToListWithOptions and reports
+// whether the entry was created by this call rather than found in place.
+func (c *Client) EnsureAddressListEntry(
+	ctx context.Context,
+	list string,
+	address string,
+	comment string,
+	timeout string,
+) (bool, error) {
 	list = strings.TrimSpace(list)
 	address = strings.TrimSpace(address)
 	if list == "" {
-		return &ValidationError{Field: "list", Reason: "is required"}
+		return false, &ValidationError{Field: "list", Reason: "is required"}
 	}
 	if address == "" {
-		return &ValidationError{Field: "address", Reason: "is required"}
+		return false, &ValidationError{Field: "address", Reason: "is required"}
 	}
 
+	comment = strings.TrimSpace(comment)
+	timeout = strings.TrimSpace(timeout)
+
 	c.addressList.Lock()
 	defer c.addressList.Unlock()
 
-	exists, err := c.AddressExists(ctx, list, address)
+	entries, err := c.findAddressEntries(ctx, list, address)
 	if err != nil {
-		return err
+		return false, err
 	}
-	if exists {
-		return nil
+	if len(entries) > 0 {
+		if timeout == "" {
+			return false, nil
+		}
+		for _, entry := range entries {
+			if !entry.Dynamic {
+				continue
+			}
+			_, err := c.RunCommand(ctx, "/ip/firewall/address-list/set", map[string]string{
+				".id":     entry.ID,
+				"timeout": timeout,
+			})
+			if err != nil && !isNotFoundError(err) {
+				return false, fmt.Errorf("refresh address-list entry %s timeout: %w", entry.ID, err)
+			}
+		}
+		return false, nil
 	}
 
-	_, err = c.RunCommand(ctx, "/ip/firewall/address-list/add", map[string]string{
+	params := map[string]string{
 		"list":    list,
 		"address": address,
-	})
+	}
+	if comment != "" {
+		params["comment"] = comment
+	}
+	if timeout != "" {
+		params["timeout"] = timeout
+	}
+	_, err = c.RunCommand(ctx, "/ip/firewall/address-list/add", params)
 	if err != nil {
 		if isAlreadyExistsError(err) {
-			return nil
+			return false, nil
 		}
-		return fmt.Errorf("add address to list %q: %w", list, err)
+		return false, fmt.Errorf("add address to list %q: %w", list, err)
 	}
-	return nil
+	return true, nil
 }
 
 // AddAddressToList executes add operation on pooled client selected by cfg.
@@ -52,6 +115,22 @@ func (m *Manager) AddAddressToList(ctx context.Context, cfg model.RouterConfig,
 	return client.AddAddressToList(ctx, list, address)
 }
 
+// AddAddressToListWithOptions executes add with comment/timeout on pooled client selected by cfg.
+func (m *Manager) AddAddressToListWithOptions(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+	address string,
+	comment string,
+	timeout string,
+) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.AddAddressToListWithOptions(ctx, list, address, comment, timeout)
+}
+
 // RemoveAddressFromList removes all matching entries from firewall address-list (idempotent).
 func (c *Client) RemoveAddressFromList(ctx context.Context, list string, address string) error {
 	list = strings.TrimSpace(list)
@@ -88,6 +167,46 @@ func (c *Client) RemoveAddressFromList(ctx context.Context, list string, address
 	return nil
 }
 
+// RemoveOwnedAddressFromList removes matching entries tagged with ownership
+// comment; entries without it belong to the user and are left in place.
+func (c *Client) RemoveOwnedAddressFromList(ctx context.Context, list, address, comment string) error {
+	list = strings.TrimSpace(list)
+	address = strings.TrimSpace(address)
+	comment = strings.TrimSpace(comment)
+	if list == "" {
+		return &ValidationError{Field: "list", Reason: "is required"}
+	}
+	if address == "" {
+		return &ValidationError{Field: "address", Reason: "is required"}
+	}
+	if comment == "" {
+		return &ValidationError{Field: "comment", Reason: "is required"}
+	}
+
+	c.addressList.Lock()
+	defer c.addressList.Unlock()
+
+	entries, err := c.findAddressEntries(ctx, list, address)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Comment != comment {
+			continue
+		}
+		_, err := c.RunCommand(ctx, "/ip/firewall/address-list/remove", map[string]string{
+			".id": entry.ID,
+		})
+		if err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return fmt.Errorf("remove address-list entry %s: %w", entry.ID, err)
+		}
+	}
+	return nil
+}
+
 // RemoveAddressFromList executes remove operation on pooled client selected by cfg.
 func (m *Manager) RemoveAddressFromList(ctx context.Context, cfg model.RouterConfig, list string, address string) error {
 	client, err := m.getClient(ctx, cfg)
@@ -121,36 +240,197 @@ func (m *Manager) AddressExists(ctx context.Context, cfg model.RouterConfig, lis
 	return client.AddressExists(ctx, list, address)
 }
 
-func (c *Client) findAddressIDs(ctx context.Context, list string, address string) ([]string, error) {
+// ListAddresses returns normalized addresses of one address-list in a single print.
+func (c *Client) ListAddresses(ctx context.Context, list string) ([]string, error) {
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return nil, nil
+	}
+
 	rows, err := c.RunCommand(ctx, "/ip/firewall/address-list/print", map[string]string{
 		"?list":     list,
-		".proplist": ".id,list,address",
+		".proplist": "list,address",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("lookup address-list %q: %w", list, err)
 	}
 
-	ids := make([]string, 0, len(rows))
+	addresses := make([]string, 0, len(rows))
 	for _, row := range rows {
 		if strings.TrimSpace(row["list"]) != list {
 			continue
 		}
-		if !equalAddressTarget(row["address"], address) {
-			continue
+		if address := normalizeAddressTarget(row["address"]); address != "" {
+			addresses = append(addresses, address)
 		}
-		id := strings.TrimSpace(row[".id"])
-		if id != "" {
-			ids = append(ids, id)
+	}
+	return addresses, nil
+}
+
+// ListAddressListAddresses lists address-list entries on pooled client selected by cfg.
+func (m *Manager) ListAddressListAddresses(ctx context.Context, cfg model.RouterConfig, list string) ([]string, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListAddresses(ctx, list)
+}
+
+// ListAddressListNames returns distinct address-list names in sorted order.
+func (c *Client) ListAddressListNames(ctx context.Context) ([]string, error) {
+	rows, err := c.RunCommand(ctx, "/ip/firewall/address-list/print", map[string]string{
+		".proplist": "list",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list address-lists: %w", err)
+	}
+	return distinctSorted(rows, "list"), nil
+}
+
+// ListAddressListNames lists address-list names on pooled client selected by cfg.
+func (m *Manager) ListAddressListNames(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListAddressListNames(ctx)
+}
+
+// ListAddressListEntries returns entries of one list, or of all lists when list is empty.
+func (c *Client) ListAddressListEntries(ctx context.Context, list string) ([]AddressListEntry, error) {
+	list = strings.TrimSpace(list)
+	params := map[string]string{
+		".proplist": ".id,list,address,comment,timeout,creation-time,dynamic,disabled",
+	}
+	if list != "" {
+		params["?list"] = list
+	}
+	rows, err := c.RunCommand(ctx, "/ip/firewall/address-list/print", params)
+	if err != nil {
+		return nil, fmt.Errorf("list address-list entries: %w", err)
+	}
+
+	entries := make([]AddressListEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := mapAddressListEntry(row)
+		if entry.ID == "" || (list != "" && entry.List != list) {
+			continue
 		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// ListAddressListEntries lists address-list entries on pooled client selected by cfg.
+func (m *Manager) ListAddressListEntries(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+) ([]AddressListEntry, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListAddressListEntries(ctx, list)
+}
+
+func (c *Client) findAddressIDs(ctx context.Context, list string, address string) ([]string, error) {
+	entries, err := c.findAddressEntries(ctx, list, address)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
 	}
 	return ids, nil
 }
 
+func (c *Client) findAddressEntries(ctx context.Context, list string, address string) ([]AddressListEntry, error) {
+	rows, err := c.RunCommand(ctx, "/ip/firewall/address-list/print", map[string]string{
+		"?list":     list,
+		".proplist": ".id,list,address,comment,dynamic",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("lookup address-list %q: %w", list, err)
+	}
+
+	entries := make([]AddressListEntry, 0, len(rows))
+	for _, row := range rows {
+		entry := mapAddressListEntry(row)
+		if entry.ID == "" || entry.List != list {
+			continue
+		}
+		if !equalAddressTarget(entry.Address, address) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+func mapAddressListEntry(row map[string]string) AddressListEntry {
+	return AddressListEntry{
+		ID:           strings.TrimSpace(row[".id"]),
+		List:         strings.TrimSpace(row["list"]),
+		Address:      strings.TrimSpace(row["address"]),
+		Comment:      strings.TrimSpace(row["comment"]),
+		Timeout:      strings.TrimSpace(row["timeout"]),
+		CreationTime: strings.TrimSpace(row["creation-time"]),
+		Dynamic:      boolFromWord(row["dynamic"]),
+		Disabled:     boolFromWord(row["disabled"]),
+	}
+}
+
 // AddAddressListEntry keeps compatibility with current automation contracts.
 func (m *Manager) AddAddressListEntry(ctx context.Context, cfg model.RouterConfig, list string, address string) error {
 	return m.AddAddressToList(ctx, cfg, list, address)
 }
 
+// AddManagedAddressListEntry adds entry tagged with ownership comment and optional timeout.
+func (m *Manager) AddManagedAddressListEntry(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+	address string,
+	comment string,
+	timeout string,
+) error {
+	return m.AddAddressToListWithOptions(ctx, cfg, list, address, comment, timeout)
+}
+
+// EnsureOwnedAddressListEntry adds entry tagged with ownership comment and
+// reports whether it was created on pooled client selected by cfg.
+func (m *Manager) EnsureOwnedAddressListEntry(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+	address string,
+	comment string,
+	timeout string,
+) (bool, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return false, err
+	}
+	return client.EnsureAddressListEntry(ctx, list, address, comment, timeout)
+}
+
+// RemoveOwnedAddressListEntry removes comment-tagged entries on pooled client selected by cfg.
+func (m *Manager) RemoveOwnedAddressListEntry(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+	address string,
+	comment string,
+) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.RemoveOwnedAddressFromList(ctx, list, address, comment)
+}
+
 // RemoveAddressListEntry keeps compatibility with current automation contracts.
 func (m *Manager) RemoveAddressListEntry(ctx context.Context, cfg model.RouterConfig, list string, address string) error {
 	return m.RemoveAddressFromList(ctx, cfg, list, address)
@@ -196,3 +196,191 @@ func decodeArgs(args []string) map[string]string {
 	}
 	return decoded
 }
+
+func TestListAddressesNormalizesEntries(t *testing.T) {
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+		if cmd != "/ip/firewall/address-list/print" {
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+		if params["?list"] != "vpn_users" {
+			return nil, fmt.Errorf("unexpected list filter %q", params["?list"])
+		}
+		return mockapi.Reply(
+			map[string]string{"list": "vpn_users", "address": "192.168.88.10/32"},
+			map[string]string{"list": "vpn_users", "address": " 192.168.88.11 "},
+			map[string]string{"list": "other", "address": "192.168.88.12"},
+		), nil
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	addresses, err := client.ListAddresses(context.Background(), "vpn_users")
+	if err != nil {
+		t.Fatalf("ListAddresses failed: %v", err)
+	}
+	if strings.Join(addresses, ",") != "192.168.88.10,192.168.88.11" {
+		t.Fatalf("unexpected addresses: %v", addresses)
+	}
+	if calls := api.CallsSnapshot(); len(calls) != 1 {
+		t.Fatalf("expected one print call, got %d", len(calls))
+	}
+}
+
+func TestListAddressListNamesReturnsDistinctSorted(t *testing.T) {
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		if cmd != "/ip/firewall/address-list/print" {
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+		if params := decodeArgs(args); params[".proplist"] != "list" {
+			return nil, fmt.Errorf("unexpected proplist %q", params[".proplist"])
+		}
+		return mockapi.Reply(
+			map[string]string{"list": "vpn_users"},
+			map[string]string{"list": "blocked"},
+			map[string]string{"list": "vpn_users"},
+			map[string]string{"list": ""},
+		), nil
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	names, err := client.ListAddressListNames(context.Background())
+	if err != nil {
+		t.Fatalf("ListAddressListNames failed: %v", err)
+	}
+	if strings.Join(names, ",") != "blocked,vpn_users" {
+		t.Fatalf("unexpected names: %v", names)
+	}
+}
+
+func TestAddAddressToListWithOptionsRefreshesDynamicTimeout(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		entries = map[string]map[string]string{}
+		sets    []map[string]string
+	)
+
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd {
+		case "/ip/firewall/address-list/print":
+			rows := make([]map[string]string, 0, len(entries))
+			for id, row := range entries {
+				rows = append(rows, map[string]string{".id": id, "list": row["list"], "address": row["address"], "dynamic": row["dynamic"]})
+			}
+			return mockapi.Reply(rows...), nil
+		case "/ip/firewall/address-list/add":
+			dynamic := "false"
+			if params["timeout"] != "" {
+				dynamic = "true"
+			}
+			entries["*1"] = map[string]string{"list": params["list"], "address": params["address"], "comment": params["comment"], "dynamic": dynamic}
+			return mockapi.Reply(), nil
+		case "/ip/firewall/address-list/set":
+			sets = append(sets, params)
+			return mockapi.Reply(), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	ctx := context.Background()
+	if err := client.AddAddressToListWithOptions(ctx, "blocked", "192.168.88.10", "mikrotik-presence:kids", "1h"); err != nil {
+		t.Fatalf("first add failed: %v", err)
+	}
+	if err := client.AddAddressToListWithOptions(ctx, "blocked", "192.168.88.10", "mikrotik-presence:kids", "1h"); err != nil {
+		t.Fatalf("second add failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if entries["*1"]["comment"] != "mikrotik-presence:kids" {
+		t.Fatalf("expected ownership comment, got %+v", entries["*1"])
+	}
+	if len(sets) != 1 || sets[0][".id"] != "*1" || sets[0]["timeout"] != "1h" {
+		t.Fatalf("expected one timeout refresh, got %+v", sets)
+	}
+}
+
+func TestEnsureAddressListEntryReportsCreationAndRemovesOwnedOnly(t *testing.T) {
+	entries := map[string]map[string]string{
+		"*1": {".id": "*1", "list": "blocked", "address": "192.168.88.10", "comment": "added by hand"},
+	}
+	var removed []string
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+		switch cmd {
+		case "/ip/firewall/address-list/print":
+			rows := make([]map[string]string, 0, len(entries))
+			for _, row := range entries {
+				rows = append(rows, row)
+			}
+			return mockapi.Reply(rows...), nil
+		case "/ip/firewall/address-list/add":
+			entries["*2"] = map[string]string{".id": "*2", "list": params["list"], "address": params["address"], "comment": params["comment"]}
+			return mockapi.Reply(), nil
+		case "/ip/firewall/address-list/remove":
+			removed = append(removed, params[".id"])
+			return mockapi.Reply(), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	ctx := context.Background()
+	created, err := client.EnsureAddressListEntry(ctx, "blocked", "192.168.88.10", "owner", "")
+	if err != nil || created {
+		t.Fatalf("existing entry must not be reported as created: created=%v err=%v", created, err)
+	}
+	created, err = client.EnsureAddressListEntry(ctx, "blocked", "192.168.88.11", "owner", "")
+	if err != nil || !created {
+		t.Fatalf("new entry must be reported as created: created=%v err=%v", created, err)
+	}
+
+	if err := client.RemoveOwnedAddressFromList(ctx, "blocked", "192.168.88.10", "owner"); err != nil {
+		t.Fatalf("RemoveOwnedAddressFromList failed: %v", err)
+	}
+	if err := client.RemoveOwnedAddressFromList(ctx, "blocked", "192.168.88.11", "owner"); err != nil {
+		t.Fatalf("RemoveOwnedAddressFromList failed: %v", err)
+	}
+	if len(removed) != 1 || removed[0] != "*2" {
+		t.Fatalf("expected only owned entry removed, got %v", removed)
+	}
+}
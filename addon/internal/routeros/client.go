@@ -40,6 +40,9 @@ type Client struct {
 	closed      chan struct{}
 	listenerWG  sync.WaitGroup
 	addressList sync.Mutex
+	queues      sync.Mutex
+	filters     sync.Mutex
+	wifiAccess  sync.Mutex
 
 	api API
 
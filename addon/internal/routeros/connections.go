@@ -0,0 +1,80 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+// KillConnections removes connection-tracking entries whose src or dst host
+// equals address and returns how many were removed. RouterOS stores connection
+// addresses as "ip:port", so rows are matched client-side and removed in one
+// call; when an entry expires in between, remaining ones are removed one by one.
+func (c *Client) KillConnections(ctx context.Context, address string) (int, error) {
+	address = normalizeAddressTarget(address)
+	if address == "" {
+		return 0, &ValidationError{Field: "address", Reason: "is required"}
+	}
+
+	rows, err := c.RunCommand(ctx, "/ip/firewall/connection/print", map[string]string{
+		".proplist": ".id,src-address,dst-address",
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list connections: %w", err)
+	}
+	ids := make([]string, 0)
+	for _, row := range rows {
+		id := strings.TrimSpace(row[".id"])
+		if id == "" {
+			continue
+		}
+		if connectionHost(row["src-address"]) == address || connectionHost(row["dst-address"]) == address {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	_, err = c.RunCommand(ctx, "/ip/firewall/connection/remove", map[string]string{".id": strings.Join(ids, ",")})
+	if err == nil {
+		return len(ids), nil
+	}
+	if !isNotFoundError(err) {
+		return 0, fmt.Errorf("remove connections: %w", err)
+	}
+
+	killed := 0
+	for _, id := range ids {
+		_, err := c.RunCommand(ctx, "/ip/firewall/connection/remove", map[string]string{".id": id})
+		if err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return killed, fmt.Errorf("remove connection %s: %w", id, err)
+		}
+		killed++
+	}
+	return killed, nil
+}
+
+// KillConnections kills address connections on pooled client selected by cfg.
+func (m *Manager) KillConnections(ctx context.Context, cfg model.RouterConfig, address string) (int, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return 0, err
+	}
+	return client.KillConnections(ctx, address)
+}
+
+// connectionHost strips port from RouterOS "ip:port" connection address.
+func connectionHost(value string) string {
+	value = strings.TrimSpace(value)
+	if host, _, err := net.SplitHostPort(value); err == nil {
+		return normalizeAddressTarget(host)
+	}
+	return normalizeAddressTarget(value)
+}
@@ -0,0 +1,93 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	goros "github.com/go-routeros/routeros/v3"
+	mockapi "github.com/micro-ha/mikrotik-presence/addon/internal/routeros/mock"
+)
+
+func TestKillConnectionsFiltersAndRemovesInOneCall(t *testing.T) {
+	var removed []string
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+		switch cmd {
+		case "/ip/firewall/connection/print":
+			if _, ok := params["?src-address"]; ok {
+				t.Fatalf("unexpected exact-match query: %v", params)
+			}
+			return mockapi.Reply(
+				map[string]string{".id": "*1", "src-address": "192.168.88.10:51000", "dst-address": "1.1.1.1:443"},
+				map[string]string{".id": "*2", "src-address": "8.8.8.8:53", "dst-address": "192.168.88.10:40000"},
+				map[string]string{".id": "*3", "src-address": "192.168.88.100:51000", "dst-address": "1.1.1.1:443"},
+				map[string]string{".id": "*4", "src-address": "192.168.88.10", "dst-address": "224.0.0.1"},
+			), nil
+		case "/ip/firewall/connection/remove":
+			removed = append(removed, params[".id"])
+			return mockapi.Reply(), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	killed, err := client.KillConnections(context.Background(), "192.168.88.10/32")
+	if err != nil {
+		t.Fatalf("KillConnections failed: %v", err)
+	}
+	if killed != 3 || len(removed) != 1 || removed[0] != "*1,*2,*4" {
+		t.Fatalf("unexpected removals: killed=%d removed=%v", killed, removed)
+	}
+}
+
+func TestKillConnectionsRetriesOneByOneWhenEntryExpired(t *testing.T) {
+	var removed []string
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+		switch cmd {
+		case "/ip/firewall/connection/print":
+			return mockapi.Reply(
+				map[string]string{".id": "*1", "src-address": "192.168.88.10:51000", "dst-address": "1.1.1.1:443"},
+				map[string]string{".id": "*2", "src-address": "192.168.88.10:51001", "dst-address": "1.1.1.1:443"},
+			), nil
+		case "/ip/firewall/connection/remove":
+			if params[".id"] != "*1" {
+				return nil, fmt.Errorf("no such item")
+			}
+			removed = append(removed, params[".id"])
+			return mockapi.Reply(), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	killed, err := client.KillConnections(context.Background(), "192.168.88.10")
+	if err != nil {
+		t.Fatalf("KillConnections failed: %v", err)
+	}
+	if killed != 1 || len(removed) != 1 || removed[0] != "*1" {
+		t.Fatalf("unexpected removals: killed=%d removed=%v", killed, removed)
+	}
+}
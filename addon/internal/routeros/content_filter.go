@@ -0,0 +1,334 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const (
+	contentFilterProplist = ".id,chain,src-address,protocol,dst-port,tls-host,action,comment,disabled,dynamic"
+	dnsStaticProplist     = ".id,name,regexp,comment"
+	dnsSinkholeAddress    = "0.0.0.0"
+)
+
+// contentFilterRule is one forward-chain rule managed for content filter.
+type contentFilterRule struct {
+	ID         string
+	SrcAddress string
+	Protocol   string
+	DstPort    string
+	TLSHost    string
+	Disabled   bool
+}
+
+func (r contentFilterRule) key() string {
+	return strings.Join([]string{normalizeAddressTarget(r.SrcAddress), r.Protocol, r.DstPort, strings.ToLower(r.TLSHost)}, "|")
+}
+
+// SetContentFilter reconciles filter rules and DNS entries tagged with comment
+// so they block exactly filter.Domains for address. Objects with other
+// comments are never touched.
+func (c *Client) SetContentFilter(
+	ctx context.Context,
+	address string,
+	comment string,
+	filter model.ContentFilter,
+) error {
+	address = normalizeAddressTarget(address)
+	comment = strings.TrimSpace(comment)
+	if address == "" {
+		return &ValidationError{Field: "address", Reason: "is required"}
+	}
+	if comment == "" {
+		return &ValidationError{Field: "comment", Reason: "is required"}
+	}
+	domains := normalizeFilterDomains(filter.Domains)
+	if len(domains) == 0 {
+		return &ValidationError{Field: "domains", Reason: "is required"}
+	}
+
+	c.filters.Lock()
+	defer c.filters.Unlock()
+
+	if err := c.reconcileContentFilterRules(ctx, comment, desiredContentFilterRules(address, domains, filter.BlockQUIC)); err != nil {
+		return err
+	}
+	var patterns []string
+	if filter.BlockDNS {
+		patterns = dnsBlockPatterns(domains)
+	}
+	return c.reconcileDNSBlockEntries(ctx, comment, patterns)
+}
+
+// RemoveContentFilter removes filter rules and DNS entries tagged with comment.
+func (c *Client) RemoveContentFilter(ctx context.Context, comment string) error {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return &ValidationError{Field: "comment", Reason: "is required"}
+	}
+
+	c.filters.Lock()
+	defer c.filters.Unlock()
+
+	if err := c.reconcileContentFilterRules(ctx, comment, nil); err != nil {
+		return err
+	}
+	return c.reconcileDNSBlockEntries(ctx, comment, nil)
+}
+
+// ListContentFilteredTargets returns src addresses of enabled tls-host rules
+// that reject or drop traffic.
+func (c *Client) ListContentFilteredTargets(ctx context.Context) ([]string, error) {
+	rows, err := c.RunCommand(ctx, "/ip/firewall/filter/print", map[string]string{
+		".proplist": contentFilterProplist,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list content filter rules: %w", err)
+	}
+	seen := map[string]struct{}{}
+	targets := make([]string, 0)
+	for _, row := range rows {
+		if boolFromWord(row["disabled"]) || strings.TrimSpace(row["tls-host"]) == "" {
+			continue
+		}
+		if action := strings.TrimSpace(row["action"]); action != "reject" && action != "drop" {
+			continue
+		}
+		address := normalizeAddressTarget(row["src-address"])
+		if address == "" {
+			continue
+		}
+		if _, ok := seen[address]; ok {
+			continue
+		}
+		seen[address] = struct{}{}
+		targets = append(targets, address)
+	}
+	sort.Strings(targets)
+	return targets, nil
+}
+
+// SetContentFilter reconciles content filter on pooled client selected by cfg.
+func (m *Manager) SetContentFilter(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	address string,
+	comment string,
+	filter model.ContentFilter,
+) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.SetContentFilter(ctx, address, comment, filter)
+}
+
+// RemoveContentFilter removes owned content filter on pooled client selected by cfg.
+func (m *Manager) RemoveContentFilter(ctx context.Context, cfg model.RouterConfig, comment string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.RemoveContentFilter(ctx, comment)
+}
+
+// ListContentFilteredTargets lists filtered addresses on pooled client selected by cfg.
+func (m *Manager) ListContentFilteredTargets(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListContentFilteredTargets(ctx)
+}
+
+func (c *Client) reconcileContentFilterRules(ctx context.Context, comment string, desired []contentFilterRule) error {
+	rows, err := c.RunCommand(ctx, "/ip/firewall/filter/print", map[string]string{
+		".proplist": contentFilterProplist,
+	})
+	if err != nil {
+		return fmt.Errorf("list content filter rules: %w", err)
+	}
+
+	want := make(map[string]contentFilterRule, len(desired))
+	for _, rule := range desired {
+		want[rule.key()] = rule
+	}
+	placeBefore := ""
+	for _, row := range rows {
+		id := strings.TrimSpace(row[".id"])
+		if id == "" {
+			continue
+		}
+		anchor := placeBefore == "" && !boolFromWord(row["dynamic"])
+		if strings.TrimSpace(row["comment"]) != comment {
+			if anchor {
+				placeBefore = id
+			}
+			continue
+		}
+		existing := contentFilterRule{
+			ID:         id,
+			SrcAddress: strings.TrimSpace(row["src-address"]),
+			Protocol:   strings.TrimSpace(row["protocol"]),
+			DstPort:    strings.TrimSpace(row["dst-port"]),
+			TLSHost:    strings.TrimSpace(row["tls-host"]),
+			Disabled:   boolFromWord(row["disabled"]),
+		}
+		if _, ok := want[existing.key()]; !ok {
+			if err := c.removeFirewallFilterRule(ctx, id); err != nil {
+				return err
+			}
+			continue
+		}
+		delete(want, existing.key())
+		if anchor {
+			placeBefore = id
+		}
+		if existing.Disabled {
+			if err := c.setFirewallRuleDisabledInTable(ctx, "filter", id, false); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, rule := range desired {
+		if _, missing := want[rule.key()]; !missing {
+			continue
+		}
+		params := map[string]string{
+			"chain":       "forward",
+			"src-address": rule.SrcAddress,
+			"protocol":    rule.Protocol,
+			"dst-port":    rule.DstPort,
+			"action":      "reject",
+			"comment":     comment,
+		}
+		if rule.TLSHost != "" {
+			params["tls-host"] = rule.TLSHost
+			params["reject-with"] = "tcp-reset"
+		} else {
+			params["reject-with"] = "icmp-admin-prohibited"
+		}
+		// Block rules go to the top so earlier accept rules cannot bypass them.
+		if placeBefore != "" {
+			params["place-before"] = placeBefore
+		}
+		if _, err := c.RunCommand(ctx, "/ip/firewall/filter/add", params); err != nil {
+			return fmt.Errorf("add content filter rule for %s: %w", firstNonEmpty(rule.TLSHost, rule.Protocol), err)
+		}
+	}
+	return nil
+}
+
+func (c *Client) removeFirewallFilterRule(ctx context.Context, id string) error {
+	_, err := c.RunCommand(ctx, "/ip/firewall/filter/remove", map[string]string{".id": id})
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("remove firewall filter rule %s: %w", id, err)
+	}
+	return nil
+}
+
+func (c *Client) reconcileDNSBlockEntries(ctx context.Context, comment string, patterns []string) error {
+	rows, err := c.RunCommand(ctx, "/ip/dns/static/print", map[string]string{
+		".proplist": dnsStaticProplist,
+	})
+	if err != nil {
+		return fmt.Errorf("list static dns entries: %w", err)
+	}
+
+	want := make(map[string]struct{}, len(patterns))
+	for _, pattern := range patterns {
+		want[pattern] = struct{}{}
+	}
+	for _, row := range rows {
+		id := strings.TrimSpace(row[".id"])
+		if id == "" || strings.TrimSpace(row["comment"]) != comment {
+			continue
+		}
+		pattern := strings.TrimSpace(row["regexp"])
+		if pattern == "" {
+			// Named entries belong to static DNS records of the same owner.
+			continue
+		}
+		if _, ok := want[pattern]; ok {
+			delete(want, pattern)
+			continue
+		}
+		_, err := c.RunCommand(ctx, "/ip/dns/static/remove", map[string]string{".id": id})
+		if err != nil && !isNotFoundError(err) {
+			return fmt.Errorf("remove static dns entry %s: %w", id, err)
+		}
+	}
+
+	for _, pattern := range patterns {
+		if _, missing := want[pattern]; !missing {
+			continue
+		}
+		_, err := c.RunCommand(ctx, "/ip/dns/static/add", map[string]string{
+			"regexp":  pattern,
+			"address": dnsSinkholeAddress,
+			"comment": comment,
+		})
+		if err != nil && !isAlreadyExistsError(err) {
+			return fmt.Errorf("add static dns entry %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// desiredContentFilterRules builds tls-host rules for each domain and its
+// subdomains, plus optional QUIC reject.
+func desiredContentFilterRules(address string, domains []string, blockQUIC bool) []contentFilterRule {
+	rules := make([]contentFilterRule, 0, len(domains)*2+1)
+	for _, domain := range domains {
+		hosts := []string{domain}
+		if !strings.HasPrefix(domain, "*") {
+			hosts = append(hosts, "*."+domain)
+		}
+		for _, host := range hosts {
+			rules = append(rules, contentFilterRule{SrcAddress: address, Protocol: "tcp", DstPort: "443", TLSHost: host})
+		}
+	}
+	if blockQUIC {
+		rules = append(rules, contentFilterRule{SrcAddress: address, Protocol: "udp", DstPort: "443"})
+	}
+	return rules
+}
+
+// dnsBlockPatterns matches each domain and its subdomains.
+func dnsBlockPatterns(domains []string) []string {
+	seen := map[string]struct{}{}
+	patterns := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		pattern := `^(.*\.)?` + regexp.QuoteMeta(strings.TrimPrefix(domain, "*.")) + `$`
+		if _, ok := seen[pattern]; ok {
+			continue
+		}
+		seen[pattern] = struct{}{}
+		patterns = append(patterns, pattern)
+	}
+	return patterns
+}
+
+func normalizeFilterDomains(domains []string) []string {
+	seen := map[string]struct{}{}
+	out := make([]string, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+		if domain == "" {
+			continue
+		}
+		if _, ok := seen[domain]; ok {
+			continue
+		}
+		seen[domain] = struct{}{}
+		out = append(out, domain)
+	}
+	sort.Strings(out)
+	return out
+}
@@ -0,0 +1,135 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	goros "github.com/go-routeros/routeros/v3"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+	mockapi "github.com/micro-ha/mikrotik-presence/addon/internal/routeros/mock"
+)
+
+func TestSetContentFilterReconcilesOwnedRulesAndDNS(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		nextID = 2
+		order  = []string{"*1"}
+		rules  = map[string]map[string]string{
+			"*1": {"chain": "forward", "action": "accept", "comment": "allow lan"},
+		}
+		dns = map[string]map[string]string{}
+	)
+
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd {
+		case "/ip/firewall/filter/print":
+			rows := make([]map[string]string, 0, len(order))
+			for _, id := range order {
+				row := map[string]string{".id": id}
+				for key, value := range rules[id] {
+					row[key] = value
+				}
+				rows = append(rows, row)
+			}
+			return mockapi.Reply(rows...), nil
+		case "/ip/firewall/filter/add":
+			if params["place-before"] != "*1" {
+				return nil, fmt.Errorf("expected rule placed before *1, got %q", params["place-before"])
+			}
+			id := fmt.Sprintf("*%d", nextID)
+			nextID++
+			rules[id] = params
+			order = append([]string{id}, order...)
+			return mockapi.Reply(map[string]string{"ret": id}), nil
+		case "/ip/firewall/filter/remove":
+			delete(rules, params[".id"])
+			for i, id := range order {
+				if id == params[".id"] {
+					order = append(order[:i], order[i+1:]...)
+					break
+				}
+			}
+			return mockapi.Reply(), nil
+		case "/ip/dns/static/print":
+			rows := make([]map[string]string, 0, len(dns))
+			for id, row := range dns {
+				rows = append(rows, map[string]string{".id": id, "regexp": row["regexp"], "comment": row["comment"]})
+			}
+			return mockapi.Reply(rows...), nil
+		case "/ip/dns/static/add":
+			id := fmt.Sprintf("*D%d", nextID)
+			nextID++
+			dns[id] = params
+			return mockapi.Reply(), nil
+		case "/ip/dns/static/remove":
+			delete(dns, params[".id"])
+			return mockapi.Reply(), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	ctx := context.Background()
+	const comment = "mikrotik-presence:kids/AA:BB:CC:DD:EE:01"
+	filter := model.ContentFilter{Domains: []string{"YouTube.com", "youtube.com"}, BlockQUIC: true, BlockDNS: true}
+	for i := 0; i < 2; i++ {
+		if err := client.SetContentFilter(ctx, "192.168.88.10", comment, filter); err != nil {
+			t.Fatalf("SetContentFilter call %d failed: %v", i, err)
+		}
+	}
+
+	ownedHosts := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		hosts := []string{}
+		for _, rule := range rules {
+			if rule["comment"] == comment {
+				hosts = append(hosts, rule["protocol"]+":"+rule["tls-host"])
+			}
+		}
+		sort.Strings(hosts)
+		return hosts
+	}
+	if got := strings.Join(ownedHosts(), ","); got != "tcp:*.youtube.com,tcp:youtube.com,udp:" {
+		t.Fatalf("unexpected owned rules: %s", got)
+	}
+	if len(dns) != 1 {
+		t.Fatalf("expected one dns entry, got %+v", dns)
+	}
+
+	targets, err := client.ListContentFilteredTargets(ctx)
+	if err != nil {
+		t.Fatalf("ListContentFilteredTargets failed: %v", err)
+	}
+	if strings.Join(targets, ",") != "192.168.88.10" {
+		t.Fatalf("unexpected filtered targets: %v", targets)
+	}
+
+	if err := client.RemoveContentFilter(ctx, comment); err != nil {
+		t.Fatalf("RemoveContentFilter failed: %v", err)
+	}
+	if hosts := ownedHosts(); len(hosts) != 0 || len(dns) != 0 || rules["*1"] == nil {
+		t.Fatalf("expected only foreign rule left, rules=%+v dns=%+v", rules, dns)
+	}
+}
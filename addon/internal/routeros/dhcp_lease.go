@@ -0,0 +1,268 @@
+package routeros
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const dhcpLeaseProplist = ".id,mac-address,address,host-name,server,status,last-seen,comment,dynamic,blocked,disabled"
+
+// DHCPServer is one /ip/dhcp-server instance.
+type DHCPServer struct {
+	Name        string
+	Interface   string
+	AddressPool string
+	Disabled    bool
+}
+
+// IPPool is one /ip/pool entry. Ranges keep RouterOS notation: single
+// address, a-b range or CIDR prefix.
+type IPPool struct {
+	Name   string
+	Ranges []string
+}
+
+// Contains reports whether address falls into any pool range.
+func (p IPPool) Contains(address string) bool {
+	ip := net.ParseIP(strings.TrimSpace(address)).To4()
+	if ip == nil {
+		return false
+	}
+	for _, raw := range p.Ranges {
+		raw = strings.TrimSpace(raw)
+		switch {
+		case strings.Contains(raw, "/"):
+			if _, network, err := net.ParseCIDR(raw); err == nil && network.Contains(ip) {
+				return true
+			}
+		case strings.Contains(raw, "-"):
+			bounds := strings.SplitN(raw, "-", 2)
+			from := net.ParseIP(strings.TrimSpace(bounds[0])).To4()
+			to := net.ParseIP(strings.TrimSpace(bounds[1])).To4()
+			if from != nil && to != nil && bytes.Compare(ip, from) >= 0 && bytes.Compare(ip, to) <= 0 {
+				return true
+			}
+		default:
+			if other := net.ParseIP(raw).To4(); other != nil && other.Equal(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ListDHCPLeases returns every lease with id and comment.
+func (c *Client) ListDHCPLeases(ctx context.Context) ([]DHCPLease, error) {
+	rows, err := c.RunCommand(ctx, "/ip/dhcp-server/lease/print", map[string]string{
+		".proplist": dhcpLeaseProplist,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list dhcp leases: %w", err)
+	}
+	return mapDHCPRows(rows), nil
+}
+
+// AddDHCPLease adds static lease of address for MAC on server.
+func (c *Client) AddDHCPLease(ctx context.Context, mac, address, server, comment string) error {
+	mac = canonicalMAC(mac)
+	if _, err := net.ParseMAC(mac); err != nil || mac == "" {
+		return &ValidationError{Field: "mac", Reason: "must be a MAC address"}
+	}
+	address, err := validLeaseAddress(address)
+	if err != nil {
+		return err
+	}
+	params := map[string]string{
+		"mac-address": mac,
+		"address":     address,
+	}
+	if server = strings.TrimSpace(server); server != "" {
+		params["server"] = server
+	}
+	if comment = strings.TrimSpace(comment); comment != "" {
+		params["comment"] = comment
+	}
+	if _, err := c.RunCommand(ctx, "/ip/dhcp-server/lease/add", params); err != nil {
+		return fmt.Errorf("add dhcp lease %s: %w", mac, err)
+	}
+	return nil
+}
+
+// MakeDHCPLeaseStatic converts dynamic lease into static one keeping its id.
+func (c *Client) MakeDHCPLeaseStatic(ctx context.Context, id string) error {
+	if _, err := c.RunCommand(ctx, "/ip/dhcp-server/lease/make-static", map[string]string{".id": id}); err != nil {
+		return fmt.Errorf("make dhcp lease %s static: %w", id, err)
+	}
+	return nil
+}
+
+// SetDHCPLeaseAddress changes address of static lease.
+func (c *Client) SetDHCPLeaseAddress(ctx context.Context, id, address string) error {
+	address, err := validLeaseAddress(address)
+	if err != nil {
+		return err
+	}
+	_, err = c.RunCommand(ctx, "/ip/dhcp-server/lease/set", map[string]string{
+		".id":     id,
+		"address": address,
+	})
+	if err != nil {
+		return fmt.Errorf("set dhcp lease %s address: %w", id, err)
+	}
+	return nil
+}
+
+// SetDHCPLeaseComment changes comment of lease; empty comment clears it.
+func (c *Client) SetDHCPLeaseComment(ctx context.Context, id, comment string) error {
+	_, err := c.RunCommand(ctx, "/ip/dhcp-server/lease/set", map[string]string{
+		".id":     id,
+		"comment": strings.TrimSpace(comment),
+	})
+	if err != nil {
+		return fmt.Errorf("set dhcp lease %s comment: %w", id, err)
+	}
+	return nil
+}
+
+// RemoveDHCPLease removes lease by id; missing lease is not an error.
+func (c *Client) RemoveDHCPLease(ctx context.Context, id string) error {
+	_, err := c.RunCommand(ctx, "/ip/dhcp-server/lease/remove", map[string]string{".id": id})
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("remove dhcp lease %s: %w", id, err)
+	}
+	return nil
+}
+
+// ListDHCPServers returns DHCP server instances.
+func (c *Client) ListDHCPServers(ctx context.Context) ([]DHCPServer, error) {
+	rows, err := c.RunCommand(ctx, "/ip/dhcp-server/print", map[string]string{
+		".proplist": "name,interface,address-pool,disabled",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list dhcp servers: %w", err)
+	}
+	servers := make([]DHCPServer, 0, len(rows))
+	for _, row := range rows {
+		name := strings.TrimSpace(row["name"])
+		if name == "" {
+			continue
+		}
+		servers = append(servers, DHCPServer{
+			Name:        name,
+			Interface:   strings.TrimSpace(row["interface"]),
+			AddressPool: strings.TrimSpace(row["address-pool"]),
+			Disabled:    boolFromWord(row["disabled"]),
+		})
+	}
+	return servers, nil
+}
+
+// ListIPPools returns IP pools with their ranges.
+func (c *Client) ListIPPools(ctx context.Context) ([]IPPool, error) {
+	rows, err := c.RunCommand(ctx, "/ip/pool/print", map[string]string{
+		".proplist": "name,ranges",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list ip pools: %w", err)
+	}
+	pools := make([]IPPool, 0, len(rows))
+	for _, row := range rows {
+		name := strings.TrimSpace(row["name"])
+		if name == "" {
+			continue
+		}
+		pool := IPPool{Name: name}
+		for _, item := range strings.Split(row["ranges"], ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				pool.Ranges = append(pool.Ranges, item)
+			}
+		}
+		pools = append(pools, pool)
+	}
+	return pools, nil
+}
+
+// ListDHCPLeases returns leases on pooled client selected by cfg.
+func (m *Manager) ListDHCPLeases(ctx context.Context, cfg model.RouterConfig) ([]DHCPLease, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListDHCPLeases(ctx)
+}
+
+// AddDHCPLease adds static lease on pooled client selected by cfg.
+func (m *Manager) AddDHCPLease(ctx context.Context, cfg model.RouterConfig, mac, address, server, comment string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.AddDHCPLease(ctx, mac, address, server, comment)
+}
+
+// MakeDHCPLeaseStatic converts lease on pooled client selected by cfg.
+func (m *Manager) MakeDHCPLeaseStatic(ctx context.Context, cfg model.RouterConfig, id string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.MakeDHCPLeaseStatic(ctx, id)
+}
+
+// SetDHCPLeaseAddress changes lease address on pooled client selected by cfg.
+func (m *Manager) SetDHCPLeaseAddress(ctx context.Context, cfg model.RouterConfig, id, address string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.SetDHCPLeaseAddress(ctx, id, address)
+}
+
+// SetDHCPLeaseComment changes lease comment on pooled client selected by cfg.
+func (m *Manager) SetDHCPLeaseComment(ctx context.Context, cfg model.RouterConfig, id, comment string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.SetDHCPLeaseComment(ctx, id, comment)
+}
+
+// RemoveDHCPLease removes lease on pooled client selected by cfg.
+func (m *Manager) RemoveDHCPLease(ctx context.Context, cfg model.RouterConfig, id string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.RemoveDHCPLease(ctx, id)
+}
+
+// ListDHCPServers returns DHCP servers on pooled client selected by cfg.
+func (m *Manager) ListDHCPServers(ctx context.Context, cfg model.RouterConfig) ([]DHCPServer, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListDHCPServers(ctx)
+}
+
+// ListIPPools returns IP pools on pooled client selected by cfg.
+func (m *Manager) ListIPPools(ctx context.Context, cfg model.RouterConfig) ([]IPPool, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListIPPools(ctx)
+}
+
+func validLeaseAddress(address string) (string, error) {
+	ip := net.ParseIP(strings.TrimSpace(address)).To4()
+	if ip == nil {
+		return "", &ValidationError{Field: "address", Reason: "must be an IPv4 address"}
+	}
+	return ip.String(), nil
+}
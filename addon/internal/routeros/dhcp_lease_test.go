@@ -0,0 +1,106 @@
+package routeros
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	goros "github.com/go-routeros/routeros/v3"
+	mockapi "github.com/micro-ha/mikrotik-presence/addon/internal/routeros/mock"
+)
+
+func TestDHCPLeaseCommands(t *testing.T) {
+	var calls []string
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+		switch cmd {
+		case "/ip/dhcp-server/lease/print":
+			return mockapi.Reply(map[string]string{
+				".id":         "*1",
+				"mac-address": "aa:bb:cc:dd:ee:01",
+				"address":     "192.168.88.20",
+				"server":      "defconf",
+				"comment":     "Laptop",
+				"dynamic":     "true",
+			}), nil
+		case "/ip/dhcp-server/lease/remove":
+			if params[".id"] == "*gone" {
+				return nil, errors.New("from RouterOS device: no such item")
+			}
+		}
+		calls = append(calls, fmt.Sprintf("%s %v", cmd, params))
+		return mockapi.Reply(), nil
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	ctx := context.Background()
+	leases, err := client.ListDHCPLeases(ctx)
+	if err != nil {
+		t.Fatalf("ListDHCPLeases() error = %v", err)
+	}
+	if len(leases) != 1 || leases[0].ID != "*1" || leases[0].MAC != "AA:BB:CC:DD:EE:01" || leases[0].Comment != "Laptop" || !leases[0].Dynamic {
+		t.Fatalf("unexpected leases %+v", leases)
+	}
+
+	if err := client.MakeDHCPLeaseStatic(ctx, "*1"); err != nil {
+		t.Fatalf("MakeDHCPLeaseStatic() error = %v", err)
+	}
+	if err := client.SetDHCPLeaseAddress(ctx, "*1", " 192.168.88.5 "); err != nil {
+		t.Fatalf("SetDHCPLeaseAddress() error = %v", err)
+	}
+	if err := client.SetDHCPLeaseComment(ctx, "*1", "Desk PC"); err != nil {
+		t.Fatalf("SetDHCPLeaseComment() error = %v", err)
+	}
+	if err := client.AddDHCPLease(ctx, "aa-bb-cc-dd-ee-02", "192.168.88.6", "defconf", ""); err != nil {
+		t.Fatalf("AddDHCPLease() error = %v", err)
+	}
+	if err := client.RemoveDHCPLease(ctx, "*gone"); err != nil {
+		t.Fatalf("RemoveDHCPLease() of missing lease error = %v", err)
+	}
+
+	want := []string{
+		"/ip/dhcp-server/lease/make-static map[.id:*1]",
+		"/ip/dhcp-server/lease/set map[.id:*1 address:192.168.88.5]",
+		"/ip/dhcp-server/lease/set map[.id:*1 comment:Desk PC]",
+		"/ip/dhcp-server/lease/add map[address:192.168.88.6 mac-address:AA:BB:CC:DD:EE:02 server:defconf]",
+	}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Fatalf("unexpected calls:\n got %v\nwant %v", calls, want)
+	}
+
+	var validationErr *ValidationError
+	if err := client.SetDHCPLeaseAddress(ctx, "*1", "fe80::1"); !errors.As(err, &validationErr) {
+		t.Fatalf("expected IPv6 address to be rejected, got %v", err)
+	}
+}
+
+func TestIPPoolContains(t *testing.T) {
+	pool := IPPool{Name: "dhcp", Ranges: []string{"192.168.88.10-192.168.88.100", "10.0.0.0/30", "172.16.0.9"}}
+	cases := map[string]bool{
+		"192.168.88.10":  true,
+		"192.168.88.100": true,
+		"192.168.88.101": false,
+		"192.168.88.9":   false,
+		"10.0.0.3":       true,
+		"10.0.0.4":       false,
+		"172.16.0.9":     true,
+		"garbage":        false,
+	}
+	for address, want := range cases {
+		if got := pool.Contains(address); got != want {
+			t.Fatalf("Contains(%q) = %v, want %v", address, got, want)
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const (
+	dnsAdlistProplist      = ".id,url,file,disabled"
+	dnsStaticEntryProplist = ".id,name,type,address,cname,forward-to,comment,disabled,dynamic"
+)
+
+// DNSAdlist is one RouterOS 7.15+ /ip/dns/adlist entry, loaded from URL or
+// local file.
+type DNSAdlist struct {
+	ID       string
+	URL      string
+	File     string
+	Disabled bool
+}
+
+// Source returns URL or file the adlist is loaded from.
+func (a DNSAdlist) Source() string {
+	return firstNonEmpty(a.URL, a.File)
+}
+
+// dnsStaticEntry is one named /ip/dns/static row.
+type dnsStaticEntry struct {
+	ID       string
+	Record   model.DNSStaticRecord
+	Disabled bool
+}
+
+// ListDNSAdlists returns all adlists.
+func (c *Client) ListDNSAdlists(ctx context.Context) ([]DNSAdlist, error) {
+	rows, err := c.RunCommand(ctx, "/ip/dns/adlist/print", map[string]string{
+		".proplist": dnsAdlistProplist,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list dns adlists: %w", err)
+	}
+	adlists := make([]DNSAdlist, 0, len(rows))
+	for _, row := range rows {
+		id := strings.TrimSpace(row[".id"])
+		if id == "" {
+			continue
+		}
+		adlists = append(adlists, DNSAdlist{
+			ID:       id,
+			URL:      strings.TrimSpace(row["url"]),
+			File:     strings.TrimSpace(row["file"]),
+			Disabled: boolFromWord(row["disabled"]),
+		})
+	}
+	return adlists, nil
+}
+
+// ListDNSAdlistSources returns adlist URLs and files in sorted order.
+func (c *Client) ListDNSAdlistSources(ctx context.Context) ([]string, error) {
+	adlists, err := c.ListDNSAdlists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]struct{}{}
+	sources := make([]string, 0, len(adlists))
+	for _, adlist := range adlists {
+		source := adlist.Source()
+		if source == "" {
+			continue
+		}
+		if _, ok := seen[source]; ok {
+			continue
+		}
+		seen[source] = struct{}{}
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	return sources, nil
+}
+
+// SetDNSAdlistsEnabled enables or disables adlists loaded from source; empty
+// source selects every adlist.
+func (c *Client) SetDNSAdlistsEnabled(ctx context.Context, source string, enabled bool) error {
+	adlists, err := c.findDNSAdlists(ctx, source)
+	if err != nil {
+		return err
+	}
+	for _, adlist := range adlists {
+		if adlist.Disabled != enabled {
+			continue
+		}
+		_, err := c.RunCommand(ctx, "/ip/dns/adlist/set", map[string]string{
+			".id":      adlist.ID,
+			"disabled": boolToWord(!enabled),
+		})
+		if err != nil {
+			return fmt.Errorf("set dns adlist %s enabled=%t: %w", adlist.Source(), enabled, err)
+		}
+	}
+	return nil
+}
+
+// GetDNSAdlistsEnabled reports whether every adlist selected by source is
+// enabled; empty source selects every adlist.
+func (c *Client) GetDNSAdlistsEnabled(ctx context.Context, source string) (bool, error) {
+	adlists, err := c.findDNSAdlists(ctx, source)
+	if err != nil {
+		return false, err
+	}
+	for _, adlist := range adlists {
+		if adlist.Disabled {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// EnsureDNSStaticRecord creates or updates static DNS entry named
+// record.Name and tagged with comment. Entries with other comments are never
+// touched, so records added by hand keep precedence rules of RouterOS.
+func (c *Client) EnsureDNSStaticRecord(ctx context.Context, record model.DNSStaticRecord, comment string) error {
+	record, err := normalizeDNSStaticRecord(record)
+	if err != nil {
+		return err
+	}
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return &ValidationError{Field: "comment", Reason: "is required"}
+	}
+
+	c.filters.Lock()
+	defer c.filters.Unlock()
+
+	owned, err := c.ownedDNSStaticEntries(ctx, record.Name, comment)
+	if err != nil {
+		return err
+	}
+	words := dnsStaticRecordWords(record)
+	if len(owned) == 0 {
+		words["name"] = record.Name
+		words["comment"] = comment
+		_, err := c.RunCommand(ctx, "/ip/dns/static/add", words)
+		if err != nil && !isAlreadyExistsError(err) {
+			return fmt.Errorf("add static dns record %q: %w", record.Name, err)
+		}
+		return nil
+	}
+
+	for i, entry := range owned {
+		if i > 0 {
+			// Keep exactly one owned entry per name.
+			if err := c.removeDNSStaticEntry(ctx, entry.ID); err != nil {
+				return err
+			}
+			continue
+		}
+		if entry.Record == record && !entry.Disabled {
+			continue
+		}
+		// Fields of other record types are ignored by RouterOS once type changes.
+		words[".id"] = entry.ID
+		words["disabled"] = "no"
+		if _, err := c.RunCommand(ctx, "/ip/dns/static/set", words); err != nil {
+			return fmt.Errorf("update static dns record %q: %w", record.Name, err)
+		}
+	}
+	return nil
+}
+
+// RemoveDNSStaticRecord removes static DNS entries named name and tagged with comment.
+func (c *Client) RemoveDNSStaticRecord(ctx context.Context, name, comment string) error {
+	name = normalizeDNSName(name)
+	comment = strings.TrimSpace(comment)
+	if name == "" {
+		return &ValidationError{Field: "name", Reason: "is required"}
+	}
+	if comment == "" {
+		return &ValidationError{Field: "comment", Reason: "is required"}
+	}
+
+	c.filters.Lock()
+	defer c.filters.Unlock()
+
+	owned, err := c.ownedDNSStaticEntries(ctx, name, comment)
+	if err != nil {
+		return err
+	}
+	for _, entry := range owned {
+		if err := c.removeDNSStaticEntry(ctx, entry.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListDNSAdlistSources lists adlist sources on pooled client selected by cfg.
+func (m *Manager) ListDNSAdlistSources(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListDNSAdlistSources(ctx)
+}
+
+// SetDNSAdlistsEnabled toggles adlists on pooled client selected by cfg.
+func (m *Manager) SetDNSAdlistsEnabled(ctx context.Context, cfg model.RouterConfig, source string, enabled bool) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.SetDNSAdlistsEnabled(ctx, source, enabled)
+}
+
+// GetDNSAdlistsEnabled reads adlist state on pooled client selected by cfg.
+func (m *Manager) GetDNSAdlistsEnabled(ctx context.Context, cfg model.RouterConfig, source string) (bool, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return false, err
+	}
+	return client.GetDNSAdlistsEnabled(ctx, source)
+}
+
+// EnsureDNSStaticRecord applies owned static DNS record on pooled client selected by cfg.
+func (m *Manager) EnsureDNSStaticRecord(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	record model.DNSStaticRecord,
+	comment string,
+) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.EnsureDNSStaticRecord(ctx, record, comment)
+}
+
+// RemoveDNSStaticRecord removes owned static DNS record on pooled client selected by cfg.
+func (m *Manager) RemoveDNSStaticRecord(ctx context.Context, cfg model.RouterConfig, name, comment string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.RemoveDNSStaticRecord(ctx, name, comment)
+}
+
+func (c *Client) findDNSAdlists(ctx context.Context, source string) ([]DNSAdlist, error) {
+	source = strings.TrimSpace(source)
+	adlists, err := c.ListDNSAdlists(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if source == "" {
+		if len(adlists) == 0 {
+			return nil, fmt.Errorf("no dns adlists configured")
+		}
+		return adlists, nil
+	}
+	matched := make([]DNSAdlist, 0, 1)
+	for _, adlist := range adlists {
+		if adlist.URL == source || adlist.File == source {
+			matched = append(matched, adlist)
+		}
+	}
+	if len(matched) == 0 {
+		return nil, fmt.Errorf("dns adlist %q %w", source, ErrNotFound)
+	}
+	return matched, nil
+}
+
+func (c *Client) ownedDNSStaticEntries(ctx context.Context, name, comment string) ([]dnsStaticEntry, error) {
+	rows, err := c.RunCommand(ctx, "/ip/dns/static/print", map[string]string{
+		".proplist": dnsStaticEntryProplist,
+		"?name":     name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list static dns records %q: %w", name, err)
+	}
+	entries := make([]dnsStaticEntry, 0, len(rows))
+	for _, row := range rows {
+		id := strings.TrimSpace(row[".id"])
+		if id == "" || boolFromWord(row["dynamic"]) || strings.TrimSpace(row["comment"]) != comment {
+			continue
+		}
+		if normalizeDNSName(row["name"]) != name {
+			continue
+		}
+		entries = append(entries, dnsStaticEntry{
+			ID:       id,
+			Record:   mapDNSStaticRecord(row),
+			Disabled: boolFromWord(row["disabled"]),
+		})
+	}
+	return entries, nil
+}
+
+func (c *Client) removeDNSStaticEntry(ctx context.Context, id string) error {
+	_, err := c.RunCommand(ctx, "/ip/dns/static/remove", map[string]string{".id": id})
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("remove static dns entry %s: %w", id, err)
+	}
+	return nil
+}
+
+func mapDNSStaticRecord(row map[string]string) model.DNSStaticRecord {
+	record := model.DNSStaticRecord{
+		Name: normalizeDNSName(row["name"]),
+		Type: strings.ToUpper(strings.TrimSpace(row["type"])),
+	}
+	switch record.Type {
+	case "", model.DNSRecordA:
+		record.Type = model.DNSRecordA
+		record.Value = strings.TrimSpace(row["address"])
+	case model.DNSRecordCNAME:
+		record.Value = normalizeDNSName(row["cname"])
+	case model.DNSRecordFWD:
+		record.Value = strings.TrimSpace(row["forward-to"])
+	}
+	return record
+}
+
+// dnsStaticRecordWords returns RouterOS attributes for record type and value.
+func dnsStaticRecordWords(record model.DNSStaticRecord) map[string]string {
+	words := map[string]string{"type": record.Type}
+	switch record.Type {
+	case model.DNSRecordA:
+		words["address"] = record.Value
+	case model.DNSRecordCNAME:
+		words["cname"] = record.Value
+	case model.DNSRecordFWD:
+		words["forward-to"] = record.Value
+	}
+	return words
+}
+
+func normalizeDNSStaticRecord(record model.DNSStaticRecord) (model.DNSStaticRecord, error) {
+	record.Name = normalizeDNSName(record.Name)
+	record.Type = strings.ToUpper(strings.TrimSpace(record.Type))
+	record.Value = strings.TrimSpace(record.Value)
+	if record.Name == "" {
+		return model.DNSStaticRecord{}, &ValidationError{Field: "name", Reason: "is required"}
+	}
+	switch record.Type {
+	case model.DNSRecordA, model.DNSRecordFWD:
+	case model.DNSRecordCNAME:
+		record.Value = normalizeDNSName(record.Value)
+	case model.DNSRecordNXDOMAIN:
+		record.Value = ""
+		return record, nil
+	default:
+		return model.DNSStaticRecord{}, &ValidationError{Field: "type", Reason: fmt.Sprintf("unsupported %q", record.Type)}
+	}
+	if record.Value == "" {
+		return model.DNSStaticRecord{}, &ValidationError{Field: "value", Reason: "is required"}
+	}
+	return record, nil
+}
+
+func normalizeDNSName(name string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".")
+}
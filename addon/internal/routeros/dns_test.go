@@ -0,0 +1,176 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	goros "github.com/go-routeros/routeros/v3"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+	mockapi "github.com/micro-ha/mikrotik-presence/addon/internal/routeros/mock"
+)
+
+func TestDNSAdlistsEnableDisable(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		sets    int
+		adlists = map[string]map[string]string{
+			"*1": {"url": "https://example.com/hosts.txt", "disabled": "true"},
+			"*2": {"file": "local.txt", "disabled": "false"},
+		}
+	)
+
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd {
+		case "/ip/dns/adlist/print":
+			rows := make([]map[string]string, 0, len(adlists))
+			for _, id := range []string{"*1", "*2"} {
+				row := map[string]string{".id": id}
+				for key, value := range adlists[id] {
+					row[key] = value
+				}
+				rows = append(rows, row)
+			}
+			return mockapi.Reply(rows...), nil
+		case "/ip/dns/adlist/set":
+			sets++
+			adlists[params[".id"]]["disabled"] = fmt.Sprint(boolFromWord(params["disabled"]))
+			return mockapi.Reply(), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	ctx := context.Background()
+	if enabled, err := client.GetDNSAdlistsEnabled(ctx, ""); err != nil || enabled {
+		t.Fatalf("expected mixed adlists to read disabled, got %v err=%v", enabled, err)
+	}
+	if enabled, err := client.GetDNSAdlistsEnabled(ctx, "local.txt"); err != nil || !enabled {
+		t.Fatalf("expected local.txt enabled, got %v err=%v", enabled, err)
+	}
+	if err := client.SetDNSAdlistsEnabled(ctx, "", true); err != nil {
+		t.Fatalf("SetDNSAdlistsEnabled failed: %v", err)
+	}
+	if sets != 1 {
+		t.Fatalf("expected only disabled adlist to be set, got %d sets", sets)
+	}
+	if enabled, err := client.GetDNSAdlistsEnabled(ctx, ""); err != nil || !enabled {
+		t.Fatalf("expected every adlist enabled, got %v err=%v", enabled, err)
+	}
+	if err := client.SetDNSAdlistsEnabled(ctx, "https://missing.example/list", false); err == nil {
+		t.Fatalf("expected error for unknown adlist")
+	}
+}
+
+func TestDNSStaticRecordEnsureAndRemove(t *testing.T) {
+	const comment = "mikrotik-presence:dns/AA:BB:CC:DD:EE:01"
+	var (
+		mu      sync.Mutex
+		nextID  = 3
+		adds    int
+		entries = map[string]map[string]string{
+			"*1": {"name": "nas.lan", "address": "10.0.0.5", "comment": "manual"},
+			"*2": {"regexp": `^(.*\.)?ads\.example$`, "address": "0.0.0.0", "comment": comment},
+		}
+	)
+
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd {
+		case "/ip/dns/static/print":
+			rows := make([]map[string]string, 0, len(entries))
+			for id, entry := range entries {
+				if name := params["?name"]; name != "" && entry["name"] != name {
+					continue
+				}
+				row := map[string]string{".id": id}
+				for key, value := range entry {
+					row[key] = value
+				}
+				rows = append(rows, row)
+			}
+			return mockapi.Reply(rows...), nil
+		case "/ip/dns/static/add":
+			adds++
+			entries[fmt.Sprintf("*%d", nextID)] = params
+			nextID++
+			return mockapi.Reply(), nil
+		case "/ip/dns/static/set":
+			for key, value := range params {
+				if key != ".id" {
+					entries[params[".id"]][key] = value
+				}
+			}
+			return mockapi.Reply(), nil
+		case "/ip/dns/static/remove":
+			delete(entries, params[".id"])
+			return mockapi.Reply(), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	ctx := context.Background()
+	record := model.DNSStaticRecord{Name: "NAS.lan", Type: model.DNSRecordA, Value: "192.168.88.10"}
+	for i := 0; i < 2; i++ {
+		if err := client.EnsureDNSStaticRecord(ctx, record, comment); err != nil {
+			t.Fatalf("EnsureDNSStaticRecord call %d failed: %v", i, err)
+		}
+	}
+	if adds != 1 || len(entries) != 3 {
+		t.Fatalf("expected one owned record added, adds=%d entries=%+v", adds, entries)
+	}
+
+	record = model.DNSStaticRecord{Name: "nas.lan", Type: model.DNSRecordCNAME, Value: "storage.lan."}
+	if err := client.EnsureDNSStaticRecord(ctx, record, comment); err != nil {
+		t.Fatalf("EnsureDNSStaticRecord update failed: %v", err)
+	}
+	if entries["*3"]["type"] != "CNAME" || entries["*3"]["cname"] != "storage.lan" {
+		t.Fatalf("expected owned record updated in place, got %+v", entries["*3"])
+	}
+
+	// Removing content filter sinkholes of the same owner keeps named records.
+	if err := client.reconcileDNSBlockEntries(ctx, comment, nil); err != nil {
+		t.Fatalf("reconcileDNSBlockEntries failed: %v", err)
+	}
+	if entries["*2"] != nil || entries["*3"] == nil {
+		t.Fatalf("expected only regexp entry removed, got %+v", entries)
+	}
+	if err := client.RemoveDNSStaticRecord(ctx, "nas.lan", comment); err != nil {
+		t.Fatalf("RemoveDNSStaticRecord failed: %v", err)
+	}
+	if len(entries) != 1 || entries["*1"] == nil {
+		t.Fatalf("expected only manual entry left, got %+v", entries)
+	}
+}
@@ -10,6 +10,23 @@ import (
 	goros "github.com/go-routeros/routeros/v3"
 )
 
+// ErrNotFound marks router objects that lookups found missing; typed lookup
+// errors match it through errors.Is.
+var ErrNotFound = errors.New("not found")
+
+// IsNotFound reports whether err means router object no longer exists: a
+// lookup error matching ErrNotFound or RouterOS "no such item" trap.
+func IsNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrNotFound) {
+		return true
+	}
+	var deviceErr *goros.DeviceError
+	return errors.As(err, &deviceErr) && strings.Contains(strings.ToLower(deviceErr.Error()), "no such item")
+}
+
 // ValidationError describes a user-supplied invalid value.
 type ValidationError struct {
 	Field  string
@@ -56,6 +73,11 @@ func (e *RuleNotFoundError) Error() string {
 	return fmt.Sprintf("firewall rule %q not found", e.ID)
 }
 
+// Is matches ErrNotFound.
+func (e *RuleNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 // AddressListNotFoundError means requested address-list entry was not found.
 type AddressListNotFoundError struct {
 	List    string
@@ -69,6 +91,11 @@ func (e *AddressListNotFoundError) Error() string {
 	return fmt.Sprintf("address-list entry %q/%q not found", e.List, e.Address)
 }
 
+// Is matches ErrNotFound.
+func (e *AddressListNotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
 func isRetryableError(err error) bool {
 	if err == nil {
 		return false
@@ -10,12 +10,16 @@ import (
 
 // FirewallRule represents a simplified RouterOS firewall rule.
 type FirewallRule struct {
-	ID       string
-	Table    string
-	Chain    string
-	Action   string
-	Comment  string
-	Disabled bool
+	ID             string
+	Table          string
+	Chain          string
+	Action         string
+	Comment        string
+	Disabled       bool
+	SrcAddressList string
+	DstAddressList string
+	Bytes          int64
+	Packets        int64
 }
 
 // ListFirewallRules returns rules from filter/nat/mangle/raw tables.
@@ -41,6 +45,19 @@ func (m *Manager) ListFirewallRules(ctx context.Context, cfg model.RouterConfig)
 	return client.ListFirewallRules(ctx)
 }
 
+// ListFirewallRulesInTable returns rules from one firewall table.
+func (m *Manager) ListFirewallRulesInTable(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	table string,
+) ([]FirewallRule, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.listFirewallRulesByTable(ctx, table)
+}
+
 // EnableRule enables a firewall rule by .id, auto-detecting table.
 func (c *Client) EnableRule(ctx context.Context, id string) error {
 	return c.setRuleDisabledByID(ctx, id, false)
@@ -117,7 +134,7 @@ func (c *Client) listFirewallRulesByTable(ctx context.Context, table string) ([]
 	}
 
 	rows, err := c.RunCommand(ctx, "/ip/firewall/"+table+"/print", map[string]string{
-		".proplist": ".id,chain,action,comment,disabled",
+		".proplist": ".id,chain,action,comment,disabled,src-address-list,dst-address-list,bytes,packets",
 	})
 	if err != nil {
 		return nil, fmt.Errorf("list firewall table %q: %w", table, err)
@@ -130,12 +147,16 @@ func (c *Client) listFirewallRulesByTable(ctx context.Context, table string) ([]
 			continue
 		}
 		rules = append(rules, FirewallRule{
-			ID:       id,
-			Table:    table,
-			Chain:    strings.TrimSpace(row["chain"]),
-			Action:   strings.TrimSpace(row["action"]),
-			Comment:  strings.TrimSpace(row["comment"]),
-			Disabled: boolFromWord(row["disabled"]),
+			ID:             id,
+			Table:          table,
+			Chain:          strings.TrimSpace(row["chain"]),
+			Action:         strings.TrimSpace(row["action"]),
+			Comment:        strings.TrimSpace(row["comment"]),
+			Disabled:       boolFromWord(row["disabled"]),
+			SrcAddressList: strings.TrimSpace(row["src-address-list"]),
+			DstAddressList: strings.TrimSpace(row["dst-address-list"]),
+			Bytes:          counterFromWord(row["bytes"]),
+			Packets:        counterFromWord(row["packets"]),
 		})
 	}
 	return rules, nil
@@ -225,7 +246,7 @@ func (m *Manager) SetFirewallRulesDisabledByComment(
 		return err
 	}
 	if len(rules) == 0 {
-		return fmt.Errorf("firewall rules with comment %q not found", comment)
+		return fmt.Errorf("firewall rules with comment %q %w", comment, ErrNotFound)
 	}
 
 	for _, rule := range rules {
@@ -255,7 +276,7 @@ func (m *Manager) GetFirewallRulesEnabledByComment(
 		return false, err
 	}
 	if len(rules) == 0 {
-		return false, fmt.Errorf("firewall rules with comment %q not found", comment)
+		return false, fmt.Errorf("firewall rules with comment %q %w", comment, ErrNotFound)
 	}
 	for _, rule := range rules {
 		if rule.Disabled {
@@ -2,6 +2,7 @@ package routeros
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -11,6 +12,7 @@ import (
 	"time"
 
 	goros "github.com/go-routeros/routeros/v3"
+	"github.com/go-routeros/routeros/v3/proto"
 	mockapi "github.com/micro-ha/mikrotik-presence/addon/internal/routeros/mock"
 )
 
@@ -91,3 +93,22 @@ func tableFromCommand(cmd string) string {
 	}
 	return parts[2]
 }
+
+func TestIsNotFoundMatchesTypedErrorsAndNoSuchItem(t *testing.T) {
+	cases := []struct {
+		err  error
+		want bool
+	}{
+		{err: &RuleNotFoundError{ID: "*5"}, want: true},
+		{err: fmt.Errorf("restore: %w", &AddressListNotFoundError{List: "blocked"}), want: true},
+		{err: fmt.Errorf("kid-control profile %q %w", "kids", ErrNotFound), want: true},
+		{err: &goros.DeviceError{Sentence: &proto.Sentence{Map: map[string]string{"message": "no such item"}}}, want: true},
+		{err: errors.New("interface not found on bridge"), want: false},
+		{err: &goros.DeviceError{Sentence: &proto.Sentence{Map: map[string]string{"message": "input does not match any value"}}}, want: false},
+	}
+	for _, tc := range cases {
+		if got := IsNotFound(tc.err); got != tc.want {
+			t.Fatalf("IsNotFound(%v) = %t, want %t", tc.err, got, tc.want)
+		}
+	}
+}
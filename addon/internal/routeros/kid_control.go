@@ -0,0 +1,291 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+// KidControlProfile is one RouterOS 7 /ip/kid-control profile ("kid").
+type KidControlProfile struct {
+	ID        string
+	Name      string
+	RateLimit string
+	Paused    bool
+	Disabled  bool
+}
+
+// KidControlDevice binds a MAC address to kid-control profile.
+type KidControlDevice struct {
+	ID      string
+	Name    string
+	MAC     string
+	Profile string
+	Comment string
+	Dynamic bool
+}
+
+// ListKidControlProfiles returns all kid-control profiles.
+func (c *Client) ListKidControlProfiles(ctx context.Context) ([]KidControlProfile, error) {
+	rows, err := c.RunCommand(ctx, "/ip/kid-control/print", map[string]string{
+		".proplist": ".id,name,rate-limit,paused,disabled",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list kid-control profiles: %w", err)
+	}
+	profiles := make([]KidControlProfile, 0, len(rows))
+	for _, row := range rows {
+		id := strings.TrimSpace(row[".id"])
+		if id == "" {
+			continue
+		}
+		profiles = append(profiles, KidControlProfile{
+			ID:        id,
+			Name:      strings.TrimSpace(row["name"]),
+			RateLimit: strings.TrimSpace(row["rate-limit"]),
+			Paused:    boolFromWord(row["paused"]),
+			Disabled:  boolFromWord(row["disabled"]),
+		})
+	}
+	return profiles, nil
+}
+
+// ListKidControlProfileNames returns kid-control profile names in sorted order.
+func (c *Client) ListKidControlProfileNames(ctx context.Context) ([]string, error) {
+	rows, err := c.RunCommand(ctx, "/ip/kid-control/print", map[string]string{
+		".proplist": "name",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list kid-control profiles: %w", err)
+	}
+	return distinctSorted(rows, "name"), nil
+}
+
+// SetKidControlPaused pauses or resumes profile by name.
+func (c *Client) SetKidControlPaused(ctx context.Context, name string, paused bool) error {
+	profile, err := c.findKidControlProfile(ctx, name)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		return fmt.Errorf("kid-control profile %q %w", name, ErrNotFound)
+	}
+	if profile.Paused == paused {
+		return nil
+	}
+	command := "/ip/kid-control/resume"
+	if paused {
+		command = "/ip/kid-control/pause"
+	}
+	if _, err := c.RunCommand(ctx, command, map[string]string{"numbers": profile.ID}); err != nil {
+		return fmt.Errorf("set kid-control profile %q paused=%t: %w", name, paused, err)
+	}
+	return nil
+}
+
+// GetKidControlPaused reports whether profile is paused.
+func (c *Client) GetKidControlPaused(ctx context.Context, name string) (bool, error) {
+	profile, err := c.findKidControlProfile(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	if profile == nil {
+		return false, fmt.Errorf("kid-control profile %q %w", name, ErrNotFound)
+	}
+	return profile.Paused, nil
+}
+
+// ListKidControlDevices returns all kid-control device bindings.
+func (c *Client) ListKidControlDevices(ctx context.Context) ([]KidControlDevice, error) {
+	rows, err := c.RunCommand(ctx, "/ip/kid-control/device/print", map[string]string{
+		".proplist": ".id,name,mac-address,user,comment,dynamic",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list kid-control devices: %w", err)
+	}
+	devices := make([]KidControlDevice, 0, len(rows))
+	for _, row := range rows {
+		id := strings.TrimSpace(row[".id"])
+		if id == "" {
+			continue
+		}
+		devices = append(devices, KidControlDevice{
+			ID:      id,
+			Name:    strings.TrimSpace(row["name"]),
+			MAC:     canonicalMAC(row["mac-address"]),
+			Profile: strings.TrimSpace(row["user"]),
+			Comment: strings.TrimSpace(row["comment"]),
+			Dynamic: boolFromWord(row["dynamic"]),
+		})
+	}
+	return devices, nil
+}
+
+// KidControlProfileForMAC returns profile MAC is bound to, or "" when unbound.
+func (c *Client) KidControlProfileForMAC(ctx context.Context, mac string) (string, error) {
+	device, err := c.findKidControlDevice(ctx, mac)
+	if err != nil || device == nil {
+		return "", err
+	}
+	return device.Profile, nil
+}
+
+// BindKidControlDevice binds MAC to profile. A static binding to another
+// profile is moved only when it carries comment, so manual setups survive.
+func (c *Client) BindKidControlDevice(ctx context.Context, profile, mac, name, comment string) error {
+	profile = strings.TrimSpace(profile)
+	mac = canonicalMAC(mac)
+	if profile == "" {
+		return &ValidationError{Field: "profile", Reason: "is required"}
+	}
+	if mac == "" {
+		return &ValidationError{Field: "mac", Reason: "is required"}
+	}
+
+	device, err := c.findKidControlDevice(ctx, mac)
+	if err != nil {
+		return err
+	}
+	if device != nil && !device.Dynamic {
+		if device.Profile == profile {
+			return nil
+		}
+		if device.Comment != strings.TrimSpace(comment) {
+			return fmt.Errorf("mac %s is bound to kid-control profile %q outside of add-on", mac, device.Profile)
+		}
+		_, err := c.RunCommand(ctx, "/ip/kid-control/device/set", map[string]string{".id": device.ID, "user": profile})
+		if err != nil {
+			return fmt.Errorf("move kid-control device %s: %w", mac, err)
+		}
+		return nil
+	}
+
+	params := map[string]string{
+		"name":        firstNonEmpty(name, mac),
+		"mac-address": mac,
+		"user":        profile,
+	}
+	if comment = strings.TrimSpace(comment); comment != "" {
+		params["comment"] = comment
+	}
+	_, err = c.RunCommand(ctx, "/ip/kid-control/device/add", params)
+	if err != nil && !isAlreadyExistsError(err) {
+		return fmt.Errorf("bind kid-control device %s: %w", mac, err)
+	}
+	return nil
+}
+
+// UnbindKidControlDevice removes static binding of MAC tagged with comment.
+func (c *Client) UnbindKidControlDevice(ctx context.Context, mac, comment string) error {
+	device, err := c.findKidControlDevice(ctx, mac)
+	if err != nil || device == nil || device.Dynamic || device.Comment != strings.TrimSpace(comment) {
+		return err
+	}
+	_, err = c.RunCommand(ctx, "/ip/kid-control/device/remove", map[string]string{".id": device.ID})
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("unbind kid-control device %s: %w", device.MAC, err)
+	}
+	return nil
+}
+
+// ListKidControlProfileNames lists kid-control profiles on pooled client selected by cfg.
+func (m *Manager) ListKidControlProfileNames(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListKidControlProfileNames(ctx)
+}
+
+// SetKidControlPaused pauses or resumes profile on pooled client selected by cfg.
+func (m *Manager) SetKidControlPaused(ctx context.Context, cfg model.RouterConfig, profile string, paused bool) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.SetKidControlPaused(ctx, profile, paused)
+}
+
+// GetKidControlPaused reads profile paused state on pooled client selected by cfg.
+func (m *Manager) GetKidControlPaused(ctx context.Context, cfg model.RouterConfig, profile string) (bool, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return false, err
+	}
+	return client.GetKidControlPaused(ctx, profile)
+}
+
+// KidControlProfileForMAC resolves MAC binding on pooled client selected by cfg.
+func (m *Manager) KidControlProfileForMAC(ctx context.Context, cfg model.RouterConfig, mac string) (string, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return "", err
+	}
+	return client.KidControlProfileForMAC(ctx, mac)
+}
+
+// BindKidControlDevice binds MAC to profile on pooled client selected by cfg.
+func (m *Manager) BindKidControlDevice(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	profile, mac, name, comment string,
+) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.BindKidControlDevice(ctx, profile, mac, name, comment)
+}
+
+// UnbindKidControlDevice removes owned MAC binding on pooled client selected by cfg.
+func (m *Manager) UnbindKidControlDevice(ctx context.Context, cfg model.RouterConfig, mac, comment string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.UnbindKidControlDevice(ctx, mac, comment)
+}
+
+func (c *Client) findKidControlProfile(ctx context.Context, name string) (*KidControlProfile, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, &ValidationError{Field: "profile", Reason: "is required"}
+	}
+	profiles, err := c.ListKidControlProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range profiles {
+		if profiles[i].Name == name {
+			return &profiles[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// findKidControlDevice prefers static binding over dynamic discovery entry.
+func (c *Client) findKidControlDevice(ctx context.Context, mac string) (*KidControlDevice, error) {
+	mac = canonicalMAC(mac)
+	if mac == "" {
+		return nil, &ValidationError{Field: "mac", Reason: "is required"}
+	}
+	devices, err := c.ListKidControlDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var found *KidControlDevice
+	for i := range devices {
+		if devices[i].MAC != mac {
+			continue
+		}
+		if !devices[i].Dynamic {
+			return &devices[i], nil
+		}
+		if found == nil {
+			found = &devices[i]
+		}
+	}
+	return found, nil
+}
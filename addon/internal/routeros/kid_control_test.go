@@ -0,0 +1,116 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	goros "github.com/go-routeros/routeros/v3"
+	mockapi "github.com/micro-ha/mikrotik-presence/addon/internal/routeros/mock"
+)
+
+func TestKidControlPauseAndDeviceBinding(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		paused  = false
+		pauses  int
+		nextID  = 2
+		devices = map[string]map[string]string{
+			"*1": {"name": "manual", "mac-address": "AA:BB:CC:DD:EE:02", "user": "adults", "comment": "", "dynamic": "false"},
+		}
+	)
+
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd {
+		case "/ip/kid-control/print":
+			return mockapi.Reply(
+				map[string]string{".id": "*A", "name": "kids", "paused": fmt.Sprint(paused)},
+				map[string]string{".id": "*B", "name": "adults", "paused": "false"},
+			), nil
+		case "/ip/kid-control/pause", "/ip/kid-control/resume":
+			if params["numbers"] != "*A" {
+				return nil, fmt.Errorf("unexpected profile %q", params["numbers"])
+			}
+			pauses++
+			paused = cmd == "/ip/kid-control/pause"
+			return mockapi.Reply(), nil
+		case "/ip/kid-control/device/print":
+			rows := make([]map[string]string, 0, len(devices))
+			for id, device := range devices {
+				row := map[string]string{".id": id}
+				for key, value := range device {
+					row[key] = value
+				}
+				rows = append(rows, row)
+			}
+			return mockapi.Reply(rows...), nil
+		case "/ip/kid-control/device/add":
+			devices[fmt.Sprintf("*%d", nextID)] = params
+			nextID++
+			return mockapi.Reply(), nil
+		case "/ip/kid-control/device/set":
+			devices[params[".id"]]["user"] = params["user"]
+			return mockapi.Reply(), nil
+		case "/ip/kid-control/device/remove":
+			delete(devices, params[".id"])
+			return mockapi.Reply(), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := client.SetKidControlPaused(ctx, "kids", true); err != nil {
+			t.Fatalf("SetKidControlPaused call %d failed: %v", i, err)
+		}
+	}
+	if pauses != 1 {
+		t.Fatalf("expected one pause command, got %d", pauses)
+	}
+	if got, err := client.GetKidControlPaused(ctx, "kids"); err != nil || !got {
+		t.Fatalf("expected kids paused, got %v err=%v", got, err)
+	}
+	if err := client.SetKidControlPaused(ctx, "missing", true); err == nil {
+		t.Fatalf("expected error for missing profile")
+	}
+
+	const comment = "mikrotik-presence:kids/aa:bb:cc:dd:ee:01"
+	if err := client.BindKidControlDevice(ctx, "kids", "aa-bb-cc-dd-ee-01", "Tablet", comment); err != nil {
+		t.Fatalf("BindKidControlDevice failed: %v", err)
+	}
+	if profile, err := client.KidControlProfileForMAC(ctx, "AA:BB:CC:DD:EE:01"); err != nil || profile != "kids" {
+		t.Fatalf("expected kids binding, got %q err=%v", profile, err)
+	}
+	if err := client.BindKidControlDevice(ctx, "kids", "AA:BB:CC:DD:EE:02", "", comment); err == nil {
+		t.Fatalf("expected manual binding to be preserved")
+	}
+
+	if err := client.UnbindKidControlDevice(ctx, "AA:BB:CC:DD:EE:02", comment); err != nil {
+		t.Fatalf("UnbindKidControlDevice foreign failed: %v", err)
+	}
+	if err := client.UnbindKidControlDevice(ctx, "AA:BB:CC:DD:EE:01", comment); err != nil {
+		t.Fatalf("UnbindKidControlDevice failed: %v", err)
+	}
+	if len(devices) != 1 || devices["*1"] == nil {
+		t.Fatalf("expected only manual binding left, got %+v", devices)
+	}
+}
@@ -9,8 +9,18 @@ import (
 	"github.com/go-routeros/routeros/v3/proto"
 )
 
+// mapReplyRows maps !re sentences; for commands without rows (add) the
+// !done "ret" value, i.e. created .id, is returned as single {"ret": id} row.
 func mapReplyRows(reply *goros.Reply) []map[string]string {
-	if reply == nil || len(reply.Re) == 0 {
+	if reply == nil {
+		return []map[string]string{}
+	}
+	if len(reply.Re) == 0 {
+		if reply.Done != nil {
+			if ret := strings.TrimSpace(reply.Done.Map["ret"]); ret != "" {
+				return []map[string]string{{"ret": ret}}
+			}
+		}
 		return []map[string]string{}
 	}
 	rows := make([]map[string]string, 0, len(reply.Re))
@@ -65,6 +75,25 @@ func mapParams(params map[string]string) []string {
 	return words
 }
 
+// distinctSorted collects unique non-empty values of key across rows.
+func distinctSorted(rows []map[string]string, key string) []string {
+	seen := make(map[string]struct{}, len(rows))
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		value := strings.TrimSpace(row[key])
+		if value == "" {
+			continue
+		}
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		values = append(values, value)
+	}
+	sortStrings(values)
+	return values
+}
+
 func sortStrings(values []string) {
 	if len(values) < 2 {
 		return
@@ -87,6 +116,15 @@ func boolFromWord(value string) bool {
 	}
 }
 
+// counterFromWord parses RouterOS counters; missing or malformed values read as zero.
+func counterFromWord(value string) int64 {
+	parsed, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return parsed
+}
+
 func boolToWord(value bool) string {
 	if value {
 		return "yes"
@@ -10,12 +10,14 @@ import (
 )
 
 type DHCPLease struct {
+	ID       string
 	MAC      string
 	Address  string
 	HostName string
 	Server   string
 	Status   string
 	LastSeen string
+	Comment  string
 	Dynamic  bool
 	Blocked  bool
 	Disabled bool
@@ -117,19 +119,21 @@ func (m *Manager) FetchSnapshot(ctx context.Context, cfg model.RouterConfig) (*S
 	return client.FetchSnapshot(ctx)
 }
 
-func (c *Client) fetchWiFiRows(ctx context.Context) ([]wifiRow, error) {
-	targets := []struct {
-		Path   string
-		Driver string
-	}{
-		{Path: "/interface/wifi/registration-table/print", Driver: "wifi"},
-		{Path: "/interface/wifiwave2/registration-table/print", Driver: "wifiwave2"},
-		{Path: "/interface/wireless/registration-table/print", Driver: "wireless"},
-	}
+// wifiDrivers lists RouterOS wifi menus in probe order; only menus of
+// installed packages exist on a given router.
+var wifiDrivers = []struct {
+	Driver string
+	Menu   string
+}{
+	{Driver: "wifi", Menu: "/interface/wifi"},
+	{Driver: "wifiwave2", Menu: "/interface/wifiwave2"},
+	{Driver: "wireless", Menu: "/interface/wireless"},
+}
 
+func (c *Client) fetchWiFiRows(ctx context.Context) ([]wifiRow, error) {
 	rows := make([]wifiRow, 0)
-	for _, target := range targets {
-		current, err := c.RunCommand(ctx, target.Path, map[string]string{
+	for _, target := range wifiDrivers {
+		current, err := c.RunCommand(ctx, target.Menu+"/registration-table/print", map[string]string{
 			".proplist": "mac-address,interface,ssid,uptime,last-activity,signal,tx-signal,auth-type,authentication-types,band",
 		})
 		if err != nil {
@@ -159,12 +163,14 @@ func mapDHCPRows(rows []map[string]string) []DHCPLease {
 			continue
 		}
 		items = append(items, DHCPLease{
+			ID:       strings.TrimSpace(row[".id"]),
 			MAC:      mac,
 			Address:  strings.TrimSpace(row["address"]),
 			HostName: strings.TrimSpace(row["host-name"]),
 			Server:   strings.TrimSpace(row["server"]),
 			Status:   strings.TrimSpace(row["status"]),
 			LastSeen: strings.TrimSpace(row["last-seen"]),
+			Comment:  strings.TrimSpace(row["comment"]),
 			Dynamic:  boolFromWord(row["dynamic"]),
 			Blocked:  boolFromWord(row["blocked"]),
 			Disabled: boolFromWord(row["disabled"]),
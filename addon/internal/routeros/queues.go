@@ -0,0 +1,439 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+const simpleQueueProplist = ".id,name,target,max-limit,burst-limit,burst-threshold,burst-time,comment,disabled,dynamic"
+
+// SimpleQueue is one /queue/simple row.
+type SimpleQueue struct {
+	ID       string
+	Name     string
+	Target   string
+	Limit    model.QueueLimit
+	Comment  string
+	Disabled bool
+	Dynamic  bool
+}
+
+// Limited reports whether queue is enabled and caps at least one direction.
+func (q SimpleQueue) Limited() bool {
+	if q.Disabled {
+		return false
+	}
+	for _, rate := range strings.Split(q.Limit.MaxLimit, "/") {
+		if rate = strings.TrimSpace(rate); rate != "" && rate != "0" {
+			return true
+		}
+	}
+	return false
+}
+
+// ListSimpleQueueNames returns /queue/simple names in sorted order.
+func (c *Client) ListSimpleQueueNames(ctx context.Context) ([]string, error) {
+	rows, err := c.RunCommand(ctx, "/queue/simple/print", map[string]string{
+		".proplist": "name",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list simple queues: %w", err)
+	}
+	return distinctSorted(rows, "name"), nil
+}
+
+// ListSimpleQueueNames lists simple queue names on pooled client selected by cfg.
+func (m *Manager) ListSimpleQueueNames(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListSimpleQueueNames(ctx)
+}
+
+// ListSimpleQueues returns all simple queues.
+func (c *Client) ListSimpleQueues(ctx context.Context) ([]SimpleQueue, error) {
+	rows, err := c.RunCommand(ctx, "/queue/simple/print", map[string]string{
+		".proplist": simpleQueueProplist,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list simple queues: %w", err)
+	}
+	queues := make([]SimpleQueue, 0, len(rows))
+	for _, row := range rows {
+		if queue := mapSimpleQueue(row); queue.ID != "" {
+			queues = append(queues, queue)
+		}
+	}
+	return queues, nil
+}
+
+// FindSimpleQueuesByTarget returns queues whose target list contains address.
+func (c *Client) FindSimpleQueuesByTarget(ctx context.Context, target string) ([]SimpleQueue, error) {
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return nil, &ValidationError{Field: "target", Reason: "is required"}
+	}
+	queues, err := c.ListSimpleQueues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]SimpleQueue, 0, 1)
+	for _, queue := range queues {
+		if queueTargets(queue, target) {
+			matched = append(matched, queue)
+		}
+	}
+	return matched, nil
+}
+
+// AddSimpleQueue creates simple queue, above placeBefore when set, and
+// returns its .id when router reports it.
+func (c *Client) AddSimpleQueue(ctx context.Context, queue SimpleQueue, placeBefore string) (string, error) {
+	queue.Name = strings.TrimSpace(queue.Name)
+	queue.Target = strings.TrimSpace(queue.Target)
+	if queue.Name == "" {
+		return "", &ValidationError{Field: "name", Reason: "is required"}
+	}
+	if queue.Target == "" {
+		return "", &ValidationError{Field: "target", Reason: "is required"}
+	}
+
+	params := queueLimitParams(queue.Limit)
+	params["name"] = queue.Name
+	params["target"] = queue.Target
+	if comment := strings.TrimSpace(queue.Comment); comment != "" {
+		params["comment"] = comment
+	}
+	if placeBefore = strings.TrimSpace(placeBefore); placeBefore != "" {
+		params["place-before"] = placeBefore
+	}
+	rows, err := c.RunCommand(ctx, "/queue/simple/add", params)
+	if err != nil {
+		return "", fmt.Errorf("add simple queue %q: %w", queue.Name, err)
+	}
+	if len(rows) == 1 {
+		return strings.TrimSpace(rows[0]["ret"]), nil
+	}
+	return "", nil
+}
+
+// SetSimpleQueueMaxLimit updates rates of one simple queue.
+func (c *Client) SetSimpleQueueMaxLimit(ctx context.Context, id string, limit model.QueueLimit) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return &ValidationError{Field: "id", Reason: "is required"}
+	}
+	params := queueLimitParams(limit)
+	params[".id"] = id
+	if _, err := c.RunCommand(ctx, "/queue/simple/set", params); err != nil {
+		return fmt.Errorf("set simple queue %s limit: %w", id, err)
+	}
+	return nil
+}
+
+// RemoveSimpleQueue removes simple queue by .id (idempotent).
+func (c *Client) RemoveSimpleQueue(ctx context.Context, id string) error {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return &ValidationError{Field: "id", Reason: "is required"}
+	}
+	_, err := c.RunCommand(ctx, "/queue/simple/remove", map[string]string{".id": id})
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("remove simple queue %s: %w", id, err)
+	}
+	return nil
+}
+
+// EnsureSimpleQueueLimit limits target through queue tagged with comment,
+// updating owned queue when present and adding one otherwise. Queues without
+// the comment are never touched.
+func (c *Client) EnsureSimpleQueueLimit(
+	ctx context.Context,
+	target string,
+	comment string,
+	limit model.QueueLimit,
+) error {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return &ValidationError{Field: "comment", Reason: "is required"}
+	}
+	if strings.TrimSpace(limit.MaxLimit) == "" {
+		return &ValidationError{Field: "max_limit", Reason: "is required"}
+	}
+	if err := validateQueueBurst(limit); err != nil {
+		return err
+	}
+	target = strings.TrimSpace(target)
+	if target == "" {
+		return &ValidationError{Field: "target", Reason: "is required"}
+	}
+
+	c.queues.Lock()
+	defer c.queues.Unlock()
+
+	queues, err := c.ListSimpleQueues(ctx)
+	if err != nil {
+		return err
+	}
+	// Simple queues match first-wins, so new limit goes above the first
+	// queue the add-on does not own.
+	placeBefore := ""
+	for _, queue := range queues {
+		if queue.Comment != comment {
+			if placeBefore == "" && !queue.Dynamic {
+				placeBefore = queue.ID
+			}
+			continue
+		}
+		if !queueTargets(queue, target) {
+			continue
+		}
+		if queue.Limit == normalizeQueueLimit(limit) {
+			return nil
+		}
+		return c.SetSimpleQueueMaxLimit(ctx, queue.ID, limit)
+	}
+
+	_, err = c.AddSimpleQueue(ctx, SimpleQueue{
+		Name:    simpleQueueName(comment, target),
+		Target:  target,
+		Limit:   limit,
+		Comment: comment,
+	}, placeBefore)
+	if err != nil && isAlreadyExistsError(err) {
+		return nil
+	}
+	return err
+}
+
+// RemoveOwnedSimpleQueues removes queues of target tagged with comment.
+func (c *Client) RemoveOwnedSimpleQueues(ctx context.Context, target string, comment string) error {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return &ValidationError{Field: "comment", Reason: "is required"}
+	}
+
+	c.queues.Lock()
+	defer c.queues.Unlock()
+
+	queues, err := c.FindSimpleQueuesByTarget(ctx, target)
+	if err != nil {
+		return err
+	}
+	for _, queue := range queues {
+		if queue.Comment != comment {
+			continue
+		}
+		if err := c.RemoveSimpleQueue(ctx, queue.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListLimitedQueueTargets returns normalized addresses covered by enabled queues with a max-limit.
+func (c *Client) ListLimitedQueueTargets(ctx context.Context) ([]string, error) {
+	queues, err := c.ListSimpleQueues(ctx)
+	if err != nil {
+		return nil, err
+	}
+	targets := make([]string, 0, len(queues))
+	for _, queue := range queues {
+		if !queue.Limited() {
+			continue
+		}
+		for _, target := range strings.Split(queue.Target, ",") {
+			if target = normalizeAddressTarget(target); target != "" {
+				targets = append(targets, target)
+			}
+		}
+	}
+	return targets, nil
+}
+
+// SetSimpleQueueLimit ensures owned queue limit on pooled client selected by cfg.
+func (m *Manager) SetSimpleQueueLimit(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	target string,
+	comment string,
+	limit model.QueueLimit,
+) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.EnsureSimpleQueueLimit(ctx, target, comment, limit)
+}
+
+// RemoveSimpleQueueLimit removes owned queues on pooled client selected by cfg.
+func (m *Manager) RemoveSimpleQueueLimit(ctx context.Context, cfg model.RouterConfig, target string, comment string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.RemoveOwnedSimpleQueues(ctx, target, comment)
+}
+
+// ListLimitedQueueTargets lists rate-limited addresses on pooled client selected by cfg.
+func (m *Manager) ListLimitedQueueTargets(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListLimitedQueueTargets(ctx)
+}
+
+func mapSimpleQueue(row map[string]string) SimpleQueue {
+	return SimpleQueue{
+		ID:     strings.TrimSpace(row[".id"]),
+		Name:   strings.TrimSpace(row["name"]),
+		Target: strings.TrimSpace(row["target"]),
+		Limit: normalizeQueueLimit(model.QueueLimit{
+			MaxLimit:       row["max-limit"],
+			BurstLimit:     row["burst-limit"],
+			BurstThreshold: row["burst-threshold"],
+			BurstTime:      row["burst-time"],
+		}),
+		Comment:  strings.TrimSpace(row["comment"]),
+		Disabled: boolFromWord(row["disabled"]),
+		Dynamic:  boolFromWord(row["dynamic"]),
+	}
+}
+
+func queueLimitParams(limit model.QueueLimit) map[string]string {
+	limit = normalizeQueueLimit(limit)
+	params := map[string]string{"max-limit": limit.MaxLimit}
+	// Burst settings are reset to 0/0 when unset so a previous burst does not linger.
+	params["burst-limit"] = firstNonEmpty(limit.BurstLimit, "0/0")
+	params["burst-threshold"] = firstNonEmpty(limit.BurstThreshold, "0/0")
+	params["burst-time"] = firstNonEmpty(limit.BurstTime, "0s/0s")
+	return params
+}
+
+// normalizeQueueLimit converts rates to plain bits per second, as printed by
+// RouterOS, and maps zero defaults to empty so limits compare reliably.
+func normalizeQueueLimit(limit model.QueueLimit) model.QueueLimit {
+	clean := func(value string, zero string) string {
+		value = strings.TrimSpace(value)
+		if value == zero {
+			return ""
+		}
+		return value
+	}
+	return model.QueueLimit{
+		MaxLimit:       clean(canonicalRatePair(limit.MaxLimit), "0/0"),
+		BurstLimit:     clean(canonicalRatePair(limit.BurstLimit), "0/0"),
+		BurstThreshold: clean(canonicalRatePair(limit.BurstThreshold), "0/0"),
+		BurstTime:      clean(limit.BurstTime, "0s/0s"),
+	}
+}
+
+// canonicalRatePair rewrites "2M/10M" into "2000000/10000000"; values that
+// do not parse are kept as-is.
+func canonicalRatePair(value string) string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return ""
+	}
+	parts := strings.Split(value, "/")
+	for i, part := range parts {
+		parts[i] = canonicalRate(part)
+	}
+	return strings.Join(parts, "/")
+}
+
+func canonicalRate(value string) string {
+	value = strings.TrimSpace(value)
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(value, "k"):
+		multiplier = 1e3
+	case strings.HasSuffix(value, "M"):
+		multiplier = 1e6
+	case strings.HasSuffix(value, "G"):
+		multiplier = 1e9
+	}
+	number := value
+	if multiplier != 1 {
+		number = value[:len(value)-1]
+	}
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil || parsed < 0 {
+		return value
+	}
+	return strconv.FormatInt(int64(math.Round(parsed*multiplier)), 10)
+}
+
+// validateQueueBurst checks each direction with a burst: RouterOS only
+// bursts when burst-limit exceeds max-limit and threshold stays below it.
+func validateQueueBurst(limit model.QueueLimit) error {
+	limit = normalizeQueueLimit(limit)
+	if limit.BurstLimit == "" {
+		return nil
+	}
+	maxRates, err := ratePair("max_limit", limit.MaxLimit)
+	if err != nil {
+		return err
+	}
+	burstRates, err := ratePair("burst_limit", limit.BurstLimit)
+	if err != nil {
+		return err
+	}
+	thresholdRates, err := ratePair("burst_threshold", limit.BurstThreshold)
+	if err != nil {
+		return err
+	}
+	for i, direction := range []string{"upload", "download"} {
+		if burstRates[i] == 0 {
+			continue
+		}
+		if burstRates[i] <= maxRates[i] {
+			return &ValidationError{Field: "burst_limit", Reason: direction + " must exceed max-limit"}
+		}
+		if thresholdRates[i] >= maxRates[i] {
+			return &ValidationError{Field: "burst_threshold", Reason: direction + " must be below max-limit"}
+		}
+	}
+	return nil
+}
+
+// ratePair parses "upload/download" rates in bits per second; empty is 0/0.
+func ratePair(field string, value string) ([2]int64, error) {
+	var rates [2]int64
+	if value == "" {
+		return rates, nil
+	}
+	parts := strings.Split(canonicalRatePair(value), "/")
+	if len(parts) != 2 {
+		return rates, &ValidationError{Field: field, Reason: "must be upload/download pair"}
+	}
+	for i, part := range parts {
+		rate, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return rates, &ValidationError{Field: field, Reason: fmt.Sprintf("invalid rate %q", part)}
+		}
+		rates[i] = rate
+	}
+	return rates, nil
+}
+
+func queueTargets(queue SimpleQueue, address string) bool {
+	for _, target := range strings.Split(queue.Target, ",") {
+		if equalAddressTarget(target, address) {
+			return true
+		}
+	}
+	return false
+}
+
+// simpleQueueName derives unique queue name from ownership comment and target.
+func simpleQueueName(comment string, target string) string {
+	return comment + "@" + normalizeAddressTarget(target)
+}
@@ -0,0 +1,198 @@
+package routeros
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	goros "github.com/go-routeros/routeros/v3"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+	mockapi "github.com/micro-ha/mikrotik-presence/addon/internal/routeros/mock"
+)
+
+func TestEnsureSimpleQueueLimitOwnsQueueByComment(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		nextID = 2
+		queues = map[string]map[string]string{
+			// Foreign queue on same target must never be touched.
+			"*1": {"name": "manual", "target": "192.168.88.10/32", "max-limit": "1000000/1000000", "comment": "manual"},
+		}
+	)
+
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd {
+		case "/queue/simple/print":
+			rows := make([]map[string]string, 0, len(queues))
+			for id, row := range queues {
+				out := map[string]string{".id": id, "disabled": "false"}
+				for key, value := range row {
+					out[key] = value
+				}
+				rows = append(rows, out)
+			}
+			return mockapi.Reply(rows...), nil
+		case "/queue/simple/add":
+			id := fmt.Sprintf("*%d", nextID)
+			nextID++
+			queues[id] = map[string]string{
+				"name":      params["name"],
+				"target":    params["target"] + "/32",
+				"max-limit": canonicalRatePair(params["max-limit"]),
+				"comment":   params["comment"],
+			}
+			return mockapi.Reply(map[string]string{"ret": id}), nil
+		case "/queue/simple/set":
+			queues[params[".id"]]["max-limit"] = canonicalRatePair(params["max-limit"])
+			return mockapi.Reply(), nil
+		case "/queue/simple/remove":
+			if _, ok := queues[params[".id"]]; !ok {
+				return nil, fmt.Errorf("no such item")
+			}
+			delete(queues, params[".id"])
+			return mockapi.Reply(), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	ctx := context.Background()
+	const comment = "mikrotik-presence:kids/AA:BB:CC:DD:EE:01"
+	limit := model.QueueLimit{MaxLimit: "2M/10M"}
+	for i := 0; i < 2; i++ {
+		if err := client.EnsureSimpleQueueLimit(ctx, "192.168.88.10", comment, limit); err != nil {
+			t.Fatalf("EnsureSimpleQueueLimit call %d failed: %v", i, err)
+		}
+	}
+	if err := client.EnsureSimpleQueueLimit(ctx, "192.168.88.10", comment, model.QueueLimit{MaxLimit: "1M/5M"}); err != nil {
+		t.Fatalf("EnsureSimpleQueueLimit update failed: %v", err)
+	}
+
+	counts := map[string]int{}
+	for _, call := range api.CallsSnapshot() {
+		counts[call.Cmd]++
+	}
+	if counts["/queue/simple/add"] != 1 || counts["/queue/simple/set"] != 1 {
+		t.Fatalf("expected one add and one set, got %+v", counts)
+	}
+
+	targets, err := client.ListLimitedQueueTargets(ctx)
+	if err != nil {
+		t.Fatalf("ListLimitedQueueTargets failed: %v", err)
+	}
+	if strings.Join(targets, ",") != "192.168.88.10,192.168.88.10" {
+		t.Fatalf("unexpected limited targets: %v", targets)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := client.RemoveOwnedSimpleQueues(ctx, "192.168.88.10", comment); err != nil {
+			t.Fatalf("RemoveOwnedSimpleQueues call %d failed: %v", i, err)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(queues) != 1 || queues["*1"] == nil {
+		t.Fatalf("expected only foreign queue to remain, got %+v", queues)
+	}
+	if queues["*1"]["max-limit"] != "1000000/1000000" {
+		t.Fatalf("foreign queue was modified: %+v", queues["*1"])
+	}
+}
+
+func TestSimpleQueueLimitedIgnoresDisabledAndUnlimited(t *testing.T) {
+	cases := []struct {
+		queue SimpleQueue
+		want  bool
+	}{
+		{SimpleQueue{Limit: model.QueueLimit{MaxLimit: "0/10000000"}}, true},
+		{SimpleQueue{Limit: normalizeQueueLimit(model.QueueLimit{MaxLimit: "0/0"})}, false},
+		{SimpleQueue{Limit: model.QueueLimit{MaxLimit: "1000000/1000000"}, Disabled: true}, false},
+	}
+	for i, tc := range cases {
+		if got := tc.queue.Limited(); got != tc.want {
+			t.Fatalf("case %d: Limited() = %v, want %v", i, got, tc.want)
+		}
+	}
+	if got := canonicalRatePair("1.5M/512k"); got != "1500000/512000" {
+		t.Fatalf("unexpected canonical rate %q", got)
+	}
+}
+
+func TestEnsureSimpleQueueLimitPlacesAboveForeignQueues(t *testing.T) {
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		switch cmd {
+		case "/queue/simple/print":
+			return mockapi.Reply(
+				map[string]string{".id": "*1", "name": "dyn", "target": "192.168.88.5/32", "dynamic": "true"},
+				map[string]string{".id": "*2", "name": "total", "target": "192.168.88.0/24", "max-limit": "50000000/50000000"},
+				map[string]string{".id": "*3", "name": "guest", "target": "192.168.88.20/32", "max-limit": "1000000/1000000"},
+			), nil
+		case "/queue/simple/add":
+			return mockapi.Reply(map[string]string{"ret": "*4"}), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	err := client.EnsureSimpleQueueLimit(context.Background(), "192.168.88.10", "mikrotik-presence:kids", model.QueueLimit{MaxLimit: "2M/10M"})
+	if err != nil {
+		t.Fatalf("EnsureSimpleQueueLimit failed: %v", err)
+	}
+	for _, call := range api.CallsSnapshot() {
+		if call.Cmd == "/queue/simple/add" {
+			if got := decodeArgs(call.Args)["place-before"]; got != "*2" {
+				t.Fatalf("expected place-before first static foreign queue, got %q", got)
+			}
+			return
+		}
+	}
+	t.Fatal("expected queue to be added")
+}
+
+func TestEnsureSimpleQueueLimitRejectsInvalidBurst(t *testing.T) {
+	client := &Client{api: &mockapi.Client{}}
+	cases := []struct {
+		limit model.QueueLimit
+		field string
+	}{
+		{model.QueueLimit{MaxLimit: "2M/10M", BurstLimit: "2M/20M", BurstThreshold: "1M/5M", BurstTime: "8s/8s"}, "burst_limit"},
+		{model.QueueLimit{MaxLimit: "2M/10M", BurstLimit: "4M/20M", BurstThreshold: "1M/10M", BurstTime: "8s/8s"}, "burst_threshold"},
+	}
+	for i, tc := range cases {
+		err := client.EnsureSimpleQueueLimit(context.Background(), "192.168.88.10", "mikrotik-presence:kids", tc.limit)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+			t.Fatalf("case %d: expected %s validation error, got %v", i, tc.field, err)
+		}
+	}
+	if err := validateQueueBurst(model.QueueLimit{MaxLimit: "2M/10M", BurstLimit: "4M/0", BurstThreshold: "1M/0"}); err != nil {
+		t.Fatalf("expected upload-only burst to pass, got %v", err)
+	}
+}
@@ -0,0 +1,354 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+// WiFiAccessRule is one access-list entry of wifi, wifiwave2 or wireless
+// driver. Action is normalized to model.WiFiAccessDeny/Allow; other values
+// (e.g. query-radius) are kept as reported.
+type WiFiAccessRule struct {
+	ID        string
+	Driver    string
+	MAC       string
+	Interface string
+	Action    string
+	VLANID    string
+	Time      string
+	Comment   string
+	Disabled  bool
+}
+
+// DisconnectWiFiClient removes MAC from registration tables of every wifi
+// driver and returns number of dropped registrations. The client may
+// reconnect unless an access-list entry denies it.
+func (c *Client) DisconnectWiFiClient(ctx context.Context, mac string) (int, error) {
+	mac, err := validWiFiMAC(mac)
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, driver := range wifiDrivers {
+		rows, err := c.RunCommand(ctx, driver.Menu+"/registration-table/print", map[string]string{
+			".proplist":    ".id,mac-address",
+			"?mac-address": mac,
+		})
+		if err != nil {
+			if isMissingCommandError(err) {
+				continue
+			}
+			return removed, fmt.Errorf("find wifi registration %s (%s): %w", mac, driver.Driver, err)
+		}
+		for _, row := range rows {
+			id := strings.TrimSpace(row[".id"])
+			if id == "" || canonicalMAC(row["mac-address"]) != mac {
+				continue
+			}
+			_, err := c.RunCommand(ctx, driver.Menu+"/registration-table/remove", map[string]string{".id": id})
+			if err != nil {
+				if isNotFoundError(err) {
+					continue
+				}
+				return removed, fmt.Errorf("disconnect wifi client %s (%s): %w", mac, driver.Driver, err)
+			}
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// EnsureWiFiAccessRule keeps entry for MAC tagged with comment in effect on
+// every installed wifi driver. Missing, outdated or shadowed entries are
+// re-added at the top of access list; entries with other comments are left
+// untouched.
+func (c *Client) EnsureWiFiAccessRule(
+	ctx context.Context,
+	mac string,
+	policy model.WiFiAccessPolicy,
+	comment string,
+) error {
+	mac, err := validWiFiMAC(mac)
+	if err != nil {
+		return err
+	}
+	policy, err = normalizeWiFiAccessPolicy(policy)
+	if err != nil {
+		return err
+	}
+	comment = strings.TrimSpace(comment)
+
+	c.wifiAccess.Lock()
+	defer c.wifiAccess.Unlock()
+
+	installed := 0
+	for _, driver := range wifiDrivers {
+		rules, ok, err := c.listWiFiAccessRules(ctx, driver.Driver, driver.Menu)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		installed++
+
+		// Entry is in effect when no other enabled entry for MAC precedes it.
+		current, shadowed := -1, false
+		for i := range rules {
+			if rules[i].MAC != mac {
+				continue
+			}
+			if rules[i].Comment == comment {
+				current = i
+				break
+			}
+			shadowed = shadowed || !rules[i].Disabled
+		}
+		if current >= 0 && !shadowed && wifiAccessRuleMatches(rules[current], policy) {
+			continue
+		}
+		if current >= 0 {
+			_, err := c.RunCommand(ctx, driver.Menu+"/access-list/remove", map[string]string{".id": rules[current].ID})
+			if err != nil && !isNotFoundError(err) {
+				return fmt.Errorf("replace wifi access rule %s (%s): %w", mac, driver.Driver, err)
+			}
+			rules = append(rules[:current], rules[current+1:]...)
+		}
+
+		params := wifiAccessParams(driver.Driver, policy)
+		params["mac-address"] = mac
+		if comment != "" {
+			params["comment"] = comment
+		}
+		if len(rules) > 0 {
+			params["place-before"] = rules[0].ID
+		}
+		if _, err := c.RunCommand(ctx, driver.Menu+"/access-list/add", params); err != nil {
+			return fmt.Errorf("add wifi access rule %s (%s): %w", mac, driver.Driver, err)
+		}
+	}
+	if installed == 0 {
+		return fmt.Errorf("router has no wifi driver with access list")
+	}
+	return nil
+}
+
+// RemoveWiFiAccessRule removes entries for MAC tagged with comment from all
+// installed wifi drivers.
+func (c *Client) RemoveWiFiAccessRule(ctx context.Context, mac, comment string) error {
+	mac, err := validWiFiMAC(mac)
+	if err != nil {
+		return err
+	}
+	comment = strings.TrimSpace(comment)
+
+	c.wifiAccess.Lock()
+	defer c.wifiAccess.Unlock()
+
+	for _, driver := range wifiDrivers {
+		rules, _, err := c.listWiFiAccessRules(ctx, driver.Driver, driver.Menu)
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			if rule.MAC != mac || rule.Comment != comment {
+				continue
+			}
+			_, err := c.RunCommand(ctx, driver.Menu+"/access-list/remove", map[string]string{".id": rule.ID})
+			if err != nil && !isNotFoundError(err) {
+				return fmt.Errorf("remove wifi access rule %s (%s): %w", mac, driver.Driver, err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetWiFiAccessDenied reports whether first enabled access-list entry for MAC
+// denies it on any installed wifi driver. Time windows are not evaluated.
+func (c *Client) GetWiFiAccessDenied(ctx context.Context, mac string) (bool, error) {
+	mac, err := validWiFiMAC(mac)
+	if err != nil {
+		return false, err
+	}
+	for _, driver := range wifiDrivers {
+		rules, _, err := c.listWiFiAccessRules(ctx, driver.Driver, driver.Menu)
+		if err != nil {
+			return false, err
+		}
+		for _, rule := range rules {
+			if rule.Disabled || rule.MAC != mac {
+				continue
+			}
+			if rule.Action == model.WiFiAccessDeny {
+				return true, nil
+			}
+			break
+		}
+	}
+	return false, nil
+}
+
+// DisconnectWiFiClient drops MAC registrations on pooled client selected by cfg.
+func (m *Manager) DisconnectWiFiClient(ctx context.Context, cfg model.RouterConfig, mac string) (int, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return 0, err
+	}
+	return client.DisconnectWiFiClient(ctx, mac)
+}
+
+// EnsureWiFiAccessRule places owned access-list entry on pooled client selected by cfg.
+func (m *Manager) EnsureWiFiAccessRule(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	mac string,
+	policy model.WiFiAccessPolicy,
+	comment string,
+) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.EnsureWiFiAccessRule(ctx, mac, policy, comment)
+}
+
+// RemoveWiFiAccessRule removes owned access-list entry on pooled client selected by cfg.
+func (m *Manager) RemoveWiFiAccessRule(ctx context.Context, cfg model.RouterConfig, mac, comment string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.RemoveWiFiAccessRule(ctx, mac, comment)
+}
+
+// GetWiFiAccessDenied reads MAC access-list state on pooled client selected by cfg.
+func (m *Manager) GetWiFiAccessDenied(ctx context.Context, cfg model.RouterConfig, mac string) (bool, error) {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return false, err
+	}
+	return client.GetWiFiAccessDenied(ctx, mac)
+}
+
+// listWiFiAccessRules reads access list of one driver; ok is false when the
+// driver package is not installed.
+func (c *Client) listWiFiAccessRules(ctx context.Context, driver, menu string) ([]WiFiAccessRule, bool, error) {
+	rows, err := c.RunCommand(ctx, menu+"/access-list/print", map[string]string{
+		".proplist": ".id,mac-address,interface,action,authentication,vlan-id,time,comment,disabled",
+	})
+	if err != nil {
+		if isMissingCommandError(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("list wifi access rules (%s): %w", driver, err)
+	}
+	rules := make([]WiFiAccessRule, 0, len(rows))
+	for _, row := range rows {
+		id := strings.TrimSpace(row[".id"])
+		if id == "" {
+			continue
+		}
+		rules = append(rules, WiFiAccessRule{
+			ID:        id,
+			Driver:    driver,
+			MAC:       canonicalMAC(row["mac-address"]),
+			Interface: strings.TrimSpace(row["interface"]),
+			Action:    wifiAccessAction(driver, row),
+			VLANID:    strings.TrimSpace(row["vlan-id"]),
+			Time:      strings.TrimSpace(row["time"]),
+			Comment:   strings.TrimSpace(row["comment"]),
+			Disabled:  boolFromWord(row["disabled"]),
+		})
+	}
+	return rules, true, nil
+}
+
+// wifiAccessAction maps driver fields to deny/allow: wifi and wifiwave2 use
+// action=reject|accept, legacy wireless uses authentication=no|yes.
+func wifiAccessAction(driver string, row map[string]string) string {
+	if driver == "wireless" {
+		if authentication := strings.TrimSpace(row["authentication"]); authentication != "" && !boolFromWord(authentication) {
+			return model.WiFiAccessDeny
+		}
+		return model.WiFiAccessAllow
+	}
+	switch action := strings.TrimSpace(row["action"]); action {
+	case "reject":
+		return model.WiFiAccessDeny
+	case "accept", "":
+		return model.WiFiAccessAllow
+	default:
+		return action
+	}
+}
+
+func wifiAccessParams(driver string, policy model.WiFiAccessPolicy) map[string]string {
+	params := map[string]string{}
+	deny := policy.Action == model.WiFiAccessDeny
+	if driver == "wireless" {
+		params["authentication"] = boolToWord(!deny)
+		params["forwarding"] = boolToWord(!deny)
+		if policy.VLANID > 0 {
+			params["vlan-mode"] = "use-tag"
+		}
+	} else if deny {
+		params["action"] = "reject"
+	} else {
+		params["action"] = "accept"
+	}
+	if policy.VLANID > 0 {
+		params["vlan-id"] = strconv.Itoa(policy.VLANID)
+	}
+	if policy.Time != "" {
+		params["time"] = policy.Time
+	}
+	return params
+}
+
+// wifiAccessRuleMatches compares entry with policy. RouterOS may expand time
+// windows with weekdays, so reported time only has to start with desired one.
+func wifiAccessRuleMatches(rule WiFiAccessRule, policy model.WiFiAccessPolicy) bool {
+	if rule.Disabled || rule.Action != policy.Action {
+		return false
+	}
+	wantVLAN := ""
+	if policy.VLANID > 0 {
+		wantVLAN = strconv.Itoa(policy.VLANID)
+	}
+	if rule.VLANID != wantVLAN && !(wantVLAN == "" && (rule.VLANID == "1" || rule.VLANID == "none")) {
+		return false
+	}
+	return strings.HasPrefix(rule.Time, policy.Time)
+}
+
+func normalizeWiFiAccessPolicy(policy model.WiFiAccessPolicy) (model.WiFiAccessPolicy, error) {
+	policy.Action = strings.ToLower(strings.TrimSpace(policy.Action))
+	policy.Time = strings.TrimSpace(policy.Time)
+	switch policy.Action {
+	case model.WiFiAccessDeny:
+		if policy.VLANID != 0 {
+			return policy, &ValidationError{Field: "vlan_id", Reason: "is only allowed for allow entries"}
+		}
+	case model.WiFiAccessAllow:
+		if policy.VLANID < 0 || policy.VLANID > 4094 {
+			return policy, &ValidationError{Field: "vlan_id", Reason: "must be between 1 and 4094"}
+		}
+	default:
+		return policy, &ValidationError{Field: "action", Reason: "must be deny or allow"}
+	}
+	return policy, nil
+}
+
+func validWiFiMAC(mac string) (string, error) {
+	mac = canonicalMAC(mac)
+	if _, err := net.ParseMAC(mac); err != nil || mac == "" {
+		return "", &ValidationError{Field: "mac", Reason: "must be a MAC address"}
+	}
+	return mac, nil
+}
@@ -0,0 +1,189 @@
+package routeros
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	goros "github.com/go-routeros/routeros/v3"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+	mockapi "github.com/micro-ha/mikrotik-presence/addon/internal/routeros/mock"
+)
+
+func TestWiFiAccessRulesAcrossDrivers(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		nextID = 10
+		// access holds ordered access lists of installed drivers.
+		access = map[string][]map[string]string{
+			"/interface/wifi": {
+				{".id": "*1", "mac-address": "AA:BB:CC:DD:EE:01", "action": "accept", "comment": "manual"},
+			},
+			"/interface/wireless": {},
+		}
+		registrations = map[string][]map[string]string{
+			"/interface/wifi":     {{".id": "*R1", "mac-address": "AA:BB:CC:DD:EE:01"}},
+			"/interface/wireless": {{".id": "*R2", "mac-address": "AA:BB:CC:DD:EE:02"}},
+		}
+		adds []map[string]string
+	)
+
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		params := decodeArgs(args)
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		menu := cmd[:strings.LastIndex(cmd, "/")]
+		menu = strings.TrimSuffix(strings.TrimSuffix(menu, "/access-list"), "/registration-table")
+		if _, ok := access[menu]; !ok {
+			return nil, errors.New("from RouterOS device: no such command prefix")
+		}
+
+		switch {
+		case strings.HasSuffix(cmd, "/access-list/print"):
+			return mockapi.Reply(access[menu]...), nil
+		case strings.HasSuffix(cmd, "/access-list/add"):
+			adds = append(adds, params)
+			row := map[string]string{".id": fmt.Sprintf("*%d", nextID)}
+			nextID++
+			for key, value := range params {
+				row[key] = value
+			}
+			position := len(access[menu])
+			for i, existing := range access[menu] {
+				if existing[".id"] == params["place-before"] {
+					position = i
+					break
+				}
+			}
+			rows := append([]map[string]string{}, access[menu][:position]...)
+			rows = append(rows, row)
+			access[menu] = append(rows, access[menu][position:]...)
+			return mockapi.Reply(), nil
+		case strings.HasSuffix(cmd, "/access-list/remove"):
+			rows := access[menu][:0]
+			for _, row := range access[menu] {
+				if row[".id"] != params[".id"] {
+					rows = append(rows, row)
+				}
+			}
+			access[menu] = rows
+			return mockapi.Reply(), nil
+		case strings.HasSuffix(cmd, "/registration-table/print"):
+			rows := make([]map[string]string, 0)
+			for _, row := range registrations[menu] {
+				if row["mac-address"] == params["?mac-address"] {
+					rows = append(rows, row)
+				}
+			}
+			return mockapi.Reply(rows...), nil
+		case strings.HasSuffix(cmd, "/registration-table/remove"):
+			registrations[menu] = nil
+			return mockapi.Reply(), nil
+		default:
+			return nil, fmt.Errorf("unexpected command %s", cmd)
+		}
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	ctx := context.Background()
+	deny := model.WiFiAccessPolicy{Action: model.WiFiAccessDeny}
+	if err := client.EnsureWiFiAccessRule(ctx, "aa-bb-cc-dd-ee-01", deny, "owned"); err != nil {
+		t.Fatalf("EnsureWiFiAccessRule() error = %v", err)
+	}
+	if len(adds) != 2 {
+		t.Fatalf("expected entry on both installed drivers, got %+v", adds)
+	}
+	if first := access["/interface/wifi"][0]; first["action"] != "reject" || first["comment"] != "owned" {
+		t.Fatalf("expected owned reject entry ahead of manual one, got %+v", access["/interface/wifi"])
+	}
+	if legacy := access["/interface/wireless"][0]; legacy["authentication"] != "no" || legacy["forwarding"] != "no" {
+		t.Fatalf("expected legacy deny via authentication=no, got %+v", legacy)
+	}
+
+	if err := client.EnsureWiFiAccessRule(ctx, "AA:BB:CC:DD:EE:01", deny, "owned"); err != nil {
+		t.Fatalf("EnsureWiFiAccessRule() second call error = %v", err)
+	}
+	if len(adds) != 2 {
+		t.Fatalf("expected in-effect entry to be kept, got %d adds", len(adds))
+	}
+
+	denied, err := client.GetWiFiAccessDenied(ctx, "AA:BB:CC:DD:EE:01")
+	if err != nil || !denied {
+		t.Fatalf("GetWiFiAccessDenied() = %v, %v; want true", denied, err)
+	}
+
+	allow := model.WiFiAccessPolicy{Action: model.WiFiAccessAllow, VLANID: 20, Time: "7h-22h"}
+	if err := client.EnsureWiFiAccessRule(ctx, "AA:BB:CC:DD:EE:01", allow, "owned"); err != nil {
+		t.Fatalf("EnsureWiFiAccessRule(allow) error = %v", err)
+	}
+	if first := access["/interface/wifi"][0]; first["action"] != "accept" || first["vlan-id"] != "20" || first["time"] != "7h-22h" {
+		t.Fatalf("expected owned entry replaced with allow policy, got %+v", first)
+	}
+	if legacy := access["/interface/wireless"][0]; legacy["vlan-mode"] != "use-tag" || legacy["vlan-id"] != "20" {
+		t.Fatalf("expected legacy entry tagged with vlan, got %+v", legacy)
+	}
+	if denied, _ := client.GetWiFiAccessDenied(ctx, "AA:BB:CC:DD:EE:01"); denied {
+		t.Fatal("expected allow entry to clear denied state")
+	}
+
+	if err := client.RemoveWiFiAccessRule(ctx, "AA:BB:CC:DD:EE:01", "owned"); err != nil {
+		t.Fatalf("RemoveWiFiAccessRule() error = %v", err)
+	}
+	if len(access["/interface/wifi"]) != 1 || access["/interface/wifi"][0]["comment"] != "manual" {
+		t.Fatalf("expected only manual entry to remain, got %+v", access["/interface/wifi"])
+	}
+	if len(access["/interface/wireless"]) != 0 {
+		t.Fatalf("expected owned legacy entry removed, got %+v", access["/interface/wireless"])
+	}
+
+	removed, err := client.DisconnectWiFiClient(ctx, "AA:BB:CC:DD:EE:02")
+	if err != nil || removed != 1 {
+		t.Fatalf("DisconnectWiFiClient() = %d, %v; want 1", removed, err)
+	}
+	if len(registrations["/interface/wireless"]) != 0 || len(registrations["/interface/wifi"]) != 1 {
+		t.Fatalf("expected only legacy registration dropped, got %+v", registrations)
+	}
+}
+
+func TestEnsureWiFiAccessRuleValidatesPolicy(t *testing.T) {
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    &mockapi.Client{},
+	}
+
+	cases := []struct {
+		mac    string
+		policy model.WiFiAccessPolicy
+		field  string
+	}{
+		{mac: "nope", policy: model.WiFiAccessPolicy{Action: model.WiFiAccessDeny}, field: "mac"},
+		{mac: "AA:BB:CC:DD:EE:01", policy: model.WiFiAccessPolicy{Action: "drop"}, field: "action"},
+		{mac: "AA:BB:CC:DD:EE:01", policy: model.WiFiAccessPolicy{Action: model.WiFiAccessDeny, VLANID: 10}, field: "vlan_id"},
+		{mac: "AA:BB:CC:DD:EE:01", policy: model.WiFiAccessPolicy{Action: model.WiFiAccessAllow, VLANID: 5000}, field: "vlan_id"},
+	}
+	for _, tc := range cases {
+		err := client.EnsureWiFiAccessRule(context.Background(), tc.mac, tc.policy, "owned")
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != tc.field {
+			t.Fatalf("EnsureWiFiAccessRule(%q, %+v) error = %v, want %s validation error", tc.mac, tc.policy, err, tc.field)
+		}
+	}
+}
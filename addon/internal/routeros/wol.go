@@ -0,0 +1,36 @@
+package routeros
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+// WakeOnLAN sends magic packet for mac through /tool/wol. Empty iface lets
+// RouterOS pick the interface, which only works on single-LAN setups.
+func (c *Client) WakeOnLAN(ctx context.Context, mac string, iface string) error {
+	mac = canonicalMAC(mac)
+	if _, err := net.ParseMAC(mac); err != nil || mac == "" {
+		return &ValidationError{Field: "mac", Reason: "must be a MAC address"}
+	}
+	params := map[string]string{"mac": mac}
+	if iface = strings.TrimSpace(iface); iface != "" {
+		params["interface"] = iface
+	}
+	if _, err := c.RunCommand(ctx, "/tool/wol", params); err != nil {
+		return fmt.Errorf("wake %s: %w", mac, err)
+	}
+	return nil
+}
+
+// WakeOnLAN sends magic packet on pooled client selected by cfg.
+func (m *Manager) WakeOnLAN(ctx context.Context, cfg model.RouterConfig, mac string, iface string) error {
+	client, err := m.getClient(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	return client.WakeOnLAN(ctx, mac, iface)
+}
@@ -0,0 +1,50 @@
+package routeros
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	goros "github.com/go-routeros/routeros/v3"
+	mockapi "github.com/micro-ha/mikrotik-presence/addon/internal/routeros/mock"
+)
+
+func TestWakeOnLANSendsMagicPacket(t *testing.T) {
+	var calls []map[string]string
+	api := &mockapi.Client{}
+	api.RunFunc = func(ctx context.Context, cmd string, args ...string) (*goros.Reply, error) {
+		_ = ctx
+		if cmd != "/tool/wol" {
+			t.Fatalf("unexpected command %s", cmd)
+		}
+		calls = append(calls, decodeArgs(args))
+		return mockapi.Reply(), nil
+	}
+
+	client := &Client{
+		config: Config{Timeout: time.Second},
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		closed: make(chan struct{}),
+		api:    api,
+	}
+
+	ctx := context.Background()
+	if err := client.WakeOnLAN(ctx, "aa-bb-cc-dd-ee-01", " bridge "); err != nil {
+		t.Fatalf("WakeOnLAN failed: %v", err)
+	}
+	if err := client.WakeOnLAN(ctx, "AA:BB:CC:DD:EE:01", ""); err != nil {
+		t.Fatalf("WakeOnLAN without interface failed: %v", err)
+	}
+	if len(calls) != 2 || calls[0]["mac"] != "AA:BB:CC:DD:EE:01" || calls[0]["interface"] != "bridge" {
+		t.Fatalf("unexpected wol calls: %+v", calls)
+	}
+	if _, ok := calls[1]["interface"]; ok {
+		t.Fatalf("expected interface to be omitted, got %+v", calls[1])
+	}
+
+	if err := client.WakeOnLAN(ctx, "not-a-mac", ""); err == nil {
+		t.Fatalf("expected invalid MAC to be rejected")
+	}
+}
@@ -0,0 +1,349 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/routeros"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/storage"
+)
+
+const artefactGCRouterTimeout = 12 * time.Second
+
+// GCRouterClient describes router writes used to undo orphaned artefacts.
+type GCRouterClient interface {
+	automationdomain.OwnedAddressListClient
+	automationdomain.FirewallRuleClient
+}
+
+type gcTemplateLister interface {
+	ListTemplates(ctx context.Context, search, category string) ([]automationdomain.CapabilityTemplate, error)
+}
+
+type gcDeviceGetter interface {
+	GetDevice(ctx context.Context, mac string) (devicedomain.Device, error)
+}
+
+// artefactReconciler removes router artefacts whose capability template was
+// deleted or whose device is no longer known.
+type artefactReconciler struct {
+	ledger    automationdomain.LedgerRepository
+	templates gcTemplateLister
+	devices   gcDeviceGetter
+	client    GCRouterClient
+	config    RouterConfigProvider
+	logger    *slog.Logger
+
+	// mu serializes passes so scheduled and manual runs do not race.
+	mu sync.Mutex
+}
+
+// WithArtefactGC enables ownership ledger reconciler.
+func (s *Service) WithArtefactGC(
+	ledger automationdomain.LedgerRepository,
+	client GCRouterClient,
+	config RouterConfigProvider,
+) *Service {
+	s.gc = &artefactReconciler{
+		ledger:    ledger,
+		templates: s.repo,
+		devices:   s.devices,
+		client:    client,
+		config:    config,
+		logger:    s.logger,
+	}
+	return s
+}
+
+// ListArtefacts returns router artefacts recorded in ownership ledger.
+func (s *Service) ListArtefacts(ctx context.Context) ([]automationdomain.RouterArtefact, error) {
+	if s.gc == nil {
+		return []automationdomain.RouterArtefact{}, nil
+	}
+	return s.gc.ledger.ListArtefacts(ctx)
+}
+
+// PreviewArtefactGC lists orphaned artefacts without touching the router.
+func (s *Service) PreviewArtefactGC(ctx context.Context) (automationdomain.GCReport, error) {
+	if s.gc == nil {
+		return automationdomain.GCReport{DryRun: true, Items: []automationdomain.GCItem{}}, nil
+	}
+	return s.gc.Reconcile(ctx, true)
+}
+
+// RunArtefactGC removes or restores orphaned artefacts on the router.
+func (s *Service) RunArtefactGC(ctx context.Context) (automationdomain.GCReport, error) {
+	if s.gc == nil {
+		return automationdomain.GCReport{Items: []automationdomain.GCItem{}}, nil
+	}
+	return s.gc.Reconcile(ctx, false)
+}
+
+// RunArtefactGCLoop reconciles on interval until ctx is done; zero interval disables it.
+func (s *Service) RunArtefactGCLoop(ctx context.Context, interval time.Duration) {
+	if s.gc == nil || interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.gc.Reconcile(ctx, false)
+			if err != nil {
+				if s.logger != nil && !errors.Is(err, automationdomain.ErrAddonNotConfigured) {
+					s.logger.Warn("scheduled artefact gc failed", "err", err)
+				}
+				continue
+			}
+			if len(report.Items) > 0 && s.logger != nil {
+				s.logger.Info("scheduled artefact gc finished", "orphaned", len(report.Items), "scanned", report.Scanned)
+			}
+		}
+	}
+}
+
+func (r *artefactReconciler) Reconcile(ctx context.Context, dryRun bool) (automationdomain.GCReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := automationdomain.GCReport{
+		DryRun:    dryRun,
+		Items:     []automationdomain.GCItem{},
+		StartedAt: time.Now().UTC(),
+	}
+	artefacts, err := r.ledger.ListArtefacts(ctx)
+	if err != nil {
+		return report, err
+	}
+	report.Scanned = len(artefacts)
+	// Earliest write holds the original router state to restore.
+	sort.Slice(artefacts, func(i, j int) bool { return artefacts[i].ID < artefacts[j].ID })
+
+	templates, err := r.templates.ListTemplates(ctx, "", "")
+	if err != nil {
+		return report, err
+	}
+	templateIDs := make(map[string]struct{}, len(templates))
+	for _, template := range templates {
+		templateIDs[template.ID] = struct{}{}
+	}
+
+	deviceKnown := map[string]bool{}
+	orphans := make([]automationdomain.GCItem, 0)
+	live := make([]automationdomain.RouterArtefact, 0, len(artefacts))
+	for _, artefact := range artefacts {
+		reason, err := r.orphanReason(ctx, artefact, templateIDs, deviceKnown)
+		if err != nil {
+			return report, err
+		}
+		if reason == "" {
+			live = append(live, artefact)
+			continue
+		}
+		orphans = append(orphans, automationdomain.GCItem{Artefact: artefact, Reason: reason})
+	}
+
+	for i := range orphans {
+		orphans[i].Operation = gcOperation(orphans[i].Artefact, live, orphans[:i])
+	}
+	report.Items = orphans
+	if dryRun || len(orphans) == 0 {
+		return report, nil
+	}
+
+	cfg, ok := r.config.Get()
+	if !ok {
+		return report, automationdomain.ErrAddonNotConfigured
+	}
+	for i := range report.Items {
+		item := &report.Items[i]
+		if err := r.apply(ctx, cfg, *item); err != nil {
+			item.Error = err.Error()
+			if r.logger != nil {
+				r.logger.Warn("artefact gc failed", "kind", item.Artefact.Kind, "path", item.Artefact.Path, "key", item.Artefact.Key, "err", err)
+			}
+			continue
+		}
+		if err := r.ledger.DeleteArtefactByID(ctx, item.Artefact.ID); err != nil {
+			item.Error = err.Error()
+			continue
+		}
+		item.Done = true
+	}
+	return report, nil
+}
+
+func (r *artefactReconciler) orphanReason(
+	ctx context.Context,
+	artefact automationdomain.RouterArtefact,
+	templateIDs map[string]struct{},
+	deviceKnown map[string]bool,
+) (string, error) {
+	if _, ok := templateIDs[artefact.CapabilityID]; !ok {
+		return automationdomain.GCReasonCapabilityDeleted, nil
+	}
+	if artefact.DeviceID == "" {
+		return "", nil
+	}
+	known, cached := deviceKnown[artefact.DeviceID]
+	if !cached {
+		_, err := r.devices.GetDevice(ctx, artefact.DeviceID)
+		switch {
+		case err == nil:
+			known = true
+		case errors.Is(err, devicedomain.ErrDeviceNotFound), errors.Is(err, storage.ErrNotFound):
+			known = false
+		default:
+			return "", err
+		}
+		deviceKnown[artefact.DeviceID] = known
+	}
+	if !known {
+		return automationdomain.GCReasonDeviceGone, nil
+	}
+	return "", nil
+}
+
+// gcOperation picks undo for orphan; objects still owned by a live capability,
+// or already handled by an earlier orphan, are only forgotten.
+func gcOperation(
+	artefact automationdomain.RouterArtefact,
+	live []automationdomain.RouterArtefact,
+	handled []automationdomain.GCItem,
+) string {
+	for _, other := range live {
+		if artefact.SameObject(other) {
+			return automationdomain.GCOperationForget
+		}
+	}
+	for _, other := range handled {
+		if artefact.SameObject(other.Artefact) && other.Operation != automationdomain.GCOperationForget {
+			return automationdomain.GCOperationForget
+		}
+	}
+
+	switch artefact.Kind {
+	case automationdomain.ArtefactAddressListEntry, automationdomain.ArtefactRouterObject,
+		automationdomain.ArtefactSimpleQueue, automationdomain.ArtefactContentFilter,
+		automationdomain.ArtefactKidControlDevice, automationdomain.ArtefactDNSStatic,
+		automationdomain.ArtefactWiFiAccess:
+		return automationdomain.GCOperationRemove
+	case automationdomain.ArtefactFirewallRule, automationdomain.ArtefactFirewallComment,
+		automationdomain.ArtefactKidControlPause, automationdomain.ArtefactDNSAdlist:
+		if artefact.Restore != "" {
+			return automationdomain.GCOperationRestore
+		}
+	}
+	return automationdomain.GCOperationForget
+}
+
+func (r *artefactReconciler) apply(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	item automationdomain.GCItem,
+) error {
+	artefact := item.Artefact
+	ctx, cancel := context.WithTimeout(ctx, artefactGCRouterTimeout)
+	defer cancel()
+
+	switch item.Operation {
+	case automationdomain.GCOperationForget:
+		return nil
+	case automationdomain.GCOperationRemove:
+		switch artefact.Kind {
+		case automationdomain.ArtefactAddressListEntry:
+			// Entries without ownership comment were the user's; row is only forgotten.
+			return r.client.RemoveOwnedAddressListEntry(ctx, cfg, artefact.Path, artefact.Key, artefactOwnerComment(artefact))
+		case automationdomain.ArtefactRouterObject:
+			commandClient, ok := r.client.(automationdomain.RouterCommandClient)
+			if !ok {
+				return fmt.Errorf("router client does not support raw commands")
+			}
+			_, err := commandClient.RunCommand(ctx, cfg, artefact.Path+"/remove", map[string]string{".id": artefact.Key})
+			if err != nil && !routeros.IsNotFound(err) {
+				return err
+			}
+			return nil
+		case automationdomain.ArtefactSimpleQueue:
+			queueClient, ok := r.client.(automationdomain.SimpleQueueClient)
+			if !ok {
+				return fmt.Errorf("router client does not support simple queues")
+			}
+			return queueClient.RemoveSimpleQueueLimit(ctx, cfg, artefact.Path, artefact.Key)
+		case automationdomain.ArtefactContentFilter:
+			filterClient, ok := r.client.(automationdomain.ContentFilterClient)
+			if !ok {
+				return fmt.Errorf("router client does not support content filters")
+			}
+			return filterClient.RemoveContentFilter(ctx, cfg, artefact.Key)
+		case automationdomain.ArtefactKidControlDevice:
+			kidClient, ok := r.client.(automationdomain.KidControlClient)
+			if !ok {
+				return fmt.Errorf("router client does not support kid-control")
+			}
+			return kidClient.UnbindKidControlDevice(ctx, cfg, artefact.Key, artefactOwnerComment(artefact))
+		case automationdomain.ArtefactDNSStatic:
+			dnsClient, ok := r.client.(automationdomain.DNSStaticClient)
+			if !ok {
+				return fmt.Errorf("router client does not support static dns")
+			}
+			return dnsClient.RemoveDNSStaticRecord(ctx, cfg, artefact.Key, artefactOwnerComment(artefact))
+		case automationdomain.ArtefactWiFiAccess:
+			wifiClient, ok := r.client.(automationdomain.WiFiAccessClient)
+			if !ok {
+				return fmt.Errorf("router client does not support wifi access lists")
+			}
+			return wifiClient.RemoveWiFiAccessRule(ctx, cfg, artefact.Key, artefactOwnerComment(artefact))
+		}
+	case automationdomain.GCOperationRestore:
+		original, err := strconv.ParseBool(artefact.Restore)
+		if err != nil {
+			return fmt.Errorf("invalid restore value %q", artefact.Restore)
+		}
+		var applyErr error
+		switch artefact.Kind {
+		case automationdomain.ArtefactFirewallRule:
+			applyErr = r.client.SetFirewallRuleDisabled(ctx, cfg, artefact.Path, artefact.Key, original)
+		case automationdomain.ArtefactFirewallComment:
+			applyErr = r.client.SetFirewallRulesDisabledByComment(ctx, cfg, artefact.Path, artefact.Key, original)
+		case automationdomain.ArtefactKidControlPause:
+			kidClient, ok := r.client.(automationdomain.KidControlClient)
+			if !ok {
+				return fmt.Errorf("router client does not support kid-control")
+			}
+			applyErr = kidClient.SetKidControlPaused(ctx, cfg, artefact.Key, original)
+		case automationdomain.ArtefactDNSAdlist:
+			adlistClient, ok := r.client.(automationdomain.DNSAdlistClient)
+			if !ok {
+				return fmt.Errorf("router client does not support dns adlists")
+			}
+			applyErr = adlistClient.SetDNSAdlistsEnabled(ctx, cfg, artefact.Key, original)
+		}
+		if applyErr != nil && !routeros.IsNotFound(applyErr) {
+			return applyErr
+		}
+		return nil
+	}
+	return fmt.Errorf("unsupported gc operation %q for %s", item.Operation, artefact.Kind)
+}
+
+// artefactOwnerComment rebuilds ownership comment the artefact was written with.
+func artefactOwnerComment(artefact automationdomain.RouterArtefact) string {
+	return automationdomain.OwnershipComment(automationdomain.OwnershipTag{
+		CapabilityID: artefact.CapabilityID,
+		DeviceID:     artefact.DeviceID,
+	})
+}
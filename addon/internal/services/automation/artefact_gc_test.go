@@ -0,0 +1,196 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type fakeLedger struct {
+	artefacts []automationdomain.RouterArtefact
+	deleted   []int64
+}
+
+func (f *fakeLedger) GetArtefact(context.Context, automationdomain.RouterArtefact) (automationdomain.RouterArtefact, bool, error) {
+	return automationdomain.RouterArtefact{}, false, nil
+}
+
+func (f *fakeLedger) UpsertArtefact(context.Context, automationdomain.RouterArtefact) error {
+	return nil
+}
+
+func (f *fakeLedger) DeleteArtefact(context.Context, automationdomain.RouterArtefact) error {
+	return nil
+}
+
+func (f *fakeLedger) DeleteArtefactByID(_ context.Context, id int64) error {
+	f.deleted = append(f.deleted, id)
+	return nil
+}
+
+func (f *fakeLedger) ListArtefacts(context.Context) ([]automationdomain.RouterArtefact, error) {
+	return append([]automationdomain.RouterArtefact(nil), f.artefacts...), nil
+}
+
+type fakeGCTemplates []string
+
+func (f fakeGCTemplates) ListTemplates(context.Context, string, string) ([]automationdomain.CapabilityTemplate, error) {
+	templates := make([]automationdomain.CapabilityTemplate, 0, len(f))
+	for _, id := range f {
+		templates = append(templates, automationdomain.CapabilityTemplate{ID: id})
+	}
+	return templates, nil
+}
+
+type fakeGCDevices map[string]bool
+
+func (f fakeGCDevices) GetDevice(_ context.Context, mac string) (devicedomain.Device, error) {
+	if !f[mac] {
+		return devicedomain.Device{}, devicedomain.ErrDeviceNotFound
+	}
+	return devicedomain.Device{}, nil
+}
+
+type fakeGCRouter struct {
+	// comments holds comment of address-list entries by list/address;
+	// entries not listed are taken as carrying ownership comment.
+	comments map[string]string
+	removed  []string
+	restored []string
+}
+
+func (f *fakeGCRouter) EnsureOwnedAddressListEntry(context.Context, model.RouterConfig, string, string, string, string) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeGCRouter) RemoveOwnedAddressListEntry(_ context.Context, _ model.RouterConfig, list, address, comment string) error {
+	if existing, ok := f.comments[list+"/"+address]; ok && existing != comment {
+		return nil
+	}
+	f.removed = append(f.removed, list+"/"+address)
+	return nil
+}
+
+func (f *fakeGCRouter) SetFirewallRuleDisabled(_ context.Context, _ model.RouterConfig, table, ruleID string, disabled bool) error {
+	if disabled {
+		f.restored = append(f.restored, table+"/"+ruleID+"=disabled")
+	} else {
+		f.restored = append(f.restored, table+"/"+ruleID+"=enabled")
+	}
+	return nil
+}
+
+func (f *fakeGCRouter) SetFirewallRulesDisabledByComment(context.Context, model.RouterConfig, string, string, bool) error {
+	return nil
+}
+
+func TestArtefactReconcilerRemovesOrphans(t *testing.T) {
+	ledger := &fakeLedger{artefacts: []automationdomain.RouterArtefact{
+		{ID: 1, CapabilityID: "deleted", Kind: automationdomain.ArtefactAddressListEntry, Path: "blocked", Key: "192.168.88.10"},
+		{ID: 2, CapabilityID: "kids", DeviceID: "AA:BB:CC:DD:EE:01", Kind: automationdomain.ArtefactAddressListEntry, Path: "blocked", Key: "192.168.88.11"},
+		{ID: 3, CapabilityID: "kids", DeviceID: "AA:BB:CC:DD:EE:02", Kind: automationdomain.ArtefactAddressListEntry, Path: "blocked", Key: "192.168.88.12"},
+		{ID: 4, CapabilityID: "deleted", Kind: automationdomain.ArtefactFirewallRule, Path: "filter", Key: "*5", Restore: "false"},
+		{ID: 5, CapabilityID: "deleted", Kind: automationdomain.ArtefactAddressListEntry, Path: "shared", Key: "10.0.0.1"},
+		{ID: 6, CapabilityID: "kids", Kind: automationdomain.ArtefactAddressListEntry, Path: "shared", Key: "10.0.0.1"},
+	}}
+	router := &fakeGCRouter{}
+	reconciler := &artefactReconciler{
+		ledger:    ledger,
+		templates: fakeGCTemplates{"kids"},
+		devices:   fakeGCDevices{"AA:BB:CC:DD:EE:02": true},
+		client:    router,
+		config:    fakeOptionsConfig{configured: true},
+	}
+
+	preview, err := reconciler.Reconcile(context.Background(), true)
+	if err != nil {
+		t.Fatalf("preview failed: %v", err)
+	}
+	if len(preview.Items) != 4 || len(router.removed) != 0 || len(ledger.deleted) != 0 {
+		t.Fatalf("preview must not touch router or ledger: %+v removed=%v deleted=%v", preview.Items, router.removed, ledger.deleted)
+	}
+	operations := map[int64]string{}
+	for _, item := range preview.Items {
+		operations[item.Artefact.ID] = item.Reason + ":" + item.Operation
+	}
+	want := map[int64]string{
+		1: "capability_deleted:remove",
+		2: "device_gone:remove",
+		4: "capability_deleted:restore",
+		5: "capability_deleted:forget",
+	}
+	for id, op := range want {
+		if operations[id] != op {
+			t.Fatalf("artefact %d: expected %s, got %q", id, op, operations[id])
+		}
+	}
+
+	report, err := reconciler.Reconcile(context.Background(), false)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	for _, item := range report.Items {
+		if !item.Done {
+			t.Fatalf("expected item %d done, got %+v", item.Artefact.ID, item)
+		}
+	}
+	if len(router.removed) != 2 || router.removed[0] != "blocked/192.168.88.10" || router.removed[1] != "blocked/192.168.88.11" {
+		t.Fatalf("unexpected removals: %v", router.removed)
+	}
+	if len(router.restored) != 1 || router.restored[0] != "filter/*5=enabled" {
+		t.Fatalf("unexpected restores: %v", router.restored)
+	}
+	if len(ledger.deleted) != 4 {
+		t.Fatalf("expected 4 ledger rows dropped, got %v", ledger.deleted)
+	}
+}
+
+func TestArtefactReconcilerRunRequiresRouterConfig(t *testing.T) {
+	reconciler := &artefactReconciler{
+		ledger: &fakeLedger{artefacts: []automationdomain.RouterArtefact{
+			{ID: 1, CapabilityID: "deleted", Kind: automationdomain.ArtefactAddressListEntry, Path: "blocked", Key: "192.168.88.10"},
+		}},
+		templates: fakeGCTemplates{},
+		devices:   fakeGCDevices{},
+		client:    &fakeGCRouter{},
+		config:    fakeOptionsConfig{},
+	}
+	if _, err := reconciler.Reconcile(context.Background(), true); err != nil {
+		t.Fatalf("preview should work without router config: %v", err)
+	}
+	if _, err := reconciler.Reconcile(context.Background(), false); !errors.Is(err, automationdomain.ErrAddonNotConfigured) {
+		t.Fatalf("expected ErrAddonNotConfigured, got %v", err)
+	}
+}
+
+func TestArtefactReconcilerKeepsUserAddressListEntry(t *testing.T) {
+	ledger := &fakeLedger{artefacts: []automationdomain.RouterArtefact{
+		{ID: 1, CapabilityID: "deleted", Kind: automationdomain.ArtefactAddressListEntry, Path: "blocked", Key: "192.168.88.10"},
+	}}
+	router := &fakeGCRouter{comments: map[string]string{"blocked/192.168.88.10": "added by hand"}}
+	reconciler := &artefactReconciler{
+		ledger:    ledger,
+		templates: fakeGCTemplates{},
+		devices:   fakeGCDevices{},
+		client:    router,
+		config:    fakeOptionsConfig{configured: true},
+	}
+
+	report, err := reconciler.Reconcile(context.Background(), false)
+	if err != nil {
+		t.Fatalf("run failed: %v", err)
+	}
+	if len(report.Items) != 1 || !report.Items[0].Done {
+		t.Fatalf("expected artefact to be handled, got %+v", report.Items)
+	}
+	if len(router.removed) != 0 {
+		t.Fatalf("user entry must stay on router, removed %v", router.removed)
+	}
+	if len(ledger.deleted) != 1 || ledger.deleted[0] != 1 {
+		t.Fatalf("expected ledger row to be forgotten, got %v", ledger.deleted)
+	}
+}
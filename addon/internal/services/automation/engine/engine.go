@@ -6,16 +6,22 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"sync"
 	"time"
 
 	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
 	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/events"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/services/automation/registry"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/storage"
 )
 
-const actionExecutionTimeout = 12 * time.Second
+const (
+	actionExecutionTimeout = 12 * time.Second
+	disableHooksTimeout    = 5 * time.Minute
+	defaultEnforceInterval = time.Minute
+)
 
 // DeviceService describes device read operations required by automation engine.
 type DeviceService interface {
@@ -41,7 +47,25 @@ type Engine struct {
 	registry     *registry.Registry
 	config       RouterConfigProvider
 	routerClient RouterClient
+	ledger       automationdomain.LedgerRepository
+	publisher    events.Publisher
 	logger       *slog.Logger
+	disableHooks sync.WaitGroup
+
+	enforceMu       sync.Mutex
+	enforceInterval time.Duration
+	lastEnforced    map[string]time.Time
+	// activeDrift holds "expected|observed" per drifted target so a drift is
+	// recorded once, not on every sync round.
+	activeDrift map[string]string
+
+	syncMu        sync.Mutex
+	syncInterval  time.Duration
+	syncStatus    map[string]*automationdomain.SyncStatus
+	syncSlots     chan struct{}
+	routerLimiter *routerLimiter
+
+	ipChanges chan devicedomain.IPChange
 }
 
 // New creates automation engine.
@@ -60,7 +84,48 @@ func New(
 		config:       config,
 		routerClient: routerClient,
 		logger:       logger,
+
+		enforceInterval: defaultEnforceInterval,
+		lastEnforced:    map[string]time.Time{},
+		activeDrift:     map[string]string{},
+
+		syncInterval:  defaultSyncInterval,
+		syncStatus:    map[string]*automationdomain.SyncStatus{},
+		syncSlots:     make(chan struct{}, defaultSyncWorkers),
+		routerLimiter: newRouterLimiter(defaultRouterConcurrency),
+
+		ipChanges: make(chan devicedomain.IPChange, ipChangeQueueSize),
+	}
+}
+
+// WithEnforceInterval sets minimum delay between drift corrections of one target.
+func (e *Engine) WithEnforceInterval(interval time.Duration) *Engine {
+	if interval > 0 {
+		e.enforceInterval = interval
+	}
+	return e
+}
+
+// WithSyncWorkers bounds how many templates sync concurrently.
+func (e *Engine) WithSyncWorkers(workers int) *Engine {
+	if workers > 0 {
+		e.syncSlots = make(chan struct{}, workers)
+	}
+	return e
+}
+
+// WithRouterConcurrency bounds concurrent sync reads against one router.
+func (e *Engine) WithRouterConcurrency(limit int) *Engine {
+	if limit > 0 {
+		e.routerLimiter = newRouterLimiter(limit)
 	}
+	return e
+}
+
+// WithEvents publishes persisted capability state changes.
+func (e *Engine) WithEvents(publisher events.Publisher) *Engine {
+	e.publisher = publisher
+	return e
 }
 
 // SetCapabilityState executes actions and persists new state.
@@ -98,8 +163,7 @@ func (e *Engine) SetCapabilityState(
 		)
 	}
 
-	stateConfig, ok := template.States[newState]
-	if !ok {
+	if _, ok := template.States[newState]; !ok {
 		return automationdomain.SetStateResult{}, fmt.Errorf("%w: unknown state %q", automationdomain.ErrCapabilityStateInvalid, newState)
 	}
 
@@ -116,13 +180,18 @@ func (e *Engine) SetCapabilityState(
 		return automationdomain.SetStateResult{OK: true}, nil
 	}
 
+	// Disabled target already ran its disable hook, so only enter actions apply.
+	actions := template.States[newState].ActionsOnEnter
+	if current.Enabled {
+		actions = template.TransitionActions(current.State, newState)
+	}
 	result := automationdomain.SetStateResult{OK: true}
 	result.Warnings = append(result.Warnings, e.executeStateActions(
 		ctx,
 		automationTarget,
 		capabilityID,
 		newState,
-		stateConfig.ActionsOnEnter,
+		actions,
 	)...)
 
 	current.Enabled = true
@@ -133,6 +202,100 @@ func (e *Engine) SetCapabilityState(
 	return result, nil
 }
 
+// ApplyEnabledChange runs hooks after target is enabled or disabled. Disable
+// runs ActionsOnDisable; re-enable re-applies enter actions of current state
+// when template has a disable hook that may have undone them.
+func (e *Engine) ApplyEnabledChange(
+	ctx context.Context,
+	targetRef automationdomain.CapabilityTargetRef,
+	template automationdomain.CapabilityTemplate,
+	state string,
+	enabled bool,
+) ([]automationdomain.ActionExecutionWarning, error) {
+	targetRef, err := normalizeTargetRef(targetRef)
+	if err != nil {
+		return nil, err
+	}
+	e.publishCapabilityState(targetRef, template.ID, targetCapabilityState{Enabled: enabled, State: state})
+	if len(template.ActionsOnDisable) == 0 {
+		return nil, nil
+	}
+	automationTarget, err := e.resolveAutomationTarget(ctx, targetRef)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := template.ActionsOnDisable
+	if enabled {
+		actions = template.States[state].ActionsOnEnter
+	}
+	return e.executeStateActions(ctx, automationTarget, template.ID, state, actions), nil
+}
+
+// RunDisableHooks runs ActionsOnDisable for every target with a stored
+// enabled state, used before template is deleted. Targets are resolved before
+// it returns; actions run in background so deletion does not wait on router.
+// Writes bypass ownership ledger since owning capability is going away.
+func (e *Engine) RunDisableHooks(ctx context.Context, template automationdomain.CapabilityTemplate) error {
+	if len(template.ActionsOnDisable) == 0 {
+		return nil
+	}
+	targets, err := e.syncTargets(ctx, template.Scope)
+	if err != nil {
+		return err
+	}
+
+	type hookTarget struct {
+		target automationdomain.AutomationTarget
+		state  string
+	}
+	enabled := make([]hookTarget, 0, len(targets))
+	for _, target := range targets {
+		current, stored, err := e.storedCapabilityState(ctx, target.Ref, template.ID, template.DefaultState)
+		if err != nil {
+			return err
+		}
+		if !stored || !current.Enabled {
+			continue
+		}
+		enabled = append(enabled, hookTarget{target: target.Target, state: current.State})
+	}
+	if len(enabled) == 0 {
+		return nil
+	}
+
+	e.disableHooks.Add(1)
+	go func() {
+		defer e.disableHooks.Done()
+		hookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), disableHooksTimeout)
+		defer cancel()
+		warnings := 0
+		for _, item := range enabled {
+			warnings += len(e.runStateActions(hookCtx, item.target, template.ID, item.state, template.ActionsOnDisable, actionOperationTeardown))
+		}
+		if warnings > 0 && e.logger != nil {
+			e.logger.Warn("capability disable hooks reported warnings", "capability_id", template.ID, "warnings", warnings)
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until background disable hooks finish or ctx is done, so
+// shutdown does not close storage under a running teardown.
+func (e *Engine) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.disableHooks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
 // SyncOnce reads external state-sources and aligns capability states.
 func (e *Engine) SyncOnce(ctx context.Context) error {
 	routerConfig, configured := e.config.Get()
@@ -145,85 +308,307 @@ func (e *Engine) SyncOnce(ctx context.Context) error {
 		return err
 	}
 
-	var syncErrors []error
+	var (
+		wg         sync.WaitGroup
+		errMu      sync.Mutex
+		syncErrors []error
+	)
 	for _, template := range templates {
-		template.Scope = automationdomain.NormalizeCapabilityScope(template.Scope)
-		if template.Sync == nil || !template.Sync.Enabled {
+		if !syncEnabled(template) || !e.claimSync(template, time.Time{}) {
 			continue
 		}
+		select {
+		case e.syncSlots <- struct{}{}:
+		case <-ctx.Done():
+			e.releaseSyncClaim(template.ID)
+			wg.Wait()
+			return ctx.Err()
+		}
 
-		source, ok := e.registry.StateSource(template.Sync.Source.TypeID)
-		if !ok {
-			syncErrors = append(syncErrors, fmt.Errorf("capability %s: statesource %q not found", template.ID, template.Sync.Source.TypeID))
+		wg.Add(1)
+		go func(template automationdomain.CapabilityTemplate) {
+			defer wg.Done()
+			defer func() { <-e.syncSlots }()
+			if err := e.runTrackedSync(ctx, routerConfig, template); err != nil {
+				errMu.Lock()
+				syncErrors = append(syncErrors, err)
+				errMu.Unlock()
+			}
+		}(template)
+	}
+	wg.Wait()
+	return errors.Join(syncErrors...)
+}
+
+// syncTemplate aligns every target of one template with its state source.
+func (e *Engine) syncTemplate(
+	ctx context.Context,
+	routerConfig model.RouterConfig,
+	template automationdomain.CapabilityTemplate,
+) (int, error) {
+	template.Scope = automationdomain.NormalizeCapabilityScope(template.Scope)
+	source, ok := e.registry.StateSource(template.Sync.Source.TypeID)
+	if !ok {
+		return 0, fmt.Errorf("capability %s: statesource %q not found", template.ID, template.Sync.Source.TypeID)
+	}
+
+	targets, err := e.syncTargets(ctx, template.Scope)
+	if err != nil {
+		return 0, fmt.Errorf("capability %s: resolve targets: %w", template.ID, err)
+	}
+
+	// One cache per run lets every target share the same router reads.
+	readClient := newSyncReadCache(e.routerClient, e.routerLimiter)
+	mode := syncMode(template.Sync.Mode)
+
+	var syncErrors []error
+	pending := make([]pendingSyncTarget, 0, len(targets))
+	for _, target := range targets {
+		current, err := e.currentCapabilityState(ctx, target.Ref, template.ID, template.DefaultState)
+		if err != nil {
+			syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: current state: %w", template.ID, target.Label, err))
 			continue
 		}
+		if !current.Enabled {
+			continue
+		}
+
+		// `internal_truth` keeps local state as source of truth.
+		if mode == automationdomain.SyncModeInternalTruth {
+			continue
+		}
+
+		if err := source.Validate(target.Target, template.Sync.Source.Params); err != nil {
+			syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: invalid sync source params: %w", template.ID, target.Label, err))
+			continue
+		}
+		pending = append(pending, pendingSyncTarget{target: target, current: current})
+	}
+
+	values, err := e.readSyncValues(ctx, source, readClient, routerConfig, template, pending)
+	if err != nil {
+		syncErrors = append(syncErrors, fmt.Errorf("capability %s: read sync source: %w", template.ID, err))
+		return len(targets), errors.Join(syncErrors...)
+	}
 
-		targets, err := e.syncTargets(ctx, template.Scope)
+	for i, item := range pending {
+		target, current := item.target, item.current
+		if values[i].Err != nil {
+			syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: read sync source: %w", template.ID, target.Label, values[i].Err))
+			continue
+		}
+
+		targetState, representable, err := observedSyncState(template, current.State, values[i].Value)
 		if err != nil {
-			syncErrors = append(syncErrors, fmt.Errorf("capability %s: resolve targets: %w", template.ID, err))
+			syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: %w", template.ID, target.Label, err))
 			continue
 		}
 
-		for _, target := range targets {
-			current, err := e.currentCapabilityState(ctx, target.Ref, template.ID, template.DefaultState)
-			if err != nil {
-				syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: current state: %w", template.ID, target.Label, err))
+		// `enforce` keeps local state and corrects router drift instead.
+		if mode == automationdomain.SyncModeEnforce {
+			if !representable {
 				continue
 			}
-			if !current.Enabled {
+			if targetState == current.State {
+				e.clearDrift(enforcementKey(template.ID, target.Ref))
 				continue
 			}
-
-			// `internal_truth` keeps local state as source of truth.
-			if strings.EqualFold(strings.TrimSpace(template.Sync.Mode), "internal_truth") {
-				continue
+			if err := e.enforceSyncState(ctx, template, target, current.State, targetState); err != nil {
+				syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: enforce sync state: %w", template.ID, target.Label, err))
 			}
+			continue
+		}
 
-			if err := source.Validate(target.Target, template.Sync.Source.Params); err != nil {
-				syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: invalid sync source params: %w", template.ID, target.Label, err))
-				continue
-			}
+		if targetState == "" || targetState == current.State {
+			continue
+		}
 
-			rawValue, err := source.Read(ctx, automationdomain.StateSourceContext{
-				Target:       target.Target,
-				RouterClient: e.routerClient,
-				RouterConfig: routerConfig,
-				Logger:       e.logger,
-			}, template.Sync.Source.Params)
-			if err != nil {
-				syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: read sync source: %w", template.ID, target.Label, err))
-				continue
+		if !template.Sync.TriggerActionsOnSync {
+			current.State = targetState
+			if err := e.persistCapabilityState(ctx, target.Ref, template.ID, current); err != nil {
+				syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: upsert sync state: %w", template.ID, target.Label, err))
 			}
+			continue
+		}
 
-			boolValue, ok := rawValue.(bool)
-			if !ok {
-				syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: expected boolean source output", template.ID, target.Label))
-				continue
-			}
+		if _, err := e.SetCapabilityState(ctx, target.Ref, template.ID, targetState); err != nil {
+			syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: apply sync state: %w", template.ID, target.Label, err))
+		}
+	}
+	return len(targets), errors.Join(syncErrors...)
+}
 
-			targetState := template.Sync.Mapping.WhenFalse
-			if boolValue {
-				targetState = template.Sync.Mapping.WhenTrue
-			}
-			targetState = strings.TrimSpace(targetState)
-			if targetState == "" || targetState == current.State {
-				continue
-			}
+// observedSyncState maps source output to capability state. Boolean output goes
+// through sync.mapping; string output must name a declared state. representable
+// is false when current state has no router representation in boolean mapping.
+func observedSyncState(
+	template automationdomain.CapabilityTemplate,
+	currentState string,
+	value any,
+) (string, bool, error) {
+	switch typed := value.(type) {
+	case bool:
+		whenTrue := strings.TrimSpace(template.Sync.Mapping.WhenTrue)
+		whenFalse := strings.TrimSpace(template.Sync.Mapping.WhenFalse)
+		representable := currentState == whenTrue || currentState == whenFalse
+		if typed {
+			return whenTrue, representable, nil
+		}
+		return whenFalse, representable, nil
+	case string:
+		state := strings.TrimSpace(typed)
+		if _, ok := template.States[state]; !ok {
+			return "", false, fmt.Errorf("source value %q is not a declared state", state)
+		}
+		return state, true, nil
+	default:
+		return "", false, fmt.Errorf("expected boolean or string source output")
+	}
+}
 
-			if !template.Sync.TriggerActionsOnSync {
-				current.State = targetState
-				if err := e.persistCapabilityState(ctx, target.Ref, template.ID, current); err != nil {
-					syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: upsert sync state: %w", template.ID, target.Label, err))
-				}
-				continue
-			}
+type pendingSyncTarget struct {
+	target  resolvedSyncTarget
+	current targetCapabilityState
+}
 
-			if _, err := e.SetCapabilityState(ctx, target.Ref, template.ID, targetState); err != nil {
-				syncErrors = append(syncErrors, fmt.Errorf("capability %s target %s: apply sync state: %w", template.ID, target.Label, err))
-			}
+// readSyncValues prefers one batch read and falls back to per-target reads.
+func (e *Engine) readSyncValues(
+	ctx context.Context,
+	source automationdomain.StateSource,
+	readClient automationdomain.RouterStateClient,
+	routerConfig model.RouterConfig,
+	template automationdomain.CapabilityTemplate,
+	pending []pendingSyncTarget,
+) ([]automationdomain.StateReadResult, error) {
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	if batchSource, ok := source.(automationdomain.BatchStateSource); ok {
+		targets := make([]automationdomain.AutomationTarget, 0, len(pending))
+		for _, item := range pending {
+			targets = append(targets, item.target.Target)
 		}
+		results, err := batchSource.ReadMany(ctx, automationdomain.StateSourceBatchContext{
+			Targets:      targets,
+			RouterClient: readClient,
+			RouterConfig: routerConfig,
+			Logger:       e.logger,
+		}, template.Sync.Source.Params)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) != len(pending) {
+			return nil, fmt.Errorf("batch read returned %d results for %d targets", len(results), len(pending))
+		}
+		return results, nil
+	}
+
+	results := make([]automationdomain.StateReadResult, len(pending))
+	for i, item := range pending {
+		results[i].Value, results[i].Err = source.Read(ctx, automationdomain.StateSourceContext{
+			Target:       item.target.Target,
+			RouterClient: readClient,
+			RouterConfig: routerConfig,
+			Logger:       e.logger,
+		}, template.Sync.Source.Params)
+	}
+	return results, nil
+}
+
+// enforceSyncState re-applies current state actions when router value drifted.
+func (e *Engine) enforceSyncState(
+	ctx context.Context,
+	template automationdomain.CapabilityTemplate,
+	target resolvedSyncTarget,
+	currentState string,
+	observedState string,
+) error {
+	event := automationdomain.DriftEvent{
+		CapabilityID:  template.ID,
+		Scope:         target.Ref.Scope,
+		DeviceID:      target.Ref.DeviceID,
+		ExpectedState: currentState,
+		ObservedState: observedState,
+		DetectedAt:    time.Now().UTC(),
+	}
+
+	key := enforcementKey(template.ID, target.Ref)
+	if !e.allowEnforcement(key, event.DetectedAt) {
+		event.Outcome = automationdomain.DriftOutcomeRateLimited
+	} else {
+		event.Warnings = e.executeStateActions(
+			ctx,
+			target.Target,
+			template.ID,
+			currentState,
+			template.States[currentState].ActionsOnEnter,
+		)
+		event.Outcome = automationdomain.DriftOutcomeCorrected
+		if len(event.Warnings) > 0 {
+			event.Outcome = automationdomain.DriftOutcomeCorrectionFailed
+		}
+	}
+
+	if !e.driftChanged(key, currentState+"|"+observedState) {
+		return nil
+	}
+	if e.logger != nil {
+		e.logger.Warn(
+			"automation router drift detected",
+			"capability_id", template.ID,
+			"target", target.Label,
+			"expected_state", event.ExpectedState,
+			"observed_state", event.ObservedState,
+			"outcome", event.Outcome,
+		)
+	}
+	return e.repo.InsertDriftEvent(ctx, event)
+}
+
+// enforcementKey identifies one target of template for drift bookkeeping.
+func enforcementKey(capabilityID string, ref automationdomain.CapabilityTargetRef) string {
+	return capabilityID + "|" + string(ref.Scope) + "|" + ref.DeviceID
+}
+
+// driftChanged remembers drift of key and reports whether it is new.
+func (e *Engine) driftChanged(key string, drift string) bool {
+	e.enforceMu.Lock()
+	defer e.enforceMu.Unlock()
+
+	if e.activeDrift[key] == drift {
+		return false
+	}
+	e.activeDrift[key] = drift
+	return true
+}
+
+func (e *Engine) clearDrift(key string) {
+	e.enforceMu.Lock()
+	defer e.enforceMu.Unlock()
+	delete(e.activeDrift, key)
+}
+
+func (e *Engine) allowEnforcement(key string, now time.Time) bool {
+	e.enforceMu.Lock()
+	defer e.enforceMu.Unlock()
+
+	if last, ok := e.lastEnforced[key]; ok && now.Sub(last) < e.enforceInterval {
+		return false
+	}
+	e.lastEnforced[key] = now
+	return true
+}
+
+func syncMode(raw string) string {
+	switch strings.ToLower(strings.TrimSpace(raw)) {
+	case automationdomain.SyncModeInternalTruth:
+		return automationdomain.SyncModeInternalTruth
+	case automationdomain.SyncModeEnforce:
+		return automationdomain.SyncModeEnforce
+	default:
+		return automationdomain.SyncModeExternalTruth
 	}
-	return errors.Join(syncErrors...)
 }
 
 type targetCapabilityState struct {
@@ -278,6 +663,18 @@ func (e *Engine) currentCapabilityState(
 	capabilityID string,
 	defaultState string,
 ) (targetCapabilityState, error) {
+	current, _, err := e.storedCapabilityState(ctx, targetRef, capabilityID, defaultState)
+	return current, err
+}
+
+// storedCapabilityState reports whether target has a persisted state row; a
+// missing row reads as enabled default state.
+func (e *Engine) storedCapabilityState(
+	ctx context.Context,
+	targetRef automationdomain.CapabilityTargetRef,
+	capabilityID string,
+	defaultState string,
+) (targetCapabilityState, bool, error) {
 	defaultState = strings.TrimSpace(defaultState)
 	targetRef.Scope = automationdomain.NormalizeCapabilityScope(targetRef.Scope)
 
@@ -285,31 +682,31 @@ func (e *Engine) currentCapabilityState(
 	case automationdomain.ScopeDevice:
 		current, exists, err := e.repo.GetDeviceCapabilityState(ctx, targetRef.DeviceID, capabilityID)
 		if err != nil {
-			return targetCapabilityState{}, err
+			return targetCapabilityState{}, false, err
 		}
 		if !exists {
-			return targetCapabilityState{Enabled: true, State: defaultState}, nil
+			return targetCapabilityState{Enabled: true, State: defaultState}, false, nil
 		}
 		state := strings.TrimSpace(current.State)
 		if state == "" {
 			state = defaultState
 		}
-		return targetCapabilityState{Enabled: current.Enabled, State: state}, nil
+		return targetCapabilityState{Enabled: current.Enabled, State: state}, true, nil
 	case automationdomain.ScopeGlobal:
 		current, err := e.repo.GetGlobalCapability(ctx, capabilityID)
 		if err != nil {
-			return targetCapabilityState{}, err
+			return targetCapabilityState{}, false, err
 		}
 		if current == nil {
-			return targetCapabilityState{Enabled: true, State: defaultState}, nil
+			return targetCapabilityState{Enabled: true, State: defaultState}, false, nil
 		}
 		state := strings.TrimSpace(current.State)
 		if state == "" {
 			state = defaultState
 		}
-		return targetCapabilityState{Enabled: current.Enabled, State: state}, nil
+		return targetCapabilityState{Enabled: current.Enabled, State: state}, true, nil
 	default:
-		return targetCapabilityState{}, fmt.Errorf("%w: unsupported scope %q", automationdomain.ErrCapabilityScopeInvalid, targetRef.Scope)
+		return targetCapabilityState{}, false, fmt.Errorf("%w: unsupported scope %q", automationdomain.ErrCapabilityScopeInvalid, targetRef.Scope)
 	}
 }
 
@@ -320,9 +717,10 @@ func (e *Engine) persistCapabilityState(
 	state targetCapabilityState,
 ) error {
 	targetRef.Scope = automationdomain.NormalizeCapabilityScope(targetRef.Scope)
+	var err error
 	switch targetRef.Scope {
 	case automationdomain.ScopeDevice:
-		return e.repo.UpsertDeviceCapabilityState(ctx, automationdomain.DeviceCapability{
+		err = e.repo.UpsertDeviceCapabilityState(ctx, automationdomain.DeviceCapability{
 			DeviceID:     targetRef.DeviceID,
 			CapabilityID: capabilityID,
 			Enabled:      state.Enabled,
@@ -330,7 +728,7 @@ func (e *Engine) persistCapabilityState(
 			UpdatedAt:    time.Now().UTC(),
 		})
 	case automationdomain.ScopeGlobal:
-		return e.repo.SaveGlobalCapability(ctx, &automationdomain.GlobalCapability{
+		err = e.repo.SaveGlobalCapability(ctx, &automationdomain.GlobalCapability{
 			CapabilityID: capabilityID,
 			Enabled:      state.Enabled,
 			State:        state.State,
@@ -338,6 +736,28 @@ func (e *Engine) persistCapabilityState(
 	default:
 		return fmt.Errorf("%w: unsupported scope %q", automationdomain.ErrCapabilityScopeInvalid, targetRef.Scope)
 	}
+	if err != nil {
+		return err
+	}
+	e.publishCapabilityState(targetRef, capabilityID, state)
+	return nil
+}
+
+func (e *Engine) publishCapabilityState(
+	targetRef automationdomain.CapabilityTargetRef,
+	capabilityID string,
+	state targetCapabilityState,
+) {
+	if e.publisher == nil {
+		return
+	}
+	e.publisher.Publish(events.TypeCapabilityState, events.CapabilityStateChange{
+		Scope:        string(targetRef.Scope),
+		DeviceID:     targetRef.DeviceID,
+		CapabilityID: capabilityID,
+		Enabled:      state.Enabled,
+		State:        state.State,
+	})
 }
 
 func (e *Engine) resolveAutomationTarget(
@@ -363,6 +783,26 @@ func (e *Engine) executeStateActions(
 	capabilityID string,
 	newState string,
 	actions []automationdomain.ActionInstance,
+) []automationdomain.ActionExecutionWarning {
+	return e.runStateActions(ctx, target, capabilityID, newState, actions, actionOperationExecute)
+}
+
+type actionOperation string
+
+const (
+	actionOperationExecute actionOperation = "execute"
+	actionOperationCleanup actionOperation = "cleanup"
+	// actionOperationTeardown executes actions outside ownership ledger.
+	actionOperationTeardown actionOperation = "teardown"
+)
+
+func (e *Engine) runStateActions(
+	ctx context.Context,
+	target automationdomain.AutomationTarget,
+	capabilityID string,
+	state string,
+	actions []automationdomain.ActionInstance,
+	operation actionOperation,
 ) []automationdomain.ActionExecutionWarning {
 	warnings := make([]automationdomain.ActionExecutionWarning, 0)
 	routerConfig, configured := e.config.Get()
@@ -376,6 +816,14 @@ func (e *Engine) executeStateActions(
 			))
 			continue
 		}
+		run := action.Execute
+		if operation == actionOperationCleanup {
+			cleaner, ok := action.(automationdomain.StaleTargetCleaner)
+			if !ok {
+				continue
+			}
+			run = cleaner.CleanupStaleTarget
+		}
 		if err := action.Validate(target, actionInstance.Params); err != nil {
 			warnings = append(warnings, warningForAction(actionInstance, err.Error()))
 			continue
@@ -393,21 +841,31 @@ func (e *Engine) executeStateActions(
 			fields := []any{
 				"scope", target.Scope,
 				"capability_id", capabilityID,
-				"state", newState,
+				"state", state,
 				"action_type", actionInstance.TypeID,
 				"action_index", index,
 			}
+			if operation != actionOperationExecute {
+				fields = append(fields, "operation", operation)
+			}
 			if target.Device != nil {
 				fields = append(fields, "device_mac", target.Device.MAC)
 			}
 			actionLogger = actionLogger.With(fields...)
 		}
 
+		routerClient := e.actionRouterClient(capabilityID, target)
+		if operation == actionOperationTeardown {
+			routerClient = e.routerClient
+		}
+
 		startedAt := time.Now()
 		actionCtx, cancel := context.WithTimeout(ctx, actionExecutionTimeout)
-		err := action.Execute(actionCtx, automationdomain.ActionExecutionContext{
+		err := run(actionCtx, automationdomain.ActionExecutionContext{
 			Target:       target,
-			RouterClient: e.routerClient,
+			CapabilityID: capabilityID,
+			State:        state,
+			RouterClient: routerClient,
 			RouterConfig: routerConfig,
 			Logger:       actionLogger,
 		}, actionInstance.Params)
@@ -3,6 +3,7 @@ package engine
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"strings"
 	"testing"
@@ -10,6 +11,7 @@ import (
 
 	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
 	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/events"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/services/automation/registry"
 )
@@ -18,6 +20,7 @@ type memoryRepository struct {
 	templates    map[string]automationdomain.CapabilityTemplate
 	states       map[string]automationdomain.DeviceCapability
 	globalStates map[string]automationdomain.GlobalCapability
+	driftEvents  []automationdomain.DriftEvent
 }
 
 func newMemoryRepository() *memoryRepository {
@@ -150,6 +153,30 @@ func (r *memoryRepository) ListGlobalCapabilities(
 	return out, nil
 }
 
+func (r *memoryRepository) InsertDriftEvent(ctx context.Context, event automationdomain.DriftEvent) error {
+	event.ID = int64(len(r.driftEvents) + 1)
+	r.driftEvents = append(r.driftEvents, event)
+	return nil
+}
+
+func (r *memoryRepository) ListDriftEvents(
+	ctx context.Context,
+	filter automationdomain.DriftEventFilter,
+) ([]automationdomain.DriftEvent, error) {
+	out := make([]automationdomain.DriftEvent, 0, len(r.driftEvents))
+	for i := len(r.driftEvents) - 1; i >= 0; i-- {
+		event := r.driftEvents[i]
+		if filter.CapabilityID != "" && event.CapabilityID != filter.CapabilityID {
+			continue
+		}
+		if filter.DeviceID != "" && event.DeviceID != filter.DeviceID {
+			continue
+		}
+		out = append(out, event)
+	}
+	return out, nil
+}
+
 func stateKey(deviceID string, capabilityID string) string {
 	return strings.ToUpper(deviceID) + "|" + capabilityID
 }
@@ -291,6 +318,31 @@ func (a *fakeAction) Execute(
 	return nil
 }
 
+type fakeCleanupAction struct {
+	fakeAction
+	cleanupIPs []string
+	executeIPs []string
+}
+
+func (a *fakeCleanupAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	a.execCalled++
+	a.executeIPs = append(a.executeIPs, *execCtx.Target.Device.LastIP)
+	return nil
+}
+
+func (a *fakeCleanupAction) CleanupStaleTarget(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	a.cleanupIPs = append(a.cleanupIPs, *execCtx.Target.Device.LastIP)
+	return nil
+}
+
 type fakeStateSource struct {
 	id    string
 	value bool
@@ -318,6 +370,48 @@ func (s *fakeStateSource) Read(
 	return s.value, nil
 }
 
+type fakeValueStateSource struct {
+	fakeStateSource
+	state string
+}
+
+func (s *fakeValueStateSource) Read(
+	ctx context.Context,
+	sourceCtx automationdomain.StateSourceContext,
+	params map[string]any,
+) (any, error) {
+	return s.state, nil
+}
+
+type fakeBatchStateSource struct {
+	fakeStateSource
+	readCalls     int
+	readManyCalls int
+	values        map[string]bool
+}
+
+func (s *fakeBatchStateSource) Read(
+	ctx context.Context,
+	sourceCtx automationdomain.StateSourceContext,
+	params map[string]any,
+) (any, error) {
+	s.readCalls++
+	return s.values[sourceCtx.Target.Device.MAC], nil
+}
+
+func (s *fakeBatchStateSource) ReadMany(
+	ctx context.Context,
+	batchCtx automationdomain.StateSourceBatchContext,
+	params map[string]any,
+) ([]automationdomain.StateReadResult, error) {
+	s.readManyCalls++
+	results := make([]automationdomain.StateReadResult, len(batchCtx.Targets))
+	for i, target := range batchCtx.Targets {
+		results[i].Value = s.values[target.Device.MAC]
+	}
+	return results, nil
+}
+
 func TestEngineSetCapabilityStateExecutesActionsAndPersistsState(t *testing.T) {
 	repo := newMemoryRepository()
 	deviceService := &fakeDeviceService{
@@ -536,6 +630,111 @@ func TestEngineSyncOnceCanTriggerActions(t *testing.T) {
 	}
 }
 
+func TestEngineSyncOnceEnforceCorrectsDriftAndRateLimits(t *testing.T) {
+	repo := newMemoryRepository()
+	deviceService := &fakeDeviceService{
+		devices: map[string]devicedomain.Device{
+			"AA:BB:CC:DD:EE:05": {
+				MAC:    "AA:BB:CC:DD:EE:05",
+				Name:   "Enforced device",
+				Online: true,
+			},
+		},
+	}
+
+	source := &fakeStateSource{id: "test.source", value: false}
+	action := &fakeAction{id: "test.action"}
+	reg := registry.New()
+	reg.RegisterStateSource(source)
+	reg.RegisterAction(action)
+
+	repo.templates["routing.enforce"] = automationdomain.CapabilityTemplate{
+		ID:           "routing.enforce",
+		Label:        "Enforced capability",
+		Control:      automationdomain.CapabilityControl{Type: automationdomain.ControlSwitch, Options: []automationdomain.CapabilityControlOption{{Value: "on", Label: "On"}, {Value: "off", Label: "Off"}}},
+		DefaultState: "off",
+		States: map[string]automationdomain.CapabilityStateConfig{
+			"on": {
+				Label: "On",
+				ActionsOnEnter: []automationdomain.ActionInstance{
+					{ID: "a1", TypeID: "test.action", Params: map[string]any{}},
+				},
+			},
+			"off": {Label: "Off"},
+		},
+		Sync: &automationdomain.CapabilitySyncConfig{
+			Enabled: true,
+			Source: automationdomain.CapabilitySyncSource{
+				TypeID: "test.source",
+				Params: map[string]any{},
+			},
+			Mapping: automationdomain.CapabilitySyncMapping{
+				WhenTrue:  "on",
+				WhenFalse: "off",
+			},
+			Mode: automationdomain.SyncModeEnforce,
+		},
+	}
+	_ = repo.UpsertDeviceCapabilityState(context.Background(), automationdomain.DeviceCapability{
+		DeviceID:     "AA:BB:CC:DD:EE:05",
+		CapabilityID: "routing.enforce",
+		Enabled:      true,
+		State:        "on",
+		UpdatedAt:    time.Now().UTC(),
+	})
+
+	engine := New(
+		repo,
+		deviceService,
+		reg,
+		fakeConfigProvider{ok: true, cfg: model.RouterConfig{Host: "router.local"}},
+		&fakeRouterClient{membershipMap: map[string]bool{}},
+		nil,
+	).WithEnforceInterval(time.Hour)
+
+	for i := 0; i < 2; i++ {
+		if err := engine.SyncOnce(context.Background()); err != nil {
+			t.Fatalf("SyncOnce returned error: %v", err)
+		}
+	}
+
+	if action.execCalled != 1 {
+		t.Fatalf("expected one drift correction, got %d", action.execCalled)
+	}
+	stored, ok, err := repo.GetDeviceCapabilityState(context.Background(), "AA:BB:CC:DD:EE:05", "routing.enforce")
+	if err != nil {
+		t.Fatalf("GetDeviceCapabilityState returned error: %v", err)
+	}
+	if !ok || stored.State != "on" {
+		t.Fatalf("expected enforced state to stay on, got %+v", stored)
+	}
+	if len(repo.driftEvents) != 1 {
+		t.Fatalf("expected ongoing drift to be recorded once, got %d", len(repo.driftEvents))
+	}
+	if repo.driftEvents[0].Outcome != automationdomain.DriftOutcomeCorrected {
+		t.Fatalf("unexpected first drift outcome: %+v", repo.driftEvents[0])
+	}
+	if repo.driftEvents[0].ExpectedState != "on" || repo.driftEvents[0].ObservedState != "off" {
+		t.Fatalf("unexpected drift states: %+v", repo.driftEvents[0])
+	}
+
+	// Drift that clears and comes back is recorded again, still rate-limited.
+	source.value = true
+	if err := engine.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("SyncOnce returned error: %v", err)
+	}
+	source.value = false
+	if err := engine.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("SyncOnce returned error: %v", err)
+	}
+	if len(repo.driftEvents) != 2 {
+		t.Fatalf("expected reappearing drift to be recorded, got %d", len(repo.driftEvents))
+	}
+	if repo.driftEvents[1].Outcome != automationdomain.DriftOutcomeRateLimited {
+		t.Fatalf("unexpected second drift outcome: %+v", repo.driftEvents[1])
+	}
+}
+
 func TestEngineSetCapabilityStateGlobalPersistsState(t *testing.T) {
 	repo := newMemoryRepository()
 	action := &fakeAction{id: "test.action"}
@@ -594,6 +793,57 @@ func TestEngineSetCapabilityStateGlobalPersistsState(t *testing.T) {
 	}
 }
 
+func TestEnginePublishesCapabilityStateEvents(t *testing.T) {
+	repo := newMemoryRepository()
+	reg := registry.New()
+	repo.templates["global.vpn_profile"] = automationdomain.CapabilityTemplate{
+		ID:           "global.vpn_profile",
+		Label:        "Global VPN profile",
+		Scope:        automationdomain.ScopeGlobal,
+		Control:      automationdomain.CapabilityControl{Type: automationdomain.ControlSwitch, Options: []automationdomain.CapabilityControlOption{{Value: "on", Label: "On"}, {Value: "off", Label: "Off"}}},
+		DefaultState: "off",
+		States: map[string]automationdomain.CapabilityStateConfig{
+			"on":  {Label: "On"},
+			"off": {Label: "Off"},
+		},
+	}
+
+	bus := events.NewBus(8)
+	_, sub, _ := bus.Subscribe([]string{events.TopicCapability}, 0)
+	defer sub.Close()
+	engine := New(
+		repo,
+		&fakeDeviceService{devices: map[string]devicedomain.Device{}},
+		reg,
+		fakeConfigProvider{ok: true, cfg: model.RouterConfig{Host: "router.local"}},
+		&fakeRouterClient{membershipMap: map[string]bool{}},
+		nil,
+	).WithEvents(bus)
+
+	target := automationdomain.CapabilityTargetRef{Scope: automationdomain.ScopeGlobal}
+	if _, err := engine.SetCapabilityState(context.Background(), target, "global.vpn_profile", "on"); err != nil {
+		t.Fatalf("SetCapabilityState returned error: %v", err)
+	}
+	if _, err := engine.ApplyEnabledChange(context.Background(), target, repo.templates["global.vpn_profile"], "on", false); err != nil {
+		t.Fatalf("ApplyEnabledChange returned error: %v", err)
+	}
+
+	want := []events.CapabilityStateChange{
+		{Scope: "global", CapabilityID: "global.vpn_profile", Enabled: true, State: "on"},
+		{Scope: "global", CapabilityID: "global.vpn_profile", Enabled: false, State: "on"},
+	}
+	for i, expected := range want {
+		select {
+		case event := <-sub.C():
+			if event.Type != events.TypeCapabilityState || event.Data != expected {
+				t.Fatalf("event %d: unexpected %+v", i, event)
+			}
+		default:
+			t.Fatalf("event %d: not published", i)
+		}
+	}
+}
+
 func TestEngineSyncOnceGlobalUpdatesState(t *testing.T) {
 	repo := newMemoryRepository()
 	source := &fakeStateSource{id: "test.source", value: true}
@@ -654,3 +904,456 @@ func TestEngineSyncOnceGlobalUpdatesState(t *testing.T) {
 		t.Fatalf("expected synced state 'on', got %q", stored.State)
 	}
 }
+
+func TestEngineSyncOnceUsesStringSourceOutputAsState(t *testing.T) {
+	repo := newMemoryRepository()
+	source := &fakeValueStateSource{fakeStateSource: fakeStateSource{id: "test.query"}, state: "strict"}
+	reg := registry.New()
+	reg.RegisterStateSource(source)
+
+	repo.templates["global.filter_profile"] = automationdomain.CapabilityTemplate{
+		ID:           "global.filter_profile",
+		Label:        "Filter profile",
+		Scope:        automationdomain.ScopeGlobal,
+		Control:      automationdomain.CapabilityControl{Type: automationdomain.ControlSelect, Options: []automationdomain.CapabilityControlOption{{Value: "open"}, {Value: "strict"}}},
+		DefaultState: "open",
+		States: map[string]automationdomain.CapabilityStateConfig{
+			"open":   {Label: "Open"},
+			"strict": {Label: "Strict"},
+		},
+		Sync: &automationdomain.CapabilitySyncConfig{
+			Enabled: true,
+			Source:  automationdomain.CapabilitySyncSource{TypeID: "test.query", Params: map[string]any{}},
+			Mode:    automationdomain.SyncModeExternalTruth,
+		},
+	}
+	_ = repo.SaveGlobalCapability(context.Background(), &automationdomain.GlobalCapability{
+		CapabilityID: "global.filter_profile",
+		Enabled:      true,
+		State:        "open",
+	})
+
+	engine := New(
+		repo,
+		&fakeDeviceService{devices: map[string]devicedomain.Device{}},
+		reg,
+		fakeConfigProvider{ok: true, cfg: model.RouterConfig{Host: "router.local"}},
+		&fakeRouterClient{membershipMap: map[string]bool{}},
+		nil,
+	)
+
+	if err := engine.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("SyncOnce returned error: %v", err)
+	}
+	stored, _ := repo.GetGlobalCapability(context.Background(), "global.filter_profile")
+	if stored == nil || stored.State != "strict" {
+		t.Fatalf("expected synced state 'strict', got %+v", stored)
+	}
+
+	source.state = "unknown"
+	if err := engine.SyncOnce(context.Background()); err == nil || !strings.Contains(err.Error(), "not a declared state") {
+		t.Fatalf("expected undeclared state error, got %v", err)
+	}
+}
+
+func TestEngineHandleDeviceIPChangeCleansUpAndReappliesState(t *testing.T) {
+	repo := newMemoryRepository()
+	currentIP := "192.168.88.20"
+	deviceService := &fakeDeviceService{
+		devices: map[string]devicedomain.Device{
+			"AA:BB:CC:DD:EE:04": {
+				MAC:    "AA:BB:CC:DD:EE:04",
+				Name:   "Moved device",
+				Online: true,
+				LastIP: &currentIP,
+			},
+		},
+	}
+	action := &fakeCleanupAction{fakeAction: fakeAction{id: "test.cleanup"}}
+	reg := registry.New()
+	reg.RegisterAction(action)
+
+	repo.templates["routing.block"] = automationdomain.CapabilityTemplate{
+		ID:           "routing.block",
+		Label:        "Block",
+		Control:      automationdomain.CapabilityControl{Type: automationdomain.ControlSwitch, Options: []automationdomain.CapabilityControlOption{{Value: "on", Label: "On"}, {Value: "off", Label: "Off"}}},
+		DefaultState: "off",
+		States: map[string]automationdomain.CapabilityStateConfig{
+			"on": {
+				Label:          "On",
+				ActionsOnEnter: []automationdomain.ActionInstance{{ID: "a1", TypeID: "test.cleanup", Params: map[string]any{}}},
+			},
+			"off": {Label: "Off"},
+		},
+	}
+	_ = repo.UpsertDeviceCapabilityState(context.Background(), automationdomain.DeviceCapability{
+		DeviceID:     "AA:BB:CC:DD:EE:04",
+		CapabilityID: "routing.block",
+		Enabled:      true,
+		State:        "on",
+		UpdatedAt:    time.Now().UTC(),
+	})
+
+	engine := New(
+		repo,
+		deviceService,
+		reg,
+		fakeConfigProvider{ok: true, cfg: model.RouterConfig{Host: "router.local"}},
+		&fakeRouterClient{membershipMap: map[string]bool{}},
+		nil,
+	)
+
+	err := engine.HandleDeviceIPChange(context.Background(), devicedomain.IPChange{
+		MAC:        "aa:bb:cc:dd:ee:04",
+		PreviousIP: "192.168.88.10",
+		CurrentIP:  currentIP,
+	})
+	if err != nil {
+		t.Fatalf("HandleDeviceIPChange returned error: %v", err)
+	}
+	if len(action.cleanupIPs) != 0 || len(action.executeIPs) != 0 || len(engine.ipChanges) != 1 {
+		t.Fatalf("expected router work to be queued, got cleanup=%v execute=%v", action.cleanupIPs, action.executeIPs)
+	}
+	if err := engine.reapplyIPChange(context.Background(), <-engine.ipChanges); err != nil {
+		t.Fatalf("reapplyIPChange returned error: %v", err)
+	}
+	if len(action.cleanupIPs) != 1 || action.cleanupIPs[0] != "192.168.88.10" {
+		t.Fatalf("expected cleanup for previous IP, got %v", action.cleanupIPs)
+	}
+	if len(action.executeIPs) != 1 || action.executeIPs[0] != currentIP {
+		t.Fatalf("expected re-apply for current IP, got %v", action.executeIPs)
+	}
+}
+
+func TestEngineHandleDeviceIPChangeDropsWhenQueueFull(t *testing.T) {
+	engine := &Engine{ipChanges: make(chan devicedomain.IPChange, 1)}
+	first := devicedomain.IPChange{MAC: "aa:bb:cc:dd:ee:04", PreviousIP: "192.168.88.10"}
+	second := devicedomain.IPChange{MAC: "aa:bb:cc:dd:ee:05", PreviousIP: "192.168.88.11"}
+
+	for _, change := range []devicedomain.IPChange{first, second} {
+		if err := engine.HandleDeviceIPChange(context.Background(), change); err != nil {
+			t.Fatalf("HandleDeviceIPChange returned error: %v", err)
+		}
+	}
+	if len(engine.ipChanges) != 1 || <-engine.ipChanges != first {
+		t.Fatal("expected first change queued and overflow dropped")
+	}
+}
+
+type snapshotRouterClient struct {
+	fakeRouterClient
+	listCalls int
+	entries   map[string][]string
+}
+
+func (f *snapshotRouterClient) ListAddressListAddresses(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+) ([]string, error) {
+	f.listCalls++
+	return f.entries[list], nil
+}
+
+func TestSyncReadCacheSharesAddressListPrint(t *testing.T) {
+	client := &snapshotRouterClient{entries: map[string][]string{"vpn": {"10.0.0.1", "10.0.0.2"}}}
+	cache := newSyncReadCache(client, newRouterLimiter(1))
+	cfg := model.RouterConfig{Host: "router.local"}
+
+	for _, tc := range []struct {
+		address string
+		want    bool
+	}{
+		{address: "10.0.0.1", want: true},
+		{address: "10.0.0.2/32", want: true},
+		{address: "10.0.0.3", want: false},
+	} {
+		got, err := cache.AddressListContains(context.Background(), cfg, "vpn", tc.address)
+		if err != nil {
+			t.Fatalf("AddressListContains returned error: %v", err)
+		}
+		if got != tc.want {
+			t.Fatalf("AddressListContains(%q) = %v, want %v", tc.address, got, tc.want)
+		}
+	}
+	if client.listCalls != 1 {
+		t.Fatalf("expected one address-list print, got %d", client.listCalls)
+	}
+}
+
+func TestEngineSyncOnceRecordsSyncStatus(t *testing.T) {
+	repo := newMemoryRepository()
+	deviceService := &fakeDeviceService{
+		devices: map[string]devicedomain.Device{
+			"AA:BB:CC:DD:EE:06": {MAC: "AA:BB:CC:DD:EE:06", Online: true},
+			"AA:BB:CC:DD:EE:07": {MAC: "AA:BB:CC:DD:EE:07", Online: true},
+		},
+	}
+
+	reg := registry.New()
+	reg.RegisterStateSource(&fakeStateSource{id: "test.source", value: true})
+
+	repo.templates["routing.status"] = automationdomain.CapabilityTemplate{
+		ID:           "routing.status",
+		Label:        "Status capability",
+		Control:      automationdomain.CapabilityControl{Type: automationdomain.ControlSwitch, Options: []automationdomain.CapabilityControlOption{{Value: "on", Label: "On"}, {Value: "off", Label: "Off"}}},
+		DefaultState: "off",
+		States: map[string]automationdomain.CapabilityStateConfig{
+			"on":  {Label: "On"},
+			"off": {Label: "Off"},
+		},
+		Sync: &automationdomain.CapabilitySyncConfig{
+			Enabled: true,
+			Source: automationdomain.CapabilitySyncSource{
+				TypeID: "test.source",
+				Params: map[string]any{},
+			},
+			Mapping: automationdomain.CapabilitySyncMapping{
+				WhenTrue:  "on",
+				WhenFalse: "off",
+			},
+			Mode:        automationdomain.SyncModeExternalTruth,
+			IntervalSec: 90,
+		},
+	}
+
+	engine := New(
+		repo,
+		deviceService,
+		reg,
+		fakeConfigProvider{ok: true, cfg: model.RouterConfig{Host: "router.local"}},
+		&fakeRouterClient{membershipMap: map[string]bool{}},
+		nil,
+	).WithSyncWorkers(2)
+
+	if err := engine.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("SyncOnce returned error: %v", err)
+	}
+
+	statuses, err := engine.SyncStatuses(context.Background())
+	if err != nil {
+		t.Fatalf("SyncStatuses returned error: %v", err)
+	}
+	if len(statuses) != 1 {
+		t.Fatalf("expected one sync status, got %d", len(statuses))
+	}
+	status := statuses[0]
+	if status.CapabilityID != "routing.status" || status.IntervalSec != 90 || status.Targets != 2 {
+		t.Fatalf("unexpected sync status: %+v", status)
+	}
+	if status.Running || status.LastRunAt == nil || status.NextRunAt == nil || status.LastError != "" {
+		t.Fatalf("unexpected sync run details: %+v", status)
+	}
+	if !status.NextRunAt.After(*status.LastRunAt) {
+		t.Fatalf("expected next run after last run: %+v", status)
+	}
+}
+
+func TestEngineSyncOncePrefersBatchStateSource(t *testing.T) {
+	repo := newMemoryRepository()
+	deviceService := &fakeDeviceService{
+		devices: map[string]devicedomain.Device{
+			"AA:BB:CC:DD:EE:08": {MAC: "AA:BB:CC:DD:EE:08", Online: true},
+			"AA:BB:CC:DD:EE:09": {MAC: "AA:BB:CC:DD:EE:09", Online: true},
+		},
+	}
+
+	source := &fakeBatchStateSource{
+		fakeStateSource: fakeStateSource{id: "test.batch"},
+		values:          map[string]bool{"AA:BB:CC:DD:EE:08": true},
+	}
+	reg := registry.New()
+	reg.RegisterStateSource(source)
+
+	repo.templates["routing.batch"] = automationdomain.CapabilityTemplate{
+		ID:           "routing.batch",
+		Label:        "Batch capability",
+		Control:      automationdomain.CapabilityControl{Type: automationdomain.ControlSwitch, Options: []automationdomain.CapabilityControlOption{{Value: "on", Label: "On"}, {Value: "off", Label: "Off"}}},
+		DefaultState: "off",
+		States: map[string]automationdomain.CapabilityStateConfig{
+			"on":  {Label: "On"},
+			"off": {Label: "Off"},
+		},
+		Sync: &automationdomain.CapabilitySyncConfig{
+			Enabled: true,
+			Source: automationdomain.CapabilitySyncSource{
+				TypeID: "test.batch",
+				Params: map[string]any{},
+			},
+			Mapping: automationdomain.CapabilitySyncMapping{
+				WhenTrue:  "on",
+				WhenFalse: "off",
+			},
+			Mode: automationdomain.SyncModeExternalTruth,
+		},
+	}
+
+	engine := New(
+		repo,
+		deviceService,
+		reg,
+		fakeConfigProvider{ok: true, cfg: model.RouterConfig{Host: "router.local"}},
+		&fakeRouterClient{membershipMap: map[string]bool{}},
+		nil,
+	)
+
+	if err := engine.SyncOnce(context.Background()); err != nil {
+		t.Fatalf("SyncOnce returned error: %v", err)
+	}
+	if source.readManyCalls != 1 || source.readCalls != 0 {
+		t.Fatalf("expected one batch read, got batch=%d single=%d", source.readManyCalls, source.readCalls)
+	}
+
+	on, ok, err := repo.GetDeviceCapabilityState(context.Background(), "AA:BB:CC:DD:EE:08", "routing.batch")
+	if err != nil || !ok || on.State != "on" {
+		t.Fatalf("unexpected state for first device: %+v ok=%v err=%v", on, ok, err)
+	}
+	if _, ok, _ := repo.GetDeviceCapabilityState(context.Background(), "AA:BB:CC:DD:EE:09", "routing.batch"); ok {
+		t.Fatalf("expected second device to keep default state without write")
+	}
+}
+
+type recordingAction struct {
+	fakeAction
+	calls []string
+}
+
+func (a *recordingAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	tag, _ := params["tag"].(string)
+	a.calls = append(a.calls, tag)
+	return nil
+}
+
+func TestEngineRunsExitTransitionEnterAndDisableHooksInOrder(t *testing.T) {
+	repo := newMemoryRepository()
+	deviceService := &fakeDeviceService{devices: map[string]devicedomain.Device{
+		"AA:BB:CC:DD:EE:01": {MAC: "AA:BB:CC:DD:EE:01"},
+	}}
+	action := &recordingAction{fakeAction: fakeAction{id: "test.record"}}
+	reg := registry.New()
+	reg.RegisterAction(action)
+
+	step := func(tag string) []automationdomain.ActionInstance {
+		return []automationdomain.ActionInstance{{ID: tag, TypeID: "test.record", Params: map[string]any{"tag": tag}}}
+	}
+	template := automationdomain.CapabilityTemplate{
+		ID:           "kids.internet",
+		DefaultState: "allowed",
+		States: map[string]automationdomain.CapabilityStateConfig{
+			"allowed": {ActionsOnEnter: step("enter-allowed"), ActionsOnExit: step("exit-allowed")},
+			"blocked": {ActionsOnEnter: step("enter-blocked"), ActionsOnExit: step("exit-blocked")},
+		},
+		Transitions: []automationdomain.CapabilityTransition{
+			{From: "allowed", To: "blocked", Actions: step("allowed-to-blocked")},
+			{From: "blocked", To: "allowed", Actions: step("blocked-to-allowed")},
+		},
+		ActionsOnDisable: step("disable"),
+	}
+	repo.templates[template.ID] = template
+
+	engine := New(
+		repo,
+		deviceService,
+		reg,
+		fakeConfigProvider{ok: true, cfg: model.RouterConfig{Host: "router.local"}},
+		&fakeRouterClient{membershipMap: map[string]bool{}},
+		nil,
+	)
+	ref := automationdomain.CapabilityTargetRef{Scope: automationdomain.ScopeDevice, DeviceID: "AA:BB:CC:DD:EE:01"}
+	ctx := context.Background()
+
+	if _, err := engine.SetCapabilityState(ctx, ref, template.ID, "blocked"); err != nil {
+		t.Fatalf("SetCapabilityState returned error: %v", err)
+	}
+	if _, err := engine.ApplyEnabledChange(ctx, ref, template, "blocked", false); err != nil {
+		t.Fatalf("disable returned error: %v", err)
+	}
+	if _, err := engine.ApplyEnabledChange(ctx, ref, template, "blocked", true); err != nil {
+		t.Fatalf("enable returned error: %v", err)
+	}
+
+	// State change of disabled target skips exit and transition actions.
+	_ = repo.UpsertDeviceCapabilityState(ctx, automationdomain.DeviceCapability{
+		DeviceID:     ref.DeviceID,
+		CapabilityID: template.ID,
+		Enabled:      false,
+		State:        "blocked",
+	})
+	if _, err := engine.SetCapabilityState(ctx, ref, template.ID, "allowed"); err != nil {
+		t.Fatalf("SetCapabilityState from disabled returned error: %v", err)
+	}
+
+	want := "exit-allowed,allowed-to-blocked,enter-blocked,disable,enter-blocked,enter-allowed"
+	if got := strings.Join(action.calls, ","); got != want {
+		t.Fatalf("unexpected action order:\n got %s\nwant %s", got, want)
+	}
+}
+
+type routerWriteAction struct {
+	fakeAction
+}
+
+func (a *routerWriteAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	a.execCalled++
+	return execCtx.RouterClient.AddAddressListEntry(ctx, execCtx.RouterConfig, "cleanup", *execCtx.Target.Device.LastIP)
+}
+
+func TestEngineRunDisableHooksSkipsUnstoredTargetsAndLedger(t *testing.T) {
+	repo := newMemoryRepository()
+	devices := map[string]devicedomain.Device{}
+	for i, mac := range []string{"AA:BB:CC:DD:EE:21", "AA:BB:CC:DD:EE:22", "AA:BB:CC:DD:EE:23"} {
+		ip := fmt.Sprintf("192.168.88.%d", 21+i)
+		devices[mac] = devicedomain.Device{MAC: mac, Name: mac, LastIP: &ip}
+	}
+	action := &routerWriteAction{fakeAction: fakeAction{id: "test.write"}}
+	reg := registry.New()
+	reg.RegisterAction(action)
+
+	template := automationdomain.CapabilityTemplate{
+		ID:               "routing.block",
+		Scope:            automationdomain.ScopeDevice,
+		DefaultState:     "off",
+		States:           map[string]automationdomain.CapabilityStateConfig{"off": {Label: "Off"}},
+		ActionsOnDisable: []automationdomain.ActionInstance{{ID: "a1", TypeID: "test.write", Params: map[string]any{}}},
+	}
+	ctx := context.Background()
+	_ = repo.UpsertDeviceCapabilityState(ctx, automationdomain.DeviceCapability{
+		DeviceID: "AA:BB:CC:DD:EE:21", CapabilityID: template.ID, Enabled: true, State: "off",
+	})
+	_ = repo.UpsertDeviceCapabilityState(ctx, automationdomain.DeviceCapability{
+		DeviceID: "AA:BB:CC:DD:EE:22", CapabilityID: template.ID, Enabled: false, State: "off",
+	})
+
+	router := &fakeRouterClient{membershipMap: map[string]bool{}}
+	ledger := &memoryLedger{rows: map[string]automationdomain.RouterArtefact{}}
+	engine := New(
+		repo,
+		&fakeDeviceService{devices: devices},
+		reg,
+		fakeConfigProvider{ok: true, cfg: model.RouterConfig{Host: "router.local"}},
+		router,
+		nil,
+	).WithLedger(ledger)
+
+	if err := engine.RunDisableHooks(ctx, template); err != nil {
+		t.Fatalf("RunDisableHooks returned error: %v", err)
+	}
+	if err := engine.Wait(ctx); err != nil {
+		t.Fatalf("Wait returned error: %v", err)
+	}
+
+	if action.execCalled != 1 || router.addCalls != 1 {
+		t.Fatalf("expected hooks for stored enabled target only, got exec=%d add=%d", action.execCalled, router.addCalls)
+	}
+	if len(ledger.rows) != 0 {
+		t.Fatalf("expected disable hook writes to bypass ledger, got %+v", ledger.rows)
+	}
+}
@@ -0,0 +1,124 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+)
+
+const (
+	ipChangeQueueSize = 256
+	ipChangeTimeout   = 2 * time.Minute
+)
+
+// HandleDeviceIPChange queues re-apply of device states after DHCP moved the
+// device; RunIPChanges does the router work so polling is not held up. When
+// queue is full the change is dropped with a warning; next sync re-applies
+// states, only stale entries for the previous IP stay behind.
+func (e *Engine) HandleDeviceIPChange(_ context.Context, change devicedomain.IPChange) error {
+	if normalizeDeviceID(change.MAC) == "" || strings.TrimSpace(change.PreviousIP) == "" {
+		return nil
+	}
+	select {
+	case e.ipChanges <- change:
+	default:
+		if e.logger != nil {
+			e.logger.Warn("device ip change queue full, change dropped", "device_mac", change.MAC, "previous_ip", change.PreviousIP)
+		}
+	}
+	return nil
+}
+
+// RunIPChanges re-applies queued IP changes one at a time until ctx is
+// cancelled; each change gets a bounded timeout.
+func (e *Engine) RunIPChanges(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case change := <-e.ipChanges:
+			changeCtx, cancel := context.WithTimeout(ctx, ipChangeTimeout)
+			err := e.reapplyIPChange(changeCtx, change)
+			cancel()
+			if err != nil && e.logger != nil {
+				e.logger.Warn("device ip change re-apply failed", "device_mac", change.MAC, "err", err)
+			}
+		}
+	}
+}
+
+// reapplyIPChange removes stale router entries for the previous IP through
+// actions implementing automation.StaleTargetCleaner, then executes
+// ActionsOnEnter of every enabled stored state against current device snapshot.
+func (e *Engine) reapplyIPChange(ctx context.Context, change devicedomain.IPChange) error {
+	deviceID := normalizeDeviceID(change.MAC)
+	previousIP := strings.TrimSpace(change.PreviousIP)
+
+	device, err := e.requireDevice(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	states, err := e.repo.ListDeviceCapabilityStates(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	currentDevice := device
+	previousDevice := device
+	previousDevice.LastIP = &previousIP
+	currentTarget := automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &currentDevice}
+	previousTarget := automationdomain.AutomationTarget{Scope: automationdomain.ScopeDevice, Device: &previousDevice}
+
+	capabilityIDs := make([]string, 0, len(states))
+	for capabilityID := range states {
+		capabilityIDs = append(capabilityIDs, capabilityID)
+	}
+	sort.Strings(capabilityIDs)
+
+	var reapplyErrors []error
+	for _, capabilityID := range capabilityIDs {
+		stored := states[capabilityID]
+		if !stored.Enabled {
+			continue
+		}
+		template, err := e.repo.GetTemplate(ctx, capabilityID)
+		if errors.Is(err, automationdomain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			reapplyErrors = append(reapplyErrors, fmt.Errorf("capability %s: %w", capabilityID, err))
+			continue
+		}
+		if automationdomain.NormalizeCapabilityScope(template.Scope) != automationdomain.ScopeDevice {
+			continue
+		}
+
+		state := strings.TrimSpace(stored.State)
+		if state == "" {
+			state = strings.TrimSpace(template.DefaultState)
+		}
+		stateConfig, ok := template.States[state]
+		if !ok || len(stateConfig.ActionsOnEnter) == 0 {
+			continue
+		}
+
+		warnings := e.runStateActions(ctx, previousTarget, capabilityID, state, stateConfig.ActionsOnEnter, actionOperationCleanup)
+		warnings = append(warnings, e.executeStateActions(ctx, currentTarget, capabilityID, state, stateConfig.ActionsOnEnter)...)
+		if len(warnings) > 0 && e.logger != nil {
+			e.logger.Warn(
+				"device ip change re-apply finished with warnings",
+				"device_mac", deviceID,
+				"capability_id", capabilityID,
+				"state", state,
+				"warnings", len(warnings),
+			)
+		}
+	}
+	return errors.Join(reapplyErrors...)
+}
@@ -0,0 +1,534 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+// contentFilterArtefactPath keys content filters by comment only, so the
+// artefact survives device IP changes.
+const contentFilterArtefactPath = "/ip/firewall/filter"
+
+// Kid-control artefacts are keyed by profile name and MAC under fixed menus.
+const (
+	kidControlArtefactPath       = "/ip/kid-control"
+	kidControlDeviceArtefactPath = "/ip/kid-control/device"
+)
+
+// DNS artefacts are keyed by adlist source or record name under fixed menus.
+const (
+	dnsAdlistArtefactPath = "/ip/dns/adlist"
+	dnsStaticArtefactPath = "/ip/dns/static"
+)
+
+// Wifi access artefacts are keyed by MAC under one menu whatever driver the
+// router runs; removal covers every installed driver.
+const wifiAccessArtefactPath = "/interface/wifi/access-list"
+
+// ledgerRouterClient records router writes made by actions in ownership
+// ledger. Reads pass through; ledger failures are logged and never fail the
+// action because the router write already happened.
+type ledgerRouterClient struct {
+	RouterClient
+	ledger automationdomain.LedgerRepository
+	owner  automationdomain.OwnershipTag
+	logger *slog.Logger
+}
+
+// WithLedger enables recording of router writes made by actions.
+func (e *Engine) WithLedger(ledger automationdomain.LedgerRepository) *Engine {
+	e.ledger = ledger
+	return e
+}
+
+func (e *Engine) actionRouterClient(capabilityID string, target automationdomain.AutomationTarget) RouterClient {
+	if e.ledger == nil {
+		return e.routerClient
+	}
+	owner := automationdomain.OwnershipTag{CapabilityID: capabilityID}
+	if target.Device != nil && automationdomain.NormalizeCapabilityScope(target.Scope) == automationdomain.ScopeDevice {
+		owner.DeviceID = target.Device.MAC
+	}
+	return &ledgerRouterClient{
+		RouterClient: e.routerClient,
+		ledger:       e.ledger,
+		owner:        owner,
+		logger:       e.logger,
+	}
+}
+
+func (c *ledgerRouterClient) artefact(kind automationdomain.ArtefactKind, path, key string) automationdomain.RouterArtefact {
+	return automationdomain.RouterArtefact{
+		CapabilityID: c.owner.CapabilityID,
+		DeviceID:     c.owner.DeviceID,
+		Kind:         kind,
+		Path:         strings.TrimSpace(path),
+		Key:          strings.TrimSpace(key),
+	}
+}
+
+func (c *ledgerRouterClient) record(ctx context.Context, artefact automationdomain.RouterArtefact) {
+	if err := c.ledger.UpsertArtefact(ctx, artefact); err != nil && c.logger != nil {
+		c.logger.Warn("record router artefact failed", "kind", artefact.Kind, "path", artefact.Path, "key", artefact.Key, "err", err)
+	}
+}
+
+func (c *ledgerRouterClient) forget(ctx context.Context, artefact automationdomain.RouterArtefact) {
+	if err := c.ledger.DeleteArtefact(ctx, artefact); err != nil && c.logger != nil {
+		c.logger.Warn("forget router artefact failed", "kind", artefact.Kind, "path", artefact.Path, "key", artefact.Key, "err", err)
+	}
+}
+
+func (c *ledgerRouterClient) AddAddressListEntry(ctx context.Context, cfg model.RouterConfig, list, address string) error {
+	return c.AddManagedAddressListEntry(ctx, cfg, list, address, automationdomain.OwnershipComment(c.owner), "")
+}
+
+// AddManagedAddressListEntry records entry only when this call created it; an
+// entry already in place may be the user's and must survive GC.
+func (c *ledgerRouterClient) AddManagedAddressListEntry(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list, address, comment, timeout string,
+) error {
+	if _, ok := c.RouterClient.(automationdomain.OwnedAddressListClient); ok {
+		_, err := c.EnsureOwnedAddressListEntry(ctx, cfg, list, address, comment, timeout)
+		return err
+	}
+	if managed, ok := c.RouterClient.(automationdomain.ManagedAddressListClient); ok {
+		return managed.AddManagedAddressListEntry(ctx, cfg, list, address, comment, timeout)
+	}
+	if timeout != "" {
+		return fmt.Errorf("router client does not support address-list timeouts")
+	}
+	return c.RouterClient.AddAddressListEntry(ctx, cfg, list, address)
+}
+
+func (c *ledgerRouterClient) EnsureOwnedAddressListEntry(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list, address, comment, timeout string,
+) (bool, error) {
+	owned, ok := c.RouterClient.(automationdomain.OwnedAddressListClient)
+	if !ok {
+		return false, fmt.Errorf("router client does not support owned address-list entries")
+	}
+	created, err := owned.EnsureOwnedAddressListEntry(ctx, cfg, list, address, comment, timeout)
+	if err != nil {
+		return false, err
+	}
+	if created {
+		c.record(ctx, c.artefact(automationdomain.ArtefactAddressListEntry, list, address))
+	}
+	return created, nil
+}
+
+func (c *ledgerRouterClient) RemoveOwnedAddressListEntry(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list, address, comment string,
+) error {
+	owned, ok := c.RouterClient.(automationdomain.OwnedAddressListClient)
+	if !ok {
+		return fmt.Errorf("router client does not support owned address-list entries")
+	}
+	if err := owned.RemoveOwnedAddressListEntry(ctx, cfg, list, address, comment); err != nil {
+		return err
+	}
+	c.forget(ctx, c.artefact(automationdomain.ArtefactAddressListEntry, list, address))
+	return nil
+}
+
+func (c *ledgerRouterClient) RemoveAddressListEntry(ctx context.Context, cfg model.RouterConfig, list, address string) error {
+	if err := c.RouterClient.RemoveAddressListEntry(ctx, cfg, list, address); err != nil {
+		return err
+	}
+	c.forget(ctx, c.artefact(automationdomain.ArtefactAddressListEntry, list, address))
+	return nil
+}
+
+func (c *ledgerRouterClient) SetFirewallRuleDisabled(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	table, ruleID string,
+	disabled bool,
+) error {
+	artefact := c.artefact(automationdomain.ArtefactFirewallRule, table, ruleID)
+	restore, known, err := c.firstWriteRestore(ctx, artefact, func() (bool, error) {
+		enabled, err := c.RouterClient.GetFirewallRuleEnabled(ctx, cfg, table, ruleID)
+		return !enabled, err
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.RouterClient.SetFirewallRuleDisabled(ctx, cfg, table, ruleID, disabled); err != nil {
+		return err
+	}
+	if !known {
+		artefact.Restore = restore
+		c.record(ctx, artefact)
+	}
+	return nil
+}
+
+func (c *ledgerRouterClient) SetFirewallRulesDisabledByComment(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	table, comment string,
+	disabled bool,
+) error {
+	artefact := c.artefact(automationdomain.ArtefactFirewallComment, table, comment)
+	restore, known, err := c.firstWriteRestore(ctx, artefact, func() (bool, error) {
+		enabled, err := c.RouterClient.GetFirewallRulesEnabledByComment(ctx, cfg, table, comment)
+		return !enabled, err
+	})
+	if err != nil {
+		return err
+	}
+	if err := c.RouterClient.SetFirewallRulesDisabledByComment(ctx, cfg, table, comment, disabled); err != nil {
+		return err
+	}
+	if !known {
+		artefact.Restore = restore
+		c.record(ctx, artefact)
+	}
+	return nil
+}
+
+func (c *ledgerRouterClient) SetSimpleQueueLimit(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	target, comment string,
+	limit model.QueueLimit,
+) error {
+	queueClient, ok := c.RouterClient.(automationdomain.SimpleQueueClient)
+	if !ok {
+		return fmt.Errorf("router client does not support simple queues")
+	}
+	if err := queueClient.SetSimpleQueueLimit(ctx, cfg, target, comment, limit); err != nil {
+		return err
+	}
+	c.record(ctx, c.artefact(automationdomain.ArtefactSimpleQueue, target, comment))
+	return nil
+}
+
+func (c *ledgerRouterClient) RemoveSimpleQueueLimit(ctx context.Context, cfg model.RouterConfig, target, comment string) error {
+	queueClient, ok := c.RouterClient.(automationdomain.SimpleQueueClient)
+	if !ok {
+		return fmt.Errorf("router client does not support simple queues")
+	}
+	if err := queueClient.RemoveSimpleQueueLimit(ctx, cfg, target, comment); err != nil {
+		return err
+	}
+	c.forget(ctx, c.artefact(automationdomain.ArtefactSimpleQueue, target, comment))
+	return nil
+}
+
+func (c *ledgerRouterClient) SetContentFilter(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	address, comment string,
+	filter model.ContentFilter,
+) error {
+	filterClient, ok := c.RouterClient.(automationdomain.ContentFilterClient)
+	if !ok {
+		return fmt.Errorf("router client does not support content filters")
+	}
+	if err := filterClient.SetContentFilter(ctx, cfg, address, comment, filter); err != nil {
+		return err
+	}
+	c.record(ctx, c.artefact(automationdomain.ArtefactContentFilter, contentFilterArtefactPath, comment))
+	return nil
+}
+
+func (c *ledgerRouterClient) RemoveContentFilter(ctx context.Context, cfg model.RouterConfig, comment string) error {
+	filterClient, ok := c.RouterClient.(automationdomain.ContentFilterClient)
+	if !ok {
+		return fmt.Errorf("router client does not support content filters")
+	}
+	if err := filterClient.RemoveContentFilter(ctx, cfg, comment); err != nil {
+		return err
+	}
+	c.forget(ctx, c.artefact(automationdomain.ArtefactContentFilter, contentFilterArtefactPath, comment))
+	return nil
+}
+
+func (c *ledgerRouterClient) SetKidControlPaused(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	profile string,
+	paused bool,
+) error {
+	kidClient, ok := c.RouterClient.(automationdomain.KidControlClient)
+	if !ok {
+		return fmt.Errorf("router client does not support kid-control")
+	}
+	artefact := c.artefact(automationdomain.ArtefactKidControlPause, kidControlArtefactPath, profile)
+	restore, known, err := c.firstWriteRestore(ctx, artefact, func() (bool, error) {
+		return kidClient.GetKidControlPaused(ctx, cfg, profile)
+	})
+	if err != nil {
+		return err
+	}
+	if err := kidClient.SetKidControlPaused(ctx, cfg, profile, paused); err != nil {
+		return err
+	}
+	if !known {
+		artefact.Restore = restore
+		c.record(ctx, artefact)
+	}
+	return nil
+}
+
+func (c *ledgerRouterClient) GetKidControlPaused(ctx context.Context, cfg model.RouterConfig, profile string) (bool, error) {
+	kidClient, ok := c.RouterClient.(automationdomain.KidControlStateClient)
+	if !ok {
+		return false, fmt.Errorf("router client does not support kid-control")
+	}
+	return kidClient.GetKidControlPaused(ctx, cfg, profile)
+}
+
+func (c *ledgerRouterClient) KidControlProfileForMAC(ctx context.Context, cfg model.RouterConfig, mac string) (string, error) {
+	kidClient, ok := c.RouterClient.(automationdomain.KidControlStateClient)
+	if !ok {
+		return "", fmt.Errorf("router client does not support kid-control")
+	}
+	return kidClient.KidControlProfileForMAC(ctx, cfg, mac)
+}
+
+func (c *ledgerRouterClient) BindKidControlDevice(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	profile, mac, name, comment string,
+) error {
+	kidClient, ok := c.RouterClient.(automationdomain.KidControlClient)
+	if !ok {
+		return fmt.Errorf("router client does not support kid-control")
+	}
+	if err := kidClient.BindKidControlDevice(ctx, cfg, profile, mac, name, comment); err != nil {
+		return err
+	}
+	c.record(ctx, c.artefact(automationdomain.ArtefactKidControlDevice, kidControlDeviceArtefactPath, mac))
+	return nil
+}
+
+func (c *ledgerRouterClient) UnbindKidControlDevice(ctx context.Context, cfg model.RouterConfig, mac, comment string) error {
+	kidClient, ok := c.RouterClient.(automationdomain.KidControlClient)
+	if !ok {
+		return fmt.Errorf("router client does not support kid-control")
+	}
+	if err := kidClient.UnbindKidControlDevice(ctx, cfg, mac, comment); err != nil {
+		return err
+	}
+	c.forget(ctx, c.artefact(automationdomain.ArtefactKidControlDevice, kidControlDeviceArtefactPath, mac))
+	return nil
+}
+
+func (c *ledgerRouterClient) SetDNSAdlistsEnabled(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	source string,
+	enabled bool,
+) error {
+	adlistClient, ok := c.RouterClient.(automationdomain.DNSAdlistClient)
+	if !ok {
+		return fmt.Errorf("router client does not support dns adlists")
+	}
+	artefact := c.artefact(automationdomain.ArtefactDNSAdlist, dnsAdlistArtefactPath, source)
+	restore, known, err := c.firstWriteRestore(ctx, artefact, func() (bool, error) {
+		return adlistClient.GetDNSAdlistsEnabled(ctx, cfg, source)
+	})
+	if err != nil {
+		return err
+	}
+	if err := adlistClient.SetDNSAdlistsEnabled(ctx, cfg, source, enabled); err != nil {
+		return err
+	}
+	if !known {
+		artefact.Restore = restore
+		c.record(ctx, artefact)
+	}
+	return nil
+}
+
+func (c *ledgerRouterClient) GetDNSAdlistsEnabled(ctx context.Context, cfg model.RouterConfig, source string) (bool, error) {
+	adlistClient, ok := c.RouterClient.(automationdomain.DNSAdlistStateClient)
+	if !ok {
+		return false, fmt.Errorf("router client does not support dns adlists")
+	}
+	return adlistClient.GetDNSAdlistsEnabled(ctx, cfg, source)
+}
+
+func (c *ledgerRouterClient) EnsureDNSStaticRecord(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	record model.DNSStaticRecord,
+	comment string,
+) error {
+	dnsClient, ok := c.RouterClient.(automationdomain.DNSStaticClient)
+	if !ok {
+		return fmt.Errorf("router client does not support static dns")
+	}
+	if err := dnsClient.EnsureDNSStaticRecord(ctx, cfg, record, comment); err != nil {
+		return err
+	}
+	c.record(ctx, c.artefact(automationdomain.ArtefactDNSStatic, dnsStaticArtefactPath, strings.ToLower(record.Name)))
+	return nil
+}
+
+func (c *ledgerRouterClient) RemoveDNSStaticRecord(ctx context.Context, cfg model.RouterConfig, name, comment string) error {
+	dnsClient, ok := c.RouterClient.(automationdomain.DNSStaticClient)
+	if !ok {
+		return fmt.Errorf("router client does not support static dns")
+	}
+	if err := dnsClient.RemoveDNSStaticRecord(ctx, cfg, name, comment); err != nil {
+		return err
+	}
+	c.forget(ctx, c.artefact(automationdomain.ArtefactDNSStatic, dnsStaticArtefactPath, strings.ToLower(name)))
+	return nil
+}
+
+func (c *ledgerRouterClient) EnsureWiFiAccessRule(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	mac string,
+	policy model.WiFiAccessPolicy,
+	comment string,
+) error {
+	wifiClient, ok := c.RouterClient.(automationdomain.WiFiAccessClient)
+	if !ok {
+		return fmt.Errorf("router client does not support wifi access lists")
+	}
+	if err := wifiClient.EnsureWiFiAccessRule(ctx, cfg, mac, policy, comment); err != nil {
+		return err
+	}
+	c.record(ctx, c.artefact(automationdomain.ArtefactWiFiAccess, wifiAccessArtefactPath, mac))
+	return nil
+}
+
+func (c *ledgerRouterClient) RemoveWiFiAccessRule(ctx context.Context, cfg model.RouterConfig, mac, comment string) error {
+	wifiClient, ok := c.RouterClient.(automationdomain.WiFiAccessClient)
+	if !ok {
+		return fmt.Errorf("router client does not support wifi access lists")
+	}
+	if err := wifiClient.RemoveWiFiAccessRule(ctx, cfg, mac, comment); err != nil {
+		return err
+	}
+	c.forget(ctx, c.artefact(automationdomain.ArtefactWiFiAccess, wifiAccessArtefactPath, mac))
+	return nil
+}
+
+func (c *ledgerRouterClient) GetWiFiAccessDenied(ctx context.Context, cfg model.RouterConfig, mac string) (bool, error) {
+	wifiClient, ok := c.RouterClient.(automationdomain.WiFiAccessStateClient)
+	if !ok {
+		return false, fmt.Errorf("router client does not support wifi access lists")
+	}
+	return wifiClient.GetWiFiAccessDenied(ctx, cfg, mac)
+}
+
+// DisconnectWiFiClient passes through; dropped registrations leave nothing to own.
+func (c *ledgerRouterClient) DisconnectWiFiClient(ctx context.Context, cfg model.RouterConfig, mac string) (int, error) {
+	wifiClient, ok := c.RouterClient.(automationdomain.WiFiAccessClient)
+	if !ok {
+		return 0, fmt.Errorf("router client does not support wifi access lists")
+	}
+	return wifiClient.DisconnectWiFiClient(ctx, cfg, mac)
+}
+
+// KillConnections passes through; killed connections leave nothing to own.
+func (c *ledgerRouterClient) KillConnections(ctx context.Context, cfg model.RouterConfig, address string) (int, error) {
+	connectionClient, ok := c.RouterClient.(automationdomain.ConnectionClient)
+	if !ok {
+		return 0, fmt.Errorf("router client does not support connection tracking")
+	}
+	return connectionClient.KillConnections(ctx, cfg, address)
+}
+
+// WakeOnLAN passes through; magic packets leave nothing to own.
+func (c *ledgerRouterClient) WakeOnLAN(ctx context.Context, cfg model.RouterConfig, mac, iface string) error {
+	wolClient, ok := c.RouterClient.(automationdomain.WakeOnLANClient)
+	if !ok {
+		return fmt.Errorf("router client does not support wake-on-lan")
+	}
+	return wolClient.WakeOnLAN(ctx, cfg, mac, iface)
+}
+
+func (c *ledgerRouterClient) ListAddressListAddresses(ctx context.Context, cfg model.RouterConfig, list string) ([]string, error) {
+	snapshotClient, ok := c.RouterClient.(automationdomain.AddressListSnapshotClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support address-list snapshots")
+	}
+	return snapshotClient.ListAddressListAddresses(ctx, cfg, list)
+}
+
+func (c *ledgerRouterClient) ListLimitedQueueTargets(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	queueClient, ok := c.RouterClient.(automationdomain.SimpleQueueStateClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support simple queues")
+	}
+	return queueClient.ListLimitedQueueTargets(ctx, cfg)
+}
+
+func (c *ledgerRouterClient) ListContentFilteredTargets(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	filterClient, ok := c.RouterClient.(automationdomain.ContentFilterStateClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support content filters")
+	}
+	return filterClient.ListContentFilteredTargets(ctx, cfg)
+}
+
+// firstWriteRestore captures original flag value ("disabled" of a rule,
+// "paused" of a kid-control profile, "enabled" of adlists) before first write
+// by this owner; known reports that ledger already holds the artefact. Failed
+// read is returned so write is skipped rather than recorded without restore.
+func (c *ledgerRouterClient) firstWriteRestore(
+	ctx context.Context,
+	artefact automationdomain.RouterArtefact,
+	readOriginal func() (bool, error),
+) (string, bool, error) {
+	if _, found, err := c.ledger.GetArtefact(ctx, artefact); err == nil && found {
+		return "", true, nil
+	}
+	original, err := readOriginal()
+	if err != nil {
+		return "", false, fmt.Errorf("read original %s %s/%s: %w", artefact.Kind, artefact.Path, artefact.Key, err)
+	}
+	return strconv.FormatBool(original), false, nil
+}
+
+func (c *ledgerRouterClient) RunCommand(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	path string,
+	params map[string]string,
+) ([]map[string]string, error) {
+	commandClient, ok := c.RouterClient.(automationdomain.RouterCommandClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support raw commands")
+	}
+	rows, err := commandClient.RunCommand(ctx, cfg, path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	menu, verb, splitErr := automationdomain.SplitCommandPath(path)
+	if splitErr != nil {
+		return rows, nil
+	}
+	switch verb {
+	case "add":
+		if len(rows) == 1 && rows[0]["ret"] != "" {
+			c.record(ctx, c.artefact(automationdomain.ArtefactRouterObject, menu, rows[0]["ret"]))
+		}
+	case "remove":
+		if id := params[".id"]; id != "" {
+			c.forget(ctx, c.artefact(automationdomain.ArtefactRouterObject, menu, id))
+		}
+	}
+	return rows, nil
+}
@@ -0,0 +1,220 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type memoryLedger struct {
+	rows map[string]automationdomain.RouterArtefact
+}
+
+func ledgerKey(a automationdomain.RouterArtefact) string {
+	return a.CapabilityID + "|" + a.DeviceID + "|" + string(a.Kind) + "|" + a.Path + "|" + a.Key
+}
+
+func (m *memoryLedger) GetArtefact(_ context.Context, key automationdomain.RouterArtefact) (automationdomain.RouterArtefact, bool, error) {
+	row, ok := m.rows[ledgerKey(key)]
+	return row, ok, nil
+}
+
+func (m *memoryLedger) UpsertArtefact(_ context.Context, artefact automationdomain.RouterArtefact) error {
+	if _, ok := m.rows[ledgerKey(artefact)]; !ok {
+		m.rows[ledgerKey(artefact)] = artefact
+	}
+	return nil
+}
+
+func (m *memoryLedger) DeleteArtefact(_ context.Context, key automationdomain.RouterArtefact) error {
+	delete(m.rows, ledgerKey(key))
+	return nil
+}
+
+func (m *memoryLedger) DeleteArtefactByID(context.Context, int64) error {
+	return nil
+}
+
+func (m *memoryLedger) ListArtefacts(context.Context) ([]automationdomain.RouterArtefact, error) {
+	rows := make([]automationdomain.RouterArtefact, 0, len(m.rows))
+	for _, row := range m.rows {
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ownedRouterClient reports address-list adds as created unless the entry
+// already exists.
+type ownedRouterClient struct {
+	fakeRouterClient
+	existing map[string]bool
+}
+
+func (f *ownedRouterClient) EnsureOwnedAddressListEntry(
+	_ context.Context,
+	_ model.RouterConfig,
+	list, address, _, _ string,
+) (bool, error) {
+	if f.existing[list+"|"+address] {
+		return false, nil
+	}
+	f.existing[list+"|"+address] = true
+	return true, nil
+}
+
+func (f *ownedRouterClient) RemoveOwnedAddressListEntry(_ context.Context, _ model.RouterConfig, list, address, _ string) error {
+	delete(f.existing, list+"|"+address)
+	return nil
+}
+
+func TestLedgerRouterClientRecordsAndForgetsWrites(t *testing.T) {
+	ledger := &memoryLedger{rows: map[string]automationdomain.RouterArtefact{}}
+	engine := (&Engine{routerClient: &ownedRouterClient{existing: map[string]bool{}}}).WithLedger(ledger)
+	device := model.DeviceView{MAC: "AA:BB:CC:DD:EE:01"}
+	client := engine.actionRouterClient("kids.internet", automationdomain.AutomationTarget{
+		Scope:  automationdomain.ScopeDevice,
+		Device: &device,
+	})
+	ctx := context.Background()
+	cfg := model.RouterConfig{Host: "router.local"}
+
+	if err := client.AddAddressListEntry(ctx, cfg, "blocked", "192.168.88.10"); err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if err := client.SetFirewallRuleDisabled(ctx, cfg, "filter", "*5", true); err != nil {
+		t.Fatalf("first toggle failed: %v", err)
+	}
+	if err := client.SetFirewallRuleDisabled(ctx, cfg, "filter", "*5", false); err != nil {
+		t.Fatalf("second toggle failed: %v", err)
+	}
+
+	rows, _ := ledger.ListArtefacts(ctx)
+	if len(rows) != 2 {
+		t.Fatalf("expected two artefacts, got %+v", rows)
+	}
+	for _, row := range rows {
+		if row.CapabilityID != "kids.internet" || row.DeviceID != device.MAC {
+			t.Fatalf("unexpected owner: %+v", row)
+		}
+		if row.Kind == automationdomain.ArtefactFirewallRule && row.Restore != "false" {
+			t.Fatalf("expected original enabled state to be kept, got %q", row.Restore)
+		}
+	}
+
+	if err := client.RemoveAddressListEntry(ctx, cfg, "blocked", "192.168.88.10"); err != nil {
+		t.Fatalf("remove failed: %v", err)
+	}
+	if rows, _ := ledger.ListArtefacts(ctx); len(rows) != 1 || rows[0].Kind != automationdomain.ArtefactFirewallRule {
+		t.Fatalf("expected address entry to be forgotten, got %+v", rows)
+	}
+}
+
+type failingReadRouterClient struct {
+	fakeRouterClient
+}
+
+func (f *failingReadRouterClient) GetFirewallRuleEnabled(context.Context, model.RouterConfig, string, string) (bool, error) {
+	return false, errors.New("router unreachable")
+}
+
+func TestLedgerRouterClientSkipsAddressListEntryItDidNotCreate(t *testing.T) {
+	ledger := &memoryLedger{rows: map[string]automationdomain.RouterArtefact{}}
+	router := &ownedRouterClient{existing: map[string]bool{"blocked|192.168.88.10": true}}
+	engine := (&Engine{routerClient: router}).WithLedger(ledger)
+	client := engine.actionRouterClient("kids.internet", automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal})
+
+	managed := client.(automationdomain.ManagedAddressListClient)
+	err := managed.AddManagedAddressListEntry(context.Background(), model.RouterConfig{}, "blocked", "192.168.88.10", "owner", "")
+	if err != nil {
+		t.Fatalf("add failed: %v", err)
+	}
+	if len(ledger.rows) != 0 {
+		t.Fatalf("pre-existing entry must not be recorded, got %+v", ledger.rows)
+	}
+}
+
+func TestLedgerRouterClientSkipsWriteWhenOriginalUnreadable(t *testing.T) {
+	ledger := &memoryLedger{rows: map[string]automationdomain.RouterArtefact{}}
+	router := &failingReadRouterClient{}
+	engine := (&Engine{routerClient: router}).WithLedger(ledger)
+	client := engine.actionRouterClient("guest.mode", automationdomain.AutomationTarget{Scope: automationdomain.ScopeGlobal})
+
+	err := client.SetFirewallRuleDisabled(context.Background(), model.RouterConfig{}, "filter", "*5", true)
+	if err == nil {
+		t.Fatal("expected error when original rule state cannot be read")
+	}
+	if router.setRuleCalls != 0 || len(ledger.rows) != 0 {
+		t.Fatalf("expected no write and no artefact, got calls=%d rows=%+v", router.setRuleCalls, ledger.rows)
+	}
+}
+
+// ledgerClientInterfaces lists every router client interface actions and
+// state sources may assert on; ledgerRouterClient must forward all of them.
+var ledgerClientInterfaces = map[string]reflect.Type{
+	"AddressListClient":         reflect.TypeOf((*automationdomain.AddressListClient)(nil)).Elem(),
+	"ManagedAddressListClient":  reflect.TypeOf((*automationdomain.ManagedAddressListClient)(nil)).Elem(),
+	"OwnedAddressListClient":    reflect.TypeOf((*automationdomain.OwnedAddressListClient)(nil)).Elem(),
+	"FirewallRuleClient":        reflect.TypeOf((*automationdomain.FirewallRuleClient)(nil)).Elem(),
+	"RouterCommandClient":       reflect.TypeOf((*automationdomain.RouterCommandClient)(nil)).Elem(),
+	"ConnectionClient":          reflect.TypeOf((*automationdomain.ConnectionClient)(nil)).Elem(),
+	"WakeOnLANClient":           reflect.TypeOf((*automationdomain.WakeOnLANClient)(nil)).Elem(),
+	"SimpleQueueClient":         reflect.TypeOf((*automationdomain.SimpleQueueClient)(nil)).Elem(),
+	"ContentFilterClient":       reflect.TypeOf((*automationdomain.ContentFilterClient)(nil)).Elem(),
+	"KidControlClient":          reflect.TypeOf((*automationdomain.KidControlClient)(nil)).Elem(),
+	"DNSAdlistClient":           reflect.TypeOf((*automationdomain.DNSAdlistClient)(nil)).Elem(),
+	"DNSStaticClient":           reflect.TypeOf((*automationdomain.DNSStaticClient)(nil)).Elem(),
+	"WiFiAccessClient":          reflect.TypeOf((*automationdomain.WiFiAccessClient)(nil)).Elem(),
+	"RouterActionClient":        reflect.TypeOf((*automationdomain.RouterActionClient)(nil)).Elem(),
+	"AddressListStateClient":    reflect.TypeOf((*automationdomain.AddressListStateClient)(nil)).Elem(),
+	"AddressListSnapshotClient": reflect.TypeOf((*automationdomain.AddressListSnapshotClient)(nil)).Elem(),
+	"FirewallRuleStateClient":   reflect.TypeOf((*automationdomain.FirewallRuleStateClient)(nil)).Elem(),
+	"SimpleQueueStateClient":    reflect.TypeOf((*automationdomain.SimpleQueueStateClient)(nil)).Elem(),
+	"ContentFilterStateClient":  reflect.TypeOf((*automationdomain.ContentFilterStateClient)(nil)).Elem(),
+	"KidControlStateClient":     reflect.TypeOf((*automationdomain.KidControlStateClient)(nil)).Elem(),
+	"DNSAdlistStateClient":      reflect.TypeOf((*automationdomain.DNSAdlistStateClient)(nil)).Elem(),
+	"WiFiAccessStateClient":     reflect.TypeOf((*automationdomain.WiFiAccessStateClient)(nil)).Elem(),
+	"RouterStateClient":         reflect.TypeOf((*automationdomain.RouterStateClient)(nil)).Elem(),
+}
+
+func TestLedgerRouterClientImplementsEveryClientInterface(t *testing.T) {
+	packages, err := parser.ParseDir(token.NewFileSet(), "../../../domain/automation", nil, 0)
+	if err != nil {
+		t.Fatalf("parse domain/automation: %v", err)
+	}
+	seen := 0
+	for _, pkg := range packages {
+		for _, file := range pkg.Files {
+			for name, object := range file.Scope.Objects {
+				spec, ok := object.Decl.(*ast.TypeSpec)
+				if !ok || !strings.HasSuffix(name, "Client") {
+					continue
+				}
+				if _, ok := spec.Type.(*ast.InterfaceType); !ok {
+					continue
+				}
+				if _, ok := ledgerClientInterfaces[name]; !ok {
+					t.Errorf("interface %s is not covered by ledgerClientInterfaces", name)
+				}
+				seen++
+			}
+		}
+	}
+	if seen != len(ledgerClientInterfaces) {
+		t.Errorf("found %d client interfaces, ledgerClientInterfaces lists %d", seen, len(ledgerClientInterfaces))
+	}
+
+	wrapper := reflect.TypeOf(&ledgerRouterClient{})
+	for name, iface := range ledgerClientInterfaces {
+		if !wrapper.Implements(iface) {
+			t.Errorf("ledgerRouterClient does not implement %s", name)
+		}
+	}
+}
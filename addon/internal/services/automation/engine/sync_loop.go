@@ -2,27 +2,215 @@ package engine
 
 import (
 	"context"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
 )
 
-const defaultSyncInterval = 20 * time.Second
+const (
+	defaultSyncInterval      = 20 * time.Second
+	defaultSyncWorkers       = 4
+	defaultRouterConcurrency = 2
+	syncSchedulerTick        = time.Second
+	// syncJitterDivisor spreads next runs by up to interval/divisor.
+	syncJitterDivisor = 10
+)
 
-// RunSyncLoop runs periodic synchronization until context cancellation.
+// RunSyncLoop schedules per-template synchronization until context cancellation.
 func (e *Engine) RunSyncLoop(ctx context.Context, interval time.Duration) {
 	if interval <= 0 {
 		interval = defaultSyncInterval
 	}
-	ticker := time.NewTicker(interval)
+	e.syncMu.Lock()
+	e.syncInterval = interval
+	e.syncMu.Unlock()
+
+	tick := syncSchedulerTick
+	if interval < tick {
+		tick = interval
+	}
+	ticker := time.NewTicker(tick)
 	defer ticker.Stop()
 
+	var wg sync.WaitGroup
+	defer wg.Wait()
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if err := e.SyncOnce(ctx); err != nil && e.logger != nil {
-				e.logger.Warn("automation sync failed", "err", err)
+			e.dispatchDueSyncs(ctx, &wg)
+		}
+	}
+}
+
+// SyncStatuses returns scheduled sync status for every sync-enabled template.
+func (e *Engine) SyncStatuses(ctx context.Context) ([]automationdomain.SyncStatus, error) {
+	templates, err := e.repo.ListTemplates(ctx, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	e.syncMu.Lock()
+	defer e.syncMu.Unlock()
+
+	out := make([]automationdomain.SyncStatus, 0, len(templates))
+	for _, template := range templates {
+		if !syncEnabled(template) {
+			continue
+		}
+		status := automationdomain.SyncStatus{CapabilityID: template.ID}
+		if current, ok := e.syncStatus[template.ID]; ok {
+			status = *current
+		}
+		status.IntervalSec = int(e.templateSyncIntervalLocked(template) / time.Second)
+		out = append(out, status)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].CapabilityID < out[j].CapabilityID })
+	return out, nil
+}
+
+func (e *Engine) dispatchDueSyncs(ctx context.Context, wg *sync.WaitGroup) {
+	routerConfig, configured := e.config.Get()
+	if !configured {
+		return
+	}
+
+	templates, err := e.repo.ListTemplates(ctx, "", "")
+	if err != nil {
+		if e.logger != nil {
+			e.logger.Warn("automation sync schedule failed", "err", err)
+		}
+		return
+	}
+	e.pruneSyncStatus(templates)
+
+	now := time.Now().UTC()
+	for _, template := range templates {
+		if !syncEnabled(template) || !e.claimSync(template, now) {
+			continue
+		}
+		select {
+		case e.syncSlots <- struct{}{}:
+		default:
+			// Pool is saturated; template stays due for next tick.
+			e.releaseSyncClaim(template.ID)
+			continue
+		}
+
+		wg.Add(1)
+		go func(template automationdomain.CapabilityTemplate) {
+			defer wg.Done()
+			defer func() { <-e.syncSlots }()
+			if err := e.runTrackedSync(ctx, routerConfig, template); err != nil && e.logger != nil {
+				e.logger.Warn("automation sync failed", "capability_id", template.ID, "err", err)
 			}
+		}(template)
+	}
+}
+
+// claimSync marks template as running when due; zero now skips schedule check.
+func (e *Engine) claimSync(template automationdomain.CapabilityTemplate, now time.Time) bool {
+	e.syncMu.Lock()
+	defer e.syncMu.Unlock()
+
+	status, ok := e.syncStatus[template.ID]
+	if !ok {
+		status = &automationdomain.SyncStatus{CapabilityID: template.ID}
+		e.syncStatus[template.ID] = status
+		if !now.IsZero() {
+			// Spread first runs so templates do not hit the router together.
+			next := now.Add(jitter(e.templateSyncIntervalLocked(template)))
+			status.NextRunAt = &next
+			return false
+		}
+	}
+	if status.Running {
+		return false
+	}
+	if !now.IsZero() && status.NextRunAt != nil && now.Before(*status.NextRunAt) {
+		return false
+	}
+	status.Running = true
+	return true
+}
+
+func (e *Engine) releaseSyncClaim(capabilityID string) {
+	e.syncMu.Lock()
+	defer e.syncMu.Unlock()
+	if status, ok := e.syncStatus[capabilityID]; ok {
+		status.Running = false
+	}
+}
+
+func (e *Engine) runTrackedSync(
+	ctx context.Context,
+	routerConfig model.RouterConfig,
+	template automationdomain.CapabilityTemplate,
+) error {
+	startedAt := time.Now().UTC()
+	targets, err := e.syncTemplate(ctx, routerConfig, template)
+	finishedAt := time.Now().UTC()
+
+	e.syncMu.Lock()
+	defer e.syncMu.Unlock()
+
+	status, ok := e.syncStatus[template.ID]
+	if !ok {
+		status = &automationdomain.SyncStatus{CapabilityID: template.ID}
+		e.syncStatus[template.ID] = status
+	}
+	interval := e.templateSyncIntervalLocked(template)
+	next := finishedAt.Add(interval + jitter(interval/syncJitterDivisor))
+
+	status.Running = false
+	status.Targets = targets
+	status.LastRunAt = &startedAt
+	status.LastDurationMS = finishedAt.Sub(startedAt).Milliseconds()
+	status.LastError = ""
+	if err != nil {
+		status.LastError = err.Error()
+	}
+	status.NextRunAt = &next
+	return err
+}
+
+func (e *Engine) pruneSyncStatus(templates []automationdomain.CapabilityTemplate) {
+	active := make(map[string]struct{}, len(templates))
+	for _, template := range templates {
+		if syncEnabled(template) {
+			active[template.ID] = struct{}{}
 		}
 	}
+
+	e.syncMu.Lock()
+	defer e.syncMu.Unlock()
+	for id, status := range e.syncStatus {
+		if _, ok := active[id]; !ok && !status.Running {
+			delete(e.syncStatus, id)
+		}
+	}
+}
+
+func (e *Engine) templateSyncIntervalLocked(template automationdomain.CapabilityTemplate) time.Duration {
+	if template.Sync != nil && template.Sync.IntervalSec > 0 {
+		return time.Duration(template.Sync.IntervalSec) * time.Second
+	}
+	return e.syncInterval
+}
+
+func syncEnabled(template automationdomain.CapabilityTemplate) bool {
+	return template.Sync != nil && template.Sync.Enabled && strings.TrimSpace(template.ID) != ""
+}
+
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
 }
@@ -0,0 +1,351 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+// routerLimiter bounds concurrent sync reads per router host.
+type routerLimiter struct {
+	limit int
+	mu    sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func newRouterLimiter(limit int) *routerLimiter {
+	return &routerLimiter{limit: limit, slots: map[string]chan struct{}{}}
+}
+
+func (l *routerLimiter) acquire(ctx context.Context, cfg model.RouterConfig) (func(), error) {
+	key := strings.ToLower(strings.TrimSpace(cfg.Host))
+
+	l.mu.Lock()
+	slot, ok := l.slots[key]
+	if !ok {
+		slot = make(chan struct{}, l.limit)
+		l.slots[key] = slot
+	}
+	l.mu.Unlock()
+
+	select {
+	case slot <- struct{}{}:
+		return func() { <-slot }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// syncReadCache memoizes router reads for one template sync run.
+type syncReadCache struct {
+	client  automationdomain.RouterStateClient
+	limiter *routerLimiter
+
+	mu    sync.Mutex
+	lists map[string]map[string]struct{}
+	// targets holds string lists read once per run, e.g. limited queue targets.
+	targets map[string][]string
+	bools   map[string]bool
+	rows    map[string][]map[string]string
+	errs    map[string]error
+}
+
+func newSyncReadCache(client automationdomain.RouterStateClient, limiter *routerLimiter) *syncReadCache {
+	return &syncReadCache{
+		client:  client,
+		limiter: limiter,
+		lists:   map[string]map[string]struct{}{},
+		targets: map[string][]string{},
+		bools:   map[string]bool{},
+		rows:    map[string][]map[string]string{},
+		errs:    map[string]error{},
+	}
+}
+
+// AddressListContains answers from one address-list print when client supports it.
+func (c *syncReadCache) AddressListContains(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+	address string,
+) (bool, error) {
+	if _, ok := c.client.(automationdomain.AddressListSnapshotClient); !ok {
+		return c.cachedBool(ctx, cfg, fmt.Sprintf("address-list|%s|%s", list, address), func() (bool, error) {
+			return c.client.AddressListContains(ctx, cfg, list, address)
+		})
+	}
+
+	entries, err := c.addressSet(ctx, cfg, list)
+	if err != nil {
+		return false, err
+	}
+	_, ok := entries[automationdomain.NormalizeAddress(address)]
+	return ok, nil
+}
+
+// ListAddressListAddresses returns cached address-list entries.
+func (c *syncReadCache) ListAddressListAddresses(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+) ([]string, error) {
+	entries, err := c.addressSet(ctx, cfg, list)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(entries))
+	for address := range entries {
+		out = append(out, address)
+	}
+	return out, nil
+}
+
+// GetFirewallRuleEnabled memoizes rule state by table and id.
+func (c *syncReadCache) GetFirewallRuleEnabled(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	table string,
+	ruleID string,
+) (bool, error) {
+	return c.cachedBool(ctx, cfg, fmt.Sprintf("firewall-id|%s|%s", table, ruleID), func() (bool, error) {
+		return c.client.GetFirewallRuleEnabled(ctx, cfg, table, ruleID)
+	})
+}
+
+// GetFirewallRulesEnabledByComment memoizes rule state by table and comment.
+func (c *syncReadCache) GetFirewallRulesEnabledByComment(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	table string,
+	comment string,
+) (bool, error) {
+	return c.cachedBool(ctx, cfg, fmt.Sprintf("firewall-comment|%s|%s", table, comment), func() (bool, error) {
+		return c.client.GetFirewallRulesEnabledByComment(ctx, cfg, table, comment)
+	})
+}
+
+// ListLimitedQueueTargets memoizes one simple-queue print per sync run.
+func (c *syncReadCache) ListLimitedQueueTargets(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	queueClient, ok := c.client.(automationdomain.SimpleQueueStateClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support simple queues")
+	}
+	return c.cachedTargets(ctx, cfg, "simple-queues", func() ([]string, error) {
+		return queueClient.ListLimitedQueueTargets(ctx, cfg)
+	})
+}
+
+// ListContentFilteredTargets memoizes one filter print per sync run.
+func (c *syncReadCache) ListContentFilteredTargets(ctx context.Context, cfg model.RouterConfig) ([]string, error) {
+	filterClient, ok := c.client.(automationdomain.ContentFilterStateClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support content filters")
+	}
+	return c.cachedTargets(ctx, cfg, "content-filters", func() ([]string, error) {
+		return filterClient.ListContentFilteredTargets(ctx, cfg)
+	})
+}
+
+// GetKidControlPaused memoizes profile paused state by name.
+func (c *syncReadCache) GetKidControlPaused(ctx context.Context, cfg model.RouterConfig, profile string) (bool, error) {
+	kidClient, ok := c.client.(automationdomain.KidControlStateClient)
+	if !ok {
+		return false, fmt.Errorf("router client does not support kid-control")
+	}
+	return c.cachedBool(ctx, cfg, "kid-control|"+profile, func() (bool, error) {
+		return kidClient.GetKidControlPaused(ctx, cfg, profile)
+	})
+}
+
+// KidControlProfileForMAC memoizes MAC binding lookups.
+func (c *syncReadCache) KidControlProfileForMAC(ctx context.Context, cfg model.RouterConfig, mac string) (string, error) {
+	kidClient, ok := c.client.(automationdomain.KidControlStateClient)
+	if !ok {
+		return "", fmt.Errorf("router client does not support kid-control")
+	}
+	profiles, err := c.cachedTargets(ctx, cfg, "kid-control-device|"+mac, func() ([]string, error) {
+		profile, err := kidClient.KidControlProfileForMAC(ctx, cfg, mac)
+		return []string{profile}, err
+	})
+	if err != nil {
+		return "", err
+	}
+	return profiles[0], nil
+}
+
+// GetDNSAdlistsEnabled memoizes adlist state by source.
+func (c *syncReadCache) GetDNSAdlistsEnabled(ctx context.Context, cfg model.RouterConfig, source string) (bool, error) {
+	adlistClient, ok := c.client.(automationdomain.DNSAdlistStateClient)
+	if !ok {
+		return false, fmt.Errorf("router client does not support dns adlists")
+	}
+	return c.cachedBool(ctx, cfg, "dns-adlist|"+source, func() (bool, error) {
+		return adlistClient.GetDNSAdlistsEnabled(ctx, cfg, source)
+	})
+}
+
+// GetWiFiAccessDenied memoizes access-list state by MAC.
+func (c *syncReadCache) GetWiFiAccessDenied(ctx context.Context, cfg model.RouterConfig, mac string) (bool, error) {
+	wifiClient, ok := c.client.(automationdomain.WiFiAccessStateClient)
+	if !ok {
+		return false, fmt.Errorf("router client does not support wifi access lists")
+	}
+	return c.cachedBool(ctx, cfg, "wifi-access|"+mac, func() (bool, error) {
+		return wifiClient.GetWiFiAccessDenied(ctx, cfg, mac)
+	})
+}
+
+// RunCommand memoizes read-only print queries; other commands are rejected.
+func (c *syncReadCache) RunCommand(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	path string,
+	params map[string]string,
+) ([]map[string]string, error) {
+	if !strings.HasSuffix(strings.TrimSpace(path), "/print") {
+		return nil, fmt.Errorf("sync reads allow print queries only, got %s", path)
+	}
+	commandClient, ok := c.client.(automationdomain.RouterCommandClient)
+	if !ok {
+		return nil, fmt.Errorf("router client does not support raw queries")
+	}
+
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var cacheKey strings.Builder
+	cacheKey.WriteString("query|" + path)
+	for _, key := range keys {
+		cacheKey.WriteString("|" + key + "=" + params[key])
+	}
+	key := cacheKey.String()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err, ok := c.errs[key]; ok {
+		return nil, err
+	}
+	if rows, ok := c.rows[key]; ok {
+		return rows, nil
+	}
+
+	release, err := c.limiter.acquire(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := commandClient.RunCommand(ctx, cfg, path, params)
+	release()
+	if err != nil {
+		c.errs[key] = err
+		return nil, err
+	}
+	c.rows[key] = rows
+	return rows, nil
+}
+
+func (c *syncReadCache) addressSet(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+) (map[string]struct{}, error) {
+	key := "address-list|" + list
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err, ok := c.errs[key]; ok {
+		return nil, err
+	}
+	if entries, ok := c.lists[list]; ok {
+		return entries, nil
+	}
+
+	snapshotClient, ok := c.client.(automationdomain.AddressListSnapshotClient)
+	if !ok {
+		return nil, fmt.Errorf("router client cannot list address-list %q", list)
+	}
+	release, err := c.limiter.acquire(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	addresses, err := snapshotClient.ListAddressListAddresses(ctx, cfg, list)
+	release()
+	if err != nil {
+		c.errs[key] = err
+		return nil, err
+	}
+
+	entries := make(map[string]struct{}, len(addresses))
+	for _, address := range addresses {
+		entries[automationdomain.NormalizeAddress(address)] = struct{}{}
+	}
+	c.lists[list] = entries
+	return entries, nil
+}
+
+func (c *syncReadCache) cachedBool(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	key string,
+	read func() (bool, error),
+) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err, ok := c.errs[key]; ok {
+		return false, err
+	}
+	if value, ok := c.bools[key]; ok {
+		return value, nil
+	}
+
+	release, err := c.limiter.acquire(ctx, cfg)
+	if err != nil {
+		return false, err
+	}
+	value, err := read()
+	release()
+	if err != nil {
+		c.errs[key] = err
+		return false, err
+	}
+	c.bools[key] = value
+	return value, nil
+}
+
+func (c *syncReadCache) cachedTargets(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	key string,
+	read func() ([]string, error),
+) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err, ok := c.errs[key]; ok {
+		return nil, err
+	}
+	if targets, ok := c.targets[key]; ok {
+		return targets, nil
+	}
+
+	release, err := c.limiter.acquire(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	targets, err := read()
+	release()
+	if err != nil {
+		c.errs[key] = err
+		return nil, err
+	}
+	c.targets[key] = targets
+	return targets, nil
+}
@@ -0,0 +1,242 @@
+package automation
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/routeros"
+)
+
+const defaultParamOptionsTTL = 30 * time.Second
+
+// OptionsRouterClient describes router reads used by param options providers.
+type OptionsRouterClient interface {
+	ListAddressListNames(ctx context.Context, cfg model.RouterConfig) ([]string, error)
+	ListFirewallRules(ctx context.Context, cfg model.RouterConfig) ([]routeros.FirewallRule, error)
+	ListInterfaces(ctx context.Context, cfg model.RouterConfig) ([]routeros.InterfaceInfo, error)
+	ListSimpleQueueNames(ctx context.Context, cfg model.RouterConfig) ([]string, error)
+	ListKidControlProfileNames(ctx context.Context, cfg model.RouterConfig) ([]string, error)
+	ListDNSAdlistSources(ctx context.Context, cfg model.RouterConfig) ([]string, error)
+}
+
+// RouterConfigProvider exposes current add-on router config.
+type RouterConfigProvider interface {
+	Get() (model.RouterConfig, bool)
+}
+
+// paramOptionsResolver resolves live option lists with short-lived caching so
+// editor pickers do not hit the router on every keystroke.
+type paramOptionsResolver struct {
+	client OptionsRouterClient
+	config RouterConfigProvider
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedParamOptions
+}
+
+type cachedParamOptions struct {
+	options []automationdomain.ParamOption
+	expires time.Time
+}
+
+func newParamOptionsResolver(client OptionsRouterClient, config RouterConfigProvider) *paramOptionsResolver {
+	return &paramOptionsResolver{
+		client: client,
+		config: config,
+		ttl:    defaultParamOptionsTTL,
+		now:    time.Now,
+		cache:  map[string]cachedParamOptions{},
+	}
+}
+
+// WithParamOptions enables router-backed param options providers.
+func (s *Service) WithParamOptions(client OptionsRouterClient, config RouterConfigProvider) *Service {
+	s.options = newParamOptionsResolver(client, config)
+	return s
+}
+
+// ParamOptions returns live values for provider; arg narrows provider output.
+func (s *Service) ParamOptions(
+	ctx context.Context,
+	provider string,
+	arg string,
+) ([]automationdomain.ParamOption, error) {
+	if s.options == nil {
+		return nil, automationdomain.ErrAddonNotConfigured
+	}
+	return s.options.Resolve(ctx, strings.TrimSpace(provider), strings.TrimSpace(arg))
+}
+
+func (r *paramOptionsResolver) Resolve(
+	ctx context.Context,
+	provider string,
+	arg string,
+) ([]automationdomain.ParamOption, error) {
+	if !knownOptionsProvider(provider) {
+		return nil, automationdomain.ErrOptionsProviderUnknown
+	}
+	cfg, ok := r.config.Get()
+	if !ok {
+		return nil, automationdomain.ErrAddonNotConfigured
+	}
+
+	key := provider + "|" + arg + "|" + cfg.Host
+	now := r.now()
+	r.mu.Lock()
+	if cached, ok := r.cache[key]; ok && now.Before(cached.expires) {
+		r.mu.Unlock()
+		return cached.options, nil
+	}
+	r.mu.Unlock()
+
+	options, err := r.fetch(ctx, cfg, provider, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[key] = cachedParamOptions{options: options, expires: now.Add(r.ttl)}
+	r.mu.Unlock()
+	return options, nil
+}
+
+func knownOptionsProvider(provider string) bool {
+	switch provider {
+	case automationdomain.OptionsRouterAddressLists,
+		automationdomain.OptionsRouterFirewallRules,
+		automationdomain.OptionsRouterFirewallComments,
+		automationdomain.OptionsRouterInterfaces,
+		automationdomain.OptionsRouterQueues,
+		automationdomain.OptionsRouterKidControlProfiles,
+		automationdomain.OptionsRouterDNSAdlists:
+		return true
+	}
+	return false
+}
+
+func (r *paramOptionsResolver) fetch(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	provider string,
+	arg string,
+) ([]automationdomain.ParamOption, error) {
+	switch provider {
+	case automationdomain.OptionsRouterAddressLists:
+		names, err := r.client.ListAddressListNames(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return namesToOptions(names), nil
+	case automationdomain.OptionsRouterQueues:
+		names, err := r.client.ListSimpleQueueNames(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return namesToOptions(names), nil
+	case automationdomain.OptionsRouterKidControlProfiles:
+		names, err := r.client.ListKidControlProfileNames(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return namesToOptions(names), nil
+	case automationdomain.OptionsRouterDNSAdlists:
+		sources, err := r.client.ListDNSAdlistSources(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return namesToOptions(sources), nil
+	case automationdomain.OptionsRouterInterfaces:
+		items, err := r.client.ListInterfaces(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+		options := make([]automationdomain.ParamOption, 0, len(items))
+		for _, item := range items {
+			if item.Name == "" {
+				continue
+			}
+			label := item.Name
+			if item.Type != "" {
+				label += " (" + item.Type + ")"
+			}
+			options = append(options, automationdomain.ParamOption{Value: item.Name, Label: label})
+		}
+		sortOptions(options)
+		return options, nil
+	case automationdomain.OptionsRouterFirewallRules:
+		rules, err := r.firewallRules(ctx, cfg, arg)
+		if err != nil {
+			return nil, err
+		}
+		options := make([]automationdomain.ParamOption, 0, len(rules))
+		for _, rule := range rules {
+			label := rule.Table + "/" + rule.Chain + " " + rule.Action
+			if rule.Comment != "" {
+				label += " — " + rule.Comment
+			}
+			options = append(options, automationdomain.ParamOption{Value: rule.ID, Label: label})
+		}
+		return options, nil
+	case automationdomain.OptionsRouterFirewallComments:
+		rules, err := r.firewallRules(ctx, cfg, arg)
+		if err != nil {
+			return nil, err
+		}
+		comments := make([]string, 0, len(rules))
+		seen := make(map[string]struct{}, len(rules))
+		for _, rule := range rules {
+			comment := strings.TrimSpace(rule.Comment)
+			if comment == "" {
+				continue
+			}
+			if _, ok := seen[comment]; ok {
+				continue
+			}
+			seen[comment] = struct{}{}
+			comments = append(comments, comment)
+		}
+		sort.Strings(comments)
+		return namesToOptions(comments), nil
+	}
+	return nil, automationdomain.ErrOptionsProviderUnknown
+}
+
+func (r *paramOptionsResolver) firewallRules(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	table string,
+) ([]routeros.FirewallRule, error) {
+	rules, err := r.client.ListFirewallRules(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if table == "" {
+		return rules, nil
+	}
+	filtered := rules[:0:0]
+	for _, rule := range rules {
+		if rule.Table == table {
+			filtered = append(filtered, rule)
+		}
+	}
+	return filtered, nil
+}
+
+func namesToOptions(names []string) []automationdomain.ParamOption {
+	options := make([]automationdomain.ParamOption, 0, len(names))
+	for _, name := range names {
+		options = append(options, automationdomain.ParamOption{Value: name, Label: name})
+	}
+	return options
+}
+
+func sortOptions(options []automationdomain.ParamOption) {
+	sort.Slice(options, func(i, j int) bool { return options[i].Value < options[j].Value })
+}
@@ -0,0 +1,101 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/routeros"
+)
+
+type fakeOptionsRouterClient struct {
+	ruleCalls int
+}
+
+func (f *fakeOptionsRouterClient) ListAddressListNames(context.Context, model.RouterConfig) ([]string, error) {
+	return []string{"blocked", "vpn_users"}, nil
+}
+
+func (f *fakeOptionsRouterClient) ListFirewallRules(context.Context, model.RouterConfig) ([]routeros.FirewallRule, error) {
+	f.ruleCalls++
+	return []routeros.FirewallRule{
+		{ID: "*1", Table: "filter", Chain: "forward", Action: "drop", Comment: "kids"},
+		{ID: "*2", Table: "filter", Chain: "forward", Action: "drop", Comment: "kids"},
+		{ID: "*3", Table: "nat", Chain: "srcnat", Action: "masquerade"},
+	}, nil
+}
+
+func (f *fakeOptionsRouterClient) ListInterfaces(context.Context, model.RouterConfig) ([]routeros.InterfaceInfo, error) {
+	return []routeros.InterfaceInfo{{Name: "ether1", Type: "ether"}}, nil
+}
+
+func (f *fakeOptionsRouterClient) ListSimpleQueueNames(context.Context, model.RouterConfig) ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeOptionsRouterClient) ListKidControlProfileNames(context.Context, model.RouterConfig) ([]string, error) {
+	return []string{"kids"}, nil
+}
+
+func (f *fakeOptionsRouterClient) ListDNSAdlistSources(context.Context, model.RouterConfig) ([]string, error) {
+	return nil, nil
+}
+
+type fakeOptionsConfig struct {
+	configured bool
+}
+
+func (f fakeOptionsConfig) Get() (model.RouterConfig, bool) {
+	return model.RouterConfig{Host: "192.168.88.1"}, f.configured
+}
+
+func TestParamOptionsResolverFiltersAndCaches(t *testing.T) {
+	client := &fakeOptionsRouterClient{}
+	resolver := newParamOptionsResolver(client, fakeOptionsConfig{configured: true})
+	now := time.Unix(1_700_000_000, 0)
+	resolver.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	rules, err := resolver.Resolve(ctx, automationdomain.OptionsRouterFirewallRules, "filter")
+	if err != nil {
+		t.Fatalf("Resolve rules failed: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Value != "*1" {
+		t.Fatalf("unexpected rule options %+v", rules)
+	}
+	if _, err := resolver.Resolve(ctx, automationdomain.OptionsRouterFirewallRules, "filter"); err != nil {
+		t.Fatalf("cached Resolve failed: %v", err)
+	}
+	if client.ruleCalls != 1 {
+		t.Fatalf("expected cached rules, got %d router calls", client.ruleCalls)
+	}
+
+	comments, err := resolver.Resolve(ctx, automationdomain.OptionsRouterFirewallComments, "filter")
+	if err != nil {
+		t.Fatalf("Resolve comments failed: %v", err)
+	}
+	if len(comments) != 1 || comments[0].Value != "kids" {
+		t.Fatalf("unexpected comment options %+v", comments)
+	}
+
+	now = now.Add(defaultParamOptionsTTL + time.Second)
+	if _, err := resolver.Resolve(ctx, automationdomain.OptionsRouterFirewallRules, "filter"); err != nil {
+		t.Fatalf("Resolve after ttl failed: %v", err)
+	}
+	if client.ruleCalls != 3 {
+		t.Fatalf("expected refetch after ttl, got %d router calls", client.ruleCalls)
+	}
+}
+
+func TestParamOptionsResolverErrors(t *testing.T) {
+	resolver := newParamOptionsResolver(&fakeOptionsRouterClient{}, fakeOptionsConfig{configured: false})
+	if _, err := resolver.Resolve(context.Background(), "router.unknown", ""); !errors.Is(err, automationdomain.ErrOptionsProviderUnknown) {
+		t.Fatalf("expected ErrOptionsProviderUnknown, got %v", err)
+	}
+	if _, err := resolver.Resolve(context.Background(), automationdomain.OptionsRouterInterfaces, ""); !errors.Is(err, automationdomain.ErrAddonNotConfigured) {
+		t.Fatalf("expected ErrAddonNotConfigured, got %v", err)
+	}
+}
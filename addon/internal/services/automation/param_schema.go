@@ -0,0 +1,235 @@
+package automation
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+)
+
+// validateParams checks params against schema and returns field errors with
+// paths prefixed by basePath. Hidden fields (VisibleIf not met) are skipped,
+// unknown keys are ignored so older templates keep loading.
+func validateParams(
+	schema []automationdomain.ParamField,
+	params map[string]any,
+	basePath string,
+) []automationdomain.FieldError {
+	var fieldErrors []automationdomain.FieldError
+	for _, field := range schema {
+		if !fieldVisible(field, params) {
+			continue
+		}
+		path := basePath + "." + field.Key
+		raw, present := params[field.Key]
+		if !present || isEmptyParam(raw) {
+			if field.Required {
+				fieldErrors = append(fieldErrors, automationdomain.FieldError{Path: path, Message: "is required"})
+			}
+			continue
+		}
+		fieldErrors = append(fieldErrors, validateParamValue(field, raw, path)...)
+	}
+	return fieldErrors
+}
+
+func fieldVisible(field automationdomain.ParamField, params map[string]any) bool {
+	if field.VisibleIf == nil {
+		return true
+	}
+	value, ok := params[field.VisibleIf.Key]
+	if !ok {
+		return false
+	}
+	return strings.TrimSpace(fmt.Sprint(value)) == field.VisibleIf.Equals
+}
+
+func isEmptyParam(raw any) bool {
+	switch value := raw.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(value) == ""
+	case []any:
+		return len(value) == 0
+	case []string:
+		return len(value) == 0
+	}
+	return false
+}
+
+func validateParamValue(field automationdomain.ParamField, raw any, path string) []automationdomain.FieldError {
+	fail := func(format string, args ...any) []automationdomain.FieldError {
+		return []automationdomain.FieldError{{Path: path, Message: fmt.Sprintf(format, args...)}}
+	}
+
+	switch field.Kind {
+	case automationdomain.ParamBool:
+		switch value := raw.(type) {
+		case bool:
+		case string:
+			if _, err := strconv.ParseBool(strings.TrimSpace(value)); err != nil {
+				return fail("must be boolean")
+			}
+		default:
+			return fail("must be boolean")
+		}
+		return nil
+	case automationdomain.ParamObject:
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return fail("must be object")
+		}
+		return validateParams(field.Fields, object, path)
+	case automationdomain.ParamStringList:
+		items, ok := stringListParam(raw)
+		if !ok {
+			return fail("must be list of strings")
+		}
+		if msg := checkBounds(field, len(items), "items"); msg != "" {
+			return fail("%s", msg)
+		}
+		var fieldErrors []automationdomain.FieldError
+		for i, item := range items {
+			if msg := checkPattern(field, item); msg != "" {
+				fieldErrors = append(fieldErrors, automationdomain.FieldError{Path: fmt.Sprintf("%s[%d]", path, i), Message: msg})
+			}
+		}
+		return fieldErrors
+	}
+
+	value, ok := scalarParam(raw)
+	if !ok {
+		return fail("must be %s", field.Kind)
+	}
+	// Placeholders are resolved at execution time, typed checks apply to literals only.
+	if strings.Contains(value, "{{") {
+		return nil
+	}
+
+	switch field.Kind {
+	case automationdomain.ParamEnum:
+		for _, option := range field.Options {
+			if value == option {
+				return nil
+			}
+		}
+		return fail("must be one of %s", strings.Join(field.Options, ", "))
+	case automationdomain.ParamInt:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fail("must be integer")
+		}
+		if msg := checkBounds(field, number, ""); msg != "" {
+			return fail("%s", msg)
+		}
+	case automationdomain.ParamDuration:
+		seconds, err := durationSeconds(value)
+		if err != nil {
+			return fail("must be duration like 30s or 5m")
+		}
+		if msg := checkBounds(field, seconds, "seconds"); msg != "" {
+			return fail("%s", msg)
+		}
+	case automationdomain.ParamIP:
+		if net.ParseIP(value) == nil {
+			return fail("must be IP address")
+		}
+	case automationdomain.ParamCIDR:
+		if _, _, err := net.ParseCIDR(value); err != nil {
+			return fail("must be CIDR prefix")
+		}
+	case automationdomain.ParamMAC:
+		if _, err := net.ParseMAC(value); err != nil {
+			return fail("must be MAC address")
+		}
+	default:
+		if msg := checkBounds(field, len(value), "characters"); msg != "" {
+			return fail("%s", msg)
+		}
+	}
+	if msg := checkPattern(field, value); msg != "" {
+		return fail("%s", msg)
+	}
+	return nil
+}
+
+// scalarParam normalizes JSON scalars; numbers arrive as float64.
+func scalarParam(raw any) (string, bool) {
+	switch value := raw.(type) {
+	case string:
+		return strings.TrimSpace(value), true
+	case float64:
+		if value == math.Trunc(value) {
+			return strconv.FormatInt(int64(value), 10), true
+		}
+		return strconv.FormatFloat(value, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(value), true
+	case bool:
+		return strconv.FormatBool(value), true
+	}
+	return "", false
+}
+
+func stringListParam(raw any) ([]string, bool) {
+	switch value := raw.(type) {
+	case []string:
+		return value, true
+	case []any:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			text, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			items = append(items, strings.TrimSpace(text))
+		}
+		return items, true
+	}
+	return nil, false
+}
+
+func durationSeconds(value string) (int, error) {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return seconds, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	return int(duration / time.Second), nil
+}
+
+func checkBounds(field automationdomain.ParamField, value int, unit string) string {
+	suffix := ""
+	if unit != "" {
+		suffix = " " + unit
+	}
+	if field.Min != nil && value < *field.Min {
+		return fmt.Sprintf("must be at least %d%s", *field.Min, suffix)
+	}
+	if field.Max != nil && value > *field.Max {
+		return fmt.Sprintf("must be at most %d%s", *field.Max, suffix)
+	}
+	return ""
+}
+
+func checkPattern(field automationdomain.ParamField, value string) string {
+	if field.Pattern == "" {
+		return ""
+	}
+	pattern, err := regexp.Compile(field.Pattern)
+	if err != nil {
+		return fmt.Sprintf("schema pattern is invalid: %v", err)
+	}
+	if !pattern.MatchString(value) {
+		return "must match " + field.Pattern
+	}
+	return ""
+}
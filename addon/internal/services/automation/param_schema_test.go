@@ -0,0 +1,158 @@
+package automation
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/services/automation/registry"
+)
+
+func TestValidateParamsReturnsFieldPaths(t *testing.T) {
+	schema := []automationdomain.ParamField{
+		{Key: "mode", Kind: automationdomain.ParamEnum, Required: true, Options: []string{"ip", "mac"}},
+		{Key: "ip", Kind: automationdomain.ParamIP, Required: true, VisibleIf: &automationdomain.VisibleIfCondition{Key: "mode", Equals: "ip"}},
+		{Key: "mac", Kind: automationdomain.ParamMAC, Required: true, VisibleIf: &automationdomain.VisibleIfCondition{Key: "mode", Equals: "mac"}},
+		{Key: "limit", Kind: automationdomain.ParamInt, Min: automationdomain.IntBound(1), Max: automationdomain.IntBound(10)},
+		{Key: "timeout", Kind: automationdomain.ParamDuration, Max: automationdomain.IntBound(60)},
+		{Key: "subnet", Kind: automationdomain.ParamCIDR},
+		{Key: "names", Kind: automationdomain.ParamStringList, Pattern: `^[a-z]+$`},
+		{Key: "target", Kind: automationdomain.ParamObject, Fields: []automationdomain.ParamField{
+			{Key: "host", Kind: automationdomain.ParamString, Required: true},
+		}},
+	}
+
+	errs := validateParams(schema, map[string]any{
+		"mode":    "ip",
+		"ip":      "300.1.1.1",
+		"mac":     "not-checked-while-hidden",
+		"limit":   float64(11),
+		"timeout": "5m",
+		"subnet":  "192.168.88.0/24",
+		"names":   []any{"ok", "Bad"},
+		"target":  map[string]any{},
+	}, "params")
+
+	want := map[string]bool{
+		"params.ip":          true,
+		"params.limit":       true,
+		"params.timeout":     true,
+		"params.names[1]":    true,
+		"params.target.host": true,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d field errors, got %+v", len(want), errs)
+	}
+	for _, fieldErr := range errs {
+		if !want[fieldErr.Path] {
+			t.Fatalf("unexpected field error %+v", fieldErr)
+		}
+	}
+
+	if errs := validateParams(schema, map[string]any{
+		"mode":  "mac",
+		"mac":   "AA:BB:CC:DD:EE:01",
+		"limit": "{{device.vendor}}",
+	}, "params"); len(errs) != 0 {
+		t.Fatalf("expected valid params, got %+v", errs)
+	}
+}
+
+func TestValidateTemplateReportsActionParamPaths(t *testing.T) {
+	reg := newSchemaTestRegistry(t)
+	template := automationdomain.CapabilityTemplate{
+		ID:           "test.schema",
+		Label:        "Schema",
+		Scope:        automationdomain.ScopeGlobal,
+		Control:      automationdomain.CapabilityControl{Type: automationdomain.ControlSwitch, Options: []automationdomain.CapabilityControlOption{{Value: "on"}, {Value: "off"}}},
+		DefaultState: "off",
+		States: map[string]automationdomain.CapabilityStateConfig{
+			"on": {ActionsOnEnter: []automationdomain.ActionInstance{
+				{ID: "a1", TypeID: "test.action", Params: map[string]any{"count": "zero"}},
+			}},
+			"off": {},
+		},
+	}
+
+	err := validateTemplate(template, reg)
+	var paramErr *automationdomain.ParamValidationError
+	if !errors.As(err, &paramErr) {
+		t.Fatalf("expected ParamValidationError, got %v", err)
+	}
+	if len(paramErr.Fields) != 1 || paramErr.Fields[0].Path != "states.on.actions_on_enter[0].params.count" {
+		t.Fatalf("unexpected field errors %+v", paramErr.Fields)
+	}
+}
+
+type schemaTestAction struct{}
+
+func (schemaTestAction) ID() string { return "test.action" }
+
+func (schemaTestAction) Metadata() automationdomain.ActionMetadata {
+	return automationdomain.ActionMetadata{
+		ID: "test.action",
+		ParamSchema: []automationdomain.ParamField{
+			{Key: "count", Kind: automationdomain.ParamInt, Required: true},
+		},
+	}
+}
+
+func (schemaTestAction) Validate(target automationdomain.AutomationTarget, params map[string]any) error {
+	return nil
+}
+
+func (schemaTestAction) Execute(
+	ctx context.Context,
+	execCtx automationdomain.ActionExecutionContext,
+	params map[string]any,
+) error {
+	return nil
+}
+
+func newSchemaTestRegistry(t *testing.T) *registry.Registry {
+	t.Helper()
+	reg := registry.New()
+	reg.RegisterAction(schemaTestAction{})
+	return reg
+}
+
+func TestValidateTemplateChecksExitTransitionAndDisableActions(t *testing.T) {
+	reg := newSchemaTestRegistry(t)
+	invalid := []automationdomain.ActionInstance{{ID: "a1", TypeID: "test.action", Params: map[string]any{}}}
+	template := automationdomain.CapabilityTemplate{
+		ID:           "test.hooks",
+		Label:        "Hooks",
+		Scope:        automationdomain.ScopeGlobal,
+		Control:      automationdomain.CapabilityControl{Type: automationdomain.ControlSwitch, Options: []automationdomain.CapabilityControlOption{{Value: "on"}, {Value: "off"}}},
+		DefaultState: "off",
+		States: map[string]automationdomain.CapabilityStateConfig{
+			"on":  {ActionsOnExit: invalid},
+			"off": {},
+		},
+		Transitions:      []automationdomain.CapabilityTransition{{From: "off", To: "on", Actions: invalid}},
+		ActionsOnDisable: invalid,
+	}
+
+	err := validateTemplate(template, reg)
+	var paramErr *automationdomain.ParamValidationError
+	if !errors.As(err, &paramErr) {
+		t.Fatalf("expected ParamValidationError, got %v", err)
+	}
+	paths := make([]string, 0, len(paramErr.Fields))
+	for _, field := range paramErr.Fields {
+		paths = append(paths, field.Path)
+	}
+	want := "states.on.actions_on_exit[0].params.count,transitions[0].actions[0].params.count,actions_on_disable[0].params.count"
+	if strings.Join(paths, ",") != want {
+		t.Fatalf("unexpected field paths %v", paths)
+	}
+
+	template.ActionsOnDisable = nil
+	template.States["on"] = automationdomain.CapabilityStateConfig{}
+	template.Transitions = []automationdomain.CapabilityTransition{{From: "off", To: "paused"}}
+	if err := validateTemplate(template, reg); err == nil || !strings.Contains(err.Error(), "transitions[0].to") {
+		t.Fatalf("expected unknown transition state error, got %v", err)
+	}
+}
@@ -21,6 +21,8 @@ type Service struct {
 	devices  devicedomain.Service
 	engine   *engine.Engine
 	registry *registry.Registry
+	options  *paramOptionsResolver
+	gc       *artefactReconciler
 	logger   *slog.Logger
 }
 
@@ -79,7 +81,7 @@ func (s *Service) GetCapability(
 func (s *Service) CreateCapability(ctx context.Context, template automationdomain.CapabilityTemplate) error {
 	template = normalizeTemplate(template)
 	if err := validateTemplate(template, s.registry); err != nil {
-		return fmt.Errorf("%w: %s", automationdomain.ErrCapabilityInvalid, err)
+		return fmt.Errorf("%w: %w", automationdomain.ErrCapabilityInvalid, err)
 	}
 	if err := s.repo.CreateTemplate(ctx, template); err != nil {
 		if utils.IsUniqueConstraintError(err) {
@@ -117,7 +119,7 @@ func (s *Service) UpdateCapability(
 
 	template = normalizeTemplate(template)
 	if err := validateTemplate(template, s.registry); err != nil {
-		return fmt.Errorf("%w: %s", automationdomain.ErrCapabilityInvalid, err)
+		return fmt.Errorf("%w: %w", automationdomain.ErrCapabilityInvalid, err)
 	}
 	if err := s.repo.UpdateTemplate(ctx, template); err != nil {
 		if errors.Is(err, automationdomain.ErrNotFound) {
@@ -128,9 +130,22 @@ func (s *Service) UpdateCapability(
 	return nil
 }
 
-// DeleteCapability deletes capability template by ID.
+// DeleteCapability starts disable hooks of enabled targets and deletes
+// capability template by ID.
 func (s *Service) DeleteCapability(ctx context.Context, capabilityID string) error {
-	if err := s.repo.DeleteTemplate(ctx, strings.TrimSpace(capabilityID)); err != nil {
+	capabilityID = strings.TrimSpace(capabilityID)
+	template, err := s.repo.GetTemplate(ctx, capabilityID)
+	if errors.Is(err, automationdomain.ErrNotFound) {
+		return automationdomain.ErrCapabilityNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if err := s.engine.RunDisableHooks(ctx, template); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteTemplate(ctx, capabilityID); err != nil {
 		if errors.Is(err, automationdomain.ErrNotFound) {
 			return automationdomain.ErrCapabilityNotFound
 		}
@@ -265,54 +280,73 @@ func (s *Service) PatchDeviceCapability(
 	}
 
 	if enabled != nil {
-		if err := s.SetDeviceCapabilityEnabled(ctx, deviceID, capabilityID, *enabled); err != nil {
+		warnings, err := s.SetDeviceCapabilityEnabled(ctx, deviceID, capabilityID, *enabled)
+		if err != nil {
 			return automationdomain.SetStateResult{}, err
 		}
+		result.Warnings = append(result.Warnings, warnings...)
 	}
 	return result, nil
 }
 
-// SetDeviceCapabilityEnabled toggles capability without executing actions.
+// SetDeviceCapabilityEnabled toggles capability and runs enable/disable hooks
+// when flag actually changes.
 func (s *Service) SetDeviceCapabilityEnabled(
 	ctx context.Context,
 	deviceID string,
 	capabilityID string,
 	enabled bool,
-) error {
+) ([]automationdomain.ActionExecutionWarning, error) {
 	deviceID = normalizeDeviceID(deviceID)
 	capabilityID = strings.TrimSpace(capabilityID)
 
 	if _, err := s.requireDevice(ctx, deviceID); err != nil {
-		return err
+		return nil, err
 	}
 	template, err := s.repo.GetTemplate(ctx, capabilityID)
 	if errors.Is(err, automationdomain.ErrNotFound) {
-		return automationdomain.ErrCapabilityNotFound
+		return nil, automationdomain.ErrCapabilityNotFound
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if automationdomain.NormalizeCapabilityScope(template.Scope) != automationdomain.ScopeDevice {
-		return fmt.Errorf("%w: capability %q is not device-scoped", automationdomain.ErrCapabilityScopeMismatch, template.ID)
+		return nil, fmt.Errorf("%w: capability %q is not device-scoped", automationdomain.ErrCapabilityScopeMismatch, template.ID)
 	}
 
 	current, exists, err := s.repo.GetDeviceCapabilityState(ctx, deviceID, capabilityID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if !exists {
+		// Missing row means enabled with default state.
 		current = automationdomain.DeviceCapability{
 			DeviceID:     deviceID,
 			CapabilityID: capabilityID,
+			Enabled:      true,
 			State:        template.DefaultState,
 		}
 	}
+	changed := current.Enabled != enabled
 	current.Enabled = enabled
 	current.UpdatedAt = time.Now().UTC()
 	if strings.TrimSpace(current.State) == "" {
 		current.State = template.DefaultState
 	}
-	return s.repo.UpsertDeviceCapabilityState(ctx, current)
+	if err := s.repo.UpsertDeviceCapabilityState(ctx, current); err != nil {
+		return nil, err
+	}
+	if !changed {
+		return nil, nil
+	}
+	if !exists {
+		// Capability never stored for device has nothing on router to tear down.
+		template.ActionsOnDisable = nil
+	}
+	return s.engine.ApplyEnabledChange(ctx, automationdomain.CapabilityTargetRef{
+		Scope:    automationdomain.ScopeDevice,
+		DeviceID: deviceID,
+	}, template, current.State, enabled)
 }
 
 // GetGlobalCapabilities returns global capabilities for controls UI.
@@ -384,48 +418,81 @@ func (s *Service) PatchGlobalCapability(
 	}
 
 	if enabled != nil {
-		if err := s.SetGlobalCapabilityEnabled(ctx, capabilityID, *enabled); err != nil {
+		warnings, err := s.SetGlobalCapabilityEnabled(ctx, capabilityID, *enabled)
+		if err != nil {
 			return automationdomain.SetStateResult{}, err
 		}
+		result.Warnings = append(result.Warnings, warnings...)
 	}
 	return result, nil
 }
 
-// SetGlobalCapabilityEnabled toggles global capability without executing actions.
+// SetGlobalCapabilityEnabled toggles global capability and runs enable/disable
+// hooks when flag actually changes.
 func (s *Service) SetGlobalCapabilityEnabled(
 	ctx context.Context,
 	capabilityID string,
 	enabled bool,
-) error {
+) ([]automationdomain.ActionExecutionWarning, error) {
 	capabilityID = strings.TrimSpace(capabilityID)
 	template, err := s.repo.GetTemplate(ctx, capabilityID)
 	if errors.Is(err, automationdomain.ErrNotFound) {
-		return automationdomain.ErrCapabilityNotFound
+		return nil, automationdomain.ErrCapabilityNotFound
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if automationdomain.NormalizeCapabilityScope(template.Scope) != automationdomain.ScopeGlobal {
-		return fmt.Errorf("%w: capability %q is not global-scoped", automationdomain.ErrCapabilityScopeMismatch, template.ID)
+		return nil, fmt.Errorf("%w: capability %q is not global-scoped", automationdomain.ErrCapabilityScopeMismatch, template.ID)
 	}
 
 	current, err := s.repo.GetGlobalCapability(ctx, capabilityID)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	// Missing row means enabled with default state.
+	changed := !enabled
 	if current == nil {
+		// Capability never stored has nothing on router to tear down.
+		template.ActionsOnDisable = nil
 		current = &automationdomain.GlobalCapability{
 			CapabilityID: capabilityID,
 			State:        template.DefaultState,
 			Enabled:      enabled,
 		}
 	} else {
+		changed = current.Enabled != enabled
 		current.Enabled = enabled
 		if strings.TrimSpace(current.State) == "" {
 			current.State = template.DefaultState
 		}
 	}
-	return s.repo.SaveGlobalCapability(ctx, current)
+	if err := s.repo.SaveGlobalCapability(ctx, current); err != nil {
+		return nil, err
+	}
+	if !changed {
+		return nil, nil
+	}
+	return s.engine.ApplyEnabledChange(ctx, automationdomain.CapabilityTargetRef{
+		Scope: automationdomain.ScopeGlobal,
+	}, template, current.State, enabled)
+}
+
+// ListDriftEvents returns drift events recorded by enforce sync mode.
+func (s *Service) ListDriftEvents(
+	ctx context.Context,
+	filter automationdomain.DriftEventFilter,
+) ([]automationdomain.DriftEvent, error) {
+	filter.CapabilityID = strings.TrimSpace(filter.CapabilityID)
+	if filter.DeviceID != "" {
+		filter.DeviceID = normalizeDeviceID(filter.DeviceID)
+	}
+	return s.repo.ListDriftEvents(ctx, filter)
+}
+
+// ListSyncStatus returns scheduled sync status per capability template.
+func (s *Service) ListSyncStatus(ctx context.Context) ([]automationdomain.SyncStatus, error) {
+	return s.engine.SyncStatuses(ctx)
 }
 
 func (s *Service) requireDevice(ctx context.Context, deviceID string) (devicedomain.Device, error) {
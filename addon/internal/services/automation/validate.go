@@ -3,6 +3,7 @@ package automation
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
@@ -48,18 +49,42 @@ func validateTemplate(template automationdomain.CapabilityTemplate, reg *registr
 		return fmt.Errorf("default_state %q is not declared in states", template.DefaultState)
 	}
 
-	for stateID, state := range template.States {
+	stateIDs := make([]string, 0, len(template.States))
+	for stateID := range template.States {
+		stateIDs = append(stateIDs, stateID)
+	}
+	sort.Strings(stateIDs)
+
+	for _, stateID := range stateIDs {
 		if strings.TrimSpace(stateID) == "" {
 			return fmt.Errorf("state key cannot be empty")
 		}
-		for _, action := range state.ActionsOnEnter {
-			actionType, ok := reg.Action(action.TypeID)
-			if !ok {
-				return fmt.Errorf("state %q has unknown action type %q", stateID, action.TypeID)
-			}
-			if err := actionType.Validate(target, action.Params); err != nil {
-				return fmt.Errorf("state %q action %q: %w", stateID, action.TypeID, err)
-			}
+	}
+	for i, transition := range template.Transitions {
+		if _, ok := template.States[transition.From]; !ok {
+			return fmt.Errorf("transitions[%d].from %q is not declared in states", i, transition.From)
+		}
+		if _, ok := template.States[transition.To]; !ok {
+			return fmt.Errorf("transitions[%d].to %q is not declared in states", i, transition.To)
+		}
+		if transition.From == transition.To {
+			return fmt.Errorf("transitions[%d] must change state", i)
+		}
+	}
+
+	var fieldErrors []automationdomain.FieldError
+	for _, location := range template.ActionLocations() {
+		action := location.Action
+		actionType, ok := reg.Action(action.TypeID)
+		if !ok {
+			return fmt.Errorf("%s has unknown action type %q", location.Path, action.TypeID)
+		}
+		if errs := validateParams(actionType.Metadata().ParamSchema, action.Params, location.Path+".params"); len(errs) > 0 {
+			fieldErrors = append(fieldErrors, errs...)
+			continue
+		}
+		if err := actionType.Validate(target, action.Params); err != nil {
+			return fmt.Errorf("%s action %q: %w", location.Path, action.TypeID, err)
 		}
 	}
 
@@ -71,7 +96,9 @@ func validateTemplate(template automationdomain.CapabilityTemplate, reg *registr
 		if !ok {
 			return fmt.Errorf("sync source type %q is not registered", template.Sync.Source.TypeID)
 		}
-		if err := source.Validate(target, template.Sync.Source.Params); err != nil {
+		if errs := validateParams(source.Metadata().ParamSchema, template.Sync.Source.Params, "sync.source.params"); len(errs) > 0 {
+			fieldErrors = append(fieldErrors, errs...)
+		} else if err := source.Validate(target, template.Sync.Source.Params); err != nil {
 			return fmt.Errorf("sync source params: %w", err)
 		}
 		if strings.TrimSpace(template.Sync.Mapping.WhenTrue) != "" {
@@ -84,9 +111,19 @@ func validateTemplate(template automationdomain.CapabilityTemplate, reg *registr
 				return fmt.Errorf("sync.mapping.when_false %q is not declared in states", template.Sync.Mapping.WhenFalse)
 			}
 		}
+		if template.Sync.IntervalSec < 0 {
+			return fmt.Errorf("sync.interval_sec must not be negative")
+		}
 		mode := strings.TrimSpace(strings.ToLower(template.Sync.Mode))
-		if mode != "" && mode != "external_truth" && mode != "internal_truth" {
-			return fmt.Errorf("sync.mode must be external_truth or internal_truth")
+		switch mode {
+		case "", automationdomain.SyncModeExternalTruth, automationdomain.SyncModeInternalTruth:
+		case automationdomain.SyncModeEnforce:
+			noMapping := strings.TrimSpace(template.Sync.Mapping.WhenTrue) == "" && strings.TrimSpace(template.Sync.Mapping.WhenFalse) == ""
+			if noMapping && source.Metadata().OutputType == automationdomain.StateOutputBoolean {
+				return fmt.Errorf("sync.mapping is required for enforce mode")
+			}
+		default:
+			return fmt.Errorf("sync.mode must be external_truth, internal_truth or enforce")
 		}
 	}
 
@@ -99,6 +136,9 @@ func validateTemplate(template automationdomain.CapabilityTemplate, reg *registr
 			return fmt.Errorf("ha_expose.entity_suffix is required when HA expose is enabled")
 		}
 	}
+	if len(fieldErrors) > 0 {
+		return &automationdomain.ParamValidationError{Fields: fieldErrors}
+	}
 	return nil
 }
 
@@ -125,6 +165,13 @@ func normalizeTemplate(template automationdomain.CapabilityTemplate) automationd
 		}
 		template.States[stateID] = state
 	}
+	for i := range template.Transitions {
+		template.Transitions[i].From = strings.TrimSpace(template.Transitions[i].From)
+		template.Transitions[i].To = strings.TrimSpace(template.Transitions[i].To)
+		if template.Transitions[i].Actions == nil {
+			template.Transitions[i].Actions = []automationdomain.ActionInstance{}
+		}
+	}
 	if template.Sync != nil {
 		template.Sync.Mode = normalizeSyncMode(template.Sync.Mode)
 		if template.Sync.Source.Params == nil {
@@ -136,10 +183,12 @@ func normalizeTemplate(template automationdomain.CapabilityTemplate) automationd
 
 func normalizeSyncMode(raw string) string {
 	switch strings.ToLower(strings.TrimSpace(raw)) {
-	case "internal_truth":
-		return "internal_truth"
+	case automationdomain.SyncModeInternalTruth:
+		return automationdomain.SyncModeInternalTruth
+	case automationdomain.SyncModeEnforce:
+		return automationdomain.SyncModeEnforce
 	default:
-		return "external_truth"
+		return automationdomain.SyncModeExternalTruth
 	}
 }
 
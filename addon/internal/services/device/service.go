@@ -11,6 +11,7 @@ import (
 
 	"github.com/micro-ha/mikrotik-presence/addon/internal/aggregator"
 	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/events"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/routeros"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/storage"
@@ -34,6 +35,11 @@ type Service struct {
 	config     RouterConfigProvider
 	thresholds model.PresenceThresholds
 	logger     *slog.Logger
+
+	ipListeners           []devicedomain.IPChangeListener
+	newDeviceListeners    []devicedomain.NewDeviceListener
+	registrationListeners []devicedomain.RegistrationListener
+	publisher             events.Publisher
 }
 
 // New creates device service with threshold defaults.
@@ -66,6 +72,37 @@ func NewWithThresholds(
 	}
 }
 
+// AddIPChangeListener registers listener notified about device IP changes.
+func (s *Service) AddIPChangeListener(listener devicedomain.IPChangeListener) {
+	if listener == nil {
+		return
+	}
+	s.ipListeners = append(s.ipListeners, listener)
+}
+
+// AddNewDeviceListener registers listener notified about first sight of
+// unregistered MACs.
+func (s *Service) AddNewDeviceListener(listener devicedomain.NewDeviceListener) {
+	if listener == nil {
+		return
+	}
+	s.newDeviceListeners = append(s.newDeviceListeners, listener)
+}
+
+// AddRegistrationListener registers listener notified after device registration.
+func (s *Service) AddRegistrationListener(listener devicedomain.RegistrationListener) {
+	if listener == nil {
+		return
+	}
+	s.registrationListeners = append(s.registrationListeners, listener)
+}
+
+// WithEvents publishes device status transitions and new devices.
+func (s *Service) WithEvents(publisher events.Publisher) *Service {
+	s.publisher = publisher
+	return s
+}
+
 // PollOnce fetches one RouterOS snapshot and persists aggregated state.
 func (s *Service) PollOnce(ctx context.Context) error {
 	cfg, ok := s.config.Get()
@@ -107,6 +144,10 @@ func (s *Service) persistSnapshot(ctx context.Context, observed map[string]model
 	states := make([]model.DeviceState, 0, len(allMACs))
 	cacheRows := make([]model.DeviceNewCache, 0, len(observed))
 	deleteMACs := make([]string, 0)
+	ipChanges := make([]devicedomain.IPChange, 0)
+	newMACs := make([]string, 0)
+	statusChanges := make([]events.DeviceStatusChange, 0)
+	newDevices := make([]events.NewDevice, 0)
 
 	for mac := range allMACs {
 		prev, hadPrev := prevStates[mac]
@@ -140,6 +181,20 @@ func (s *Service) persistSnapshot(ctx context.Context, observed map[string]model
 				next.LastSeenAt = obs.LastSeenAt
 			}
 			if obs.IP != "" {
+				if hadPrev {
+					previousIP := ""
+					if prev.LastIP != nil {
+						previousIP = strings.TrimSpace(*prev.LastIP)
+					}
+					if previousIP != obs.IP {
+						ipChanges = append(ipChanges, devicedomain.IPChange{
+							MAC:        mac,
+							PreviousIP: previousIP,
+							CurrentIP:  obs.IP,
+							DetectedAt: now,
+						})
+					}
+				}
 				ip := obs.IP
 				next.LastIP = &ip
 			}
@@ -156,6 +211,9 @@ func (s *Service) persistSnapshot(ctx context.Context, observed map[string]model
 			cache, hasCache := newCache[mac]
 			if !hasCache {
 				cache = model.DeviceNewCache{MAC: mac, FirstSeenAt: now}
+				if !isRegistered {
+					newMACs = append(newMACs, mac)
+				}
 			}
 			if cache.FirstSeenAt.IsZero() {
 				cache.FirstSeenAt = now
@@ -171,6 +229,14 @@ func (s *Service) persistSnapshot(ctx context.Context, observed map[string]model
 				cache.GeneratedName = obs.Generated
 			}
 			cacheRows = append(cacheRows, cache)
+			if !hasCache && !isRegistered {
+				newDevices = append(newDevices, events.NewDevice{
+					MAC:    mac,
+					Name:   cache.GeneratedName,
+					Vendor: cache.Vendor,
+					IP:     obs.IP,
+				})
+			}
 		} else {
 			next.Online = false
 			next.LastSourcesJSON = "[]"
@@ -178,6 +244,21 @@ func (s *Service) persistSnapshot(ctx context.Context, observed map[string]model
 			next.ConnectionStatus = string(status)
 			next.StatusReason = reason
 		}
+		if !hadPrev || prev.ConnectionStatus != next.ConnectionStatus {
+			change := events.DeviceStatusChange{
+				MAC:    mac,
+				Status: next.ConnectionStatus,
+				Reason: next.StatusReason,
+				Online: next.Online,
+			}
+			if hadPrev {
+				change.PreviousStatus = prev.ConnectionStatus
+			}
+			if next.LastIP != nil {
+				change.IP = *next.LastIP
+			}
+			statusChanges = append(statusChanges, change)
+		}
 		states = append(states, next)
 	}
 
@@ -197,9 +278,62 @@ func (s *Service) persistSnapshot(ctx context.Context, observed map[string]model
 			return err
 		}
 	}
+	s.notifyIPChanges(ctx, ipChanges)
+	s.notifyNewDevices(ctx, newMACs)
+	s.publishSnapshotEvents(statusChanges, newDevices)
 	return nil
 }
 
+func (s *Service) publishSnapshotEvents(changes []events.DeviceStatusChange, devices []events.NewDevice) {
+	if s.publisher == nil {
+		return
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].MAC < changes[j].MAC })
+	sort.Slice(devices, func(i, j int) bool { return devices[i].MAC < devices[j].MAC })
+	for _, device := range devices {
+		s.publisher.Publish(events.TypeDeviceNew, device)
+	}
+	for _, change := range changes {
+		s.publisher.Publish(events.TypeDeviceStatus, change)
+	}
+}
+
+func (s *Service) notifyNewDevices(ctx context.Context, macs []string) {
+	if len(macs) == 0 || len(s.newDeviceListeners) == 0 {
+		return
+	}
+	sort.Strings(macs)
+	for _, mac := range macs {
+		for _, listener := range s.newDeviceListeners {
+			if err := listener.HandleNewDevice(ctx, mac); err != nil && s.logger != nil {
+				s.logger.Warn("new device listener failed", "mac", mac, "err", err)
+			}
+		}
+	}
+}
+
+func (s *Service) notifyIPChanges(ctx context.Context, changes []devicedomain.IPChange) {
+	if len(changes) == 0 || len(s.ipListeners) == 0 {
+		return
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].MAC < changes[j].MAC })
+	for _, change := range changes {
+		if s.logger != nil {
+			s.logger.Info(
+				"device ip changed",
+				"mac", change.MAC,
+				"previous_ip", change.PreviousIP,
+				"current_ip", change.CurrentIP,
+			)
+		}
+		for _, listener := range s.ipListeners {
+			if err := listener.HandleDeviceIPChange(ctx, change); err != nil && s.logger != nil {
+				s.logger.Warn("device ip change listener failed", "mac", change.MAC, "err", err)
+			}
+		}
+	}
+}
+
 // ListDevices returns filtered device list for API.
 func (s *Service) ListDevices(ctx context.Context, filter devicedomain.ListFilter) ([]devicedomain.Device, error) {
 	states, err := s.repo.LoadAllStates(ctx)
@@ -259,16 +393,34 @@ func (s *Service) GetDevice(ctx context.Context, mac string) (devicedomain.Devic
 
 // RegisterDevice creates or updates registered metadata for MAC.
 func (s *Service) RegisterDevice(ctx context.Context, mac string, in devicedomain.RegisterInput) error {
-	return s.repo.UpsertRegistered(ctx, normalizeMAC(mac), in.Name, in.Icon, in.Comment)
+	mac = normalizeMAC(mac)
+	if err := s.repo.UpsertRegistered(ctx, mac, in.Name, in.Icon, in.Comment); err != nil {
+		return err
+	}
+	s.notifyRegistered(ctx, mac)
+	return nil
 }
 
 // PatchDevice updates partial registered metadata for MAC.
 func (s *Service) PatchDevice(ctx context.Context, mac string, in devicedomain.RegisterInput) error {
-	err := s.repo.PatchRegistered(ctx, normalizeMAC(mac), in.Name, in.Icon, in.Comment)
+	mac = normalizeMAC(mac)
+	err := s.repo.PatchRegistered(ctx, mac, in.Name, in.Icon, in.Comment)
 	if errors.Is(err, storage.ErrNotFound) {
 		return devicedomain.ErrDeviceNotFound
 	}
-	return err
+	if err != nil {
+		return err
+	}
+	s.notifyRegistered(ctx, mac)
+	return nil
+}
+
+func (s *Service) notifyRegistered(ctx context.Context, mac string) {
+	for _, listener := range s.registrationListeners {
+		if err := listener.HandleDeviceRegistered(ctx, mac); err != nil && s.logger != nil {
+			s.logger.Warn("device registration listener failed", "mac", mac, "err", err)
+		}
+	}
 }
 
 func filterViews(items []model.DeviceView, filter devicedomain.ListFilter) []model.DeviceView {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/events"
 	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
 )
 
@@ -180,3 +181,184 @@ func TestPersistSnapshotRemovesUnregisteredWhenNotObserved(t *testing.T) {
 		t.Fatalf("unexpected deleted rows: %v", repo.deletedRows)
 	}
 }
+
+type recordingIPListener struct {
+	changes []devicedomain.IPChange
+}
+
+func (l *recordingIPListener) HandleDeviceIPChange(ctx context.Context, change devicedomain.IPChange) error {
+	_ = ctx
+	l.changes = append(l.changes, change)
+	return nil
+}
+
+func TestPersistSnapshotNotifiesIPChange(t *testing.T) {
+	repo := newMemoryRepo()
+	mac := "AA:BB:CC:DD:EE:44"
+	previousIP := "192.168.88.44"
+	repo.states[mac] = devicedomain.State{
+		MAC:              mac,
+		LastIP:           &previousIP,
+		ConnectionStatus: string(model.ConnectionStatusOnline),
+		UpdatedAt:        time.Now().UTC(),
+	}
+	repo.registered[mac] = devicedomain.Registered{MAC: mac, CreatedAt: time.Now().UTC()}
+
+	listener := &recordingIPListener{}
+	svc := &Service{repo: repo, thresholds: model.DefaultPresenceThresholds()}
+	svc.AddIPChangeListener(listener)
+
+	observed := map[string]model.Observation{
+		mac: {
+			MAC:              mac,
+			IP:               "192.168.88.45",
+			ObservedAt:       time.Now().UTC(),
+			ConnectionStatus: model.ConnectionStatusOnline,
+			Sources:          []string{model.SourceDHCP},
+		},
+	}
+	if err := svc.persistSnapshot(context.Background(), observed); err != nil {
+		t.Fatalf("persistSnapshot failed: %v", err)
+	}
+	if err := svc.persistSnapshot(context.Background(), observed); err != nil {
+		t.Fatalf("persistSnapshot failed: %v", err)
+	}
+
+	if len(listener.changes) != 1 {
+		t.Fatalf("expected one ip change, got %d", len(listener.changes))
+	}
+	change := listener.changes[0]
+	if change.MAC != mac || change.PreviousIP != previousIP || change.CurrentIP != "192.168.88.45" {
+		t.Fatalf("unexpected ip change: %+v", change)
+	}
+}
+
+func TestPersistSnapshotNotifiesFirstIPAssignment(t *testing.T) {
+	repo := newMemoryRepo()
+	mac := "AA:BB:CC:DD:EE:45"
+	repo.states[mac] = devicedomain.State{
+		MAC:              mac,
+		ConnectionStatus: string(model.ConnectionStatusOnline),
+		UpdatedAt:        time.Now().UTC(),
+	}
+
+	listener := &recordingIPListener{}
+	svc := &Service{repo: repo, thresholds: model.DefaultPresenceThresholds()}
+	svc.AddIPChangeListener(listener)
+
+	observed := map[string]model.Observation{
+		mac: {
+			MAC:              mac,
+			IP:               "192.168.88.46",
+			ObservedAt:       time.Now().UTC(),
+			ConnectionStatus: model.ConnectionStatusOnline,
+			Sources:          []string{model.SourceDHCP},
+		},
+	}
+	if err := svc.persistSnapshot(context.Background(), observed); err != nil {
+		t.Fatalf("persistSnapshot failed: %v", err)
+	}
+	if len(listener.changes) != 1 || listener.changes[0].PreviousIP != "" || listener.changes[0].CurrentIP != "192.168.88.46" {
+		t.Fatalf("expected first ip assignment change, got %+v", listener.changes)
+	}
+}
+
+type recordingOnboardingListener struct {
+	newMACs        []string
+	registeredMACs []string
+}
+
+func (l *recordingOnboardingListener) HandleNewDevice(ctx context.Context, mac string) error {
+	_ = ctx
+	l.newMACs = append(l.newMACs, mac)
+	return nil
+}
+
+func (l *recordingOnboardingListener) HandleDeviceRegistered(ctx context.Context, mac string) error {
+	_ = ctx
+	l.registeredMACs = append(l.registeredMACs, mac)
+	return nil
+}
+
+func TestPersistSnapshotNotifiesNewUnregisteredDevicesOnce(t *testing.T) {
+	t.Helper()
+
+	repo := newMemoryRepo()
+	known := "AA:BB:CC:DD:EE:55"
+	repo.registered[known] = devicedomain.Registered{MAC: known, CreatedAt: time.Now().UTC()}
+
+	listener := &recordingOnboardingListener{}
+	svc := &Service{repo: repo, thresholds: model.DefaultPresenceThresholds()}
+	svc.AddNewDeviceListener(listener)
+	svc.AddRegistrationListener(listener)
+
+	observed := map[string]model.Observation{}
+	for _, mac := range []string{known, "AA:BB:CC:DD:EE:66"} {
+		observed[mac] = model.Observation{
+			MAC:              mac,
+			ObservedAt:       time.Now().UTC(),
+			ConnectionStatus: model.ConnectionStatusOnline,
+			Sources:          []string{model.SourceDHCP},
+		}
+	}
+	for i := 0; i < 2; i++ {
+		if err := svc.persistSnapshot(context.Background(), observed); err != nil {
+			t.Fatalf("persistSnapshot failed: %v", err)
+		}
+	}
+	if !reflect.DeepEqual(listener.newMACs, []string{"AA:BB:CC:DD:EE:66"}) {
+		t.Fatalf("unexpected new devices: %v", listener.newMACs)
+	}
+
+	if err := svc.RegisterDevice(context.Background(), "aa-bb-cc-dd-ee-66", devicedomain.RegisterInput{}); err != nil {
+		t.Fatalf("RegisterDevice failed: %v", err)
+	}
+	if !reflect.DeepEqual(listener.registeredMACs, []string{"AA:BB:CC:DD:EE:66"}) {
+		t.Fatalf("unexpected registrations: %v", listener.registeredMACs)
+	}
+}
+
+type recordingPublisher struct {
+	published []events.Event
+}
+
+func (p *recordingPublisher) Publish(eventType string, data any) events.Event {
+	event := events.Event{ID: uint64(len(p.published) + 1), Type: eventType, Data: data}
+	p.published = append(p.published, event)
+	return event
+}
+
+func TestPersistSnapshotPublishesNewDeviceAndStatusEvents(t *testing.T) {
+	t.Helper()
+
+	repo := newMemoryRepo()
+	publisher := &recordingPublisher{}
+	svc := (&Service{repo: repo, thresholds: model.DefaultPresenceThresholds()}).WithEvents(publisher)
+
+	mac := "AA:BB:CC:DD:EE:77"
+	observed := map[string]model.Observation{
+		mac: {
+			MAC:              mac,
+			ObservedAt:       time.Now().UTC(),
+			ConnectionStatus: model.ConnectionStatusOnline,
+			Sources:          []string{model.SourceDHCP},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		if err := svc.persistSnapshot(context.Background(), observed); err != nil {
+			t.Fatalf("persistSnapshot failed: %v", err)
+		}
+	}
+
+	var types []string
+	for _, event := range publisher.published {
+		types = append(types, event.Type)
+	}
+	if !reflect.DeepEqual(types, []string{events.TypeDeviceNew, events.TypeDeviceStatus}) {
+		t.Fatalf("unexpected events: %v", types)
+	}
+	change, ok := publisher.published[1].Data.(events.DeviceStatusChange)
+	if !ok || change.MAC != mac || change.Status != string(model.ConnectionStatusOnline) || change.PreviousStatus != "" {
+		t.Fatalf("unexpected status change: %#v", publisher.published[1].Data)
+	}
+}
@@ -0,0 +1,376 @@
+package quarantine
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	quarantinedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/quarantine"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+// ownerID tags router objects written by onboarding policy.
+const ownerID = "quarantine"
+
+const (
+	taskQueueSize = 256
+	taskTimeout   = 30 * time.Second
+)
+
+// RouterClient defines router writes used to hold and release devices.
+type RouterClient interface {
+	EnsureWiFiAccessRule(ctx context.Context, cfg model.RouterConfig, mac string, policy model.WiFiAccessPolicy, comment string) error
+	RemoveWiFiAccessRule(ctx context.Context, cfg model.RouterConfig, mac, comment string) error
+	DisconnectWiFiClient(ctx context.Context, cfg model.RouterConfig, mac string) (int, error)
+	AddManagedAddressListEntry(ctx context.Context, cfg model.RouterConfig, list, address, comment, timeout string) error
+	RemoveAddressListEntry(ctx context.Context, cfg model.RouterConfig, list, address string) error
+}
+
+// RouterConfigProvider supplies current add-on router config.
+type RouterConfigProvider interface {
+	Get() (model.RouterConfig, bool)
+}
+
+// DeviceService reads and registers devices.
+type DeviceService interface {
+	GetDevice(ctx context.Context, mac string) (devicedomain.Device, error)
+	RegisterDevice(ctx context.Context, mac string, in devicedomain.RegisterInput) error
+}
+
+// Service implements quarantine.Service and places unknown MACs on hold
+// according to add-on onboarding policy.
+type Service struct {
+	repo    quarantinedomain.Repository
+	devices DeviceService
+	router  RouterClient
+	config  RouterConfigProvider
+	policy  model.QuarantineConfig
+	logger  *slog.Logger
+	tasks   chan task
+
+	mu sync.Mutex
+}
+
+// task is router work queued from device poll path and run by Run.
+type task struct {
+	mac string
+	run func(ctx context.Context) error
+}
+
+// New creates quarantine service.
+func New(
+	repo quarantinedomain.Repository,
+	devices DeviceService,
+	client RouterClient,
+	cfg RouterConfigProvider,
+	policy model.QuarantineConfig,
+	logger *slog.Logger,
+) *Service {
+	return &Service{
+		repo:    repo,
+		devices: devices,
+		router:  client,
+		config:  cfg,
+		policy:  policy,
+		logger:  logger,
+		tasks:   make(chan task, taskQueueSize),
+	}
+}
+
+// Run applies queued holds until ctx is cancelled, so slow router writes
+// never stall device polling.
+func (s *Service) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case next := <-s.tasks:
+			taskCtx, cancel := context.WithTimeout(ctx, taskTimeout)
+			err := next.run(taskCtx)
+			cancel()
+			if err != nil && s.logger != nil {
+				s.logger.Warn("quarantine task failed", "mac", next.mac, "err", err)
+			}
+		}
+	}
+}
+
+// enqueue hands work to Run; it only blocks when queue is full.
+func (s *Service) enqueue(ctx context.Context, mac string, run func(ctx context.Context) error) error {
+	select {
+	case s.tasks <- task{mac: mac, run: run}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// HandleNewDevice queues quarantine of first-seen unregistered MAC when
+// policy is on.
+func (s *Service) HandleNewDevice(ctx context.Context, mac string) error {
+	if !s.policy.Enabled() {
+		return nil
+	}
+	return s.enqueue(ctx, mac, func(ctx context.Context) error {
+		return s.quarantine(ctx, mac)
+	})
+}
+
+// quarantine holds new MAC. Address-list mode needs an IP, so devices first
+// seen without one stay pending, are audited as apply_failed and get held
+// once their first IP change arrives.
+func (s *Service) quarantine(ctx context.Context, mac string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok, err := s.repo.GetEntry(ctx, mac); err != nil || ok {
+		return err
+	}
+	now := time.Now().UTC()
+	entry := quarantinedomain.Entry{
+		MAC:       mac,
+		Status:    quarantinedomain.StatusPending,
+		Mode:      s.policy.Mode,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if s.policy.Mode == model.QuarantineAddressList {
+		if device, err := s.devices.GetDevice(ctx, mac); err == nil && device.LastIP != nil {
+			entry.Address = strings.TrimSpace(*device.LastIP)
+		}
+	}
+	if err := s.repo.UpsertEntry(ctx, entry); err != nil {
+		return err
+	}
+	if err := s.hold(ctx, entry); err != nil {
+		s.audit(ctx, mac, quarantinedomain.AuditApplyFailed, quarantinedomain.ActorPolicy, err.Error())
+		return err
+	}
+	s.audit(ctx, mac, quarantinedomain.AuditQuarantined, quarantinedomain.ActorPolicy, s.describe(entry))
+	return nil
+}
+
+// HandleDeviceRegistered releases quarantined MAC once it is registered.
+func (s *Service) HandleDeviceRegistered(ctx context.Context, mac string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok, err := s.repo.GetEntry(ctx, mac)
+	if err != nil || !ok {
+		return err
+	}
+	return s.release(ctx, entry, quarantinedomain.AuditReleased, "registered")
+}
+
+// HandleDeviceIPChange queues moving address-list quarantine to device's
+// new IP.
+func (s *Service) HandleDeviceIPChange(ctx context.Context, change devicedomain.IPChange) error {
+	return s.enqueue(ctx, change.MAC, func(ctx context.Context) error {
+		return s.followIP(ctx, change)
+	})
+}
+
+// followIP moves hold to current IP, or applies it for the first time when
+// device was quarantined before it had an address.
+func (s *Service) followIP(ctx context.Context, change devicedomain.IPChange) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok, err := s.repo.GetEntry(ctx, change.MAC)
+	if err != nil || !ok || entry.Mode != model.QuarantineAddressList || entry.Address == change.CurrentIP {
+		return err
+	}
+	cfg, ok := s.config.Get()
+	if !ok {
+		return devicedomain.ErrAddonNotConfigured
+	}
+	firstAddress := entry.Address == ""
+	if !firstAddress {
+		if err := s.router.RemoveAddressListEntry(ctx, cfg, s.policy.AddressList, entry.Address); err != nil {
+			return fmt.Errorf("remove quarantined address %s: %w", entry.Address, err)
+		}
+	}
+	entry.Address = change.CurrentIP
+	entry.UpdatedAt = time.Now().UTC()
+	if err := s.repo.UpsertEntry(ctx, entry); err != nil {
+		return err
+	}
+	if err := s.hold(ctx, entry); err != nil {
+		s.audit(ctx, entry.MAC, quarantinedomain.AuditApplyFailed, quarantinedomain.ActorPolicy, err.Error())
+		return err
+	}
+	if firstAddress {
+		s.audit(ctx, entry.MAC, quarantinedomain.AuditQuarantined, quarantinedomain.ActorPolicy, s.describe(entry))
+	}
+	return nil
+}
+
+// ListEntries returns quarantine queue, optionally narrowed to one status.
+func (s *Service) ListEntries(ctx context.Context, status quarantinedomain.Status) ([]quarantinedomain.Entry, error) {
+	switch status {
+	case "", quarantinedomain.StatusPending, quarantinedomain.StatusDenied:
+	default:
+		return nil, quarantinedomain.ErrStatusInvalid
+	}
+	return s.repo.ListEntries(ctx, status)
+}
+
+// Approve releases quarantined MAC and registers it with in metadata.
+func (s *Service) Approve(ctx context.Context, mac string, in devicedomain.RegisterInput) error {
+	mac = normalizeMAC(mac)
+	s.mu.Lock()
+	entry, ok, err := s.repo.GetEntry(ctx, mac)
+	if err == nil && !ok {
+		err = quarantinedomain.ErrNotQuarantined
+	}
+	if err == nil {
+		err = s.release(ctx, entry, quarantinedomain.AuditApproved, "")
+	}
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return s.devices.RegisterDevice(ctx, mac, in)
+}
+
+// Deny keeps MAC out of the network: wifi clients get a deny entry,
+// address-list quarantine stays in place.
+func (s *Service) Deny(ctx context.Context, mac string) error {
+	mac = normalizeMAC(mac)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok, err := s.repo.GetEntry(ctx, mac)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return quarantinedomain.ErrNotQuarantined
+	}
+	if entry.Status == quarantinedomain.StatusDenied {
+		return nil
+	}
+	entry.Status = quarantinedomain.StatusDenied
+	entry.UpdatedAt = time.Now().UTC()
+	if err := s.hold(ctx, entry); err != nil {
+		return err
+	}
+	if err := s.repo.UpsertEntry(ctx, entry); err != nil {
+		return err
+	}
+	s.audit(ctx, mac, quarantinedomain.AuditDenied, quarantinedomain.ActorAPI, s.describe(entry))
+	return nil
+}
+
+// ListAudit returns newest onboarding audit events first.
+func (s *Service) ListAudit(ctx context.Context, filter quarantinedomain.AuditFilter) ([]quarantinedomain.AuditEvent, error) {
+	if filter.MAC != "" {
+		filter.MAC = normalizeMAC(filter.MAC)
+	}
+	return s.repo.ListAuditEvents(ctx, filter)
+}
+
+// hold applies entry restriction on router.
+func (s *Service) hold(ctx context.Context, entry quarantinedomain.Entry) error {
+	cfg, ok := s.config.Get()
+	if !ok {
+		return devicedomain.ErrAddonNotConfigured
+	}
+	comment := ownershipComment(entry.MAC)
+	switch entry.Mode {
+	case model.QuarantineWiFiVLAN:
+		policy := model.WiFiAccessPolicy{Action: model.WiFiAccessAllow, VLANID: s.policy.VLANID}
+		if entry.Status == quarantinedomain.StatusDenied {
+			policy = model.WiFiAccessPolicy{Action: model.WiFiAccessDeny}
+		}
+		if err := s.router.EnsureWiFiAccessRule(ctx, cfg, entry.MAC, policy, comment); err != nil {
+			return err
+		}
+		if _, err := s.router.DisconnectWiFiClient(ctx, cfg, entry.MAC); err != nil {
+			return fmt.Errorf("disconnect wifi client %s: %w", entry.MAC, err)
+		}
+		return nil
+	case model.QuarantineAddressList:
+		if entry.Address == "" {
+			return fmt.Errorf("device %s has no IP address yet", entry.MAC)
+		}
+		return s.router.AddManagedAddressListEntry(ctx, cfg, s.policy.AddressList, entry.Address, comment, "")
+	default:
+		return fmt.Errorf("unsupported quarantine mode %q", entry.Mode)
+	}
+}
+
+// release undoes entry restriction, drops the entry and records action.
+func (s *Service) release(ctx context.Context, entry quarantinedomain.Entry, action, detail string) error {
+	cfg, ok := s.config.Get()
+	if !ok {
+		return devicedomain.ErrAddonNotConfigured
+	}
+	switch entry.Mode {
+	case model.QuarantineWiFiVLAN:
+		if err := s.router.RemoveWiFiAccessRule(ctx, cfg, entry.MAC, ownershipComment(entry.MAC)); err != nil {
+			return err
+		}
+		if _, err := s.router.DisconnectWiFiClient(ctx, cfg, entry.MAC); err != nil {
+			return fmt.Errorf("disconnect wifi client %s: %w", entry.MAC, err)
+		}
+	case model.QuarantineAddressList:
+		if entry.Address != "" {
+			if err := s.router.RemoveAddressListEntry(ctx, cfg, s.policy.AddressList, entry.Address); err != nil {
+				return err
+			}
+		}
+	}
+	if err := s.repo.DeleteEntry(ctx, entry.MAC); err != nil {
+		return err
+	}
+	actor := quarantinedomain.ActorAPI
+	if action == quarantinedomain.AuditReleased {
+		actor = quarantinedomain.ActorPolicy
+	}
+	s.audit(ctx, entry.MAC, action, actor, detail)
+	return nil
+}
+
+func (s *Service) describe(entry quarantinedomain.Entry) string {
+	switch {
+	case entry.Mode == model.QuarantineWiFiVLAN && entry.Status == quarantinedomain.StatusDenied:
+		return "wifi access denied"
+	case entry.Mode == model.QuarantineWiFiVLAN:
+		return fmt.Sprintf("wifi vlan %d", s.policy.VLANID)
+	default:
+		return fmt.Sprintf("address list %s (%s)", s.policy.AddressList, entry.Address)
+	}
+}
+
+func (s *Service) audit(ctx context.Context, mac, action, actor, detail string) {
+	err := s.repo.InsertAuditEvent(ctx, quarantinedomain.AuditEvent{
+		MAC:       mac,
+		Action:    action,
+		Actor:     actor,
+		Detail:    detail,
+		CreatedAt: time.Now().UTC(),
+	})
+	if err != nil && s.logger != nil {
+		s.logger.Warn("quarantine audit write failed", "mac", mac, "action", action, "err", err)
+	}
+}
+
+func ownershipComment(mac string) string {
+	return automationdomain.OwnershipComment(automationdomain.OwnershipTag{CapabilityID: ownerID, DeviceID: mac})
+}
+
+func normalizeMAC(mac string) string {
+	mac = strings.TrimSpace(mac)
+	if decoded, err := url.PathUnescape(mac); err == nil {
+		mac = decoded
+	}
+	mac = strings.ReplaceAll(mac, " ", "")
+	return strings.ToUpper(strings.ReplaceAll(mac, "-", ":"))
+}
@@ -0,0 +1,376 @@
+package quarantine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	quarantinedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/quarantine"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+)
+
+type memoryRepo struct {
+	entries map[string]quarantinedomain.Entry
+	audit   []quarantinedomain.AuditEvent
+}
+
+func newMemoryRepo() *memoryRepo {
+	return &memoryRepo{entries: map[string]quarantinedomain.Entry{}}
+}
+
+func (r *memoryRepo) GetEntry(ctx context.Context, mac string) (quarantinedomain.Entry, bool, error) {
+	_ = ctx
+	entry, ok := r.entries[mac]
+	return entry, ok, nil
+}
+
+func (r *memoryRepo) ListEntries(ctx context.Context, status quarantinedomain.Status) ([]quarantinedomain.Entry, error) {
+	_ = ctx
+	items := make([]quarantinedomain.Entry, 0, len(r.entries))
+	for _, entry := range r.entries {
+		if status == "" || entry.Status == status {
+			items = append(items, entry)
+		}
+	}
+	return items, nil
+}
+
+func (r *memoryRepo) UpsertEntry(ctx context.Context, entry quarantinedomain.Entry) error {
+	_ = ctx
+	r.entries[entry.MAC] = entry
+	return nil
+}
+
+func (r *memoryRepo) DeleteEntry(ctx context.Context, mac string) error {
+	_ = ctx
+	delete(r.entries, mac)
+	return nil
+}
+
+func (r *memoryRepo) InsertAuditEvent(ctx context.Context, event quarantinedomain.AuditEvent) error {
+	_ = ctx
+	r.audit = append(r.audit, event)
+	return nil
+}
+
+func (r *memoryRepo) ListAuditEvents(ctx context.Context, filter quarantinedomain.AuditFilter) ([]quarantinedomain.AuditEvent, error) {
+	_ = ctx
+	_ = filter
+	return r.audit, nil
+}
+
+func (r *memoryRepo) actions() []string {
+	out := make([]string, 0, len(r.audit))
+	for _, event := range r.audit {
+		out = append(out, event.Action)
+	}
+	return out
+}
+
+type fakeRouter struct {
+	wifiRules    map[string]model.WiFiAccessPolicy
+	addressList  map[string]string
+	disconnects  int
+	wifiAccessOK bool
+}
+
+func newFakeRouter() *fakeRouter {
+	return &fakeRouter{
+		wifiRules:    map[string]model.WiFiAccessPolicy{},
+		addressList:  map[string]string{},
+		wifiAccessOK: true,
+	}
+}
+
+func (r *fakeRouter) EnsureWiFiAccessRule(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	mac string,
+	policy model.WiFiAccessPolicy,
+	comment string,
+) error {
+	_, _, _ = ctx, cfg, comment
+	if !r.wifiAccessOK {
+		return fmt.Errorf("router has no wifi driver with access list")
+	}
+	r.wifiRules[mac] = policy
+	return nil
+}
+
+func (r *fakeRouter) RemoveWiFiAccessRule(ctx context.Context, cfg model.RouterConfig, mac, comment string) error {
+	_, _, _ = ctx, cfg, comment
+	delete(r.wifiRules, mac)
+	return nil
+}
+
+func (r *fakeRouter) DisconnectWiFiClient(ctx context.Context, cfg model.RouterConfig, mac string) (int, error) {
+	_, _, _ = ctx, cfg, mac
+	r.disconnects++
+	return 1, nil
+}
+
+func (r *fakeRouter) AddManagedAddressListEntry(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list, address, comment, timeout string,
+) error {
+	_, _, _ = ctx, cfg, timeout
+	r.addressList[address] = list + "|" + comment
+	return nil
+}
+
+func (r *fakeRouter) RemoveAddressListEntry(ctx context.Context, cfg model.RouterConfig, list, address string) error {
+	_, _, _ = ctx, cfg, list
+	delete(r.addressList, address)
+	return nil
+}
+
+type staticConfig struct{}
+
+func (staticConfig) Get() (model.RouterConfig, bool) {
+	return model.RouterConfig{Host: "router"}, true
+}
+
+type fakeDevices struct {
+	ips        map[string]string
+	registered []string
+	listener   devicedomain.RegistrationListener
+}
+
+func (d *fakeDevices) GetDevice(ctx context.Context, mac string) (devicedomain.Device, error) {
+	_ = ctx
+	ip, ok := d.ips[mac]
+	if !ok {
+		return devicedomain.Device{}, devicedomain.ErrDeviceNotFound
+	}
+	return devicedomain.Device{MAC: mac, LastIP: &ip}, nil
+}
+
+func (d *fakeDevices) RegisterDevice(ctx context.Context, mac string, in devicedomain.RegisterInput) error {
+	_ = in
+	d.registered = append(d.registered, mac)
+	if d.listener != nil {
+		return d.listener.HandleDeviceRegistered(ctx, mac)
+	}
+	return nil
+}
+
+// runQueued executes work queued from poll path the way Run does.
+func runQueued(ctx context.Context, svc *Service) error {
+	var errs []error
+	for {
+		select {
+		case next := <-svc.tasks:
+			errs = append(errs, next.run(ctx))
+		default:
+			return errors.Join(errs...)
+		}
+	}
+}
+
+func TestWiFiVLANQuarantineApproveFlow(t *testing.T) {
+	repo := newMemoryRepo()
+	router := newFakeRouter()
+	devices := &fakeDevices{}
+	svc := New(repo, devices, router, staticConfig{}, model.QuarantineConfig{
+		Mode:   model.QuarantineWiFiVLAN,
+		VLANID: 666,
+	}, nil)
+	devices.listener = svc
+
+	ctx := context.Background()
+	mac := "AA:BB:CC:DD:EE:01"
+	if err := svc.HandleNewDevice(ctx, mac); err != nil {
+		t.Fatalf("HandleNewDevice() error = %v", err)
+	}
+	if err := runQueued(ctx, svc); err != nil {
+		t.Fatalf("queued quarantine error = %v", err)
+	}
+	if got := router.wifiRules[mac]; got.Action != model.WiFiAccessAllow || got.VLANID != 666 {
+		t.Fatalf("expected quarantine vlan entry, got %+v", got)
+	}
+	if router.disconnects != 1 {
+		t.Fatalf("expected client reconnect into vlan, got %d disconnects", router.disconnects)
+	}
+	pending, _ := svc.ListEntries(ctx, quarantinedomain.StatusPending)
+	if len(pending) != 1 || pending[0].MAC != mac {
+		t.Fatalf("expected pending entry, got %+v", pending)
+	}
+
+	if err := svc.HandleNewDevice(ctx, mac); err != nil {
+		t.Fatalf("HandleNewDevice() repeat error = %v", err)
+	}
+	if err := runQueued(ctx, svc); err != nil {
+		t.Fatalf("queued quarantine repeat error = %v", err)
+	}
+	if router.disconnects != 1 {
+		t.Fatal("expected repeated first sight to be ignored")
+	}
+
+	name := "Laptop"
+	if err := svc.Approve(ctx, mac, devicedomain.RegisterInput{Name: &name}); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if _, ok := router.wifiRules[mac]; ok {
+		t.Fatal("expected wifi access entry removed on approve")
+	}
+	if len(devices.registered) != 1 || devices.registered[0] != mac {
+		t.Fatalf("expected device registered on approve, got %v", devices.registered)
+	}
+	if _, ok := repo.entries[mac]; ok {
+		t.Fatal("expected entry removed from queue")
+	}
+	if got := fmt.Sprint(repo.actions()); got != "[quarantined approved]" {
+		t.Fatalf("unexpected audit trail %s", got)
+	}
+
+	if err := svc.Approve(ctx, mac, devicedomain.RegisterInput{}); !errors.Is(err, quarantinedomain.ErrNotQuarantined) {
+		t.Fatalf("Approve() on released device error = %v, want ErrNotQuarantined", err)
+	}
+}
+
+func TestWiFiVLANQuarantineDeny(t *testing.T) {
+	repo := newMemoryRepo()
+	router := newFakeRouter()
+	svc := New(repo, &fakeDevices{}, router, staticConfig{}, model.QuarantineConfig{
+		Mode:   model.QuarantineWiFiVLAN,
+		VLANID: 666,
+	}, nil)
+
+	ctx := context.Background()
+	mac := "AA:BB:CC:DD:EE:02"
+	if err := svc.HandleNewDevice(ctx, mac); err != nil {
+		t.Fatalf("HandleNewDevice() error = %v", err)
+	}
+	if err := runQueued(ctx, svc); err != nil {
+		t.Fatalf("queued quarantine error = %v", err)
+	}
+	if err := svc.Deny(ctx, mac); err != nil {
+		t.Fatalf("Deny() error = %v", err)
+	}
+	if got := router.wifiRules[mac]; got.Action != model.WiFiAccessDeny {
+		t.Fatalf("expected deny entry, got %+v", got)
+	}
+	if repo.entries[mac].Status != quarantinedomain.StatusDenied {
+		t.Fatalf("expected denied status, got %+v", repo.entries[mac])
+	}
+	if got := fmt.Sprint(repo.actions()); got != "[quarantined denied]" {
+		t.Fatalf("unexpected audit trail %s", got)
+	}
+}
+
+func TestAddressListQuarantineFollowsIPAndReleasesOnRegister(t *testing.T) {
+	repo := newMemoryRepo()
+	router := newFakeRouter()
+	mac := "AA:BB:CC:DD:EE:03"
+	devices := &fakeDevices{ips: map[string]string{mac: "192.168.88.30"}}
+	svc := New(repo, devices, router, staticConfig{}, model.QuarantineConfig{
+		Mode:        model.QuarantineAddressList,
+		AddressList: "quarantine",
+	}, nil)
+
+	ctx := context.Background()
+	if err := svc.HandleNewDevice(ctx, mac); err != nil {
+		t.Fatalf("HandleNewDevice() error = %v", err)
+	}
+	if err := runQueued(ctx, svc); err != nil {
+		t.Fatalf("queued quarantine error = %v", err)
+	}
+	if got := router.addressList["192.168.88.30"]; got != "quarantine|mikrotik-presence:quarantine/"+mac {
+		t.Fatalf("expected owned quarantine address-list entry, got %q", got)
+	}
+
+	err := svc.HandleDeviceIPChange(ctx, devicedomain.IPChange{MAC: mac, PreviousIP: "192.168.88.30", CurrentIP: "192.168.88.31"})
+	if err != nil {
+		t.Fatalf("HandleDeviceIPChange() error = %v", err)
+	}
+	if err := runQueued(ctx, svc); err != nil {
+		t.Fatalf("queued ip change error = %v", err)
+	}
+	if _, ok := router.addressList["192.168.88.30"]; ok || router.addressList["192.168.88.31"] == "" {
+		t.Fatalf("expected quarantine moved to new ip, got %v", router.addressList)
+	}
+
+	if err := svc.HandleDeviceRegistered(ctx, mac); err != nil {
+		t.Fatalf("HandleDeviceRegistered() error = %v", err)
+	}
+	if len(router.addressList) != 0 {
+		t.Fatalf("expected address-list entry removed, got %v", router.addressList)
+	}
+	if got := fmt.Sprint(repo.actions()); got != "[quarantined released]" {
+		t.Fatalf("unexpected audit trail %s", got)
+	}
+}
+
+func TestAddressListQuarantineHoldsOnFirstIP(t *testing.T) {
+	repo := newMemoryRepo()
+	router := newFakeRouter()
+	mac := "AA:BB:CC:DD:EE:06"
+	svc := New(repo, &fakeDevices{}, router, staticConfig{}, model.QuarantineConfig{
+		Mode:        model.QuarantineAddressList,
+		AddressList: "quarantine",
+	}, nil)
+
+	ctx := context.Background()
+	if err := svc.HandleNewDevice(ctx, mac); err != nil {
+		t.Fatalf("HandleNewDevice() error = %v", err)
+	}
+	if err := runQueued(ctx, svc); err == nil {
+		t.Fatal("expected apply error for device without ip")
+	}
+	if repo.entries[mac].Status != quarantinedomain.StatusPending || len(router.addressList) != 0 {
+		t.Fatalf("expected pending entry without hold, got %+v %v", repo.entries[mac], router.addressList)
+	}
+
+	if err := svc.HandleDeviceIPChange(ctx, devicedomain.IPChange{MAC: mac, CurrentIP: "192.168.88.60"}); err != nil {
+		t.Fatalf("HandleDeviceIPChange() error = %v", err)
+	}
+	if err := runQueued(ctx, svc); err != nil {
+		t.Fatalf("queued first ip error = %v", err)
+	}
+	if router.addressList["192.168.88.60"] == "" || repo.entries[mac].Address != "192.168.88.60" {
+		t.Fatalf("expected hold on first ip, got %v %+v", router.addressList, repo.entries[mac])
+	}
+	if got := fmt.Sprint(repo.actions()); got != "[apply_failed quarantined]" {
+		t.Fatalf("unexpected audit trail %s", got)
+	}
+}
+
+func TestQuarantineRecordsApplyFailure(t *testing.T) {
+	repo := newMemoryRepo()
+	router := newFakeRouter()
+	router.wifiAccessOK = false
+	svc := New(repo, &fakeDevices{}, router, staticConfig{}, model.QuarantineConfig{
+		Mode:   model.QuarantineWiFiVLAN,
+		VLANID: 666,
+	}, nil)
+
+	if err := svc.HandleNewDevice(context.Background(), "AA:BB:CC:DD:EE:04"); err != nil {
+		t.Fatalf("HandleNewDevice() error = %v", err)
+	}
+	if err := runQueued(context.Background(), svc); err == nil {
+		t.Fatal("expected apply error")
+	}
+	if len(repo.entries) != 1 {
+		t.Fatal("expected device to stay queued for review")
+	}
+	if got := fmt.Sprint(repo.actions()); got != "[apply_failed]" {
+		t.Fatalf("unexpected audit trail %s", got)
+	}
+}
+
+func TestQuarantineOffIgnoresNewDevices(t *testing.T) {
+	repo := newMemoryRepo()
+	svc := New(repo, &fakeDevices{}, newFakeRouter(), staticConfig{}, model.QuarantineConfig{Mode: model.QuarantineOff}, nil)
+	if err := svc.HandleNewDevice(context.Background(), "AA:BB:CC:DD:EE:05"); err != nil {
+		t.Fatalf("HandleNewDevice() error = %v", err)
+	}
+	if len(svc.tasks) != 0 {
+		t.Fatal("expected no queued work when policy is off")
+	}
+	if len(repo.entries) != 0 || len(repo.audit) != 0 {
+		t.Fatal("expected no quarantine when policy is off")
+	}
+}
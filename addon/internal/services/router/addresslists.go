@@ -0,0 +1,86 @@
+package router
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	routerdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/router"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/routeros"
+)
+
+// ListAddressLists returns address-list names with total and managed entry counts.
+func (s *Service) ListAddressLists(ctx context.Context) ([]routerdomain.AddressList, error) {
+	entries, err := s.addressListEntries(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	byName := map[string]*routerdomain.AddressList{}
+	for _, entry := range entries {
+		item, ok := byName[entry.List]
+		if !ok {
+			item = &routerdomain.AddressList{Name: entry.List}
+			byName[entry.List] = item
+		}
+		item.Entries++
+		if entry.Managed {
+			item.Managed++
+		}
+	}
+
+	items := make([]routerdomain.AddressList, 0, len(byName))
+	for _, item := range byName {
+		items = append(items, *item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	return items, nil
+}
+
+// ListAddressListEntries returns entries of one address-list with ownership info.
+func (s *Service) ListAddressListEntries(
+	ctx context.Context,
+	list string,
+) ([]routerdomain.AddressListEntry, error) {
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return []routerdomain.AddressListEntry{}, nil
+	}
+	return s.addressListEntries(ctx, list)
+}
+
+func (s *Service) addressListEntries(ctx context.Context, list string) ([]routerdomain.AddressListEntry, error) {
+	cfg, ok := s.config.Get()
+	if !ok {
+		return nil, routerdomain.ErrAddonNotConfigured
+	}
+	rows, err := s.router.ListAddressListEntries(ctx, cfg, list)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]routerdomain.AddressListEntry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, mapAddressListEntry(row))
+	}
+	return entries, nil
+}
+
+func mapAddressListEntry(row routeros.AddressListEntry) routerdomain.AddressListEntry {
+	entry := routerdomain.AddressListEntry{
+		ID:           row.ID,
+		List:         row.List,
+		Address:      row.Address,
+		Comment:      row.Comment,
+		Timeout:      row.Timeout,
+		CreationTime: row.CreationTime,
+		Dynamic:      row.Dynamic,
+		Disabled:     row.Disabled,
+	}
+	if tag, ok := automationdomain.ParseOwnershipComment(row.Comment); ok {
+		entry.Managed = true
+		entry.Owner = &tag
+	}
+	return entry
+}
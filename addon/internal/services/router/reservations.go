@@ -0,0 +1,240 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	routerdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/router"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/routeros"
+)
+
+// ReserveAddress pins address for MAC with static DHCP lease. Device's own
+// lease is made static and moved; without one a new lease is added. A new
+// address must not be leased to another MAC nor lie in server's dynamic pool,
+// where it could already be handed out.
+func (s *Service) ReserveAddress(
+	ctx context.Context,
+	mac string,
+	in routerdomain.ReservationInput,
+) (routerdomain.Reservation, error) {
+	mac, err := parseMAC(mac)
+	if err != nil {
+		return routerdomain.Reservation{}, err
+	}
+	address := strings.TrimSpace(in.Address)
+	if address != "" {
+		ip := net.ParseIP(address).To4()
+		if ip == nil {
+			return routerdomain.Reservation{}, routerdomain.ErrAddressInvalid
+		}
+		address = ip.String()
+	}
+	cfg, ok := s.config.Get()
+	if !ok {
+		return routerdomain.Reservation{}, routerdomain.ErrAddonNotConfigured
+	}
+
+	s.leases.Lock()
+	defer s.leases.Unlock()
+
+	leases, err := s.router.ListDHCPLeases(ctx, cfg)
+	if err != nil {
+		return routerdomain.Reservation{}, err
+	}
+	server := strings.TrimSpace(in.Server)
+	var own *routeros.DHCPLease
+	for i := range leases {
+		lease := &leases[i]
+		if lease.MAC != mac || (server != "" && lease.Server != server) {
+			continue
+		}
+		if own == nil || (own.Dynamic && !lease.Dynamic) {
+			own = lease
+		}
+	}
+	if own != nil && server == "" {
+		server = own.Server
+	}
+	if address == "" {
+		if own == nil || own.Address == "" {
+			return routerdomain.Reservation{}, fmt.Errorf("%w: device has no lease to pin", routerdomain.ErrAddressInvalid)
+		}
+		address = own.Address
+	}
+
+	dhcpServer, err := s.dhcpServer(ctx, cfg, server)
+	if err != nil {
+		return routerdomain.Reservation{}, err
+	}
+	for _, lease := range leases {
+		if lease.MAC != mac && lease.Address == address && !lease.Disabled {
+			return routerdomain.Reservation{}, fmt.Errorf("%w: %s holds %s", routerdomain.ErrAddressConflict, lease.MAC, address)
+		}
+	}
+	if own == nil || own.Address != address {
+		if err := s.checkOutsidePool(ctx, cfg, dhcpServer, address); err != nil {
+			return routerdomain.Reservation{}, err
+		}
+	}
+
+	comment := strings.TrimSpace(in.Comment)
+	if comment == "" {
+		comment = s.leaseComment(ctx, mac)
+	}
+	if own == nil {
+		if err := s.router.AddDHCPLease(ctx, cfg, mac, address, dhcpServer.Name, comment); err != nil {
+			return routerdomain.Reservation{}, err
+		}
+	} else {
+		if own.Dynamic {
+			if err := s.router.MakeDHCPLeaseStatic(ctx, cfg, own.ID); err != nil {
+				return routerdomain.Reservation{}, err
+			}
+		}
+		if own.Address != address {
+			if err := s.router.SetDHCPLeaseAddress(ctx, cfg, own.ID, address); err != nil {
+				return routerdomain.Reservation{}, err
+			}
+		}
+		if comment != "" && comment != own.Comment {
+			if err := s.router.SetDHCPLeaseComment(ctx, cfg, own.ID, comment); err != nil {
+				return routerdomain.Reservation{}, err
+			}
+		}
+		if comment == "" {
+			comment = own.Comment
+		}
+	}
+	s.logger.Info("dhcp reservation set", "mac", mac, "address", address, "server", dhcpServer.Name)
+	return routerdomain.Reservation{MAC: mac, Address: address, Server: dhcpServer.Name, Comment: comment}, nil
+}
+
+// RemoveReservation removes static DHCP leases of MAC; device falls back to
+// a dynamic lease on next renewal.
+func (s *Service) RemoveReservation(ctx context.Context, mac string) error {
+	mac, err := parseMAC(mac)
+	if err != nil {
+		return err
+	}
+	cfg, ok := s.config.Get()
+	if !ok {
+		return routerdomain.ErrAddonNotConfigured
+	}
+
+	s.leases.Lock()
+	defer s.leases.Unlock()
+
+	leases, err := s.router.ListDHCPLeases(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	removed := 0
+	for _, lease := range leases {
+		if lease.MAC != mac || lease.Dynamic {
+			continue
+		}
+		if err := s.router.RemoveDHCPLease(ctx, cfg, lease.ID); err != nil {
+			return err
+		}
+		removed++
+	}
+	if removed == 0 {
+		return routerdomain.ErrReservationNotFound
+	}
+	s.logger.Info("dhcp reservation removed", "mac", mac)
+	return nil
+}
+
+// HandleDeviceRegistered pushes registered name to static leases of MAC when
+// lease comments are enabled.
+func (s *Service) HandleDeviceRegistered(ctx context.Context, mac string) error {
+	name := s.leaseComment(ctx, mac)
+	if name == "" {
+		return nil
+	}
+	cfg, ok := s.config.Get()
+	if !ok {
+		return routerdomain.ErrAddonNotConfigured
+	}
+
+	s.leases.Lock()
+	defer s.leases.Unlock()
+
+	leases, err := s.router.ListDHCPLeases(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	for _, lease := range leases {
+		if lease.MAC != mac || lease.Dynamic || lease.Comment == name {
+			continue
+		}
+		if err := s.router.SetDHCPLeaseComment(ctx, cfg, lease.ID, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// leaseComment returns registered name of MAC, or empty string when lease
+// comments are off or device is not registered.
+func (s *Service) leaseComment(ctx context.Context, mac string) string {
+	if s.leaseNames == nil {
+		return ""
+	}
+	device, err := s.leaseNames.GetDevice(ctx, mac)
+	if err != nil || device.Status != "registered" {
+		return ""
+	}
+	return strings.TrimSpace(device.Name)
+}
+
+// dhcpServer resolves server by name; empty name is allowed when router runs
+// exactly one enabled server.
+func (s *Service) dhcpServer(ctx context.Context, cfg model.RouterConfig, name string) (routeros.DHCPServer, error) {
+	servers, err := s.router.ListDHCPServers(ctx, cfg)
+	if err != nil {
+		return routeros.DHCPServer{}, err
+	}
+	if name != "" {
+		for _, server := range servers {
+			if server.Name == name {
+				return server, nil
+			}
+		}
+		return routeros.DHCPServer{}, fmt.Errorf("%w: %s", routerdomain.ErrDHCPServerNotFound, name)
+	}
+	enabled := make([]routeros.DHCPServer, 0, len(servers))
+	for _, server := range servers {
+		if !server.Disabled {
+			enabled = append(enabled, server)
+		}
+	}
+	if len(enabled) != 1 {
+		return routeros.DHCPServer{}, routerdomain.ErrDHCPServerRequired
+	}
+	return enabled[0], nil
+}
+
+func (s *Service) checkOutsidePool(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	server routeros.DHCPServer,
+	address string,
+) error {
+	if server.AddressPool == "" || server.AddressPool == "static-only" {
+		return nil
+	}
+	pools, err := s.router.ListIPPools(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	for _, pool := range pools {
+		if pool.Name == server.AddressPool && pool.Contains(address) {
+			return fmt.Errorf("%w: %s is in pool %s", routerdomain.ErrAddressInPool, address, pool.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	routerdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/router"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/routeros"
+)
+
+// RouterClient defines router reads/writes used by router service.
+type RouterClient interface {
+	ListFirewallRulesInTable(ctx context.Context, cfg model.RouterConfig, table string) ([]routeros.FirewallRule, error)
+	SetFirewallRuleDisabled(ctx context.Context, cfg model.RouterConfig, table string, ruleID string, disabled bool) error
+	ListAddressListEntries(ctx context.Context, cfg model.RouterConfig, list string) ([]routeros.AddressListEntry, error)
+	WakeOnLAN(ctx context.Context, cfg model.RouterConfig, mac string, iface string) error
+	ListDHCPLeases(ctx context.Context, cfg model.RouterConfig) ([]routeros.DHCPLease, error)
+	AddDHCPLease(ctx context.Context, cfg model.RouterConfig, mac, address, server, comment string) error
+	MakeDHCPLeaseStatic(ctx context.Context, cfg model.RouterConfig, id string) error
+	SetDHCPLeaseAddress(ctx context.Context, cfg model.RouterConfig, id, address string) error
+	SetDHCPLeaseComment(ctx context.Context, cfg model.RouterConfig, id, comment string) error
+	RemoveDHCPLease(ctx context.Context, cfg model.RouterConfig, id string) error
+	ListDHCPServers(ctx context.Context, cfg model.RouterConfig) ([]routeros.DHCPServer, error)
+	ListIPPools(ctx context.Context, cfg model.RouterConfig) ([]routeros.IPPool, error)
+}
+
+// RouterConfigProvider supplies current add-on router config.
+type RouterConfigProvider interface {
+	Get() (model.RouterConfig, bool)
+}
+
+// TemplateLister reads capability templates to resolve rule references.
+type TemplateLister interface {
+	ListTemplates(ctx context.Context, search, category string) ([]automationdomain.CapabilityTemplate, error)
+}
+
+// DeviceReader resolves registered device names for lease comments.
+type DeviceReader interface {
+	GetDevice(ctx context.Context, mac string) (devicedomain.Device, error)
+}
+
+// Service implements router.Service use-cases.
+type Service struct {
+	router    RouterClient
+	config    RouterConfigProvider
+	templates TemplateLister
+	logger    *slog.Logger
+
+	// leaseNames is set when registered names are pushed as lease comments.
+	leaseNames DeviceReader
+	leases     sync.Mutex
+}
+
+// New creates router service.
+func New(
+	client RouterClient,
+	cfg RouterConfigProvider,
+	templates TemplateLister,
+	logger *slog.Logger,
+) *Service {
+	return &Service{
+		router:    client,
+		config:    cfg,
+		templates: templates,
+		logger:    logger,
+	}
+}
+
+// WithLeaseComments pushes registered device names as comments of their
+// static DHCP leases.
+func (s *Service) WithLeaseComments(devices DeviceReader) *Service {
+	s.leaseNames = devices
+	return s
+}
+
+// ListFirewallRules returns rules of one table annotated with capability references.
+func (s *Service) ListFirewallRules(ctx context.Context, table string) ([]routerdomain.FirewallRule, error) {
+	table, err := normalizeTable(table)
+	if err != nil {
+		return nil, err
+	}
+	cfg, ok := s.config.Get()
+	if !ok {
+		return nil, routerdomain.ErrAddonNotConfigured
+	}
+	return s.listFirewallRules(ctx, cfg, table)
+}
+
+// SetFirewallRuleDisabled toggles one rule and returns its refreshed view.
+func (s *Service) SetFirewallRuleDisabled(
+	ctx context.Context,
+	table string,
+	id string,
+	disabled bool,
+) (routerdomain.FirewallRule, error) {
+	table, err := normalizeTable(table)
+	if err != nil {
+		return routerdomain.FirewallRule{}, err
+	}
+	cfg, ok := s.config.Get()
+	if !ok {
+		return routerdomain.FirewallRule{}, routerdomain.ErrAddonNotConfigured
+	}
+	rules, err := s.listFirewallRules(ctx, cfg, table)
+	if err != nil {
+		return routerdomain.FirewallRule{}, err
+	}
+
+	id = strings.TrimSpace(id)
+	for _, rule := range rules {
+		if rule.ID != id {
+			continue
+		}
+		if rule.Disabled == disabled {
+			return rule, nil
+		}
+		if err := s.router.SetFirewallRuleDisabled(ctx, cfg, table, rule.ID, disabled); err != nil {
+			return routerdomain.FirewallRule{}, err
+		}
+		s.logger.Info("firewall rule toggled", "table", table, "id", rule.ID, "disabled", disabled)
+		rule.Disabled = disabled
+		return rule, nil
+	}
+	return routerdomain.FirewallRule{}, fmt.Errorf("%w: %s", routerdomain.ErrFirewallRuleNotFound, id)
+}
+
+func (s *Service) listFirewallRules(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	table string,
+) ([]routerdomain.FirewallRule, error) {
+	rules, err := s.router.ListFirewallRulesInTable(ctx, cfg, table)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := s.templates.ListTemplates(ctx, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]routerdomain.FirewallRule, 0, len(rules))
+	for _, rule := range rules {
+		items = append(items, mapFirewallRule(rule, templates))
+	}
+	return items, nil
+}
+
+func normalizeTable(table string) (string, error) {
+	table = strings.ToLower(strings.TrimSpace(table))
+	for _, known := range routerdomain.FirewallTables {
+		if table == known {
+			return table, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %q", routerdomain.ErrFirewallTableInvalid, table)
+}
+
+func mapFirewallRule(rule routeros.FirewallRule, templates []automationdomain.CapabilityTemplate) routerdomain.FirewallRule {
+	return routerdomain.FirewallRule{
+		ID:             rule.ID,
+		Table:          rule.Table,
+		Chain:          rule.Chain,
+		Action:         rule.Action,
+		Comment:        rule.Comment,
+		Disabled:       rule.Disabled,
+		SrcAddressList: rule.SrcAddressList,
+		DstAddressList: rule.DstAddressList,
+		Bytes:          rule.Bytes,
+		Packets:        rule.Packets,
+		References:     ruleReferences(rule, templates),
+	}
+}
+
+// ruleReferences finds action/source params that target rule by id or comment.
+// Matching is param-based so any action type using table+rule_id/comment is covered.
+func ruleReferences(
+	rule routeros.FirewallRule,
+	templates []automationdomain.CapabilityTemplate,
+) []routerdomain.RuleReference {
+	references := make([]routerdomain.RuleReference, 0)
+	for _, template := range templates {
+		add := func(location string, params map[string]any) {
+			if matchBy, ok := matchRuleParams(rule, params); ok {
+				references = append(references, routerdomain.RuleReference{
+					CapabilityID:    template.ID,
+					CapabilityLabel: template.Label,
+					Location:        location,
+					MatchBy:         matchBy,
+				})
+			}
+		}
+
+		for _, location := range template.ActionLocations() {
+			add(location.Path, location.Action.Params)
+		}
+		if template.Sync != nil {
+			add("sync.source", template.Sync.Source.Params)
+		}
+	}
+	return references
+}
+
+func matchRuleParams(rule routeros.FirewallRule, params map[string]any) (string, bool) {
+	if !strings.EqualFold(paramText(params, "table"), rule.Table) {
+		return "", false
+	}
+	switch paramText(params, "match_by") {
+	case "comment":
+		comment := paramText(params, "comment")
+		return "comment", comment != "" && comment == rule.Comment
+	case "id":
+		return "id", paramText(params, "rule_id") == rule.ID
+	}
+	return "", false
+}
+
+func paramText(params map[string]any, key string) string {
+	value, _ := params[key].(string)
+	return strings.TrimSpace(value)
+}
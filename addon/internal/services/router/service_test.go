@@ -0,0 +1,347 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	automationdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/automation"
+	devicedomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/device"
+	routerdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/router"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/model"
+	"github.com/micro-ha/mikrotik-presence/addon/internal/routeros"
+)
+
+type fakeRouterClient struct {
+	rules    []routeros.FirewallRule
+	entries  []routeros.AddressListEntry
+	setCall  []string
+	wakeCall []string
+	leases   []routeros.DHCPLease
+	servers  []routeros.DHCPServer
+	pools    []routeros.IPPool
+	leaseOps []string
+}
+
+func (f *fakeRouterClient) ListFirewallRulesInTable(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	table string,
+) ([]routeros.FirewallRule, error) {
+	rules := make([]routeros.FirewallRule, 0, len(f.rules))
+	for _, rule := range f.rules {
+		if rule.Table == table {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (f *fakeRouterClient) SetFirewallRuleDisabled(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	table string,
+	ruleID string,
+	disabled bool,
+) error {
+	f.setCall = append(f.setCall, table+"/"+ruleID)
+	return nil
+}
+
+func (f *fakeRouterClient) ListAddressListEntries(
+	ctx context.Context,
+	cfg model.RouterConfig,
+	list string,
+) ([]routeros.AddressListEntry, error) {
+	entries := make([]routeros.AddressListEntry, 0, len(f.entries))
+	for _, entry := range f.entries {
+		if list == "" || entry.List == list {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (f *fakeRouterClient) WakeOnLAN(ctx context.Context, cfg model.RouterConfig, mac string, iface string) error {
+	f.wakeCall = append(f.wakeCall, mac+"@"+iface)
+	return nil
+}
+
+func (f *fakeRouterClient) ListDHCPLeases(ctx context.Context, cfg model.RouterConfig) ([]routeros.DHCPLease, error) {
+	return append([]routeros.DHCPLease(nil), f.leases...), nil
+}
+
+func (f *fakeRouterClient) AddDHCPLease(ctx context.Context, cfg model.RouterConfig, mac, address, server, comment string) error {
+	f.leaseOps = append(f.leaseOps, "add "+mac+" "+address+"@"+server+" "+comment)
+	f.leases = append(f.leases, routeros.DHCPLease{ID: "*new", MAC: mac, Address: address, Server: server, Comment: comment})
+	return nil
+}
+
+func (f *fakeRouterClient) MakeDHCPLeaseStatic(ctx context.Context, cfg model.RouterConfig, id string) error {
+	f.leaseOps = append(f.leaseOps, "make-static "+id)
+	return nil
+}
+
+func (f *fakeRouterClient) SetDHCPLeaseAddress(ctx context.Context, cfg model.RouterConfig, id, address string) error {
+	f.leaseOps = append(f.leaseOps, "address "+id+" "+address)
+	return nil
+}
+
+func (f *fakeRouterClient) SetDHCPLeaseComment(ctx context.Context, cfg model.RouterConfig, id, comment string) error {
+	f.leaseOps = append(f.leaseOps, "comment "+id+" "+comment)
+	return nil
+}
+
+func (f *fakeRouterClient) RemoveDHCPLease(ctx context.Context, cfg model.RouterConfig, id string) error {
+	f.leaseOps = append(f.leaseOps, "remove "+id)
+	return nil
+}
+
+func (f *fakeRouterClient) ListDHCPServers(ctx context.Context, cfg model.RouterConfig) ([]routeros.DHCPServer, error) {
+	return f.servers, nil
+}
+
+func (f *fakeRouterClient) ListIPPools(ctx context.Context, cfg model.RouterConfig) ([]routeros.IPPool, error) {
+	return f.pools, nil
+}
+
+type fakeConfig struct{}
+
+func (fakeConfig) Get() (model.RouterConfig, bool) {
+	return model.RouterConfig{Host: "192.168.88.1"}, true
+}
+
+type fakeTemplates []automationdomain.CapabilityTemplate
+
+func (f fakeTemplates) ListTemplates(ctx context.Context, search, category string) ([]automationdomain.CapabilityTemplate, error) {
+	return f, nil
+}
+
+func newTestService(client *fakeRouterClient) *Service {
+	templates := fakeTemplates{
+		{
+			ID:    "kids.internet",
+			Label: "Kids internet",
+			States: map[string]automationdomain.CapabilityStateConfig{
+				"off": {ActionsOnEnter: []automationdomain.ActionInstance{{
+					ID:     "a1",
+					TypeID: "mikrotik.firewall.rule.toggle",
+					Params: map[string]any{"table": "filter", "mode": "enable", "match_by": "comment", "comment": "kids"},
+				}}},
+			},
+			Sync: &automationdomain.CapabilitySyncConfig{Source: automationdomain.CapabilitySyncSource{
+				TypeID: "mikrotik.firewall.rule.enabled",
+				Params: map[string]any{"table": "filter", "match_by": "id", "rule_id": "*2"},
+			}},
+		},
+	}
+	return New(client, fakeConfig{}, templates, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestListFirewallRulesResolvesReferences(t *testing.T) {
+	client := &fakeRouterClient{rules: []routeros.FirewallRule{
+		{ID: "*1", Table: "filter", Chain: "forward", Action: "drop", Comment: "kids", Bytes: 120},
+		{ID: "*2", Table: "filter", Chain: "forward", Action: "accept"},
+		{ID: "*3", Table: "nat", Chain: "srcnat", Action: "masquerade", Comment: "kids"},
+	}}
+	service := newTestService(client)
+
+	rules, err := service.ListFirewallRules(context.Background(), "Filter")
+	if err != nil {
+		t.Fatalf("ListFirewallRules returned error: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 filter rules, got %+v", rules)
+	}
+	if refs := rules[0].References; len(refs) != 1 || refs[0].Location != "states.off.actions_on_enter[0]" || refs[0].MatchBy != "comment" {
+		t.Fatalf("unexpected comment references %+v", refs)
+	}
+	if refs := rules[1].References; len(refs) != 1 || refs[0].Location != "sync.source" || refs[0].MatchBy != "id" {
+		t.Fatalf("unexpected id references %+v", refs)
+	}
+
+	if _, err := service.ListFirewallRules(context.Background(), "bridge"); !errors.Is(err, routerdomain.ErrFirewallTableInvalid) {
+		t.Fatalf("expected ErrFirewallTableInvalid, got %v", err)
+	}
+}
+
+func TestSetFirewallRuleDisabled(t *testing.T) {
+	client := &fakeRouterClient{rules: []routeros.FirewallRule{
+		{ID: "*1", Table: "filter", Chain: "forward", Action: "drop"},
+	}}
+	service := newTestService(client)
+
+	rule, err := service.SetFirewallRuleDisabled(context.Background(), "filter", "*1", true)
+	if err != nil {
+		t.Fatalf("SetFirewallRuleDisabled returned error: %v", err)
+	}
+	if !rule.Disabled || len(client.setCall) != 1 || client.setCall[0] != "filter/*1" {
+		t.Fatalf("unexpected toggle result %+v calls=%v", rule, client.setCall)
+	}
+
+	if _, err := service.SetFirewallRuleDisabled(context.Background(), "filter", "*1", false); err != nil {
+		t.Fatalf("SetFirewallRuleDisabled returned error: %v", err)
+	}
+	if _, err := service.SetFirewallRuleDisabled(context.Background(), "filter", "*9", true); !errors.Is(err, routerdomain.ErrFirewallRuleNotFound) {
+		t.Fatalf("expected ErrFirewallRuleNotFound, got %v", err)
+	}
+}
+
+func TestAddressListsResolveOwnership(t *testing.T) {
+	client := &fakeRouterClient{entries: []routeros.AddressListEntry{
+		{ID: "*1", List: "blocked", Address: "192.168.88.10", Comment: "mikrotik-presence:kids.internet/AA:BB:CC:DD:EE:01", Timeout: "59m", Dynamic: true},
+		{ID: "*2", List: "blocked", Address: "192.168.88.11", Comment: "added by hand"},
+		{ID: "*3", List: "vpn", Address: "10.0.0.2"},
+	}}
+	service := newTestService(client)
+
+	lists, err := service.ListAddressLists(context.Background())
+	if err != nil {
+		t.Fatalf("ListAddressLists returned error: %v", err)
+	}
+	if len(lists) != 2 || lists[0].Name != "blocked" || lists[0].Entries != 2 || lists[0].Managed != 1 {
+		t.Fatalf("unexpected lists %+v", lists)
+	}
+
+	entries, err := service.ListAddressListEntries(context.Background(), "blocked")
+	if err != nil {
+		t.Fatalf("ListAddressListEntries returned error: %v", err)
+	}
+	if len(entries) != 2 || !entries[0].Managed || entries[0].Owner.CapabilityID != "kids.internet" || entries[1].Managed {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+}
+
+func TestWakeOnLANNormalizesMAC(t *testing.T) {
+	client := &fakeRouterClient{}
+	service := newTestService(client)
+
+	if err := service.WakeOnLAN(context.Background(), "aa-bb-cc-dd-ee-01", " bridge "); err != nil {
+		t.Fatalf("WakeOnLAN returned error: %v", err)
+	}
+	if len(client.wakeCall) != 1 || client.wakeCall[0] != "AA:BB:CC:DD:EE:01@bridge" {
+		t.Fatalf("unexpected wake calls %v", client.wakeCall)
+	}
+	if err := service.WakeOnLAN(context.Background(), "nas", ""); !errors.Is(err, routerdomain.ErrMACInvalid) {
+		t.Fatalf("expected ErrMACInvalid, got %v", err)
+	}
+}
+
+type fakeDeviceReader map[string]devicedomain.Device
+
+func (f fakeDeviceReader) GetDevice(ctx context.Context, mac string) (devicedomain.Device, error) {
+	device, ok := f[mac]
+	if !ok {
+		return devicedomain.Device{}, devicedomain.ErrDeviceNotFound
+	}
+	return device, nil
+}
+
+func newLeaseTestClient() *fakeRouterClient {
+	return &fakeRouterClient{
+		leases: []routeros.DHCPLease{
+			{ID: "*1", MAC: "AA:BB:CC:DD:EE:01", Address: "192.168.88.150", Server: "lan", Dynamic: true},
+			{ID: "*2", MAC: "AA:BB:CC:DD:EE:02", Address: "192.168.88.5", Server: "lan", Comment: "printer"},
+		},
+		servers: []routeros.DHCPServer{{Name: "lan", Interface: "bridge", AddressPool: "dhcp"}},
+		pools:   []routeros.IPPool{{Name: "dhcp", Ranges: []string{"192.168.88.100-192.168.88.254"}}},
+	}
+}
+
+func TestReserveAddressPinsCurrentLease(t *testing.T) {
+	client := newLeaseTestClient()
+	service := newTestService(client).WithLeaseComments(fakeDeviceReader{
+		"AA:BB:CC:DD:EE:01": {MAC: "AA:BB:CC:DD:EE:01", Name: "Laptop", Status: "registered"},
+	})
+
+	reservation, err := service.ReserveAddress(context.Background(), "aa-bb-cc-dd-ee-01", routerdomain.ReservationInput{})
+	if err != nil {
+		t.Fatalf("ReserveAddress returned error: %v", err)
+	}
+	if reservation.Address != "192.168.88.150" || reservation.Server != "lan" || reservation.Comment != "Laptop" {
+		t.Fatalf("unexpected reservation %+v", reservation)
+	}
+	if fmt.Sprint(client.leaseOps) != "[make-static *1 comment *1 Laptop]" {
+		t.Fatalf("unexpected lease ops %v", client.leaseOps)
+	}
+}
+
+func TestReserveAddressConflicts(t *testing.T) {
+	client := newLeaseTestClient()
+	service := newTestService(client)
+	ctx := context.Background()
+
+	_, err := service.ReserveAddress(ctx, "AA:BB:CC:DD:EE:01", routerdomain.ReservationInput{Address: "192.168.88.5"})
+	if !errors.Is(err, routerdomain.ErrAddressConflict) {
+		t.Fatalf("expected ErrAddressConflict, got %v", err)
+	}
+	_, err = service.ReserveAddress(ctx, "AA:BB:CC:DD:EE:01", routerdomain.ReservationInput{Address: "192.168.88.120"})
+	if !errors.Is(err, routerdomain.ErrAddressInPool) {
+		t.Fatalf("expected ErrAddressInPool, got %v", err)
+	}
+	_, err = service.ReserveAddress(ctx, "AA:BB:CC:DD:EE:01", routerdomain.ReservationInput{Address: "nas"})
+	if !errors.Is(err, routerdomain.ErrAddressInvalid) {
+		t.Fatalf("expected ErrAddressInvalid, got %v", err)
+	}
+	_, err = service.ReserveAddress(ctx, "AA:BB:CC:DD:EE:09", routerdomain.ReservationInput{})
+	if !errors.Is(err, routerdomain.ErrAddressInvalid) {
+		t.Fatalf("expected ErrAddressInvalid for device without lease, got %v", err)
+	}
+	if len(client.leaseOps) != 0 {
+		t.Fatalf("expected no router writes, got %v", client.leaseOps)
+	}
+
+	reservation, err := service.ReserveAddress(ctx, "AA:BB:CC:DD:EE:01", routerdomain.ReservationInput{Address: "192.168.88.20"})
+	if err != nil || reservation.Address != "192.168.88.20" {
+		t.Fatalf("ReserveAddress = %+v, %v", reservation, err)
+	}
+	if fmt.Sprint(client.leaseOps) != "[make-static *1 address *1 192.168.88.20]" {
+		t.Fatalf("unexpected lease ops %v", client.leaseOps)
+	}
+}
+
+func TestReserveAddressAddsLeaseForUnleasedDevice(t *testing.T) {
+	client := newLeaseTestClient()
+	client.servers = append(client.servers, routeros.DHCPServer{Name: "guest", AddressPool: "guest"})
+	service := newTestService(client)
+	ctx := context.Background()
+
+	input := routerdomain.ReservationInput{Address: "192.168.88.30", Comment: "NAS"}
+	if _, err := service.ReserveAddress(ctx, "AA:BB:CC:DD:EE:09", input); !errors.Is(err, routerdomain.ErrDHCPServerRequired) {
+		t.Fatalf("expected ErrDHCPServerRequired, got %v", err)
+	}
+	input.Server = "lan"
+	if _, err := service.ReserveAddress(ctx, "AA:BB:CC:DD:EE:09", input); err != nil {
+		t.Fatalf("ReserveAddress returned error: %v", err)
+	}
+	if fmt.Sprint(client.leaseOps) != "[add AA:BB:CC:DD:EE:09 192.168.88.30@lan NAS]" {
+		t.Fatalf("unexpected lease ops %v", client.leaseOps)
+	}
+}
+
+func TestRemoveReservationAndLeaseComments(t *testing.T) {
+	client := newLeaseTestClient()
+	service := newTestService(client).WithLeaseComments(fakeDeviceReader{
+		"AA:BB:CC:DD:EE:02": {MAC: "AA:BB:CC:DD:EE:02", Name: "Office printer", Status: "registered"},
+	})
+	ctx := context.Background()
+
+	if err := service.HandleDeviceRegistered(ctx, "AA:BB:CC:DD:EE:02"); err != nil {
+		t.Fatalf("HandleDeviceRegistered returned error: %v", err)
+	}
+	if err := service.HandleDeviceRegistered(ctx, "AA:BB:CC:DD:EE:01"); err != nil {
+		t.Fatalf("HandleDeviceRegistered for unknown device returned error: %v", err)
+	}
+	if err := service.RemoveReservation(ctx, "AA:BB:CC:DD:EE:02"); err != nil {
+		t.Fatalf("RemoveReservation returned error: %v", err)
+	}
+	if err := service.RemoveReservation(ctx, "AA:BB:CC:DD:EE:01"); !errors.Is(err, routerdomain.ErrReservationNotFound) {
+		t.Fatalf("expected ErrReservationNotFound for dynamic lease, got %v", err)
+	}
+	if fmt.Sprint(client.leaseOps) != "[comment *2 Office printer remove *2]" {
+		t.Fatalf("unexpected lease ops %v", client.leaseOps)
+	}
+}
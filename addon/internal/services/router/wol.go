@@ -0,0 +1,32 @@
+package router
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	routerdomain "github.com/micro-ha/mikrotik-presence/addon/internal/domain/router"
+)
+
+// WakeOnLAN sends magic packet for mac out of iface; empty iface lets the
+// router choose.
+func (s *Service) WakeOnLAN(ctx context.Context, mac string, iface string) error {
+	mac, err := parseMAC(mac)
+	if err != nil {
+		return err
+	}
+	cfg, ok := s.config.Get()
+	if !ok {
+		return routerdomain.ErrAddonNotConfigured
+	}
+	return s.router.WakeOnLAN(ctx, cfg, mac, strings.TrimSpace(iface))
+}
+
+// parseMAC returns upper-case colon form of 48-bit MAC.
+func parseMAC(mac string) (string, error) {
+	hw, err := net.ParseMAC(strings.TrimSpace(mac))
+	if err != nil || len(hw) != 6 {
+		return "", routerdomain.ErrMACInvalid
+	}
+	return strings.ToUpper(hw.String()), nil
+}
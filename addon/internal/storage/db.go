@@ -107,6 +107,45 @@ func (r *Repository) migrate(ctx context.Context) error {
 			state TEXT NOT NULL,
 			updated_at DATETIME NOT NULL
 		);`,
+		`CREATE TABLE IF NOT EXISTS capability_drift_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			capability_id TEXT NOT NULL,
+			scope TEXT NOT NULL,
+			device_id TEXT NOT NULL DEFAULT '',
+			expected_state TEXT NOT NULL,
+			observed_state TEXT NOT NULL,
+			outcome TEXT NOT NULL,
+			warnings_json TEXT NOT NULL DEFAULT '[]',
+			detected_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS router_artefacts (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			capability_id TEXT NOT NULL,
+			device_id TEXT NOT NULL DEFAULT '',
+			kind TEXT NOT NULL,
+			path TEXT NOT NULL,
+			object_key TEXT NOT NULL,
+			restore TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL,
+			UNIQUE (capability_id, device_id, kind, path, object_key)
+		);`,
+		`CREATE TABLE IF NOT EXISTS device_quarantine (
+			mac TEXT PRIMARY KEY,
+			status TEXT NOT NULL,
+			mode TEXT NOT NULL,
+			address TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL,
+			updated_at TEXT NOT NULL
+		);`,
+		`CREATE TABLE IF NOT EXISTS quarantine_audit (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			mac TEXT NOT NULL,
+			action TEXT NOT NULL,
+			actor TEXT NOT NULL,
+			detail TEXT NOT NULL DEFAULT '',
+			created_at TEXT NOT NULL
+		);`,
 	}
 
 	for _, stmt := range statements {
@@ -129,6 +168,15 @@ func (r *Repository) migrate(ctx context.Context) error {
 	if _, err := r.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_global_cap_state_updated_at ON global_capabilities_state(updated_at);`); err != nil {
 		return err
 	}
+	if _, err := r.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_drift_events_capability ON capability_drift_events(capability_id, id);`); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_router_artefacts_object ON router_artefacts(kind, path, object_key);`); err != nil {
+		return err
+	}
+	if _, err := r.db.ExecContext(ctx, `CREATE INDEX IF NOT EXISTS idx_quarantine_audit_mac ON quarantine_audit(mac, id);`); err != nil {
+		return err
+	}
 	if err := r.ensureStateColumns(ctx); err != nil {
 		return err
 	}
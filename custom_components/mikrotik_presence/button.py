@@ -0,0 +1,119 @@
+"""Button platform for MikroTik Presence device actions."""
+
+from __future__ import annotations
+
+from homeassistant.components.button import ButtonEntity
+from homeassistant.config_entries import ConfigEntry
+from homeassistant.core import HomeAssistant, callback
+from homeassistant.exceptions import HomeAssistantError
+from homeassistant.helpers.device_registry import DeviceInfo
+from homeassistant.helpers.entity_platform import AddEntitiesCallback
+from homeassistant.helpers.update_coordinator import CoordinatorEntity
+
+from .client import DeviceDTO, MikrotikApiError, MikrotikPresenceClient
+from .const import DATA_CLIENT, DATA_COORDINATOR_DEVICES, DOMAIN
+from .coordinator import DevicesCoordinator
+
+
+async def async_setup_entry(
+    hass: HomeAssistant,
+    entry: ConfigEntry,
+    async_add_entities: AddEntitiesCallback,
+) -> None:
+    """Set up button entities from a config entry."""
+    data = hass.data[DOMAIN][entry.entry_id]
+    client: MikrotikPresenceClient = data[DATA_CLIENT]
+    devices_coordinator: DevicesCoordinator = data[DATA_COORDINATOR_DEVICES]
+
+    known_unique_ids: set[str] = set()
+
+    def _discover_new_entities() -> list[MikrotikWakeButton]:
+        entities: list[MikrotikWakeButton] = []
+
+        for device in (devices_coordinator.data or {}).values():
+            entity = MikrotikWakeButton(devices_coordinator, client, device)
+            if entity.unique_id in known_unique_ids:
+                continue
+
+            known_unique_ids.add(entity.unique_id)
+            entities.append(entity)
+
+        return entities
+
+    initial_entities = _discover_new_entities()
+    if initial_entities:
+        async_add_entities(initial_entities)
+
+    @callback
+    def _handle_coordinator_update() -> None:
+        new_entities = _discover_new_entities()
+        if new_entities:
+            async_add_entities(new_entities)
+
+    entry.async_on_unload(devices_coordinator.async_add_listener(_handle_coordinator_update))
+
+
+class MikrotikWakeButton(CoordinatorEntity[DevicesCoordinator], ButtonEntity):
+    """Button entity sending Wake-on-LAN packet to a registered device."""
+
+    _attr_should_poll = False
+    _attr_icon = "mdi:power"
+
+    def __init__(
+        self,
+        coordinator: DevicesCoordinator,
+        client: MikrotikPresenceClient,
+        device: DeviceDTO,
+    ) -> None:
+        super().__init__(coordinator)
+        self._client = client
+        self._device_id = device.id
+        self._fallback_device_name = device.name
+        self._fallback_device_vendor = device.vendor
+
+    @property
+    def name(self) -> str:
+        """Return display name for entity."""
+        device = self._device
+        device_name = device.name if device is not None else self._fallback_device_name
+        return f"{device_name} Wake"
+
+    @property
+    def unique_id(self) -> str:
+        """Return stable unique ID."""
+        return f"mikrotik_presence_{self._device_id}_wake"
+
+    @property
+    def device_info(self) -> DeviceInfo:
+        """Return device registry info."""
+        device = self._device
+        return DeviceInfo(
+            identifiers={(DOMAIN, self._device_id)},
+            name=(device.name if device is not None else self._fallback_device_name),
+            manufacturer=(
+                device.vendor
+                if device is not None and device.vendor
+                else self._fallback_device_vendor or "MikroTik Client"
+            ),
+            model="MikroTik Client",
+        )
+
+    @property
+    def available(self) -> bool:
+        """Return availability; offline devices are exactly the ones to wake."""
+        return self.coordinator.last_update_success and self._device is not None
+
+    @property
+    def _device(self) -> DeviceDTO | None:
+        """Return current device from coordinator data."""
+        data = self.coordinator.data
+        if not isinstance(data, dict):
+            return None
+        return data.get(self._device_id)
+
+    async def async_press(self) -> None:
+        """Send Wake-on-LAN packet through backend."""
+        try:
+            await self._client.async_wake_device(self._device_id)
+        except MikrotikApiError as err:
+            raise HomeAssistantError(str(err)) from err
@@ -123,6 +123,10 @@ class MikrotikPresenceClient:
             payload={"state": state},
         )
 
+    async def async_wake_device(self, device_id: str) -> None:
+        """Send Wake-on-LAN packet to one device."""
+        await self._async_request("POST", f"/api/devices/{device_id}/wake")
+
     async def _async_request(
         self,
         method: str,
@@ -1,7 +1,7 @@
 """Constants for MikroTik Presence integration."""
 
 DOMAIN = "mikrotik_presence"
-PLATFORMS: list[str] = ["switch", "select"]
+PLATFORMS: list[str] = ["switch", "select", "button"]
 
 CONF_BASE_URL = "base_url"
 CONF_API_KEY = "api_key"